@@ -0,0 +1,106 @@
+package ptd
+
+// ScoreAggregate holds a player's accumulated results across matches
+type ScoreAggregate struct {
+	PlayerID     string  `json:"player_id"`
+	DisplayName  string  `json:"display_name"`
+	MatchesWon   int     `json:"matches_won"`
+	MatchesLost  int     `json:"matches_lost"`
+	SetsWon      int     `json:"sets_won"`
+	SetsLost     int     `json:"sets_lost"`
+	PointsWon    int     `json:"points_won"`
+	PointsLost   int     `json:"points_lost"`
+	RatingPoints float64 `json:"rating_points"`
+}
+
+// AggregateScoresByPlayer accumulates the results of scored matches per
+// player, for seeding later rounds. Players are keyed by PlayerID, or by
+// display name when they have none.
+//
+// Every player in an entry is credited with the entry's matches, sets and
+// points. RatingPoints is the entry's share of the result split equally
+// between its players: a match win is worth 1, so each doubles partner earns
+// 0.5. The winner is the match's Winner or, failing that, the side with more
+// sets. Matches without a score or whose entries are unknown are skipped.
+func AggregateScoresByPlayer(matches []Envelope[Match], entries []Envelope[Entry]) map[string]*ScoreAggregate {
+	entryByID := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		entryByID[entry.ID] = entry.Spec
+	}
+
+	aggregates := make(map[string]*ScoreAggregate)
+	credit := func(entry Entry, won bool, setsWon, setsLost, pointsWon, pointsLost int) {
+		var players []Player
+		for _, player := range entry.Players {
+			if aggregateKey(player) != "" {
+				players = append(players, player)
+			}
+		}
+		for _, player := range players {
+			key := aggregateKey(player)
+			agg := aggregates[key]
+			if agg == nil {
+				agg = &ScoreAggregate{PlayerID: key, DisplayName: playerName(player)}
+				aggregates[key] = agg
+			}
+			if won {
+				agg.MatchesWon++
+				agg.RatingPoints += 1 / float64(len(players))
+			} else {
+				agg.MatchesLost++
+			}
+			agg.SetsWon += setsWon
+			agg.SetsLost += setsLost
+			agg.PointsWon += pointsWon
+			agg.PointsLost += pointsLost
+		}
+	}
+
+	for _, match := range matches {
+		m := match.Spec
+		if m.Score == nil || m.HomeEntry == nil || m.AwayEntry == nil {
+			continue
+		}
+		home, ok := entryByID[m.HomeEntry.EntryID]
+		if !ok {
+			continue
+		}
+		away, ok := entryByID[m.AwayEntry.EntryID]
+		if !ok {
+			continue
+		}
+
+		homeSets, awaySets := m.Score.SetWins()
+		var homePoints, awayPoints int
+		for _, set := range m.Score.Sets {
+			homePoints += set.HomeScore
+			awayPoints += set.AwayScore
+		}
+
+		var homeWon bool
+		switch m.Winner {
+		case m.HomeEntry.EntryID:
+			homeWon = true
+		case m.AwayEntry.EntryID:
+			homeWon = false
+		default:
+			if homeSets == awaySets {
+				continue
+			}
+			homeWon = homeSets > awaySets
+		}
+
+		credit(home, homeWon, homeSets, awaySets, homePoints, awayPoints)
+		credit(away, !homeWon, awaySets, homeSets, awayPoints, homePoints)
+	}
+
+	return aggregates
+}
+
+// aggregateKey returns the key a player's results are aggregated under
+func aggregateKey(player Player) string {
+	if player.PlayerID != "" {
+		return player.PlayerID
+	}
+	return playerName(player)
+}
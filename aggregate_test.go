@@ -0,0 +1,77 @@
+package ptd
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAggregateScoresByPlayer(t *testing.T) {
+	singles := func(id, playerID, name string) Envelope[Entry] {
+		return Envelope[Entry]{ID: id, Type: TypeEntry, Spec: Entry{
+			EntryType: "individual",
+			Players:   []Player{{PlayerID: playerID, DisplayName: name}},
+		}}
+	}
+	doubles := Envelope[Entry]{ID: "ptd:entry:d1", Type: TypeEntry, Spec: Entry{
+		EntryType: "doubles",
+		Players:   []Player{{PlayerID: "P1", DisplayName: "Ma Long"}, {FirstName: "Xu", LastName: "Xin"}},
+	}}
+	entries := []Envelope[Entry]{
+		singles("ptd:entry:s1", "P1", "Ma Long"),
+		singles("ptd:entry:s2", "P2", "Fan Zhendong"),
+		singles("ptd:entry:s3", "P3", "Lin Gaoyuan"),
+		doubles,
+	}
+
+	match := func(home, away, winner string, score *Score) Envelope[Match] {
+		return Envelope[Match]{Type: TypeMatch, Spec: Match{
+			Status:    "completed",
+			HomeEntry: &EntryRef{EntryID: home},
+			AwayEntry: &EntryRef{EntryID: away},
+			Winner:    winner,
+			Score:     score,
+		}}
+	}
+	matches := []Envelope[Match]{
+		// Winner inferred from sets: P1 wins 2-1
+		match("ptd:entry:s1", "ptd:entry:s2", "", newTestScore("2-1",
+			[2]int{11, 9},
+			[2]int{8, 11},
+			[2]int{11, 5})),
+		// Doubles pair loses to P3
+		match("ptd:entry:d1", "ptd:entry:s3", "ptd:entry:s3", newTestScore("0-1",
+			[2]int{7, 11})),
+		// Doubles pair wins against P2
+		match("ptd:entry:s2", "ptd:entry:d1", "ptd:entry:d1", newTestScore("0-1",
+			[2]int{3, 11})),
+		// Skipped: unscored, and unknown entry
+		match("ptd:entry:s1", "ptd:entry:s3", "", nil),
+		match("ptd:entry:s1", "ptd:entry:missing", "ptd:entry:s1", newTestScore("1-0")),
+	}
+
+	aggregates := AggregateScoresByPlayer(matches, entries)
+	if len(aggregates) != 4 {
+		t.Fatalf("Expected 4 players, got %d", len(aggregates))
+	}
+
+	p1 := aggregates["P1"]
+	if p1.DisplayName != "Ma Long" || p1.MatchesWon != 2 || p1.MatchesLost != 1 {
+		t.Errorf("Unexpected P1 aggregate: %+v", p1)
+	}
+	if p1.SetsWon != 3 || p1.SetsLost != 2 || p1.PointsWon != 30+7+11 || p1.PointsLost != 25+11+3 {
+		t.Errorf("Unexpected P1 sets/points: %+v", p1)
+	}
+	if math.Abs(p1.RatingPoints-1.5) > 1e-9 {
+		t.Errorf("Expected P1 rating points 1.5, got %v", p1.RatingPoints)
+	}
+
+	xu := aggregates["Xu Xin"]
+	if xu == nil || xu.MatchesWon != 1 || xu.MatchesLost != 1 || math.Abs(xu.RatingPoints-0.5) > 1e-9 {
+		t.Errorf("Unexpected doubles partner aggregate: %+v", xu)
+	}
+
+	p2 := aggregates["P2"]
+	if p2.MatchesWon != 0 || p2.MatchesLost != 2 || p2.RatingPoints != 0 {
+		t.Errorf("Unexpected P2 aggregate: %+v", p2)
+	}
+}
@@ -0,0 +1,317 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AnonymizationMap records how real player identities were replaced
+type AnonymizationMap struct {
+	PlayerIDs map[string]string `json:"player_ids"`          // Real PlayerID -> anonymized PlayerID
+	CoachIDs  map[string]string `json:"coach_ids,omitempty"` // Real CoachID -> anonymized CoachID
+	Tokens    map[string]string `json:"tokens"`              // Real PlayerID or CoachID, or display name if none -> token (e.g., "Player1", "Coach1")
+}
+
+// anonymizer assigns stable tokens to players across all envelopes
+type anonymizer struct {
+	mapping    *AnonymizationMap
+	byIdentity map[string]Player // identity key -> anonymized player
+	coaches    map[string]Coach  // identity key -> anonymized coach
+	entryNames map[string]string // entry ID -> anonymized display name
+	ids        *IDGenerator
+}
+
+// Anonymize returns a copy of the package with all player-identifying data
+// replaced by tokens. See AnonymizeWithMap.
+func (p *Package) Anonymize() (*Package, error) {
+	anon, _, err := p.AnonymizeWithMap()
+	return anon, err
+}
+
+// AnonymizeWithMap returns a copy of the package with all player-identifying
+// data replaced, together with the mapping from real to anonymized identities.
+//
+// Each distinct player (by PlayerID, or by name when no PlayerID is set) gets
+// the same token ("Player1", "Player2", ...) and a fresh ULID PlayerID in
+// every envelope they appear in. Email, phone and birth date are cleared, and
+// entry display names in matches are rewritten to the players' tokens.
+// Coaches likewise become "Coach1", "Coach2", ... with a fresh CoachID and
+// no contact details, and the player and coach IDs they reference are
+// rewritten to the anonymized ones. The manifest's catalog metadata is
+// kept. Entity and package signatures are dropped, since the signed content changes.
+func (p *Package) AnonymizeWithMap() (*Package, *AnonymizationMap, error) {
+	if p.Manifest == nil {
+		return nil, nil, ErrManifestMissing
+	}
+
+	a := &anonymizer{
+		mapping: &AnonymizationMap{
+			PlayerIDs: make(map[string]string),
+			CoachIDs:  make(map[string]string),
+			Tokens:    make(map[string]string),
+		},
+		byIdentity: make(map[string]Player),
+		coaches:    make(map[string]Coach),
+		entryNames: make(map[string]string),
+		ids:        NewIDGenerator(),
+	}
+
+	anon := NewPackage(strings.TrimSpace(p.Manifest.Description + " (anonymized)"))
+	anon.Manifest.Creator = p.Manifest.Creator
+	anon.Manifest.Languages = slices.Clone(p.Manifest.Languages)
+	anon.Manifest.Sport = p.Manifest.Sport
+	anon.Manifest.GoverningBody = p.Manifest.GoverningBody
+	anon.Manifest.License = p.Manifest.License
+	if p.Manifest.Publisher != nil {
+		anon.Manifest.Publisher = p.Manifest.Publisher.clone()
+	}
+	anon.Manifest.Tags = slices.Clone(p.Manifest.Tags)
+
+	// Entries first so that match display names can be resolved
+	types := p.entityTypes()
+	ordered := make([]string, 0, len(types))
+	for _, entityType := range types {
+		if entityType == TypeEntry {
+			ordered = append([]string{entityType}, ordered...)
+		} else {
+			ordered = append(ordered, entityType)
+		}
+	}
+
+	for _, entityType := range ordered {
+		lines, err := p.readEntityLines(entityType)
+		if err != nil {
+			anon.Cleanup()
+			return nil, nil, err
+		}
+
+		entities := make([]interface{}, 0, len(lines))
+		for i, line := range lines {
+			entity, err := a.anonymizeLine(entityType, line)
+			if err != nil {
+				anon.Cleanup()
+				return nil, nil, fmt.Errorf("%w: %s entity %d: %v", ErrInvalidFormat, entityType, i+1, err)
+			}
+			entities = append(entities, entity)
+		}
+
+		if err := anon.AddEntities(entityType, entities); err != nil {
+			anon.Cleanup()
+			return nil, nil, err
+		}
+	}
+
+	return anon, a.mapping, nil
+}
+
+// anonymizeLine anonymizes a single NDJSON line of the given entity type
+func (a *anonymizer) anonymizeLine(entityType string, line json.RawMessage) (interface{}, error) {
+	switch entityType {
+	case TypePlayer:
+		var env Envelope[Player]
+		if err := json.Unmarshal(line, &env); err != nil {
+			return nil, err
+		}
+		env.Spec = a.player(env.Spec)
+		env.Meta.Signature = nil
+		return env, nil
+
+	case TypeEntry:
+		var env Envelope[Entry]
+		if err := json.Unmarshal(line, &env); err != nil {
+			return nil, err
+		}
+		names := make([]string, len(env.Spec.Players))
+		for i, player := range env.Spec.Players {
+			env.Spec.Players[i] = a.player(player)
+			names[i] = env.Spec.Players[i].DisplayName
+		}
+		if env.Spec.Team != nil {
+			for i, playerID := range env.Spec.Team.Players {
+				env.Spec.Team.Players[i] = a.playerRef(playerID)
+			}
+		}
+		if len(names) > 0 {
+			a.entryNames[env.ID] = strings.Join(names, " / ")
+		}
+		env.Meta.Signature = nil
+		return env, nil
+
+	case TypeCoach:
+		var env Envelope[Coach]
+		if err := json.Unmarshal(line, &env); err != nil {
+			return nil, err
+		}
+		env.Spec = a.coach(env.Spec)
+		env.Meta.Signature = nil
+		return env, nil
+
+	case TypeRanking:
+		var env Envelope[Ranking]
+		if err := json.Unmarshal(line, &env); err != nil {
+			return nil, err
+		}
+		env.Spec.Player = a.player(env.Spec.Player)
+		env.Meta.Signature = nil
+		return env, nil
+
+	case TypeMatch:
+		var env Envelope[Match]
+		if err := json.Unmarshal(line, &env); err != nil {
+			return nil, err
+		}
+		a.entryRef(env.Spec.HomeEntry)
+		a.entryRef(env.Spec.AwayEntry)
+		env.Meta.Signature = nil
+		return env, nil
+
+	default:
+		return line, nil
+	}
+}
+
+// player returns the anonymized form of a player, assigning a new token
+// the first time an identity is seen
+func (a *anonymizer) player(player Player) Player {
+	key := playerIdentity(player)
+	if anon, ok := a.byIdentity[key]; ok {
+		return anon
+	}
+
+	token := fmt.Sprintf("Player%d", len(a.byIdentity)+1)
+	anon := player
+	anon.FirstName = token
+	anon.LastName = ""
+	anon.DisplayName = token
+	anon.Email = ""
+	anon.Phone = ""
+	anon.BirthDate = time.Time{}
+	if player.PlayerID != "" {
+		anon.PlayerID = a.playerID(player.PlayerID)
+	} else {
+		anon.PlayerID = a.ids.GenerateULID()
+	}
+	anon.CoachID = a.coachRef(player.CoachID)
+
+	a.byIdentity[key] = anon
+	if player.PlayerID != "" {
+		a.mapping.Tokens[player.PlayerID] = token
+	} else {
+		a.mapping.Tokens[playerName(player)] = token
+	}
+
+	return anon
+}
+
+// coach returns the anonymized form of a coach, assigning a new token the
+// first time a coach is seen
+func (a *anonymizer) coach(coach Coach) Coach {
+	key := coachIdentity(coach)
+	if anon, ok := a.coaches[key]; ok {
+		return anon
+	}
+
+	token := fmt.Sprintf("Coach%d", len(a.coaches)+1)
+	anon := coach
+	anon.FirstName = token
+	anon.LastName = ""
+	anon.ContactInfo = nil
+	anon.CoachID = a.coachID(coach.CoachID)
+	anon.Players = nil
+	for _, playerID := range coach.Players {
+		anon.Players = append(anon.Players, a.playerRef(playerID))
+	}
+
+	a.coaches[key] = anon
+	if coach.CoachID != "" {
+		a.mapping.Tokens[coach.CoachID] = token
+	} else {
+		a.mapping.Tokens[strings.TrimSpace(coach.FirstName+" "+coach.LastName)] = token
+	}
+
+	return anon
+}
+
+// playerID returns the anonymized form of a real PlayerID, assigning a new
+// ULID the first time it is seen, so that references to a player resolve
+// whichever envelope is anonymized first
+func (a *anonymizer) playerID(id string) string {
+	return a.remapID(a.mapping.PlayerIDs, id)
+}
+
+// coachID returns the anonymized form of a real CoachID, like playerID
+func (a *anonymizer) coachID(id string) string {
+	return a.remapID(a.mapping.CoachIDs, id)
+}
+
+// playerRef returns the anonymized form of a reference to a player. PTD
+// entity IDs are kept, as envelope IDs are not changed by anonymization;
+// other IDs are taken to be PlayerIDs.
+func (a *anonymizer) playerRef(id string) string {
+	if ValidateID(id) {
+		return id
+	}
+	return a.playerID(id)
+}
+
+// coachRef returns the anonymized form of a reference to a coach, like
+// playerRef
+func (a *anonymizer) coachRef(id string) string {
+	if ValidateID(id) {
+		return id
+	}
+	return a.coachID(id)
+}
+
+// remapID returns the ID mapped to id in ids, adding a new ULID if none
+// is; empty IDs stay empty
+func (a *anonymizer) remapID(ids map[string]string, id string) string {
+	if id == "" {
+		return ""
+	}
+	if anonID, ok := ids[id]; ok {
+		return anonID
+	}
+	anonID := a.ids.GenerateULID()
+	ids[id] = anonID
+	return anonID
+}
+
+// entryRef rewrites an entry reference's display name to the anonymized names
+func (a *anonymizer) entryRef(ref *EntryRef) {
+	if ref == nil {
+		return
+	}
+	if name, ok := a.entryNames[ref.EntryID]; ok {
+		ref.DisplayName = name
+	} else {
+		ref.DisplayName = ""
+	}
+}
+
+// playerIdentity returns the key used to recognize the same player across envelopes
+func playerIdentity(player Player) string {
+	if player.PlayerID != "" {
+		return "id:" + player.PlayerID
+	}
+	return "name:" + strings.ToLower(playerName(player))
+}
+
+// coachIdentity returns the key used to recognize the same coach across envelopes
+func coachIdentity(coach Coach) string {
+	if coach.CoachID != "" {
+		return "id:" + coach.CoachID
+	}
+	return "name:" + strings.ToLower(strings.TrimSpace(coach.FirstName+" "+coach.LastName))
+}
+
+// playerName returns a player's display name, falling back to first and last name
+func playerName(player Player) string {
+	if player.DisplayName != "" {
+		return player.DisplayName
+	}
+	return strings.TrimSpace(player.FirstName + " " + player.LastName)
+}
@@ -0,0 +1,308 @@
+package ptd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackage_Anonymize(t *testing.T) {
+	pkg := NewPackage("Club championship")
+	defer pkg.Cleanup()
+
+	maLong := Player{
+		FirstName: "Ma",
+		LastName:  "Long",
+		Email:     "ma@example.com",
+		Phone:     "+86 555 0100",
+		BirthDate: time.Date(1988, 10, 20, 0, 0, 0, 0, time.UTC),
+		PlayerID:  "ittf-105649",
+		Country:   "CHN",
+	}
+	timoBoll := Player{FirstName: "Timo", LastName: "Boll", Country: "GER"}
+
+	entryID := GenerateID(TypeEntry)
+	entries := []interface{}{
+		Envelope[Entry]{
+			ID:   entryID,
+			Type: TypeEntry,
+			Spec: Entry{EventID: "ptd:event:1", Players: []Player{maLong}},
+			Meta: Meta{Schema: "ptd.v1.entry@1.0.0"},
+		},
+		Envelope[Entry]{
+			ID:   GenerateID(TypeEntry),
+			Type: TypeEntry,
+			Spec: Entry{EventID: "ptd:event:2", Players: []Player{maLong, timoBoll}},
+			Meta: Meta{Schema: "ptd.v1.entry@1.0.0"},
+		},
+	}
+	players := []interface{}{
+		Envelope[Player]{
+			ID:   GenerateID(TypePlayer),
+			Type: TypePlayer,
+			Spec: maLong,
+			Meta: Meta{Schema: "ptd.v1.player@1.0.0"},
+		},
+	}
+	matches := []interface{}{
+		Envelope[Match]{
+			ID:   GenerateID(TypeMatch),
+			Type: TypeMatch,
+			Spec: Match{
+				EventID:     "ptd:event:1",
+				MatchNumber: "M1",
+				HomeEntry:   &EntryRef{EntryID: entryID, DisplayName: "Ma Long"},
+			},
+			Meta: Meta{Schema: "ptd.v1.match@1.0.0"},
+		},
+	}
+	for entityType, entities := range map[string][]interface{}{
+		TypeEntry:  entries,
+		TypePlayer: players,
+		TypeMatch:  matches,
+	} {
+		if err := pkg.AddEntities(entityType, entities); err != nil {
+			t.Fatalf("Failed to add entities: %v", err)
+		}
+	}
+
+	anon, mapping, err := pkg.AnonymizeWithMap()
+	if err != nil {
+		t.Fatalf("Anonymize failed: %v", err)
+	}
+	defer anon.Cleanup()
+
+	if !strings.Contains(anon.Manifest.Description, "anonymized") {
+		t.Errorf("Description should note anonymization: %s", anon.Manifest.Description)
+	}
+	if anon.ID == pkg.ID {
+		t.Error("Anonymized package should have a new ID")
+	}
+
+	// No identifying data remains anywhere
+	data, err := anon.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	for _, leak := range []string{"Long", "Boll", "ma@example.com", "555 0100", "ittf-105649", "1988"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Anonymized package still contains %q", leak)
+		}
+	}
+
+	// Same person gets the same token everywhere
+	entryLines, _ := anon.readEntityLines(TypeEntry)
+	var e1, e2 Envelope[Entry]
+	json.Unmarshal(entryLines[0], &e1)
+	json.Unmarshal(entryLines[1], &e2)
+	if e1.Spec.Players[0].FirstName != "Player1" || e2.Spec.Players[0].FirstName != "Player1" {
+		t.Errorf("Expected Player1 in both entries, got %s and %s", e1.Spec.Players[0].FirstName, e2.Spec.Players[0].FirstName)
+	}
+	if e2.Spec.Players[1].FirstName != "Player2" {
+		t.Errorf("Expected Player2, got %s", e2.Spec.Players[1].FirstName)
+	}
+	if !IsULID(e1.Spec.Players[0].PlayerID) || e1.Spec.Players[0].PlayerID != e2.Spec.Players[0].PlayerID {
+		t.Error("PlayerID should be replaced consistently with a ULID")
+	}
+	if e1.Spec.Players[0].Country != "CHN" {
+		t.Error("Non-identifying fields should be preserved")
+	}
+
+	playerLines, _ := anon.readEntityLines(TypePlayer)
+	var p1 Envelope[Player]
+	json.Unmarshal(playerLines[0], &p1)
+	if p1.Spec.DisplayName != "Player1" || p1.Spec.PlayerID != e1.Spec.Players[0].PlayerID {
+		t.Errorf("Player entity not anonymized consistently: %+v", p1.Spec)
+	}
+
+	matchLines, _ := anon.readEntityLines(TypeMatch)
+	var m1 Envelope[Match]
+	json.Unmarshal(matchLines[0], &m1)
+	if m1.Spec.HomeEntry.DisplayName != "Player1" {
+		t.Errorf("Match display name not anonymized: %s", m1.Spec.HomeEntry.DisplayName)
+	}
+
+	// Mapping
+	if mapping.PlayerIDs["ittf-105649"] != e1.Spec.Players[0].PlayerID {
+		t.Error("Mapping should record the anonymized PlayerID")
+	}
+	if mapping.Tokens["ittf-105649"] != "Player1" || mapping.Tokens["Timo Boll"] != "Player2" {
+		t.Errorf("Unexpected token mapping: %v", mapping.Tokens)
+	}
+}
+
+func TestPackage_Anonymize_Rankings(t *testing.T) {
+	pkg := NewPackage("World ranking")
+	defer pkg.Cleanup()
+
+	rankings, err := ImportITTFWorldRankingCSV(strings.NewReader(sampleITTFRanking))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entities := make([]interface{}, len(rankings))
+	for i, ranking := range rankings {
+		entities[i] = ranking
+	}
+	if err := pkg.AddEntities(TypeRanking, entities); err != nil {
+		t.Fatal(err)
+	}
+
+	anon, mapping, err := pkg.AnonymizeWithMap()
+	if err != nil {
+		t.Fatalf("Anonymize failed: %v", err)
+	}
+	defer anon.Cleanup()
+
+	data, err := anon.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"WANG", "Chuqin", "Ovtcharov", "Moreg"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Anonymized package still contains %q", leak)
+		}
+	}
+
+	anonymized, err := ExtractEntities[Ranking](anon, TypeRanking)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := anonymized[0].Spec
+	if first.Player.DisplayName != "Player1" || first.Rank != 1 || first.Points != 9875 || first.Player.Country != "CHN" {
+		t.Errorf("Anonymized ranking = %+v, want Player1 with the ranking kept", first)
+	}
+	if mapping.Tokens["WANG Chuqin"] != "Player1" {
+		t.Errorf("Unexpected token mapping: %v", mapping.Tokens)
+	}
+}
+
+func TestPackage_Anonymize_Coaches(t *testing.T) {
+	pkg := NewPackage("Youth league")
+	defer pkg.Cleanup()
+
+	coachID := GenerateID(TypeCoach)
+	playerEntityID := GenerateID(TypePlayer)
+	coaches := []interface{}{
+		Envelope[Coach]{
+			ID:   coachID,
+			Type: TypeCoach,
+			Spec: Coach{
+				CoachID:     "DTTB-42",
+				FirstName:   "Jörg",
+				LastName:    "Roßkopf",
+				Club:        "TTC Example",
+				ContactInfo: &Contact{Email: "joerg@example.com"},
+				Players:     []string{playerEntityID, "ittf-105649"},
+			},
+			Meta: Meta{Schema: "ptd.v1.coach@1.0.0"},
+		},
+	}
+	players := []interface{}{
+		Envelope[Player]{
+			ID:   playerEntityID,
+			Type: TypePlayer,
+			Spec: Player{FirstName: "Timo", LastName: "Boll", CoachID: coachID},
+			Meta: Meta{Schema: "ptd.v1.player@1.0.0"},
+		},
+		Envelope[Player]{
+			ID:   GenerateID(TypePlayer),
+			Type: TypePlayer,
+			Spec: Player{FirstName: "Ma", LastName: "Long", PlayerID: "ittf-105649", CoachID: "DTTB-42"},
+			Meta: Meta{Schema: "ptd.v1.player@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeCoach, coaches); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.AddEntities(TypePlayer, players); err != nil {
+		t.Fatal(err)
+	}
+
+	anon, mapping, err := pkg.AnonymizeWithMap()
+	if err != nil {
+		t.Fatalf("Anonymize failed: %v", err)
+	}
+	defer anon.Cleanup()
+
+	data, err := anon.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"Jörg", "Roßkopf", "joerg@example.com", "DTTB-42", "ittf-105649"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("Anonymized package still contains %q", leak)
+		}
+	}
+
+	anonCoaches, err := ExtractEntities[Coach](anon, TypeCoach)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coach := anonCoaches[0].Spec
+	if coach.FirstName != "Coach1" || coach.LastName != "" || coach.ContactInfo != nil || coach.Club != "TTC Example" {
+		t.Errorf("Anonymized coach = %+v, want Coach1 without contact details", coach)
+	}
+	if coach.CoachID != mapping.CoachIDs["DTTB-42"] || !IsULID(coach.CoachID) {
+		t.Errorf("CoachID = %q, want the mapped ULID %q", coach.CoachID, mapping.CoachIDs["DTTB-42"])
+	}
+	if mapping.Tokens["DTTB-42"] != "Coach1" {
+		t.Errorf("Unexpected token mapping: %v", mapping.Tokens)
+	}
+
+	// References stay resolvable: entity IDs are kept, external IDs remapped
+	anonPlayers, err := ExtractEntities[Player](anon, TypePlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(coach.Players) != 2 || coach.Players[0] != playerEntityID || coach.Players[1] != anonPlayers[1].Spec.PlayerID {
+		t.Errorf("Coach players = %v, want %s and %s", coach.Players, playerEntityID, anonPlayers[1].Spec.PlayerID)
+	}
+	if anonPlayers[0].Spec.CoachID != coachID {
+		t.Errorf("Player CoachID = %q, want the coach entity %s", anonPlayers[0].Spec.CoachID, coachID)
+	}
+	if anonPlayers[1].Spec.CoachID != coach.CoachID {
+		t.Errorf("Player CoachID = %q, want the anonymized CoachID %s", anonPlayers[1].Spec.CoachID, coach.CoachID)
+	}
+}
+
+func TestPackage_Anonymize_SameNames(t *testing.T) {
+	pkg := NewPackage("Open")
+	defer pkg.Cleanup()
+	pkg.Manifest.Languages = []string{"en", "zh"}
+	pkg.Manifest.Sport = "table_tennis"
+	pkg.Manifest.GoverningBody = "ITTF"
+	pkg.Manifest.License = "CC-BY-4.0"
+	pkg.Manifest.Publisher = &Publisher{Name: "Example Federation"}
+	pkg.Manifest.Tags = []string{"open"}
+
+	// Two different players who share a name
+	var players []interface{}
+	for _, playerID := range []string{"ittf-1", "ittf-2"} {
+		players = append(players, Envelope[Player]{
+			ID:   GenerateID(TypePlayer),
+			Type: TypePlayer,
+			Spec: Player{FirstName: "Li", LastName: "Wei", PlayerID: playerID},
+			Meta: Meta{Schema: "ptd.v1.player@1.0.0"},
+		})
+	}
+	if err := pkg.AddEntities(TypePlayer, players); err != nil {
+		t.Fatal(err)
+	}
+
+	anon, mapping, err := pkg.AnonymizeWithMap()
+	if err != nil {
+		t.Fatalf("Anonymize failed: %v", err)
+	}
+	defer anon.Cleanup()
+
+	if mapping.Tokens["ittf-1"] != "Player1" || mapping.Tokens["ittf-2"] != "Player2" {
+		t.Errorf("Unexpected token mapping: %v", mapping.Tokens)
+	}
+
+	m := anon.Manifest
+	if len(m.Languages) != 2 || m.Sport != "table_tennis" || m.GoverningBody != "ITTF" || m.License != "CC-BY-4.0" ||
+		m.Publisher == nil || m.Publisher.Name != "Example Federation" || len(m.Tags) != 1 {
+		t.Errorf("Catalog metadata not kept: %+v", m)
+	}
+}
@@ -0,0 +1,305 @@
+package ptd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ArchiveFormat is the container format of a package archive
+type ArchiveFormat string
+
+const (
+	ArchiveZIP   ArchiveFormat = "zip"    // ZIP, the default; files can be read in any order
+	ArchiveTarGz ArchiveFormat = "tar.gz" // Gzip-compressed tar stream, read sequentially
+)
+
+// ArchiveFormatForPath returns the archive format implied by a file name:
+// ArchiveTarGz for names ending in ".tar.gz" or ".tgz", otherwise ArchiveZIP
+func ArchiveFormatForPath(path string) ArchiveFormat {
+	name := strings.ToLower(path)
+	if strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") {
+		return ArchiveTarGz
+	}
+	return ArchiveZIP
+}
+
+// archiveWriter writes the files of a package archive in one format
+type archiveWriter interface {
+	// WriteFile adds a file of the given size read from r and returns the
+	// number of bytes written
+	WriteFile(name string, size int64, modified time.Time, r io.Reader) (int64, error)
+	Close() error
+}
+
+// newArchiveWriter returns a writer of archives of the given format to w,
+// compressing the files of ZIP archives with the given method and level
+func newArchiveWriter(w io.Writer, format ArchiveFormat, compression Compression, level int) (archiveWriter, error) {
+	switch format {
+	case ArchiveZIP, "":
+		archive := &zipArchiveWriter{zip: zip.NewWriter(w), method: zip.Deflate}
+		switch compression {
+		case CompressionDeflate:
+			if level != flate.DefaultCompression {
+				archive.zip.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+					return flate.NewWriter(w, level)
+				})
+			}
+		case CompressionZstd:
+			encoderLevel := zstd.SpeedDefault
+			if level != flate.DefaultCompression {
+				encoderLevel = zstd.EncoderLevelFromZstd(level)
+			}
+			archive.method = zstd.ZipMethodWinZip
+			archive.zip.RegisterCompressor(zstd.ZipMethodWinZip, zstd.ZipCompressor(zstd.WithEncoderLevel(encoderLevel)))
+		}
+		return archive, nil
+	case ArchiveTarGz:
+		if compression == CompressionZstd {
+			return nil, fmt.Errorf("%w: %s archives are always gzip-compressed, not %s", ErrValidation, format, compression)
+		}
+		gz := gzip.NewWriter(w)
+		return &tarGzArchiveWriter{gzip: gz, tar: tar.NewWriter(gz)}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown archive format %q", ErrInvalidPackage, format)
+	}
+}
+
+// zipArchiveWriter writes ZIP archives
+type zipArchiveWriter struct {
+	zip    *zip.Writer
+	method uint16          // Compression method of the files
+	stored map[string]bool // Files written uncompressed
+}
+
+func (a *zipArchiveWriter) WriteFile(name string, size int64, modified time.Time, r io.Reader) (int64, error) {
+	method := a.method
+	if a.stored[name] {
+		method = zip.Store
+	}
+	w, err := a.zip.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(w, r)
+}
+
+func (a *zipArchiveWriter) Close() error {
+	return a.zip.Close()
+}
+
+// tarGzArchiveWriter writes gzip-compressed tar archives
+type tarGzArchiveWriter struct {
+	gzip *gzip.Writer
+	tar  *tar.Writer
+}
+
+func (a *tarGzArchiveWriter) WriteFile(name string, size int64, modified time.Time, r io.Reader) (int64, error) {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     filepath.ToSlash(name),
+		Size:     size,
+		Mode:     0644,
+		ModTime:  modified,
+		Format:   tar.FormatPAX,
+	}
+	if err := a.tar.WriteHeader(header); err != nil {
+		return 0, err
+	}
+	return io.Copy(a.tar, r)
+}
+
+func (a *tarGzArchiveWriter) Close() error {
+	if err := a.tar.Close(); err != nil {
+		return err
+	}
+	return a.gzip.Close()
+}
+
+// detectArchiveFormat identifies an archive by its leading magic bytes
+func detectArchiveFormat(r io.ReaderAt, size int64) (ArchiveFormat, error) {
+	magic := make([]byte, len(encryptionMagic))
+	n, err := r.ReadAt(magic, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK")):
+		return ArchiveZIP, nil
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return ArchiveTarGz, nil
+	case bytes.Equal(magic, []byte(encryptionMagic)):
+		return "", fmt.Errorf("%w: open it with a decryption key", ErrEncrypted)
+	default:
+		return "", fmt.Errorf("%w: not a ZIP or tar.gz archive", ErrInvalidPackage)
+	}
+}
+
+// newZipReader opens a ZIP archive, decompressing both DEFLATE and
+// Zstandard files
+func newZipReader(r io.ReaderAt, size int64) (*zip.Reader, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	registerZipDecompressors(reader)
+	return reader, nil
+}
+
+// registerZipDecompressors adds the decompressors of the methods beyond
+// DEFLATE that packages may use
+func registerZipDecompressors(reader *zip.Reader) {
+	reader.RegisterDecompressor(zstd.ZipMethodWinZip, zstd.ZipDecompressor())
+}
+
+// forEachArchiveFile calls fn with the name and contents of each file of
+// an archive, in archive order, stopping at the first error
+func forEachArchiveFile(r io.ReaderAt, size int64, format ArchiveFormat, fn func(name string, file io.Reader) error) error {
+	if format == ArchiveTarGz {
+		gz, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+		}
+		defer gz.Close()
+
+		reader := tar.NewReader(gz)
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+			}
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := fn(header.Name, reader); err != nil {
+				return err
+			}
+		}
+	}
+
+	reader, err := newZipReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", f.Name, err)
+		}
+		err = fn(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openFile opens a file of the archive by its package-relative path.
+// Returns an error wrapping os.ErrNotExist if the archive has no such file.
+func (s *archiveSource) openFile(relPath string) (io.ReadCloser, error) {
+	r, size, closer, err := s.contents()
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.ToSlash(relPath)
+
+	if s.format == ArchiveZIP || s.format == "" {
+		reader, err := newZipReader(r, size)
+		if err != nil {
+			closer.Close()
+			return nil, err
+		}
+		for _, f := range reader.File {
+			if filepath.ToSlash(f.Name) != name {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				closer.Close()
+				return nil, err
+			}
+			return &archiveFileReader{Reader: rc, closers: []io.Closer{rc, closer}}, nil
+		}
+		closer.Close()
+		return nil, fmt.Errorf("%s: %w", relPath, os.ErrNotExist)
+	}
+
+	// A tar stream is read up to the file, which is then read in place
+	gz, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		closer.Close()
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			gz.Close()
+			closer.Close()
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+		}
+		if header.Typeflag == tar.TypeReg && header.Name == name {
+			return &archiveFileReader{Reader: reader, closers: []io.Closer{gz, closer}}, nil
+		}
+	}
+	gz.Close()
+	closer.Close()
+	return nil, fmt.Errorf("%s: %w", relPath, os.ErrNotExist)
+}
+
+// contents returns the archive's contents and size, and a closer that
+// releases the archive file
+func (s *archiveSource) contents() (io.ReaderAt, int64, io.Closer, error) {
+	if s.path == "" {
+		return s.readerAt, s.size, io.NopCloser(nil), nil
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	return file, info.Size(), file, nil
+}
+
+// archiveFileReader reads a file of an archive and closes the archive with it
+type archiveFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the file and its archive
+func (r *archiveFileReader) Close() error {
+	var errs []error
+	for _, closer := range r.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
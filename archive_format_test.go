@@ -0,0 +1,207 @@
+package ptd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveFormatForPath(t *testing.T) {
+	tests := map[string]ArchiveFormat{
+		"tournament.ptd":        ArchiveZIP,
+		"tournament.zip":        ArchiveZIP,
+		"tournament.ptd.tar.gz": ArchiveTarGz,
+		"TOURNAMENT.TGZ":        ArchiveTarGz,
+		"tournament.gz":         ArchiveZIP,
+	}
+	for path, want := range tests {
+		if got := ArchiveFormatForPath(path); got != want {
+			t.Errorf("ArchiveFormatForPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestPackage_TarGzRoundTrip(t *testing.T) {
+	pkg, wantIDs := newEditTestPackage(t, 3)
+	archivePath := filepath.Join(t.TempDir(), "tournament.ptd.tar.gz")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		t.Fatal("CreateArchive() did not write a gzip stream for a .tar.gz path")
+	}
+
+	// The format is detected from the contents, not the name
+	renamed := filepath.Join(t.TempDir(), "tournament.ptd")
+	if err := os.WriteFile(renamed, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenPackage(renamed)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	inMemory, err := PackageFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PackageFromReader() error = %v", err)
+	}
+	spooled, err := PackageFromReaderWithOptions(bytes.NewReader(data), OpenOptions{MaxInMemoryBytes: -1})
+	if err != nil {
+		t.Fatalf("PackageFromReaderWithOptions() error = %v", err)
+	}
+	t.Cleanup(func() { spooled.Cleanup() })
+	readerAt, err := OpenPackageFrom(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("OpenPackageFrom() error = %v", err)
+	}
+
+	for name, opened := range map[string]*Package{"file": opened, "memory": inMemory, "spooled": spooled, "reader_at": readerAt} {
+		t.Run(name, func(t *testing.T) {
+			for path, want := range pkg.Manifest.Files {
+				if got, ok := opened.Manifest.Files[path]; !ok || got.Hash != want.Hash {
+					t.Errorf("Manifest file %s = %+v, want %+v", path, got, want)
+				}
+			}
+
+			events, err := ExtractEntities[Event](opened, TypeEvent)
+			if err != nil {
+				t.Fatalf("ExtractEntities() error = %v", err)
+			}
+			var ids []string
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			if !reflect.DeepEqual(ids, wantIDs) {
+				t.Errorf("ExtractEntities() IDs = %v, want %v", ids, wantIDs)
+			}
+
+			for range opened.Entities(TypeMatch) {
+				t.Error("Entities() of a type the package does not hold yielded an envelope")
+			}
+		})
+	}
+}
+
+func TestPackage_CreateArchiveToFormat(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+
+	var zipped, tarred bytes.Buffer
+	if err := pkg.CreateArchiveToFormat(&zipped, ArchiveZIP); err != nil {
+		t.Fatalf("CreateArchiveToFormat(zip) error = %v", err)
+	}
+	if err := pkg.CreateArchiveToFormat(&tarred, ArchiveTarGz); err != nil {
+		t.Fatalf("CreateArchiveToFormat(tar.gz) error = %v", err)
+	}
+	if !bytes.HasPrefix(zipped.Bytes(), []byte("PK")) {
+		t.Error("ZIP archive does not start with the ZIP signature")
+	}
+	if !bytes.HasPrefix(tarred.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Error("tar.gz archive does not start with the gzip signature")
+	}
+
+	if err := pkg.CreateArchiveToFormat(io.Discard, "rar"); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("CreateArchiveToFormat(rar) error = %v, want %v", err, ErrInvalidPackage)
+	}
+}
+
+func TestOpenPackage_TarGzErrors(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	var valid bytes.Buffer
+	if err := pkg.CreateArchiveToFormat(&valid, ArchiveTarGz); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rewrite the archive, replacing the contents of the entity file
+	tampered := rewriteTarGz(t, valid.Bytes(), func(name string, data []byte) []byte {
+		if name == entityFilePath(TypeEvent) {
+			return append(data, '\n')
+		}
+		return data
+	})
+	noManifest := rewriteTarGz(t, valid.Bytes(), func(name string, data []byte) []byte {
+		if name == "manifest.json" {
+			return nil
+		}
+		return data
+	})
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"hash mismatch", tampered, ErrHashMismatch},
+		{"missing manifest", noManifest, ErrManifestMissing},
+		{"not an archive", []byte("plain text"), ErrInvalidPackage},
+		{"empty", nil, ErrInvalidPackage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OpenPackageFrom(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("OpenPackageFrom() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	truncated := valid.Bytes()[:valid.Len()/2]
+	if _, err := OpenPackageFrom(bytes.NewReader(truncated), int64(len(truncated))); err == nil {
+		t.Error("OpenPackageFrom() of a truncated archive succeeded")
+	}
+}
+
+// rewriteTarGz copies a tar.gz archive, passing each file through edit;
+// files for which edit returns nil are dropped
+func rewriteTarGz(t *testing.T, archive []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := tar.NewReader(gz)
+
+	var out bytes.Buffer
+	gzw := gzip.NewWriter(&out)
+	writer := tar.NewWriter(gzw)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = edit(header.Name, data)
+		if data == nil {
+			continue
+		}
+		header.Size = int64(len(data))
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
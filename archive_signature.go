@@ -0,0 +1,135 @@
+package ptd
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// ArchiveSignature is a detached signature over the bytes of an archive
+// file, stored next to it in a sidecar (see ArchiveSignaturePath). Unlike
+// SignPackage it signs a finished archive without rewriting it.
+type ArchiveSignature struct {
+	Signature
+	SHA256 string `json:"sha256"` // Hex-encoded SHA-256 hash of the archive
+	Size   int64  `json:"size"`   // Size of the archive in bytes
+}
+
+// CanonicalJSON returns the canonical JSON of the signature for signing:
+// the archive hash and size with the signer's details, without the
+// signature value
+func (s *ArchiveSignature) CanonicalJSON() ([]byte, error) {
+	temp := *s
+	temp.Signature.Signature = ""
+	return json.Marshal(temp)
+}
+
+// ArchiveSignaturePath returns the path of the signature sidecar of the
+// archive at archivePath, e.g. "results.ptd.sig" for "results.ptd"
+func ArchiveSignaturePath(archivePath string) string {
+	return archivePath + ".sig"
+}
+
+// SignArchiveFile signs the archive at archivePath with signer, writing the
+// signature to its sidecar and returning it. The archive is not modified;
+// any later change to it, such as by Package.Save or RepairPackage, fails
+// verification until it is signed again.
+func SignArchiveFile(archivePath string, signer *Signer) (*ArchiveSignature, error) {
+	hash, size, err := hashArchiveFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &ArchiveSignature{
+		Signature: Signature{
+			Algorithm:   "ed25519",
+			PublicKeyID: signer.publicKeyID,
+			SignedAt:    time.Now(),
+			SignedBy:    signer.signedBy,
+		},
+		SHA256: hash,
+		Size:   size,
+	}
+	canonical, err := sig.CanonicalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get canonical JSON: %w", err)
+	}
+	sig.Signature.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(signer.privateKey, canonical))
+
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signature: %w", err)
+	}
+	if err := os.WriteFile(ArchiveSignaturePath(archivePath), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write signature: %w", err)
+	}
+	return sig, nil
+}
+
+// VerifyArchiveFile verifies the archive at archivePath against the
+// signature in its sidecar, returning the signature. It returns
+// ErrSignatureMissing if there is no sidecar, ErrSignatureInvalid if the
+// sidecar cannot be read, and ErrSignatureFailed if the archive changed
+// since it was signed or the signature is not publicKey's.
+func VerifyArchiveFile(archivePath string, publicKey ed25519.PublicKey) (*ArchiveSignature, error) {
+	data, err := os.ReadFile(ArchiveSignaturePath(archivePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no signature for %s", ErrSignatureMissing, archivePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	var sig ArchiveSignature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+	}
+	if sig.Algorithm != "ed25519" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrSignatureInvalid, sig.Algorithm)
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature.Signature)
+	if err != nil {
+		return nil, ErrSignatureInvalid
+	}
+
+	canonical, err := sig.CanonicalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get canonical JSON: %w", err)
+	}
+	if !ed25519.Verify(publicKey, canonical, signature) {
+		return nil, ErrSignatureFailed
+	}
+
+	hash, size, err := hashArchiveFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	if size != sig.Size || hash != sig.SHA256 {
+		return nil, fmt.Errorf("%w: archive changed since it was signed", ErrSignatureFailed)
+	}
+	return &sig, nil
+}
+
+// hashArchiveFile returns the hex-encoded SHA-256 hash and the size of a file
+func hashArchiveFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read archive: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
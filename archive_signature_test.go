@@ -0,0 +1,102 @@
+package ptd
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSignArchiveFile(t *testing.T) {
+	signer, err := NewSigner("archive-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivePath, _ := newEntitiesTestArchive(t, 2)
+	before, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyArchiveFile(archivePath, signer.publicKey); !errors.Is(err, ErrSignatureMissing) {
+		t.Fatalf("VerifyArchiveFile() of an unsigned archive error = %v, want ErrSignatureMissing", err)
+	}
+
+	signed, err := SignArchiveFile(archivePath, signer)
+	if err != nil {
+		t.Fatalf("SignArchiveFile() error = %v", err)
+	}
+	if after, _ := os.ReadFile(archivePath); string(after) != string(before) {
+		t.Error("SignArchiveFile() modified the archive")
+	}
+	if signed.Size != int64(len(before)) {
+		t.Errorf("Size = %d, want %d", signed.Size, len(before))
+	}
+
+	verified, err := VerifyArchiveFile(archivePath, signer.publicKey)
+	if err != nil {
+		t.Fatalf("VerifyArchiveFile() error = %v", err)
+	}
+	if verified.PublicKeyID != "archive-key" || verified.SignedBy != "test" || verified.SHA256 != signed.SHA256 {
+		t.Errorf("VerifyArchiveFile() = %+v, want %+v", verified, signed)
+	}
+
+	// Another key does not verify
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if _, err := VerifyArchiveFile(archivePath, otherKey); !errors.Is(err, ErrSignatureFailed) {
+		t.Errorf("VerifyArchiveFile() with another key error = %v, want ErrSignatureFailed", err)
+	}
+
+	// The signature stays valid for a copy with its sidecar
+	copyPath := filepath.Join(t.TempDir(), "copy.ptd")
+	sidecar, _ := os.ReadFile(ArchiveSignaturePath(archivePath))
+	os.WriteFile(copyPath, before, 0644)
+	os.WriteFile(ArchiveSignaturePath(copyPath), sidecar, 0644)
+	if _, err := VerifyArchiveFile(copyPath, signer.publicKey); err != nil {
+		t.Errorf("VerifyArchiveFile() of a copy error = %v", err)
+	}
+}
+
+func TestVerifyArchiveFile_Tampered(t *testing.T) {
+	signer, err := NewSigner("archive-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archivePath, _ := newEntitiesTestArchive(t, 2)
+	if _, err := SignArchiveFile(archivePath, signer); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rewritten archive no longer matches its signature
+	pkg, err := OpenPackageForAppend(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pkg.Cleanup()
+	pkg.Manifest.Description = "changed"
+	if err := pkg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchiveFile(archivePath, signer.publicKey); !errors.Is(err, ErrSignatureFailed) {
+		t.Errorf("VerifyArchiveFile() of a changed archive error = %v, want ErrSignatureFailed", err)
+	}
+
+	// Signing again covers the new archive
+	if _, err := SignArchiveFile(archivePath, signer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyArchiveFile(archivePath, signer.publicKey); err != nil {
+		t.Errorf("VerifyArchiveFile() after re-signing error = %v", err)
+	}
+
+	// Editing the sidecar invalidates its signature
+	os.WriteFile(ArchiveSignaturePath(archivePath), []byte(`{"algorithm": "ed25519", "signature": "!!"}`), 0644)
+	if _, err := VerifyArchiveFile(archivePath, signer.publicKey); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("VerifyArchiveFile() of a corrupt sidecar error = %v, want ErrSignatureInvalid", err)
+	}
+	os.WriteFile(ArchiveSignaturePath(archivePath), []byte(`not json`), 0644)
+	if _, err := VerifyArchiveFile(archivePath, signer.publicKey); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("VerifyArchiveFile() of a corrupt sidecar error = %v, want ErrSignatureInvalid", err)
+	}
+}
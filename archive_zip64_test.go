@@ -0,0 +1,100 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestOpenPackage_ZIP64 opens an archive with more files than a ZIP
+// without ZIP64 records can hold
+func TestOpenPackage_ZIP64(t *testing.T) {
+	const files = 1 << 16
+	manifest := &Manifest{Version: "1.0.0", Files: make(map[string]*FileEntry, files)}
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for i := 0; i < files; i++ {
+		name := fmt.Sprintf("media/%05d.txt", i)
+		data := []byte(name)
+		manifest.Files[name] = newFileEntry(name, data, time.Time{})
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(data)
+	}
+	writer, _ := zipWriter.Create("manifest.json")
+	if err := json.NewEncoder(writer).Encode(manifest); err != nil {
+		t.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("PK\x06\x06")) {
+		t.Fatal("Archive has no ZIP64 end of central directory record")
+	}
+
+	pkg, err := OpenPackageFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenPackageFrom() error = %v", err)
+	}
+	if got := len(pkg.Manifest.Files); got != files {
+		t.Errorf("Manifest has %d files, want %d", got, files)
+	}
+	rc, err := pkg.openPackageFile("media/65535.txt")
+	if err != nil {
+		t.Fatalf("openPackageFile() error = %v", err)
+	}
+	rc.Close()
+}
+
+// TestPackage_LargeArchive round-trips a package with a file beyond the
+// 4 GiB limit of ZIP without ZIP64, checking that memory stays bounded.
+// It writes several gigabytes, so it only runs with PTD_LARGE_TESTS set.
+func TestPackage_LargeArchive(t *testing.T) {
+	if os.Getenv("PTD_LARGE_TESTS") == "" {
+		t.Skip("set PTD_LARGE_TESTS=1 to run multi-gigabyte archive tests")
+	}
+	const size = 4<<30 + 1<<20
+
+	pkg := NewPackage("Large archive test")
+	defer pkg.Cleanup()
+	largePath := filepath.Join(pkg.tempDir, "media", "large.bin")
+	if err := os.MkdirAll(filepath.Dir(largePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A sparse file of zeros: large to read, small on disk and compressed
+	large, err := os.Create(largePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := large.Truncate(size); err != nil {
+		t.Fatal(err)
+	}
+	large.Close()
+
+	archivePath := filepath.Join(t.TempDir(), "large.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if got := opened.Manifest.Files["media/large.bin"].Size; got != size {
+		t.Errorf("Manifest size = %d, want %d", got, size)
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	if stats.Sys > 512<<20 {
+		t.Errorf("Memory obtained from the system = %d MiB, want at most 512 MiB", stats.Sys>>20)
+	}
+}
@@ -0,0 +1,120 @@
+package ptd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// attachmentsDir is the area of a package that holds attachments
+const attachmentsDir = "attachments"
+
+// Attachment describes a media file carried by a package, such as a draw
+// PDF, a venue map or a player photo
+type Attachment struct {
+	Name        string // Path within the attachments area, e.g. "draws/main.pdf"
+	ContentType string // MIME type
+	Size        int64  // Size in bytes
+	Hash        string // Hex-encoded SHA-256 of the contents
+}
+
+// AddAttachment adds the contents of r to the package as the attachment
+// name, a slash-separated path within the attachments area, replacing any
+// attachment of the same name. An empty contentType is derived from the
+// name's extension. Attachments are listed in the manifest and their
+// hashes verified like entity files. Returns ErrInvalidPackage if the
+// package has no working directory, such as one loaded with OpenPackage.
+func (p *Package) AddAttachment(name string, r io.Reader, contentType string) error {
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+	relPath, err := attachmentPath(name)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	filePath := filepath.Join(p.tempDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hasher), r)
+	if err != nil {
+		return fmt.Errorf("failed to write attachment %s: %w", name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write attachment %s: %w", name, err)
+	}
+
+	p.Manifest.Files[relPath] = &FileEntry{
+		Path:     relPath,
+		Size:     size,
+		Hash:     hex.EncodeToString(hasher.Sum(nil)),
+		Modified: time.Now(),
+		Type:     contentType,
+	}
+	return nil
+}
+
+// Attachments lists the attachments of the package, ordered by name
+func (p *Package) Attachments() []Attachment {
+	var attachments []Attachment
+	for relPath, entry := range p.Manifest.Files {
+		name, ok := strings.CutPrefix(filepath.ToSlash(relPath), attachmentsDir+"/")
+		if !ok {
+			continue
+		}
+		attachments = append(attachments, Attachment{
+			Name:        name,
+			ContentType: entry.Type,
+			Size:        entry.Size,
+			Hash:        entry.Hash,
+		})
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].Name < attachments[j].Name
+	})
+	return attachments
+}
+
+// OpenAttachment opens an attachment for reading, from the working
+// directory or, for an opened package, straight from the archive. Returns
+// an error wrapping os.ErrNotExist if the package has no such attachment.
+func (p *Package) OpenAttachment(name string) (io.ReadCloser, error) {
+	relPath, err := attachmentPath(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.Manifest.Files[relPath]; !ok {
+		return nil, fmt.Errorf("attachment %s: %w", name, os.ErrNotExist)
+	}
+	return p.openPackageFile(relPath)
+}
+
+// attachmentPath returns the package-relative path of an attachment
+func attachmentPath(name string) (string, error) {
+	if name == "" || !filepath.IsLocal(filepath.FromSlash(name)) || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("%w: invalid attachment name %q", ErrValidation, name)
+	}
+	return filepath.Join(attachmentsDir, filepath.FromSlash(path.Clean(name))), nil
+}
@@ -0,0 +1,126 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPackage_Attachments(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	draw := []byte("%PDF-1.4 main draw")
+	if err := pkg.AddAttachment("draws/main.pdf", bytes.NewReader(draw), ""); err != nil {
+		t.Fatalf("AddAttachment() error = %v", err)
+	}
+	if err := pkg.AddAttachment("photos/player-1", strings.NewReader("JPEG"), "image/jpeg"); err != nil {
+		t.Fatalf("AddAttachment() error = %v", err)
+	}
+
+	// Readable from the working directory
+	rc, err := pkg.OpenAttachment("draws/main.pdf")
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(data, draw) {
+		t.Errorf("OpenAttachment() read %q, want %q", data, draw)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "attachments.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+
+	want := []Attachment{
+		{Name: "draws/main.pdf", ContentType: "application/pdf", Size: int64(len(draw)), Hash: pkg.Manifest.Files[filepath.Join("attachments", "draws", "main.pdf")].Hash},
+		{Name: "photos/player-1", ContentType: "image/jpeg", Size: 4, Hash: pkg.Manifest.Files[filepath.Join("attachments", "photos", "player-1")].Hash},
+	}
+	if got := opened.Attachments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Attachments() = %+v, want %+v", got, want)
+	}
+
+	rc, err = opened.OpenAttachment("photos/player-1")
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	data, _ = io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "JPEG" {
+		t.Errorf("OpenAttachment() read %q, want JPEG", data)
+	}
+
+	if _, err := opened.OpenAttachment("photos/missing.jpg"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenAttachment() of a missing attachment error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestPackage_Attachments_Verified(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	if err := pkg.AddAttachment("venue.png", strings.NewReader("venue map"), ""); err != nil {
+		t.Fatal(err)
+	}
+	var archive bytes.Buffer
+	if err := pkg.CreateArchiveTo(&archive); err != nil {
+		t.Fatal(err)
+	}
+	tampered := rewriteZip(t, archive.Bytes(), func(name string, data []byte) []byte {
+		if name == "attachments/venue.png" {
+			return []byte("other map")
+		}
+		return data
+	})
+
+	if _, err := PackageFromReader(bytes.NewReader(tampered)); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("PackageFromReader() error = %v, want ErrHashMismatch", err)
+	}
+
+	deferred, err := PackageFromReaderWithOptions(bytes.NewReader(tampered), OpenOptions{Verification: VerifyDeferred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := deferred.OpenAttachment("venue.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("Reading a tampered attachment error = %v, want ErrHashMismatch", err)
+	}
+}
+
+func TestPackage_AddAttachment_InvalidName(t *testing.T) {
+	pkg := NewPackage("Attachments")
+	defer pkg.Cleanup()
+
+	for _, name := range []string{"", "../escape.pdf", "/abs.pdf", `dir\file.pdf`} {
+		if err := pkg.AddAttachment(name, strings.NewReader("x"), ""); !errors.Is(err, ErrValidation) {
+			t.Errorf("AddAttachment(%q) error = %v, want ErrValidation", name, err)
+		}
+	}
+	if len(pkg.Manifest.Files) != 0 {
+		t.Errorf("Manifest lists %d files, want none", len(pkg.Manifest.Files))
+	}
+}
+
+func TestPackage_AddAttachment_Opened(t *testing.T) {
+	pkg, _, dir := openEditTestArchive(t, 1)
+
+	err := pkg.AddAttachment("photos/team.jpg", strings.NewReader("jpeg"), "")
+	if !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("AddAttachment() on an opened package error = %v, want ErrInvalidPackage", err)
+	}
+	if len(pkg.Attachments()) != 0 {
+		t.Errorf("Attachments() = %v, want none", pkg.Attachments())
+	}
+	assertEmptyDir(t, dir)
+}
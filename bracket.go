@@ -0,0 +1,332 @@
+package ptd
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AutoAdvance places the winner of a completed match into the next match
+// of its bracket, the one named by WinnerGoesToMatchID. The winner is the
+// match's Winner or, if unset, the side that won more sets in Score.
+//
+// The position in the target match follows the order of its feeder matches
+// in allMatches: the first match whose winner goes to the target fills
+// HomeEntry, the second AwayEntry. m is located in allMatches by identity
+// if it points into the slice, otherwise by event, bracket and match
+// number. An existing entry in the position is replaced, so advancing again
+// after a score correction moves the new winner on.
+//
+// The target is updated in place in allMatches and returned. If bracket is
+// non-nil the target must belong to the bracket's event.
+func (m *Match) AutoAdvance(bracket *Bracket, allMatches []Envelope[Match]) (*Envelope[Match], error) {
+	if m.Status != "completed" {
+		return nil, newValidationError(ErrValidation, TypeMatch, "match.status", "cannot advance winner of %s match", m.Status)
+	}
+	if m.WinnerGoesToMatchID == "" {
+		return nil, newValidationError(ErrMissingField, TypeMatch, "match.winner_goes_to_match_id", "match has no next match")
+	}
+
+	winner, err := m.winnerRef()
+	if err != nil {
+		return nil, err
+	}
+
+	target := -1
+	for i := range allMatches {
+		if allMatches[i].ID == m.WinnerGoesToMatchID {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("%w: next match %s not found", ErrInvalidID, m.WinnerGoesToMatchID)
+	}
+	next := &allMatches[target]
+	if bracket != nil && next.Spec.EventID != bracket.EventID {
+		return nil, newValidationError(ErrValidation, TypeMatch, "match.event_id", "next match %s is not in event %s", next.ID, bracket.EventID)
+	}
+
+	position, found := 0, false
+	for i := range allMatches {
+		feeder := &allMatches[i].Spec
+		if feeder.WinnerGoesToMatchID != m.WinnerGoesToMatchID {
+			continue
+		}
+		if feeder == m || (feeder.EventID == m.EventID && feeder.BracketID == m.BracketID && feeder.MatchNumber == m.MatchNumber) {
+			found = true
+			break
+		}
+		position++
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: match %s not found in bracket matches", ErrInvalidID, m.MatchNumber)
+	}
+
+	switch position {
+	case 0:
+		next.Spec.HomeEntry = winner
+	case 1:
+		next.Spec.AwayEntry = winner
+	default:
+		return nil, newValidationError(ErrValidation, TypeMatch, "match.winner_goes_to_match_id", "next match %s already has two feeder matches", next.ID)
+	}
+
+	return next, nil
+}
+
+// winnerRef returns a copy of the entry reference of the match winner
+func (m *Match) winnerRef() (*EntryRef, error) {
+	var ref *EntryRef
+
+	switch {
+	case m.Winner != "":
+		switch {
+		case m.HomeEntry != nil && m.Winner == m.HomeEntry.EntryID:
+			ref = m.HomeEntry
+		case m.AwayEntry != nil && m.Winner == m.AwayEntry.EntryID:
+			ref = m.AwayEntry
+		default:
+			return nil, newValidationError(ErrValidation, TypeMatch, "match.winner", "winner %s is not an entry of this match", m.Winner)
+		}
+	case m.Score != nil:
+		home, away := m.Score.SetWins()
+		switch {
+		case home > away:
+			ref = m.HomeEntry
+		case away > home:
+			ref = m.AwayEntry
+		}
+	}
+
+	if ref == nil {
+		return nil, newValidationError(ErrMissingField, TypeMatch, "match.winner", "match has no winner")
+	}
+
+	winner := *ref
+	return &winner, nil
+}
+
+// Bracket integrity rules reported by VerifyBracketIntegrity
+const (
+	RuleSeed1TopHalf    = "seed_1_top_half"
+	RuleSeed2BottomHalf = "seed_2_bottom_half"
+	RuleSeeds34Halves   = "seeds_3_4_alternate_halves"
+	RuleByePosition     = "bye_position"
+)
+
+// IntegrityViolation describes a first-round match whose placement breaks a
+// draw rule. ExpectedHomeSeed is the seed the conventional draw places at
+// the match's home position and ActualHomeSeed the seed found there; 0
+// means unseeded or a BYE.
+type IntegrityViolation struct {
+	MatchID          string `json:"match_id"`
+	ExpectedHomeSeed int    `json:"expected_home_seed"`
+	ActualHomeSeed   int    `json:"actual_home_seed"`
+	Rule             string `json:"rule"`
+}
+
+// VerifyBracketIntegrity checks the first-round placement of a
+// single-elimination bracket: seed 1 must be in the top half, seed 2 in
+// the bottom half, seeds 3 and 4 in different halves, and BYEs must face
+// the top seeds, one per missing entry.
+//
+// The first round is the round (by RoundID) with the most matches of the
+// bracket's event. Draw positions follow the order of its matches in
+// matches, home before away, so match i holds positions 2i and 2i+1. An
+// empty HomeEntry or AwayEntry is a BYE. Seeds are taken from the entries,
+// falling back to the seed on the match's entry reference.
+func VerifyBracketIntegrity(bracket *Bracket, entries []Envelope[Entry], matches []Envelope[Match]) []IntegrityViolation {
+	inBracket := func(eventID string) bool {
+		return bracket == nil || bracket.EventID == "" || eventID == bracket.EventID
+	}
+
+	var drawEntries []Envelope[Entry]
+	for _, entry := range entries {
+		if inBracket(entry.Spec.EventID) {
+			drawEntries = append(drawEntries, entry)
+		}
+	}
+
+	rounds := make(map[string][]Envelope[Match])
+	var first string
+	for _, match := range matches {
+		if !inBracket(match.Spec.EventID) {
+			continue
+		}
+		roundID := match.Spec.RoundID
+		rounds[roundID] = append(rounds[roundID], match)
+		if len(rounds[roundID]) > len(rounds[first]) {
+			first = roundID
+		}
+	}
+	firstRound := rounds[first]
+	if len(firstRound) == 0 {
+		return nil
+	}
+
+	seeds := entrySeeds(drawEntries)
+	drawSize := 2 * len(firstRound)
+	byes := max(drawSize-len(drawEntries), 0)
+
+	// Conventional seed at each draw position, limited to the seeds in use
+	expected := standardSeedOrder(drawSize)
+	for i, seed := range expected {
+		if seed > len(seeds) {
+			expected[i] = 0
+		}
+	}
+
+	var violations []IntegrityViolation
+	violate := func(i int, rule string) {
+		violations = append(violations, IntegrityViolation{
+			MatchID:          firstRound[i].ID,
+			ExpectedHomeSeed: expected[2*i],
+			ActualHomeSeed:   refSeed(firstRound[i].Spec.HomeEntry, seeds),
+			Rule:             rule,
+		})
+	}
+
+	hasBye := func(seed int) bool { return seed > 0 && seed <= byes }
+	positions := make(map[int]int) // seed -> draw position
+	for i, match := range firstRound {
+		home, away := match.Spec.HomeEntry, match.Spec.AwayEntry
+		homeSeed, awaySeed := refSeed(home, seeds), refSeed(away, seeds)
+		if homeSeed > 0 {
+			positions[homeSeed] = 2 * i
+		}
+		if awaySeed > 0 {
+			positions[awaySeed] = 2*i + 1
+		}
+
+		switch {
+		case home == nil && away == nil:
+			violate(i, RuleByePosition)
+		case home == nil:
+			if !hasBye(awaySeed) {
+				violate(i, RuleByePosition)
+			}
+		case away == nil:
+			if !hasBye(homeSeed) {
+				violate(i, RuleByePosition)
+			}
+		case hasBye(homeSeed) || hasBye(awaySeed):
+			violate(i, RuleByePosition)
+		}
+	}
+
+	topHalf := func(position int) bool { return position < drawSize/2 }
+	if p, ok := positions[1]; ok && !topHalf(p) {
+		violate(p/2, RuleSeed1TopHalf)
+	}
+	if p, ok := positions[2]; ok && topHalf(p) {
+		violate(p/2, RuleSeed2BottomHalf)
+	}
+	p3, ok3 := positions[3]
+	p4, ok4 := positions[4]
+	if ok3 && ok4 && topHalf(p3) == topHalf(p4) {
+		violate(p4/2, RuleSeeds34Halves)
+	}
+
+	return violations
+}
+
+// standardSeedOrder returns the seed at each position of a conventional
+// single-elimination draw of the given size (a power of two), where every
+// first-round match pairs seeds that add up to size+1
+func standardSeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// Position returns the match's draw position, or 0 if unset
+func (m *Match) Position() int {
+	if m == nil {
+		return 0
+	}
+	return m.DrawPosition
+}
+
+// AssignDrawPositions returns a copy of the matches with DrawPosition set to
+// each match's slot within its round, numbered from 1 at the top of the
+// bracket diagram. Rounds are ordered by RoundNumber within their event and
+// matches belong to a round through RoundID; matches of other rounds are
+// returned unchanged.
+//
+// The bracket tree is followed from the last round back: the last round's
+// matches take their order in matches, and the feeders of the match at
+// position p (matches whose WinnerGoesToMatchID names it, in order) take
+// positions 2p-1 and 2p in the round before. Matches not linked into the
+// tree fill the remaining positions of their round in order.
+func AssignDrawPositions(matches []Envelope[Match], rounds []Envelope[Round]) []Envelope[Match] {
+	assigned := make([]Envelope[Match], len(matches))
+	copy(assigned, matches)
+
+	byEvent := make(map[string][]Envelope[Round])
+	var eventIDs []string
+	for _, round := range rounds {
+		if _, ok := byEvent[round.Spec.EventID]; !ok {
+			eventIDs = append(eventIDs, round.Spec.EventID)
+		}
+		byEvent[round.Spec.EventID] = append(byEvent[round.Spec.EventID], round)
+	}
+
+	byRound := make(map[string][]int) // round ID -> indexes into assigned
+	for i, match := range assigned {
+		byRound[match.Spec.RoundID] = append(byRound[match.Spec.RoundID], i)
+	}
+	positionOf := make(map[string]int) // match ID -> position
+
+	for _, eventID := range eventIDs {
+		eventRounds := byEvent[eventID]
+		sort.SliceStable(eventRounds, func(i, j int) bool {
+			return eventRounds[i].Spec.RoundNumber < eventRounds[j].Spec.RoundNumber
+		})
+
+		for r := len(eventRounds) - 1; r >= 0; r-- {
+			indexes := byRound[eventRounds[r].ID]
+			taken := make(map[int]bool)
+
+			// Feeders of each next-round match, in order
+			feederCount := make(map[string]int)
+			for _, i := range indexes {
+				match := &assigned[i]
+				next, ok := positionOf[match.Spec.WinnerGoesToMatchID]
+				if !ok {
+					match.Spec.DrawPosition = 0
+					continue
+				}
+				feeder := feederCount[match.Spec.WinnerGoesToMatchID]
+				feederCount[match.Spec.WinnerGoesToMatchID]++
+				position := 2*next - 1 + feeder
+				if feeder > 1 || taken[position] {
+					match.Spec.DrawPosition = 0
+					continue
+				}
+				match.Spec.DrawPosition = position
+				taken[position] = true
+			}
+
+			next := 1
+			for _, i := range indexes {
+				match := &assigned[i]
+				if match.Spec.DrawPosition == 0 {
+					for taken[next] {
+						next++
+					}
+					match.Spec.DrawPosition = next
+					taken[next] = true
+				}
+				positionOf[match.ID] = match.Spec.DrawPosition
+			}
+		}
+	}
+
+	return assigned
+}
@@ -0,0 +1,247 @@
+package ptd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func newBracketTestMatches() []Envelope[Match] {
+	match := func(id, number, next string, home, away string) Envelope[Match] {
+		m := Match{EventID: "ptd:event:1", BracketID: "ptd:bracket:1", MatchNumber: number, Status: "scheduled", WinnerGoesToMatchID: next}
+		if home != "" {
+			m.HomeEntry = &EntryRef{EntryID: home, DisplayName: home}
+		}
+		if away != "" {
+			m.AwayEntry = &EntryRef{EntryID: away, DisplayName: away}
+		}
+		return Envelope[Match]{ID: id, Type: TypeMatch, Spec: m}
+	}
+	return []Envelope[Match]{
+		match("ptd:match:sf1", "SF1", "ptd:match:f", "e1", "e4"),
+		match("ptd:match:sf2", "SF2", "ptd:match:f", "e2", "e3"),
+		match("ptd:match:f", "F", "", "", ""),
+	}
+}
+
+func TestMatch_AutoAdvance(t *testing.T) {
+	matches := newBracketTestMatches()
+	bracket := &Bracket{EventID: "ptd:event:1", Name: "Main Draw", Format: "single_elimination", Size: 4}
+
+	sf2 := &matches[1].Spec
+	sf2.Status = "completed"
+	sf2.Score = newTestScore("1-3", [2]int{11, 9}, [2]int{5, 11}, [2]int{7, 11}, [2]int{9, 11})
+
+	final, err := sf2.AutoAdvance(bracket, matches)
+	if err != nil {
+		t.Fatalf("AutoAdvance() error = %v", err)
+	}
+	if final.ID != "ptd:match:f" {
+		t.Fatalf("AutoAdvance() returned %s, want ptd:match:f", final.ID)
+	}
+	if final.Spec.HomeEntry != nil {
+		t.Errorf("HomeEntry = %v, want nil", final.Spec.HomeEntry)
+	}
+	if final.Spec.AwayEntry == nil || final.Spec.AwayEntry.EntryID != "e3" {
+		t.Errorf("AwayEntry = %v, want e3", final.Spec.AwayEntry)
+	}
+
+	// A copy of the match resolves to its feeder position by match number
+	sf1 := matches[0].Spec
+	sf1.Status = "completed"
+	sf1.Winner = "e1"
+	if _, err := sf1.AutoAdvance(bracket, matches); err != nil {
+		t.Fatalf("AutoAdvance() error = %v", err)
+	}
+	if got := matches[2].Spec.HomeEntry; got == nil || got.EntryID != "e1" {
+		t.Errorf("HomeEntry = %v, want e1", got)
+	}
+	if matches[2].Spec.HomeEntry == sf1.HomeEntry {
+		t.Error("HomeEntry shares the feeder match's entry reference")
+	}
+}
+
+func TestMatch_AutoAdvance_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(m *Match)
+		bracket *Bracket
+		want    error
+	}{
+		{"not completed", func(m *Match) { m.Status = "in_progress" }, nil, ErrValidation},
+		{"no winner", func(m *Match) {}, nil, ErrMissingField},
+		{"winner not an entry", func(m *Match) { m.Winner = "e9" }, nil, ErrValidation},
+		{"no next match", func(m *Match) { m.Winner = "e1"; m.WinnerGoesToMatchID = "" }, nil, ErrMissingField},
+		{"unknown next match", func(m *Match) { m.Winner = "e1"; m.WinnerGoesToMatchID = "ptd:match:x" }, nil, ErrInvalidID},
+		{"other event", func(m *Match) { m.Winner = "e1" }, &Bracket{EventID: "ptd:event:2"}, ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := newBracketTestMatches()
+			m := &matches[0].Spec
+			m.Status = "completed"
+			tt.modify(m)
+
+			_, err := m.AutoAdvance(tt.bracket, matches)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("AutoAdvance() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// newIntegrityTestDraw builds a first round from the seeds at each draw
+// position; -1 is a BYE and 0 an unseeded entry
+func newIntegrityTestDraw(positions ...int) ([]Envelope[Entry], []Envelope[Match]) {
+	var entries []Envelope[Entry]
+	ref := func(i, seed int) *EntryRef {
+		if seed < 0 {
+			return nil
+		}
+		id := fmt.Sprintf("ptd:entry:%d", i)
+		entry := Envelope[Entry]{ID: id, Type: TypeEntry, Spec: Entry{EventID: "ptd:event:1"}}
+		if seed > 0 {
+			entry.Spec.Seed = intPtr(seed)
+		}
+		entries = append(entries, entry)
+		return &EntryRef{EntryID: id, DisplayName: id}
+	}
+
+	var matches []Envelope[Match]
+	for i := 0; i < len(positions); i += 2 {
+		matches = append(matches, Envelope[Match]{
+			ID:   fmt.Sprintf("ptd:match:r1-%d", i/2+1),
+			Type: TypeMatch,
+			Spec: Match{
+				EventID:   "ptd:event:1",
+				RoundID:   "ptd:round:1",
+				HomeEntry: ref(i, positions[i]),
+				AwayEntry: ref(i+1, positions[i+1]),
+			},
+		})
+	}
+	return entries, matches
+}
+
+func TestStandardSeedOrder(t *testing.T) {
+	got := standardSeedOrder(8)
+	want := []int{1, 8, 4, 5, 2, 7, 3, 6}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("standardSeedOrder(8) = %v, want %v", got, want)
+	}
+}
+
+func TestVerifyBracketIntegrity(t *testing.T) {
+	bracket := &Bracket{EventID: "ptd:event:1", Name: "Main Draw", Format: "single_elimination", Size: 8}
+
+	tests := []struct {
+		name      string
+		positions []int
+		want      []IntegrityViolation
+	}{
+		{
+			name:      "valid draw",
+			positions: []int{1, 0, 4, 0, 3, 0, 0, 2},
+		},
+		{
+			name:      "valid draw with byes",
+			positions: []int{1, -1, 4, 0, 3, 0, -1, 2},
+		},
+		{
+			name:      "seeds 1 and 2 swapped",
+			positions: []int{2, 0, 4, 0, 3, 0, 0, 1},
+			want: []IntegrityViolation{
+				{MatchID: "ptd:match:r1-4", ExpectedHomeSeed: 3, ActualHomeSeed: 0, Rule: RuleSeed1TopHalf},
+				{MatchID: "ptd:match:r1-1", ExpectedHomeSeed: 1, ActualHomeSeed: 2, Rule: RuleSeed2BottomHalf},
+			},
+		},
+		{
+			name:      "seeds 3 and 4 in the same half",
+			positions: []int{1, 0, 4, 3, 0, 0, 0, 2},
+			want: []IntegrityViolation{
+				{MatchID: "ptd:match:r1-2", ExpectedHomeSeed: 4, ActualHomeSeed: 4, Rule: RuleSeeds34Halves},
+			},
+		},
+		{
+			name:      "bye given to unseeded entry",
+			positions: []int{1, 0, 4, 0, 3, -1, 0, 2},
+			want: []IntegrityViolation{
+				{MatchID: "ptd:match:r1-1", ExpectedHomeSeed: 1, ActualHomeSeed: 1, Rule: RuleByePosition},
+				{MatchID: "ptd:match:r1-3", ExpectedHomeSeed: 2, ActualHomeSeed: 3, Rule: RuleByePosition},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, matches := newIntegrityTestDraw(tt.positions...)
+			got := VerifyBracketIntegrity(bracket, entries, matches)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VerifyBracketIntegrity() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyBracketIntegrity_OtherEvent(t *testing.T) {
+	entries, matches := newIntegrityTestDraw(2, 0, 0, 1)
+	if got := VerifyBracketIntegrity(&Bracket{EventID: "ptd:event:2"}, entries, matches); got != nil {
+		t.Errorf("VerifyBracketIntegrity() = %+v, want nil", got)
+	}
+}
+
+func TestAssignDrawPositions(t *testing.T) {
+	rounds := []Envelope[Round]{
+		{ID: "ptd:round:f", Spec: Round{EventID: "ptd:event:1", Name: "Final", RoundNumber: 3}},
+		{ID: "ptd:round:qf", Spec: Round{EventID: "ptd:event:1", Name: "Quarterfinals", RoundNumber: 1}},
+		{ID: "ptd:round:sf", Spec: Round{EventID: "ptd:event:1", Name: "Semifinals", RoundNumber: 2}},
+	}
+	match := func(id, round, next string) Envelope[Match] {
+		return Envelope[Match]{ID: id, Type: TypeMatch, Spec: Match{EventID: "ptd:event:1", RoundID: round, WinnerGoesToMatchID: next}}
+	}
+	// Listed out of bracket order: the tree decides the positions
+	matches := []Envelope[Match]{
+		match("qf4", "ptd:round:qf", "sf2"),
+		match("f", "ptd:round:f", ""),
+		match("qf2", "ptd:round:qf", "sf1"),
+		match("sf2", "ptd:round:sf", "f"),
+		match("qf1", "ptd:round:qf", "sf1"),
+		match("sf1", "ptd:round:sf", "f"),
+		match("qf3", "ptd:round:qf", "sf2"),
+		match("other", "ptd:round:unknown", ""),
+	}
+	matches[7].Spec.DrawPosition = 9
+
+	assigned := AssignDrawPositions(matches, rounds)
+
+	want := map[string]int{"f": 1, "sf1": 2, "sf2": 1, "qf1": 4, "qf2": 3, "qf3": 2, "qf4": 1, "other": 9}
+	for _, m := range assigned {
+		if got := m.Spec.Position(); got != want[m.ID] {
+			t.Errorf("%s position = %d, want %d", m.ID, got, want[m.ID])
+		}
+	}
+	if matches[0].Spec.DrawPosition != 0 {
+		t.Error("AssignDrawPositions() modified its input")
+	}
+}
+
+func TestAssignDrawPositions_Unlinked(t *testing.T) {
+	rounds := []Envelope[Round]{{ID: "ptd:round:1", Spec: Round{EventID: "ptd:event:1", RoundNumber: 1}}}
+	var matches []Envelope[Match]
+	for i := 1; i <= 4; i++ {
+		matches = append(matches, Envelope[Match]{ID: fmt.Sprintf("m%d", i), Spec: Match{RoundID: "ptd:round:1"}})
+	}
+
+	for i, m := range AssignDrawPositions(matches, rounds) {
+		if m.Spec.DrawPosition != i+1 {
+			t.Errorf("%s position = %d, want %d", m.ID, m.Spec.DrawPosition, i+1)
+		}
+	}
+
+	var nilMatch *Match
+	if nilMatch.Position() != 0 {
+		t.Error("Position() of nil match should be 0")
+	}
+}
@@ -0,0 +1,189 @@
+package ptd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CalendarEvent is a calendar entry derived from a tournament or event
+type CalendarEvent struct {
+	Title       string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+	EntryURL    string
+	UID         string // Stable identifier so re-exports update existing entries
+}
+
+// CalendarEvents returns one calendar entry per day of the tournament, e.g.
+// for blocking courts on a venue's calendar. Each day runs from the
+// tournament's start time of day to its end time of day. If venue is nil,
+// the tournament's own venue is used for the location.
+func (t *Tournament) CalendarEvents(venue *Venue) []CalendarEvent {
+	if t.StartDate.IsZero() || t.EndDate.IsZero() || t.EndDate.Before(t.StartDate) {
+		return nil
+	}
+	if venue == nil {
+		venue = t.Venue
+	}
+
+	loc := t.StartDate.Location()
+	end := t.EndDate.In(loc)
+	firstDay := truncateToDay(t.StartDate)
+	lastDay := truncateToDay(end)
+	days := int(lastDay.Sub(firstDay).Hours()/24+0.5) + 1
+
+	events := make([]CalendarEvent, 0, days)
+	for i := 0; i < days; i++ {
+		day := firstDay.AddDate(0, 0, i)
+		start := atClock(day, t.StartDate)
+		finish := atClock(day, end)
+		if !finish.After(start) {
+			finish = day.AddDate(0, 0, 1)
+		}
+
+		title := t.Name.Default
+		if days > 1 {
+			title = fmt.Sprintf("%s (Day %d)", t.Name.Default, i+1)
+		}
+
+		events = append(events, CalendarEvent{
+			Title:       title,
+			Location:    venueLocation(venue),
+			Description: t.Description,
+			Start:       start,
+			End:         finish,
+			EntryURL:    t.Website,
+			UID:         calendarUID("tournament", t.Name.Default, start.Format("2006-01-02")),
+		})
+	}
+
+	return events
+}
+
+// CalendarEvent returns a single calendar entry spanning the event
+func (e *Event) CalendarEvent() CalendarEvent {
+	title := e.Name.Default
+	if e.EventCode != "" {
+		title = fmt.Sprintf("%s (%s)", e.Name.Default, e.EventCode)
+	}
+
+	return CalendarEvent{
+		Title:       title,
+		Description: strings.TrimSpace(strings.Join([]string{e.EventType, e.Gender}, " ")),
+		Start:       e.StartDate,
+		End:         e.EndDate,
+		UID:         calendarUID("event", e.TournamentID, e.EventCode, e.Name.Default),
+	}
+}
+
+// ExportICalendar writes events as an RFC 5545 iCalendar document
+func ExportICalendar(events []CalendarEvent, w io.Writer) error {
+	cw := &icalWriter{w: w}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:-//Suparena//PTD//EN")
+	cw.line("CALSCALE:GREGORIAN")
+
+	stamp := icalTime(time.Now())
+	for _, event := range events {
+		cw.line("BEGIN:VEVENT")
+		cw.line("UID:" + icalEscape(event.UID))
+		cw.line("DTSTAMP:" + stamp)
+		cw.line("DTSTART:" + icalTime(event.Start))
+		cw.line("DTEND:" + icalTime(event.End))
+		cw.line("SUMMARY:" + icalEscape(event.Title))
+		if event.Location != "" {
+			cw.line("LOCATION:" + icalEscape(event.Location))
+		}
+		if event.Description != "" {
+			cw.line("DESCRIPTION:" + icalEscape(event.Description))
+		}
+		if event.EntryURL != "" {
+			cw.line("URL:" + event.EntryURL)
+		}
+		cw.line("END:VEVENT")
+	}
+
+	cw.line("END:VCALENDAR")
+
+	if cw.err != nil {
+		return fmt.Errorf("%w: %v", ErrExportFailed, cw.err)
+	}
+	return nil
+}
+
+// icalWriter writes CRLF-terminated content lines folded at 75 octets
+type icalWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes one content line, folding it as required by RFC 5545
+func (cw *icalWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, cw.err = io.WriteString(cw.w, b.String())
+}
+
+// icalEscape escapes TEXT values per RFC 5545 section 3.3.11
+func icalEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icalTime formats a time as a UTC DATE-TIME value
+func icalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// calendarUID derives a stable UID from the given parts
+func calendarUID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16]) + "@ptd"
+}
+
+// venueLocation formats a venue as a single-line location
+func venueLocation(v *Venue) string {
+	if v == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range []string{v.Name.Default, v.Address, v.City, v.State, v.PostCode, v.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// truncateToDay returns midnight of t's day in t's location
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// atClock returns day with the time of day taken from clock
+func atClock(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+}
@@ -0,0 +1,112 @@
+package ptd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTournament_CalendarEvents(t *testing.T) {
+	tournament := Tournament{
+		Name:      MultiName{Default: "Summer Open"},
+		StartDate: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 7, 3, 18, 0, 0, 0, time.UTC),
+		Website:   "https://example.com/open",
+		Venue:     &Venue{Name: MultiName{Default: "Sports Complex"}, City: "Springfield"},
+	}
+
+	events := tournament.CalendarEvents(nil)
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 calendar events, got %d", len(events))
+	}
+
+	second := events[1]
+	if second.Title != "Summer Open (Day 2)" {
+		t.Errorf("Unexpected title: %s", second.Title)
+	}
+	if !second.Start.Equal(time.Date(2025, 7, 2, 9, 0, 0, 0, time.UTC)) || !second.End.Equal(time.Date(2025, 7, 2, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected day 2 times: %s - %s", second.Start, second.End)
+	}
+	if second.Location != "Sports Complex, Springfield" {
+		t.Errorf("Unexpected location: %s", second.Location)
+	}
+	if second.EntryURL != tournament.Website {
+		t.Errorf("Unexpected URL: %s", second.EntryURL)
+	}
+	if events[0].UID == events[1].UID {
+		t.Error("Each day should have its own UID")
+	}
+
+	// Explicit venue overrides the tournament venue
+	events = tournament.CalendarEvents(&Venue{Name: MultiName{Default: "Annex"}})
+	if events[0].Location != "Annex" {
+		t.Errorf("Expected explicit venue, got %s", events[0].Location)
+	}
+
+	// Stable UIDs across calls
+	if again := tournament.CalendarEvents(nil); again[0].UID != tournament.CalendarEvents(nil)[0].UID {
+		t.Error("UIDs should be stable")
+	}
+
+	if len((&Tournament{Name: MultiName{Default: "Undated"}}).CalendarEvents(nil)) != 0 {
+		t.Error("Undated tournament should have no calendar events")
+	}
+}
+
+func TestEvent_CalendarEvent(t *testing.T) {
+	event := Event{
+		TournamentID: "ptd:tournament:1",
+		Name:         MultiName{Default: "Men's Singles"},
+		EventCode:    "MS",
+		EventType:    "singles",
+		StartDate:    time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2025, 7, 2, 18, 0, 0, 0, time.UTC),
+	}
+
+	ce := event.CalendarEvent()
+	if ce.Title != "Men's Singles (MS)" || !ce.Start.Equal(event.StartDate) || !ce.End.Equal(event.EndDate) || ce.UID == "" {
+		t.Errorf("Unexpected calendar event: %+v", ce)
+	}
+}
+
+func TestExportICalendar(t *testing.T) {
+	events := []CalendarEvent{
+		{
+			Title:       "Summer Open, Day 1",
+			Location:    "Court 1; Court 2",
+			Description: strings.Repeat("Long description ", 10),
+			Start:       time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+			End:         time.Date(2025, 7, 1, 18, 0, 0, 0, time.UTC),
+			UID:         "abc@ptd",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportICalendar(events, &buf); err != nil {
+		t.Fatalf("ExportICalendar failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"VERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\n",
+		"UID:abc@ptd\r\n",
+		"DTSTART:20250701T090000Z\r\n",
+		"DTEND:20250701T180000Z\r\n",
+		`SUMMARY:Summer Open\, Day 1` + "\r\n",
+		`LOCATION:Court 1\; Court 2` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("iCalendar output missing %q", want)
+		}
+	}
+
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line exceeds 75 octets: %q", line)
+		}
+	}
+}
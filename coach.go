@@ -0,0 +1,15 @@
+package ptd
+
+// LookupCoachForPlayer returns the first coach whose Players list contains
+// playerID, and whether one was found
+func LookupCoachForPlayer(playerID string, coaches []Envelope[Coach]) (*Envelope[Coach], bool) {
+	if playerID == "" {
+		return nil, false
+	}
+	for i := range coaches {
+		if contains(coaches[i].Spec.Players, playerID) {
+			return &coaches[i], true
+		}
+	}
+	return nil, false
+}
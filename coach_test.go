@@ -0,0 +1,26 @@
+package ptd
+
+import "testing"
+
+func TestLookupCoachForPlayer(t *testing.T) {
+	p1, p2, p3 := GenerateID(TypePlayer), GenerateID(TypePlayer), GenerateID(TypePlayer)
+	coaches := []Envelope[Coach]{
+		{ID: GenerateID(TypeCoach), Type: TypeCoach, Spec: Coach{FirstName: "Liu", LastName: "Guoliang", Players: []string{p1}}},
+		{ID: GenerateID(TypeCoach), Type: TypeCoach, Spec: Coach{FirstName: "Jörg", LastName: "Roßkopf", Players: []string{p2, p3}}},
+	}
+
+	coach, ok := LookupCoachForPlayer(p3, coaches)
+	if !ok || coach.ID != coaches[1].ID {
+		t.Errorf("Expected second coach for %s, got %v, %v", p3, coach, ok)
+	}
+	if coach != &coaches[1] {
+		t.Error("Expected a pointer into the coaches slice")
+	}
+
+	if _, ok := LookupCoachForPlayer(GenerateID(TypePlayer), coaches); ok {
+		t.Error("Expected no coach for unknown player")
+	}
+	if _, ok := LookupCoachForPlayer("", coaches); ok {
+		t.Error("Expected no coach for empty player ID")
+	}
+}
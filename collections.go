@@ -0,0 +1,254 @@
+package ptd
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortField selects the ordering used by SortEntries
+type SortField int
+
+const (
+	SortBySeed             SortField = iota // Ascending seed; unseeded entries last
+	SortByPlayerName                        // First player's last name, then first name
+	SortByRegistrationDate                  // Earliest registration first; unregistered last
+	SortByRating                            // Highest first player rating first; unrated last
+)
+
+// MatchSortField selects the ordering used by SortMatches
+type MatchSortField int
+
+const (
+	SortByScheduledAt MatchSortField = iota // Earliest first; unscheduled last
+	SortByMatchNumber                       // Natural order ("M2" before "M10")
+	SortByCourt                             // Natural order by court name
+)
+
+// SortEntries returns a copy of entries sorted by the given field.
+// The sort is stable, so entries that compare equal keep their original order.
+func SortEntries(entries []Envelope[Entry], by SortField) []Envelope[Entry] {
+	sorted := make([]Envelope[Entry], len(entries))
+	copy(sorted, entries)
+
+	var less func(a, b *Entry) bool
+	switch by {
+	case SortBySeed:
+		less = func(a, b *Entry) bool {
+			if a.Seed == nil || b.Seed == nil {
+				return a.Seed != nil && b.Seed == nil
+			}
+			return *a.Seed < *b.Seed
+		}
+	case SortByPlayerName:
+		less = func(a, b *Entry) bool {
+			return playerSortKey(a) < playerSortKey(b)
+		}
+	case SortByRegistrationDate:
+		less = func(a, b *Entry) bool {
+			if a.Registration == nil || b.Registration == nil {
+				return a.Registration != nil && b.Registration == nil
+			}
+			return a.Registration.RegisteredAt.Before(b.Registration.RegisteredAt)
+		}
+	case SortByRating:
+		less = func(a, b *Entry) bool {
+			ra, rb := entryRating(a), entryRating(b)
+			if ra == nil || rb == nil {
+				return ra != nil && rb == nil
+			}
+			return ra.Value > rb.Value
+		}
+	default:
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(&sorted[i].Spec, &sorted[j].Spec)
+	})
+
+	return sorted
+}
+
+// SortMatches returns a copy of matches sorted by the given field.
+// The sort is stable, so matches that compare equal keep their original order.
+func SortMatches(matches []Envelope[Match], by MatchSortField) []Envelope[Match] {
+	sorted := make([]Envelope[Match], len(matches))
+	copy(sorted, matches)
+
+	var less func(a, b *Match) bool
+	switch by {
+	case SortByScheduledAt:
+		less = func(a, b *Match) bool {
+			if a.ScheduledAt == nil || b.ScheduledAt == nil {
+				return a.ScheduledAt != nil && b.ScheduledAt == nil
+			}
+			return a.ScheduledAt.Before(*b.ScheduledAt)
+		}
+	case SortByMatchNumber:
+		less = func(a, b *Match) bool {
+			return naturalLess(a.MatchNumber, b.MatchNumber)
+		}
+	case SortByCourt:
+		less = func(a, b *Match) bool {
+			return naturalLess(a.Court, b.Court)
+		}
+	default:
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(&sorted[i].Spec, &sorted[j].Spec)
+	})
+
+	return sorted
+}
+
+// playerSortKey returns the case-insensitive name key of an entry's first player
+func playerSortKey(e *Entry) string {
+	if len(e.Players) == 0 {
+		if e.Team != nil {
+			return strings.ToLower(e.Team.Name)
+		}
+		return ""
+	}
+	p := e.Players[0]
+	return strings.ToLower(p.LastName + "\x00" + p.FirstName + "\x00" + p.DisplayName)
+}
+
+// entryRating returns the rating of an entry's first player, if any
+func entryRating(e *Entry) *Rating {
+	if len(e.Players) == 0 {
+		return nil
+	}
+	return e.Players[0].Rating
+}
+
+// naturalLess compares strings treating runs of digits as numbers,
+// so that "M2" sorts before "M10"
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := isDigit(a[0]), isDigit(b[0])
+		if da && db {
+			na, ra := splitDigits(a)
+			nb, rb := splitDigits(b)
+			// Compare numerically: longer (after trimming zeros) is larger
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if len(ta) != len(tb) {
+				return len(ta) < len(tb)
+			}
+			if ta != tb {
+				return ta < tb
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// splitDigits splits a leading run of digits from s
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// GroupMatchesByRound groups matches by RoundID. Matches without a round
+// are placed in the "" bucket. Each group preserves the input order.
+func GroupMatchesByRound(matches []Envelope[Match]) map[string][]Envelope[Match] {
+	groups := make(map[string][]Envelope[Match])
+	for _, match := range matches {
+		groups[match.Spec.RoundID] = append(groups[match.Spec.RoundID], match)
+	}
+	return groups
+}
+
+// GroupMatchesByEvent groups matches by EventID. Matches without an event
+// are placed in the "" bucket. Each group preserves the input order.
+func GroupMatchesByEvent(matches []Envelope[Match]) map[string][]Envelope[Match] {
+	groups := make(map[string][]Envelope[Match])
+	for _, match := range matches {
+		groups[match.Spec.EventID] = append(groups[match.Spec.EventID], match)
+	}
+	return groups
+}
+
+// MatchesByStatus partitions matches by their status
+type MatchesByStatus struct {
+	Scheduled  []Envelope[Match]
+	InProgress []Envelope[Match]
+	Completed  []Envelope[Match]
+	Cancelled  []Envelope[Match]
+}
+
+// SplitMatchesByStatus partitions matches by status in a single pass. Each
+// group preserves the input order; matches with any other status are left
+// out.
+func SplitMatchesByStatus(matches []Envelope[Match]) MatchesByStatus {
+	var split MatchesByStatus
+	for _, match := range matches {
+		switch match.Spec.Status {
+		case "scheduled":
+			split.Scheduled = append(split.Scheduled, match)
+		case "in_progress":
+			split.InProgress = append(split.InProgress, match)
+		case "completed":
+			split.Completed = append(split.Completed, match)
+		case "cancelled":
+			split.Cancelled = append(split.Cancelled, match)
+		}
+	}
+	return split
+}
+
+// PendingMatches returns the scheduled and in-progress matches, in order
+func PendingMatches(matches []Envelope[Match]) []Envelope[Match] {
+	return FilterEnvelopes(matches, func(match Envelope[Match]) bool {
+		return match.Spec.Status == "scheduled" || match.Spec.Status == "in_progress"
+	})
+}
+
+// TerminalMatches returns the completed and cancelled matches, in order
+func TerminalMatches(matches []Envelope[Match]) []Envelope[Match] {
+	return FilterEnvelopes(matches, func(match Envelope[Match]) bool {
+		return match.Spec.Status == "completed" || match.Spec.Status == "cancelled"
+	})
+}
+
+// FilterEnvelopes returns the envelopes for which pred returns true, in order
+func FilterEnvelopes[T any](envelopes []Envelope[T], pred func(Envelope[T]) bool) []Envelope[T] {
+	var filtered []Envelope[T]
+	for _, envelope := range envelopes {
+		if pred(envelope) {
+			filtered = append(filtered, envelope)
+		}
+	}
+	return filtered
+}
+
+// MapEnvelopes applies fn to each envelope. Envelopes for which fn fails are
+// left out of the result and their errors are returned alongside it.
+func MapEnvelopes[T, U any](envelopes []Envelope[T], fn func(Envelope[T]) (Envelope[U], error)) ([]Envelope[U], []error) {
+	var mapped []Envelope[U]
+	var errs []error
+	for _, envelope := range envelopes {
+		result, err := fn(envelope)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		mapped = append(mapped, result)
+	}
+	return mapped, errs
+}
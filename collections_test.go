@@ -0,0 +1,225 @@
+package ptd
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestEntry(firstName, lastName string, seed *int, rating int, registeredAt time.Time) Envelope[Entry] {
+	player := Player{FirstName: firstName, LastName: lastName}
+	if rating > 0 {
+		player.Rating = &Rating{Value: rating, System: "ITTF"}
+	}
+
+	entry := Entry{
+		EventID: "ptd:event:test",
+		Status:  "confirmed",
+		Seed:    seed,
+		Players: []Player{player},
+	}
+	if !registeredAt.IsZero() {
+		entry.Registration = &Registration{RegisteredAt: registeredAt}
+	}
+
+	return Envelope[Entry]{
+		ID:   GenerateID(TypeEntry),
+		Type: TypeEntry,
+		Spec: entry,
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Envelope[Entry]{
+		newTestEntry("Ma", "Long", intPtr(2), 2800, base.Add(48*time.Hour)),
+		newTestEntry("Fan", "Zhendong", intPtr(1), 3000, base.Add(24*time.Hour)),
+		newTestEntry("Timo", "Boll", nil, 0, time.Time{}),
+		newTestEntry("Hugo", "Calderano", intPtr(3), 2900, base),
+	}
+
+	names := func(sorted []Envelope[Entry]) []string {
+		out := make([]string, len(sorted))
+		for i, e := range sorted {
+			out[i] = e.Spec.Players[0].LastName
+		}
+		return out
+	}
+
+	tests := []struct {
+		by   SortField
+		want []string
+	}{
+		{SortBySeed, []string{"Zhendong", "Long", "Calderano", "Boll"}},
+		{SortByPlayerName, []string{"Boll", "Calderano", "Long", "Zhendong"}},
+		{SortByRegistrationDate, []string{"Calderano", "Zhendong", "Long", "Boll"}},
+		{SortByRating, []string{"Zhendong", "Calderano", "Long", "Boll"}},
+	}
+
+	for _, tt := range tests {
+		got := names(SortEntries(entries, tt.by))
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortEntries(%d) = %v, want %v", tt.by, got, tt.want)
+				break
+			}
+		}
+	}
+
+	// Input is not modified
+	if entries[0].Spec.Players[0].LastName != "Long" {
+		t.Error("SortEntries should not modify its input")
+	}
+}
+
+func TestSortEntries_Stable(t *testing.T) {
+	entries := []Envelope[Entry]{
+		newTestEntry("A", "Same", nil, 0, time.Time{}),
+		newTestEntry("B", "Other", intPtr(1), 0, time.Time{}),
+		newTestEntry("C", "Same", nil, 0, time.Time{}),
+	}
+
+	sorted := SortEntries(entries, SortBySeed)
+	if sorted[1].ID != entries[0].ID || sorted[2].ID != entries[2].ID {
+		t.Error("Unseeded entries should keep their original order")
+	}
+}
+
+func TestSortMatches(t *testing.T) {
+	t1 := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	matches := []Envelope[Match]{
+		{ID: "m10", Spec: Match{MatchNumber: "M10", Court: "Court 10", ScheduledAt: &t2}},
+		{ID: "m2", Spec: Match{MatchNumber: "M2", Court: "Court 2"}},
+		{ID: "m1", Spec: Match{MatchNumber: "M1", Court: "Court 1", ScheduledAt: &t1}},
+	}
+
+	ids := func(sorted []Envelope[Match]) string {
+		out := ""
+		for _, m := range sorted {
+			out += m.ID + " "
+		}
+		return out
+	}
+
+	if got := ids(SortMatches(matches, SortByScheduledAt)); got != "m1 m10 m2 " {
+		t.Errorf("SortByScheduledAt = %s", got)
+	}
+	if got := ids(SortMatches(matches, SortByMatchNumber)); got != "m1 m2 m10 " {
+		t.Errorf("SortByMatchNumber = %s", got)
+	}
+	if got := ids(SortMatches(matches, SortByCourt)); got != "m1 m2 m10 " {
+		t.Errorf("SortByCourt = %s", got)
+	}
+}
+
+func TestGroupMatches(t *testing.T) {
+	matches := []Envelope[Match]{
+		{ID: "m1", Spec: Match{EventID: "e1", RoundID: "r1"}},
+		{ID: "m2", Spec: Match{EventID: "e1", RoundID: "r2"}},
+		{ID: "m3", Spec: Match{EventID: "e2", RoundID: "r1"}},
+		{ID: "m4", Spec: Match{}},
+	}
+
+	byRound := GroupMatchesByRound(matches)
+	if len(byRound) != 3 {
+		t.Errorf("Expected 3 round groups, got %d", len(byRound))
+	}
+	if len(byRound["r1"]) != 2 || byRound["r1"][0].ID != "m1" || byRound["r1"][1].ID != "m3" {
+		t.Errorf("Unexpected r1 group: %v", byRound["r1"])
+	}
+	if len(byRound[""]) != 1 || byRound[""][0].ID != "m4" {
+		t.Error("Match without round should be in the empty bucket")
+	}
+
+	byEvent := GroupMatchesByEvent(matches)
+	if len(byEvent["e1"]) != 2 || len(byEvent["e2"]) != 1 || len(byEvent[""]) != 1 {
+		t.Errorf("Unexpected event groups: %v", byEvent)
+	}
+
+	if len(GroupMatchesByRound(nil)) != 0 {
+		t.Error("Grouping no matches should return an empty map")
+	}
+}
+
+func TestSplitMatchesByStatus(t *testing.T) {
+	matches := []Envelope[Match]{
+		{ID: "m1", Spec: Match{Status: "completed"}},
+		{ID: "m2", Spec: Match{Status: "scheduled"}},
+		{ID: "m3", Spec: Match{Status: "in_progress"}},
+		{ID: "m4", Spec: Match{Status: "cancelled"}},
+		{ID: "m5", Spec: Match{Status: "scheduled"}},
+		{ID: "m6", Spec: Match{Status: "postponed"}},
+		{ID: "m7", Spec: Match{Status: "completed"}},
+	}
+
+	ids := func(matches []Envelope[Match]) string {
+		var s string
+		for _, m := range matches {
+			s += m.ID + " "
+		}
+		return s
+	}
+
+	split := SplitMatchesByStatus(matches)
+	for name, tt := range map[string]struct{ got, want string }{
+		"Scheduled":  {ids(split.Scheduled), "m2 m5 "},
+		"InProgress": {ids(split.InProgress), "m3 "},
+		"Completed":  {ids(split.Completed), "m1 m7 "},
+		"Cancelled":  {ids(split.Cancelled), "m4 "},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", name, tt.got, tt.want)
+		}
+	}
+
+	if got := ids(PendingMatches(matches)); got != "m2 m3 m5 " {
+		t.Errorf("PendingMatches() = %q, want %q", got, "m2 m3 m5 ")
+	}
+	if got := ids(TerminalMatches(matches)); got != "m1 m4 m7 " {
+		t.Errorf("TerminalMatches() = %q, want %q", got, "m1 m4 m7 ")
+	}
+
+	if empty := SplitMatchesByStatus(nil); empty.Scheduled != nil || empty.Completed != nil {
+		t.Errorf("SplitMatchesByStatus(nil) = %+v, want empty groups", empty)
+	}
+}
+
+func TestFilterEnvelopes(t *testing.T) {
+	matches := []Envelope[Match]{
+		{ID: "m1", Spec: Match{Status: "completed"}},
+		{ID: "m2", Spec: Match{Status: "scheduled"}},
+		{ID: "m3", Spec: Match{Status: "completed"}},
+	}
+
+	completed := FilterEnvelopes(matches, func(m Envelope[Match]) bool {
+		return m.Spec.Status == "completed"
+	})
+	if len(completed) != 2 || completed[0].ID != "m1" || completed[1].ID != "m3" {
+		t.Errorf("Unexpected filter result: %v", completed)
+	}
+
+	if none := FilterEnvelopes(matches, func(Envelope[Match]) bool { return false }); len(none) != 0 {
+		t.Errorf("Expected no matches, got %d", len(none))
+	}
+}
+
+func TestMapEnvelopes(t *testing.T) {
+	matches := []Envelope[Match]{
+		{ID: "m1", Spec: Match{Court: "1"}},
+		{ID: "m2", Spec: Match{Court: "x"}},
+		{ID: "m3", Spec: Match{Court: "3"}},
+	}
+
+	courts, errs := MapEnvelopes(matches, func(m Envelope[Match]) (Envelope[int], error) {
+		n, err := strconv.Atoi(m.Spec.Court)
+		return Envelope[int]{ID: m.ID, Spec: n}, err
+	})
+	if len(courts) != 2 || courts[1].ID != "m3" || courts[1].Spec != 3 {
+		t.Errorf("Unexpected map result: %v", courts)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %v", errs)
+	}
+}
@@ -0,0 +1,270 @@
+// Package pdf imports tournament data published as PDF documents.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/suparena/ptd"
+)
+
+// MaxDecompressedBytes caps the total size ImportDraw inflates the page
+// content streams of a PDF to, so a small file cannot expand into
+// gigabytes of content
+const MaxDecompressedBytes = 32 << 20
+
+// drawSource is the provenance source of matches imported from PDF draw sheets
+const drawSource = "pdf:draw_sheet"
+
+// ParseReport summarizes a best-effort import
+type ParseReport struct {
+	Extracted     int      `json:"extracted"`                // Matches extracted
+	Failed        int      `json:"failed"`                   // Lines that looked like matches but could not be parsed
+	UnparsedLines []string `json:"unparsed_lines,omitempty"` // Text lines that did not yield a match, in page order
+}
+
+// Match lines such as "M12  Fan Zhendong vs Harimoto Tomokazu 3-1 (11-9, 8-11, 11-7, 11-5)"
+var (
+	matchLine = regexp.MustCompile(`^(?:(?i:match|m)\s*)?#?(\d+)[.):]?\s+(.+?)\s+(?i:vs\.?|v\.?)\s+(.+?)(?:\s+(\d+\s*[-:]\s*\d+(?:\s*\([^)]*\))?|(?i:w/?o)))?\s*$`)
+	versus    = regexp.MustCompile(`(?i)(?:^|\s)(?:vs\.?|v\.?)(?:\s|$)`)
+	setScore  = regexp.MustCompile(`(\d+)\s*[-:]\s*(\d+)`)
+)
+
+// ImportDraw extracts the matches of a draw sheet or result list
+// published as PDF. This is best effort: the document is read with
+// github.com/ledongthuc/pdf, which decodes text through the fonts'
+// encodings, and the text of each page is assembled into lines by
+// position. Each line of the form "<number> <home> vs <away> [<final>
+// (<set>, ...)]" becomes a match of the event. PDFs that are scanned
+// images or encrypted yield little or no text; the report lists every line
+// that could not be used.
+//
+// Entries are identified only by the names printed on the sheet, in
+// EntryRef.DisplayName; EntryID and Winner are left for the caller to link.
+// Matches with a score are marked completed, the rest scheduled.
+//
+// Returns ptd.ErrImportFailed if the data is not a readable PDF, holds no
+// text, or its page contents exceed MaxDecompressedBytes.
+func ImportDraw(r io.Reader, eventID string) ([]ptd.Envelope[ptd.Match], *ParseReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: failed to read PDF: %v", ptd.ErrImportFailed, err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, nil, fmt.Errorf("%w: not a PDF file", ptd.ErrImportFailed)
+	}
+
+	lines, err := extractText(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil, fmt.Errorf("%w: PDF has no extractable text", ptd.ErrImportFailed)
+	}
+
+	now := time.Now()
+	report := &ParseReport{}
+	var matches []ptd.Envelope[ptd.Match]
+	for _, line := range lines {
+		match, ok := parseMatchLine(line)
+		if !ok {
+			if versus.MatchString(line) {
+				report.Failed++
+			}
+			report.UnparsedLines = append(report.UnparsedLines, line)
+			continue
+		}
+
+		match.EventID = eventID
+		importedAt := now
+		matches = append(matches, ptd.Envelope[ptd.Match]{
+			ID:   ptd.GenerateID(ptd.TypeMatch),
+			Type: ptd.TypeMatch,
+			Spec: match,
+			Meta: ptd.Meta{
+				Schema:    "ptd.v1.match@1.0.0",
+				Version:   1,
+				CreatedAt: now,
+				UpdatedAt: now,
+				Source:    "pdf",
+				Provenance: &ptd.Provenance{
+					OriginalSource: drawSource,
+					ImportedAt:     &importedAt,
+				},
+			},
+		})
+		report.Extracted++
+	}
+
+	return matches, report, nil
+}
+
+// parseMatchLine parses one text line of a draw sheet into a match
+func parseMatchLine(line string) (ptd.Match, bool) {
+	parts := matchLine.FindStringSubmatch(line)
+	if parts == nil {
+		return ptd.Match{}, false
+	}
+
+	match := ptd.Match{
+		MatchNumber: parts[1],
+		Status:      "scheduled",
+		HomeEntry:   &ptd.EntryRef{DisplayName: strings.TrimSpace(parts[2])},
+		AwayEntry:   &ptd.EntryRef{DisplayName: strings.TrimSpace(parts[3])},
+	}
+
+	result := strings.TrimSpace(parts[4])
+	if result == "" {
+		return match, true
+	}
+	match.Status = "completed"
+
+	if strings.EqualFold(strings.ReplaceAll(result, "/", ""), "wo") {
+		match.Score = &ptd.Score{Final: "W/O", Walkover: true}
+		return match, true
+	}
+
+	final, sets, _ := strings.Cut(result, "(")
+	score := &ptd.Score{}
+	if games := setScore.FindStringSubmatch(final); games != nil {
+		home, _ := strconv.Atoi(games[1])
+		away, _ := strconv.Atoi(games[2])
+		score.Final = fmt.Sprintf("%d-%d", home, away)
+	}
+	for i, set := range setScore.FindAllStringSubmatch(sets, -1) {
+		home, _ := strconv.Atoi(set[1])
+		away, _ := strconv.Atoi(set[2])
+		score.Sets = append(score.Sets, ptd.SetScore{SetNumber: i + 1, HomeScore: home, AwayScore: away})
+	}
+	match.Score = score
+
+	return match, true
+}
+
+// textRun is a string shown at a position on the page
+type textRun struct {
+	x, y float64
+	text string
+}
+
+// extractText returns the text lines of every page of a PDF, top to
+// bottom. Runs on the same baseline are joined left to right.
+func extractText(data []byte) (lines []string, err error) {
+	// The PDF library panics on some malformed documents
+	defer func() {
+		if r := recover(); r != nil {
+			lines, err = nil, fmt.Errorf("%w: failed to read PDF: %v", ptd.ErrImportFailed, r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read PDF: %v", ptd.ErrImportFailed, err)
+	}
+
+	budget := int64(MaxDecompressedBytes)
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		if err := checkContentSize(page, &budget); err != nil {
+			return nil, err
+		}
+
+		runs := textRuns(page.Content().Text)
+		sort.SliceStable(runs, func(i, j int) bool {
+			yi, yj := math.Round(runs[i].y), math.Round(runs[j].y)
+			if yi != yj {
+				return yi > yj
+			}
+			return runs[i].x < runs[j].x
+		})
+
+		var current []string
+		for i, run := range runs {
+			if i > 0 && math.Round(run.y) != math.Round(runs[i-1].y) {
+				lines = appendLine(lines, current)
+				current = nil
+			}
+			current = append(current, run.text)
+		}
+		lines = appendLine(lines, current)
+	}
+	return lines, nil
+}
+
+// checkContentSize deducts the decompressed size of a page's content
+// streams from budget, returning ptd.ErrImportFailed once it is exhausted.
+// Streams are inflated through a limited reader, so the check itself never
+// reads more than the budget.
+func checkContentSize(page pdf.Page, budget *int64) error {
+	contents := page.V.Key("Contents")
+	streams := []pdf.Value{contents}
+	if contents.Kind() == pdf.Array {
+		streams = streams[:0]
+		for i := 0; i < contents.Len(); i++ {
+			streams = append(streams, contents.Index(i))
+		}
+	}
+
+	for _, stream := range streams {
+		if stream.Kind() != pdf.Stream {
+			continue
+		}
+		rc := stream.Reader()
+		n, err := io.Copy(io.Discard, io.LimitReader(rc, *budget+1))
+		rc.Close()
+		if n > *budget {
+			return fmt.Errorf("%w: PDF page contents decompress to more than %d bytes", ptd.ErrImportFailed, MaxDecompressedBytes)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: failed to read PDF page contents: %v", ptd.ErrImportFailed, err)
+		}
+		*budget -= n
+	}
+	return nil
+}
+
+// textRuns groups the glyphs of a page, in the order they were drawn, into
+// runs of text that continue one another on the same baseline. A glyph that
+// does not start where the previous one ended, such as the next column or a
+// word moved apart by a TJ adjustment, starts a new run.
+func textRuns(glyphs []pdf.Text) []textRun {
+	var runs []textRun
+	var endX, y float64
+	for i, glyph := range glyphs {
+		continues := i > 0 && glyph.Y == y && math.Abs(glyph.X-endX) < 0.5
+		if continues {
+			runs[len(runs)-1].text += glyph.S
+		} else {
+			runs = append(runs, textRun{x: glyph.X, y: glyph.Y, text: glyph.S})
+		}
+		endX, y = glyph.X+glyph.W, glyph.Y
+	}
+
+	kept := runs[:0]
+	for _, run := range runs {
+		if strings.TrimSpace(run.text) != "" {
+			kept = append(kept, run)
+		}
+	}
+	return kept
+}
+
+// appendLine joins the runs of a line and appends it unless it is blank
+func appendLine(lines []string, runs []string) []string {
+	line := strings.Join(strings.Fields(strings.Join(runs, " ")), " ")
+	if line == "" {
+		return lines
+	}
+	return append(lines, line)
+}
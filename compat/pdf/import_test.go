@@ -0,0 +1,196 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/suparena/ptd"
+)
+
+func TestImportDraw(t *testing.T) {
+	// Columns are drawn separately, as draw sheet generators usually do
+	page := &testPage{}
+	page.text(50, 800, 14, true, "Men's Singles - Quarterfinals")
+	page.text(50, 760, 10, false, "M1")
+	page.text(90, 760, 10, false, "Fan Zhendong vs Harimoto Tomokazu")
+	page.text(350, 760, 10, false, "3-1 (11-9, 8-11, 11-7, 11-5)")
+	page.text(50, 740, 10, false, "M2")
+	page.text(90, 740, 10, false, "Wang Chuqin vs Truls Moregard")
+	page.text(50, 720, 10, false, "M3")
+	page.text(90, 720, 10, false, "Lin Yun-Ju vs Hugo Calderano")
+	page.text(350, 720, 10, false, "W/O")
+	page.text(50, 700, 10, false, "Felix Lebrun vs")
+
+	matches, report, err := ImportDraw(bytes.NewReader(page.document()), "ptd:event:ms")
+	if err != nil {
+		t.Fatalf("ImportDraw() error = %v", err)
+	}
+
+	if report.Extracted != 3 || report.Failed != 1 {
+		t.Errorf("report = %+v, want 3 extracted, 1 failed", report)
+	}
+	if len(report.UnparsedLines) != 2 || report.UnparsedLines[0] != "Men's Singles - Quarterfinals" {
+		t.Errorf("UnparsedLines = %q", report.UnparsedLines)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3", len(matches))
+	}
+
+	m := matches[0].Spec
+	if m.EventID != "ptd:event:ms" || m.MatchNumber != "1" || m.Status != "completed" {
+		t.Errorf("match 1 = %+v", m)
+	}
+	if m.HomeEntry.DisplayName != "Fan Zhendong" || m.AwayEntry.DisplayName != "Harimoto Tomokazu" {
+		t.Errorf("entries = %q vs %q", m.HomeEntry.DisplayName, m.AwayEntry.DisplayName)
+	}
+	if m.Score == nil || m.Score.Final != "3-1" || len(m.Score.Sets) != 4 || m.Score.Sets[3].HomeScore != 11 || m.Score.Sets[3].AwayScore != 5 {
+		t.Errorf("score = %+v", m.Score)
+	}
+
+	if got := matches[1].Spec; got.Status != "scheduled" || got.Score != nil || got.AwayEntry.DisplayName != "Truls Moregard" {
+		t.Errorf("match 2 = %+v", got)
+	}
+	if got := matches[2].Spec; got.Score == nil || !got.Score.Walkover {
+		t.Errorf("match 3 score = %+v, want walkover", got.Score)
+	}
+
+	if matches[0].Meta.Provenance == nil || matches[0].Meta.Provenance.OriginalSource != drawSource {
+		t.Errorf("provenance = %+v", matches[0].Meta.Provenance)
+	}
+}
+
+func TestImportDraw_FlateDecode(t *testing.T) {
+	content := "BT /F1 10 Tf 50 700 Td [(Match 7 Ma Long v) -250 (Xu Xin)] TJ ET\n" +
+		"BT 1 0 0 1 300 700 Tm <342D30> Tj ET\n" +
+		"BT 12 TL 50 680 Td (Results) Tj (continued\\051) ' ET\n"
+	page := &testPage{flate: true}
+	page.content.WriteString(content)
+
+	matches, report, err := ImportDraw(bytes.NewReader(page.document()), "ptd:event:1")
+	if err != nil {
+		t.Fatalf("ImportDraw() error = %v", err)
+	}
+	if len(report.UnparsedLines) != 2 || report.UnparsedLines[1] != "continued)" {
+		t.Errorf("UnparsedLines = %q", report.UnparsedLines)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1", len(matches))
+	}
+	if got := matches[0].Spec; got.MatchNumber != "7" || got.HomeEntry.DisplayName != "Ma Long" ||
+		got.AwayEntry.DisplayName != "Xu Xin" || got.Score == nil || got.Score.Final != "4-0" {
+		t.Errorf("match = %+v", got)
+	}
+}
+
+func TestParseMatchLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		ok    bool
+		home  string
+		final string
+	}{
+		{"12. Ito Mima vs. Sun Yingsha 2:3 (11-8 9-11 7-11 11-6 8-11)", true, "Ito Mima", "2-3"},
+		{"#4 Ma Long v Xu Xin", true, "Ma Long", ""},
+		{"Round of 16", false, "", ""},
+		{"Ito Mima vs Sun Yingsha", false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			m, ok := parseMatchLine(tt.line)
+			if ok != tt.ok {
+				t.Fatalf("parseMatchLine() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if m.HomeEntry.DisplayName != tt.home {
+				t.Errorf("home = %q, want %q", m.HomeEntry.DisplayName, tt.home)
+			}
+			final := ""
+			if m.Score != nil {
+				final = m.Score.Final
+			}
+			if final != tt.final {
+				t.Errorf("final = %q, want %q", final, tt.final)
+			}
+		})
+	}
+}
+
+func TestImportDraw_DecompressionLimit(t *testing.T) {
+	page := &testPage{flate: true}
+	page.content.WriteString("BT /F1 10 Tf 50 700 Td (1 Ma Long vs Xu Xin) Tj ET\n")
+	page.content.Write(make([]byte, MaxDecompressedBytes))
+
+	if _, _, err := ImportDraw(bytes.NewReader(page.document()), ""); !errors.Is(err, ptd.ErrImportFailed) || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("ImportDraw(decompression bomb) error = %v, want ErrImportFailed", err)
+	}
+}
+
+func TestImportDraw_Invalid(t *testing.T) {
+	if _, _, err := ImportDraw(strings.NewReader("not a pdf"), ""); !errors.Is(err, ptd.ErrImportFailed) {
+		t.Errorf("ImportDraw(non-PDF) error = %v, want ptd.ErrImportFailed", err)
+	}
+	if _, _, err := ImportDraw(strings.NewReader("%PDF-1.4\n%%EOF\n"), ""); !errors.Is(err, ptd.ErrImportFailed) {
+		t.Errorf("ImportDraw(no text) error = %v, want ptd.ErrImportFailed", err)
+	}
+}
+
+// testPage builds a single-page PDF using the standard Helvetica fonts,
+// like the draw sheets of most tournament software
+type testPage struct {
+	content bytes.Buffer
+	flate   bool // compress the content stream with FlateDecode
+}
+
+// text draws s with its baseline at (x, y)
+func (p *testPage) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	s = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(s)
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, s)
+}
+
+// document returns the PDF file
+func (p *testPage) document() []byte {
+	content, filter := p.content.Bytes(), ""
+	if p.flate {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content)
+		zw.Close()
+		content, filter = compressed.Bytes(), " /Filter /FlateDecode"
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] " +
+			"/Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d%s >>\nstream\n%s\nendstream", len(content), filter, content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
@@ -0,0 +1,125 @@
+package ptd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// iso3166Alpha2 maps ISO 3166-1 alpha-2 country codes to their alpha-3 codes
+var iso3166Alpha2 = map[string]string{
+	"AD": "AND", "AE": "ARE", "AF": "AFG", "AG": "ATG", "AI": "AIA", "AL": "ALB", "AM": "ARM", "AO": "AGO",
+	"AQ": "ATA", "AR": "ARG", "AS": "ASM", "AT": "AUT", "AU": "AUS", "AW": "ABW", "AX": "ALA", "AZ": "AZE",
+	"BA": "BIH", "BB": "BRB", "BD": "BGD", "BE": "BEL", "BF": "BFA", "BG": "BGR", "BH": "BHR", "BI": "BDI",
+	"BJ": "BEN", "BL": "BLM", "BM": "BMU", "BN": "BRN", "BO": "BOL", "BQ": "BES", "BR": "BRA", "BS": "BHS",
+	"BT": "BTN", "BV": "BVT", "BW": "BWA", "BY": "BLR", "BZ": "BLZ", "CA": "CAN", "CC": "CCK", "CD": "COD",
+	"CF": "CAF", "CG": "COG", "CH": "CHE", "CI": "CIV", "CK": "COK", "CL": "CHL", "CM": "CMR", "CN": "CHN",
+	"CO": "COL", "CR": "CRI", "CU": "CUB", "CV": "CPV", "CW": "CUW", "CX": "CXR", "CY": "CYP", "CZ": "CZE",
+	"DE": "DEU", "DJ": "DJI", "DK": "DNK", "DM": "DMA", "DO": "DOM", "DZ": "DZA", "EC": "ECU", "EE": "EST",
+	"EG": "EGY", "EH": "ESH", "ER": "ERI", "ES": "ESP", "ET": "ETH", "FI": "FIN", "FJ": "FJI", "FK": "FLK",
+	"FM": "FSM", "FO": "FRO", "FR": "FRA", "GA": "GAB", "GB": "GBR", "GD": "GRD", "GE": "GEO", "GF": "GUF",
+	"GG": "GGY", "GH": "GHA", "GI": "GIB", "GL": "GRL", "GM": "GMB", "GN": "GIN", "GP": "GLP", "GQ": "GNQ",
+	"GR": "GRC", "GS": "SGS", "GT": "GTM", "GU": "GUM", "GW": "GNB", "GY": "GUY", "HK": "HKG", "HM": "HMD",
+	"HN": "HND", "HR": "HRV", "HT": "HTI", "HU": "HUN", "ID": "IDN", "IE": "IRL", "IL": "ISR", "IM": "IMN",
+	"IN": "IND", "IO": "IOT", "IQ": "IRQ", "IR": "IRN", "IS": "ISL", "IT": "ITA", "JE": "JEY", "JM": "JAM",
+	"JO": "JOR", "JP": "JPN", "KE": "KEN", "KG": "KGZ", "KH": "KHM", "KI": "KIR", "KM": "COM", "KN": "KNA",
+	"KP": "PRK", "KR": "KOR", "KW": "KWT", "KY": "CYM", "KZ": "KAZ", "LA": "LAO", "LB": "LBN", "LC": "LCA",
+	"LI": "LIE", "LK": "LKA", "LR": "LBR", "LS": "LSO", "LT": "LTU", "LU": "LUX", "LV": "LVA", "LY": "LBY",
+	"MA": "MAR", "MC": "MCO", "MD": "MDA", "ME": "MNE", "MF": "MAF", "MG": "MDG", "MH": "MHL", "MK": "MKD",
+	"ML": "MLI", "MM": "MMR", "MN": "MNG", "MO": "MAC", "MP": "MNP", "MQ": "MTQ", "MR": "MRT", "MS": "MSR",
+	"MT": "MLT", "MU": "MUS", "MV": "MDV", "MW": "MWI", "MX": "MEX", "MY": "MYS", "MZ": "MOZ", "NA": "NAM",
+	"NC": "NCL", "NE": "NER", "NF": "NFK", "NG": "NGA", "NI": "NIC", "NL": "NLD", "NO": "NOR", "NP": "NPL",
+	"NR": "NRU", "NU": "NIU", "NZ": "NZL", "OM": "OMN", "PA": "PAN", "PE": "PER", "PF": "PYF", "PG": "PNG",
+	"PH": "PHL", "PK": "PAK", "PL": "POL", "PM": "SPM", "PN": "PCN", "PR": "PRI", "PS": "PSE", "PT": "PRT",
+	"PW": "PLW", "PY": "PRY", "QA": "QAT", "RE": "REU", "RO": "ROU", "RS": "SRB", "RU": "RUS", "RW": "RWA",
+	"SA": "SAU", "SB": "SLB", "SC": "SYC", "SD": "SDN", "SE": "SWE", "SG": "SGP", "SH": "SHN", "SI": "SVN",
+	"SJ": "SJM", "SK": "SVK", "SL": "SLE", "SM": "SMR", "SN": "SEN", "SO": "SOM", "SR": "SUR", "SS": "SSD",
+	"ST": "STP", "SV": "SLV", "SX": "SXM", "SY": "SYR", "SZ": "SWZ", "TC": "TCA", "TD": "TCD", "TF": "ATF",
+	"TG": "TGO", "TH": "THA", "TJ": "TJK", "TK": "TKL", "TL": "TLS", "TM": "TKM", "TN": "TUN", "TO": "TON",
+	"TR": "TUR", "TT": "TTO", "TV": "TUV", "TW": "TWN", "TZ": "TZA", "UA": "UKR", "UG": "UGA", "UM": "UMI",
+	"US": "USA", "UY": "URY", "UZ": "UZB", "VA": "VAT", "VC": "VCT", "VE": "VEN", "VG": "VGB", "VI": "VIR",
+	"VN": "VNM", "VU": "VUT", "WF": "WLF", "WS": "WSM", "YE": "YEM", "YT": "MYT", "ZA": "ZAF", "ZM": "ZMB",
+	"ZW": "ZWE",
+}
+
+// iso3166Alpha3 is the set of ISO 3166-1 alpha-3 country codes
+var iso3166Alpha3 = func() map[string]bool {
+	codes := make(map[string]bool, len(iso3166Alpha2))
+	for _, alpha3 := range iso3166Alpha2 {
+		codes[alpha3] = true
+	}
+	return codes
+}()
+
+// iocCodes maps ISO 3166-1 alpha-3 codes to the IOC country codes used by
+// sports federations such as the ITTF and FIDE, where the two differ
+var iocCodes = map[string]string{
+	"ABW": "ARU", "AGO": "ANG", "ARE": "UAE", "ASM": "ASA", "ATG": "ANT", "BGD": "BAN", "BGR": "BUL", "BHR": "BRN",
+	"BHS": "BAH", "BLZ": "BIZ", "BMU": "BER", "BRB": "BAR", "BRN": "BRU", "BTN": "BHU", "BWA": "BOT", "CHE": "SUI",
+	"CHL": "CHI", "CYM": "CAY", "COG": "CGO", "CRI": "CRC", "DEU": "GER", "DNK": "DEN", "DZA": "ALG", "FJI": "FIJ",
+	"GIN": "GUI", "GMB": "GAM", "GNB": "GBS", "GNQ": "GEQ", "GRC": "GRE", "GRD": "GRN", "GTM": "GUA", "HND": "HON",
+	"HRV": "CRO", "HTI": "HAI", "IDN": "INA", "IRN": "IRI", "KHM": "CAM", "KNA": "SKN", "KWT": "KUW", "LBN": "LIB",
+	"LBY": "LBA", "LKA": "SRI", "LSO": "LES", "LVA": "LAT", "MCO": "MON", "MDG": "MAD", "MMR": "MYA", "MNG": "MGL",
+	"MRT": "MTN", "MUS": "MRI", "MWI": "MAW", "MYS": "MAS", "NER": "NIG", "NGA": "NGR", "NIC": "NCA", "NLD": "NED",
+	"NPL": "NEP", "OMN": "OMA", "PHL": "PHI", "PRI": "PUR", "PRT": "POR", "PRY": "PAR", "PSE": "PLE", "SAU": "KSA",
+	"SDN": "SUD", "SGP": "SIN", "SLB": "SOL", "SLV": "ESA", "SVN": "SLO", "SYC": "SEY", "TCD": "CHA", "TGO": "TOG",
+	"TON": "TGA", "TWN": "TPE", "TZA": "TAN", "URY": "URU", "VCT": "VIN", "VGB": "IVB", "VIR": "ISV", "VNM": "VIE",
+	"VUT": "VAN", "WSM": "SAM", "ZAF": "RSA", "ZMB": "ZAM", "ZWE": "ZIM",
+}
+
+// iocToISO maps the IOC codes in iocCodes back to ISO 3166-1 alpha-3.
+// IOC codes that are also an ISO code, such as Bahrain's "BRN" (Brunei in
+// ISO), are left out: ISO takes precedence.
+var iocToISO = func() map[string]string {
+	codes := make(map[string]string, len(iocCodes))
+	for iso, ioc := range iocCodes {
+		if !iso3166Alpha3[ioc] {
+			codes[ioc] = iso
+		}
+	}
+	return codes
+}()
+
+// ValidateCountryCode checks that code is an ISO 3166-1 alpha-2 or alpha-3
+// country code, or an IOC country code. Codes are matched
+// case-insensitively.
+func ValidateCountryCode(code string) error {
+	_, err := NormalizeCountryCode(code)
+	return err
+}
+
+// NormalizeCountryCode returns the ISO 3166-1 alpha-3 form of an alpha-2 or
+// alpha-3 country code, the form PTD stores. "us", "US" and "usa" all
+// normalize to "USA". IOC codes used by sports federations are converted
+// too, so the ITTF's "GER" normalizes to "DEU"; where an IOC code is also
+// an ISO code, it is read as ISO.
+func NormalizeCountryCode(code string) (string, error) {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	switch len(normalized) {
+	case 2:
+		if alpha3, ok := iso3166Alpha2[normalized]; ok {
+			return alpha3, nil
+		}
+	case 3:
+		if iso3166Alpha3[normalized] {
+			return normalized, nil
+		}
+		if alpha3, ok := iocToISO[normalized]; ok {
+			return alpha3, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown ISO 3166-1 country code %q", ErrValidation, code)
+}
+
+// IOCCountryCode returns the IOC country code of any code accepted by
+// NormalizeCountryCode, for exports to federations that use IOC codes.
+// "DEU", "DE" and "GER" all return "GER".
+func IOCCountryCode(code string) (string, error) {
+	alpha3, err := NormalizeCountryCode(code)
+	if err != nil {
+		return "", err
+	}
+	if ioc, ok := iocCodes[alpha3]; ok {
+		return ioc, nil
+	}
+	return alpha3, nil
+}
@@ -0,0 +1,81 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCountryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"US", "USA"},
+		{"usa", "USA"},
+		{" jp ", "JPN"},
+		{"DE", "DEU"},
+		{"CHN", "CHN"},
+		{"ax", "ALA"},
+		{"GER", "DEU"},
+		{"sui", "CHE"},
+		{"TPE", "TWN"},
+		{"BRN", "BRN"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeCountryCode(tt.code)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeCountryCode(%q) = %q, %v; want %q", tt.code, got, err, tt.want)
+		}
+		if err := ValidateCountryCode(tt.code); err != nil {
+			t.Errorf("ValidateCountryCode(%q) error = %v", tt.code, err)
+		}
+	}
+
+	for _, code := range []string{"", "U", "XX", "XYZ", "Japan", "USAA"} {
+		if _, err := NormalizeCountryCode(code); !errors.Is(err, ErrValidation) {
+			t.Errorf("NormalizeCountryCode(%q) error = %v, want ErrValidation", code, err)
+		}
+		if err := ValidateCountryCode(code); !errors.Is(err, ErrValidation) {
+			t.Errorf("ValidateCountryCode(%q) error = %v, want ErrValidation", code, err)
+		}
+	}
+}
+
+func TestISO3166Tables(t *testing.T) {
+	if len(iso3166Alpha2) != 249 || len(iso3166Alpha3) != 249 {
+		t.Errorf("ISO 3166-1 tables have %d alpha-2 and %d alpha-3 codes, want 249", len(iso3166Alpha2), len(iso3166Alpha3))
+	}
+}
+
+func TestIOCCountryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"DEU", "GER"},
+		{"de", "GER"},
+		{"GER", "GER"},
+		{"NLD", "NED"},
+		{"CHN", "CHN"},
+		{"BHR", "BRN"},
+		{"BRN", "BRU"},
+	}
+	for _, tt := range tests {
+		if got, err := IOCCountryCode(tt.code); err != nil || got != tt.want {
+			t.Errorf("IOCCountryCode(%q) = %q, %v; want %q", tt.code, got, err, tt.want)
+		}
+	}
+	if _, err := IOCCountryCode("XYZ"); !errors.Is(err, ErrValidation) {
+		t.Errorf("IOCCountryCode(XYZ) error = %v, want ErrValidation", err)
+	}
+
+	// Every IOC code maps back to its ISO code unless ISO claims it
+	for iso, ioc := range iocCodes {
+		if !iso3166Alpha3[iso] {
+			t.Errorf("iocCodes key %s is not an ISO 3166-1 alpha-3 code", iso)
+		}
+		if got, _ := NormalizeCountryCode(ioc); got != iso && !iso3166Alpha3[ioc] {
+			t.Errorf("NormalizeCountryCode(%q) = %q, want %q", ioc, got, iso)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ptd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// matchCSVHeaders are the columns produced by Match.ToCSVRow
+var matchCSVHeaders = []string{
+	"event_id", "round_id", "bracket_id", "match_number", "scheduled_at",
+	"court", "status", "home_entry_id", "home_name", "away_entry_id",
+	"away_name", "winner", "score_final", "score_sets", "duration_minutes",
+	"walkover", "retirement", "disqualify",
+}
+
+// CSVHeaders returns the column names for Match.ToCSVRow
+func (m *Match) CSVHeaders() []string {
+	return append([]string(nil), matchCSVHeaders...)
+}
+
+// ToCSVRow returns the match as CSV values in CSVHeaders order. Nil fields
+// produce empty values; sets are written space-separated as "11-9 8-11" and
+// times in RFC 3339.
+func (m *Match) ToCSVRow() []string {
+	var scheduledAt string
+	if m.ScheduledAt != nil {
+		scheduledAt = m.ScheduledAt.Format(time.RFC3339)
+	}
+
+	var homeID, homeName, awayID, awayName string
+	if m.HomeEntry != nil {
+		homeID, homeName = m.HomeEntry.EntryID, m.HomeEntry.DisplayName
+	}
+	if m.AwayEntry != nil {
+		awayID, awayName = m.AwayEntry.EntryID, m.AwayEntry.DisplayName
+	}
+
+	var final, sets, duration, walkover, retirement, disqualify string
+	if m.Score != nil {
+		final = m.Score.Final
+		setScores := make([]string, len(m.Score.Sets))
+		for i, set := range m.Score.Sets {
+			setScores[i] = fmt.Sprintf("%d-%d", set.HomeScore, set.AwayScore)
+		}
+		sets = strings.Join(setScores, " ")
+		if m.Score.Duration != nil {
+			duration = strconv.Itoa(m.Score.Duration.Minutes)
+		}
+		walkover = strconv.FormatBool(m.Score.Walkover)
+		retirement = strconv.FormatBool(m.Score.Retirement)
+		disqualify = strconv.FormatBool(m.Score.Disqualify)
+	}
+
+	return []string{
+		m.EventID, m.RoundID, m.BracketID, m.MatchNumber, scheduledAt,
+		m.Court, m.Status, homeID, homeName, awayID,
+		awayName, m.Winner, final, sets, duration,
+		walkover, retirement, disqualify,
+	}
+}
@@ -0,0 +1,77 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestMatch_ToCSVRow(t *testing.T) {
+	scheduled := time.Date(2025, 7, 1, 10, 30, 0, 0, time.UTC)
+	score := newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{11, 5})
+	score.Duration = &Duration{Minutes: 42}
+	match := Match{
+		EventID:     "ptd:event:1",
+		MatchNumber: "M1",
+		ScheduledAt: &scheduled,
+		Court:       "1",
+		Status:      "completed",
+		HomeEntry:   &EntryRef{EntryID: "ptd:entry:1", DisplayName: "Ma, Long"},
+		AwayEntry:   &EntryRef{EntryID: "ptd:entry:2", DisplayName: "Fan Zhendong"},
+		Winner:      "ptd:entry:1",
+		Score:       score,
+	}
+
+	headers := match.CSVHeaders()
+	row := match.ToCSVRow()
+	if len(headers) != len(row) {
+		t.Fatalf("Header and row lengths differ: %d vs %d", len(headers), len(row))
+	}
+
+	values := make(map[string]string)
+	for i, h := range headers {
+		values[h] = row[i]
+	}
+	for column, want := range map[string]string{
+		"scheduled_at":     "2025-07-01T10:30:00Z",
+		"home_name":        "Ma, Long",
+		"score_final":      "3-1",
+		"score_sets":       "11-9 8-11 11-7 11-5",
+		"duration_minutes": "42",
+		"walkover":         "false",
+	} {
+		if values[column] != want {
+			t.Errorf("%s = %q, want %q", column, values[column], want)
+		}
+	}
+
+	// Headers are a copy
+	headers[0] = "changed"
+	if match.CSVHeaders()[0] != "event_id" {
+		t.Error("CSVHeaders should return a fresh slice")
+	}
+
+	// Nil pointers produce empty columns
+	empty := Match{EventID: "ptd:event:1", Status: "scheduled"}
+	for i, value := range empty.ToCSVRow() {
+		switch matchCSVHeaders[i] {
+		case "event_id", "status":
+		default:
+			if value != "" {
+				t.Errorf("%s should be empty, got %q", matchCSVHeaders[i], value)
+			}
+		}
+	}
+
+	// Rows round trip through encoding/csv
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(match.CSVHeaders())
+	w.Write(match.ToCSVRow())
+	w.Flush()
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 2 || records[1][8] != "Ma, Long" {
+		t.Errorf("CSV round trip failed: %v %v", records, err)
+	}
+}
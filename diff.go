@@ -0,0 +1,164 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ChangeType describes how an entity differs between two package versions
+type ChangeType string
+
+// Entity change types
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// FieldChange is a difference in one field of an envelope. Values are in
+// their decoded JSON form; a nil Old or New means the field is absent.
+type FieldChange struct {
+	Path string      `json:"path"` // e.g., "spec.score.sets[2].home_score"
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// EnvelopeDiff describes how one entity changed
+type EnvelopeDiff struct {
+	EntityID   string        `json:"entity_id"`
+	EntityType string        `json:"entity_type"`
+	Change     ChangeType    `json:"change"`
+	Fields     []FieldChange `json:"fields,omitempty"` // Only for ChangeModified
+}
+
+// DiffEnvelopes compares two envelopes field by field in their JSON form and
+// returns the changed fields ordered by path. Arrays are compared element by
+// element.
+func DiffEnvelopes(old, updated interface{}) ([]FieldChange, error) {
+	oldValue, err := toJSONValue(old)
+	if err != nil {
+		return nil, err
+	}
+	newValue, err := toJSONValue(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	diffJSONValues("", oldValue, newValue, &changes)
+	return changes, nil
+}
+
+// DiffPackageEntities compares the entities of one type in two versions of
+// a package, matching them by ID. Entities only in updated are additions,
+// entities only in old are deletions, and entities in both are compared with
+// DiffEnvelopes; unchanged entities are omitted. Diffs are ordered by entity
+// ID. Packages may be working copies or opened archives.
+func DiffPackageEntities(old, updated *Package, entityType string) ([]EnvelopeDiff, error) {
+	oldEntities, err := entitiesByID(old, entityType)
+	if err != nil {
+		return nil, err
+	}
+	newEntities, err := entitiesByID(updated, entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(oldEntities)+len(newEntities))
+	for id := range oldEntities {
+		ids = append(ids, id)
+	}
+	for id := range newEntities {
+		if _, ok := oldEntities[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var diffs []EnvelopeDiff
+	for _, id := range ids {
+		oldLine, inOld := oldEntities[id]
+		newLine, inNew := newEntities[id]
+		switch {
+		case !inOld:
+			diffs = append(diffs, EnvelopeDiff{EntityID: id, EntityType: entityType, Change: ChangeAdded})
+		case !inNew:
+			diffs = append(diffs, EnvelopeDiff{EntityID: id, EntityType: entityType, Change: ChangeRemoved})
+		default:
+			fields, err := DiffEnvelopes(oldLine, newLine)
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s %s: %w", entityType, id, err)
+			}
+			if len(fields) > 0 {
+				diffs = append(diffs, EnvelopeDiff{EntityID: id, EntityType: entityType, Change: ChangeModified, Fields: fields})
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// entitiesByID reads a package's entity lines keyed by envelope ID. Later
+// lines replace earlier ones with the same ID.
+func entitiesByID(pkg *Package, entityType string) (map[string]json.RawMessage, error) {
+	if pkg == nil {
+		return nil, fmt.Errorf("%w: package is nil", ErrInvalidPackage)
+	}
+	lines, err := pkg.readEntityLines(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make(map[string]json.RawMessage, len(lines))
+	for _, line := range lines {
+		id, err := envelopeID(line)
+		if err != nil {
+			return nil, err
+		}
+		entities[id] = line
+	}
+	return entities, nil
+}
+
+// diffJSONValues appends the differences between two decoded JSON values
+func diffJSONValues(path string, old, updated interface{}, changes *[]FieldChange) {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		if n, ok := updated.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(o)+len(n))
+			for key := range o {
+				keys = append(keys, key)
+			}
+			for key := range n {
+				if _, ok := o[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				diffJSONValues(joinFieldPath(path, key), o[key], n[key], changes)
+			}
+			return
+		}
+	case []interface{}:
+		if n, ok := updated.([]interface{}); ok {
+			for i := 0; i < max(len(o), len(n)); i++ {
+				var oldItem, newItem interface{}
+				if i < len(o) {
+					oldItem = o[i]
+				}
+				if i < len(n) {
+					newItem = n[i]
+				}
+				diffJSONValues(fmt.Sprintf("%s[%d]", path, i), oldItem, newItem, changes)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(old, updated) {
+		*changes = append(*changes, FieldChange{Path: path, Old: old, New: updated})
+	}
+}
@@ -0,0 +1,121 @@
+package ptd
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiffEnvelopes(t *testing.T) {
+	old := Envelope[Match]{ID: "ptd:match:1", Type: TypeMatch, Spec: Match{
+		EventID: "e", MatchNumber: "M1", Status: "in_progress",
+		Score: newTestScore("1-0", [2]int{11, 9}),
+	}}
+	updated := old
+	updated.Spec.Status = "completed"
+	updated.Spec.Score = newTestScore("2-0", [2]int{11, 9}, [2]int{11, 4})
+
+	changes, err := DiffEnvelopes(old, updated)
+	if err != nil {
+		t.Fatalf("DiffEnvelopes failed: %v", err)
+	}
+
+	want := []string{"spec.score.final", "spec.score.sets[1]", "spec.status"}
+	if len(changes) != len(want) {
+		t.Fatalf("Expected %d changes, got %+v", len(want), changes)
+	}
+	for i, path := range want {
+		if changes[i].Path != path {
+			t.Errorf("Change %d: expected path %s, got %s", i, path, changes[i].Path)
+		}
+	}
+	if changes[0].Old != "1-0" || changes[0].New != "2-0" {
+		t.Errorf("Unexpected final change: %+v", changes[0])
+	}
+	if changes[1].Old != nil || changes[1].New == nil {
+		t.Errorf("Expected added set, got %+v", changes[1])
+	}
+
+	if changes, _ := DiffEnvelopes(old, old); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffPackageEntities(t *testing.T) {
+	oldPkg, ids := newEditTestPackage(t, 3)
+	newPkg, err := oldPkg.Repack(RepackOptions{})
+	if err != nil {
+		t.Fatalf("Repack failed: %v", err)
+	}
+	defer newPkg.Cleanup()
+
+	// Modify the first event, delete the second and add a fourth
+	if err := newPkg.ReplaceEntity(TypeEvent, Envelope[Event]{
+		ID: ids[0], Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Renamed"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 2},
+	}); err != nil {
+		t.Fatalf("ReplaceEntity failed: %v", err)
+	}
+	if _, err := newPkg.DeleteEntities(TypeEvent, []string{ids[1]}); err != nil {
+		t.Fatalf("DeleteEntities failed: %v", err)
+	}
+	added := GenerateID(TypeEvent)
+	if _, err := newPkg.UpsertEntity(TypeEvent, Envelope[Event]{
+		ID: added, Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "New"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+	}); err != nil {
+		t.Fatalf("UpsertEntity failed: %v", err)
+	}
+
+	diffs, err := DiffPackageEntities(oldPkg, newPkg, TypeEvent)
+	if err != nil {
+		t.Fatalf("DiffPackageEntities failed: %v", err)
+	}
+
+	changes := make(map[string]EnvelopeDiff)
+	for i, diff := range diffs {
+		if i > 0 && diffs[i-1].EntityID >= diff.EntityID {
+			t.Errorf("Diffs not ordered by ID: %s before %s", diffs[i-1].EntityID, diff.EntityID)
+		}
+		changes[diff.EntityID] = diff
+	}
+	if len(diffs) != 3 {
+		t.Fatalf("Expected 3 diffs, got %+v", diffs)
+	}
+	if changes[ids[0]].Change != ChangeModified || len(changes[ids[0]].Fields) == 0 {
+		t.Errorf("Expected %s modified, got %+v", ids[0], changes[ids[0]])
+	}
+	if changes[ids[1]].Change != ChangeRemoved {
+		t.Errorf("Expected %s removed, got %+v", ids[1], changes[ids[1]])
+	}
+	if changes[added].Change != ChangeAdded {
+		t.Errorf("Expected %s added, got %+v", added, changes[added])
+	}
+	if _, ok := changes[ids[2]]; ok {
+		t.Errorf("Unchanged entity %s should not be reported", ids[2])
+	}
+
+	var renamed bool
+	for _, field := range changes[ids[0]].Fields {
+		if field.Path == "spec.name" && field.New == "Renamed" {
+			renamed = true
+		}
+	}
+	if !renamed {
+		t.Errorf("Expected spec.name change, got %+v", changes[ids[0]].Fields)
+	}
+
+	// An opened archive compares the same as its working copy
+	archivePath := filepath.Join(t.TempDir(), "new.ptd")
+	if err := newPkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive failed: %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage failed: %v", err)
+	}
+	if openedDiffs, err := DiffPackageEntities(oldPkg, opened, TypeEvent); err != nil || len(openedDiffs) != 3 {
+		t.Errorf("DiffPackageEntities(opened archive) = %d diffs, %v; want 3", len(openedDiffs), err)
+	}
+}
@@ -0,0 +1,179 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+// Draw sheet limits: seven rounds hold a 128-entry draw
+const (
+	MaxDrawRounds  = 7
+	MaxDrawEntries = 128
+)
+
+// DrawSheet is a single-elimination bracket laid out by round. Each round
+// should hold half as many matches as the one before it, with matches 2i and
+// 2i+1 feeding match i of the next round.
+type DrawSheet struct {
+	EventID string      `json:"event_id,omitempty"`
+	Title   string      `json:"title,omitempty"`
+	Rounds  []DrawRound `json:"rounds"`
+}
+
+// DrawRound is one column of a draw sheet
+type DrawRound struct {
+	Name    string      `json:"name,omitempty"` // e.g., "Quarterfinals"
+	Matches []DrawMatch `json:"matches"`
+}
+
+// DrawMatch is a slot in a draw sheet. Nil entries are not yet decided.
+type DrawMatch struct {
+	MatchID   string    `json:"match_id,omitempty"`
+	HomeEntry *EntryRef `json:"home_entry,omitempty"`
+	AwayEntry *EntryRef `json:"away_entry,omitempty"`
+	Winner    string    `json:"winner,omitempty"` // entry_id of winner
+	Score     *Score    `json:"score,omitempty"`
+}
+
+// Draw sheet SVG layout, in pixels
+const (
+	drawPadding        = 20
+	drawTitleSpace     = 30
+	drawRoundNameSpace = 20
+	drawBoxWidth       = 200
+	drawBoxHeight      = 44
+	drawColumnGap      = 40
+	drawRowGap         = 12
+)
+
+// ExportDrawSheetSVG renders the draw as an SVG bracket: a box per match
+// with both entries, lines to the next round, and for completed matches the
+// sets won by each side with the winner in bold. The image grows with the
+// draw size; up to MaxDrawRounds rounds and MaxDrawEntries entries are
+// supported.
+func ExportDrawSheetSVG(draw *DrawSheet) ([]byte, error) {
+	if draw == nil || len(draw.Rounds) == 0 {
+		return nil, fmt.Errorf("%w: draw sheet has no rounds", ErrValidation)
+	}
+	if len(draw.Rounds) > MaxDrawRounds {
+		return nil, fmt.Errorf("%w: draw sheet has %d rounds, at most %d are supported", ErrValidation, len(draw.Rounds), MaxDrawRounds)
+	}
+	if entries := 2 * len(draw.Rounds[0].Matches); entries > MaxDrawEntries {
+		return nil, fmt.Errorf("%w: draw sheet has %d entries, at most %d are supported", ErrValidation, entries, MaxDrawEntries)
+	}
+
+	slot := float64(drawBoxHeight + drawRowGap)
+	top := float64(drawPadding)
+	if draw.Title != "" {
+		top += drawTitleSpace
+	}
+	for _, round := range draw.Rounds {
+		if round.Name != "" {
+			top += drawRoundNameSpace
+			break
+		}
+	}
+
+	// boxTop returns the top edge of match j in round r, centered between its feeders
+	boxTop := func(r, j int) float64 {
+		span := float64(int(1) << r)
+		return top + slot*(span*float64(j)+(span-1)/2)
+	}
+	boxLeft := func(r int) float64 {
+		return float64(drawPadding + r*(drawBoxWidth+drawColumnGap))
+	}
+
+	width := drawPadding*2 + len(draw.Rounds)*drawBoxWidth + (len(draw.Rounds)-1)*drawColumnGap
+	height := int(top) + len(draw.Rounds[0].Matches)*int(slot) - drawRowGap + drawPadding
+	if width < 2*drawPadding+drawBoxWidth {
+		width = 2*drawPadding + drawBoxWidth
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	if draw.Title != "" {
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="16" font-weight="bold">%s</text>`+"\n", drawPadding, drawPadding+16, svgEscape(draw.Title))
+	}
+
+	for r, round := range draw.Rounds {
+		x := boxLeft(r)
+		if round.Name != "" {
+			fmt.Fprintf(&buf, `<text x="%.0f" y="%.0f" fill="#555555">%s</text>`+"\n", x, top-8, svgEscape(round.Name))
+		}
+
+		for j, match := range round.Matches {
+			y := boxTop(r, j)
+
+			// Connector to the next round
+			if r+1 < len(draw.Rounds) && j/2 < len(draw.Rounds[r+1].Matches) {
+				fromX := x + drawBoxWidth
+				fromY := y + drawBoxHeight/2
+				toX := boxLeft(r + 1)
+				toY := boxTop(r+1, j/2) + drawBoxHeight/2
+				midX := fromX + drawColumnGap/2
+				fmt.Fprintf(&buf, `<path d="M%.1f %.1f H%.1f V%.1f H%.1f" fill="none" stroke="#888888"/>`+"\n", fromX, fromY, midX, toY, toX)
+			}
+
+			fmt.Fprintf(&buf, `<g class="match"%s>`+"\n", svgAttr("data-match-id", match.MatchID))
+			fmt.Fprintf(&buf, `<rect x="%.1f" y="%.1f" width="%d" height="%d" fill="#f7f7f7" stroke="#333333"/>`+"\n", x, y, drawBoxWidth, drawBoxHeight)
+			fmt.Fprintf(&buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#cccccc"/>`+"\n", x, y+drawBoxHeight/2, x+drawBoxWidth, y+drawBoxHeight/2)
+
+			var homeSets, awaySets int
+			completed := match.Score != nil && match.Winner != ""
+			if completed {
+				homeSets, awaySets = match.Score.SetWins()
+			}
+			for side, ref := range []*EntryRef{match.HomeEntry, match.AwayEntry} {
+				textY := y + float64(side*drawBoxHeight/2) + 15
+				weight := ""
+				if ref != nil && match.Winner != "" && ref.EntryID == match.Winner {
+					weight = ` font-weight="bold"`
+				}
+				fmt.Fprintf(&buf, `<text x="%.1f" y="%.1f"%s>%s</text>`+"\n", x+6, textY, weight, svgEscape(drawEntryName(ref)))
+				if completed {
+					sets := homeSets
+					if side == 1 {
+						sets = awaySets
+					}
+					fmt.Fprintf(&buf, `<text x="%.1f" y="%.1f" text-anchor="end"%s>%d</text>`+"\n", x+drawBoxWidth-6, textY, weight, sets)
+				}
+			}
+			buf.WriteString("</g>\n")
+		}
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// drawEntryName returns the name shown for an entry in a draw sheet
+func drawEntryName(ref *EntryRef) string {
+	switch {
+	case ref == nil:
+		return "TBD"
+	case ref.DisplayName != "" && ref.Seed != nil:
+		return fmt.Sprintf("[%d] %s", *ref.Seed, ref.DisplayName)
+	case ref.DisplayName != "":
+		return ref.DisplayName
+	default:
+		return ref.EntryID
+	}
+}
+
+// svgEscape escapes text for use in SVG content and attribute values
+func svgEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// svgAttr formats an optional attribute, omitting it when value is empty
+func svgAttr(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, name, svgEscape(value))
+}
@@ -0,0 +1,105 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// newTestDrawSheet builds a bracket for the given number of entries with
+// the first round completed by the home entries
+func newTestDrawSheet(entries int) *DrawSheet {
+	draw := &DrawSheet{Title: fmt.Sprintf("Draw of %d", entries)}
+	var first DrawRound
+	for i := 0; i < entries/2; i++ {
+		home := &EntryRef{EntryID: fmt.Sprintf("ptd:entry:%d", 2*i), DisplayName: fmt.Sprintf("Player %d", 2*i)}
+		away := &EntryRef{EntryID: fmt.Sprintf("ptd:entry:%d", 2*i+1), DisplayName: fmt.Sprintf("Player %d", 2*i+1)}
+		first.Matches = append(first.Matches, DrawMatch{
+			MatchID:   fmt.Sprintf("ptd:match:%d", i),
+			HomeEntry: home,
+			AwayEntry: away,
+			Winner:    home.EntryID,
+			Score:     newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{11, 5}),
+		})
+	}
+	draw.Rounds = append(draw.Rounds, first)
+	for n := entries / 4; n >= 1; n /= 2 {
+		draw.Rounds = append(draw.Rounds, DrawRound{Matches: make([]DrawMatch, n)})
+	}
+	return draw
+}
+
+// checkWellFormed decodes the whole document to verify it is well-formed XML
+func checkWellFormed(t *testing.T, svg []byte) {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if err == io.EOF {
+				return
+			}
+			t.Fatalf("SVG is not well-formed: %v", err)
+		}
+	}
+}
+
+func TestExportDrawSheetSVG(t *testing.T) {
+	for _, entries := range []int{4, 16} {
+		t.Run(fmt.Sprintf("%d players", entries), func(t *testing.T) {
+			draw := newTestDrawSheet(entries)
+			svg, err := ExportDrawSheetSVG(draw)
+			if err != nil {
+				t.Fatalf("ExportDrawSheetSVG failed: %v", err)
+			}
+
+			out := strings.TrimSpace(string(svg))
+			if !strings.HasPrefix(out, "<svg") || !strings.HasSuffix(out, "</svg>") {
+				t.Fatalf("Output is not an SVG document: %.40q", out)
+			}
+			checkWellFormed(t, svg)
+
+			if n := strings.Count(out, `<g class="match"`); n != entries-1 {
+				t.Errorf("Expected %d match boxes, got %d", entries-1, n)
+			}
+			if n := strings.Count(out, "<path "); n != entries-2 {
+				t.Errorf("Expected %d connectors, got %d", entries-2, n)
+			}
+			if !strings.Contains(out, `font-weight="bold">Player 0</text>`) {
+				t.Error("Winner should be bold")
+			}
+			if !strings.Contains(out, ">TBD</text>") {
+				t.Error("Undecided slots should show TBD")
+			}
+		})
+	}
+}
+
+func TestExportDrawSheetSVG_Escaping(t *testing.T) {
+	draw := &DrawSheet{Rounds: []DrawRound{{Name: "Final", Matches: []DrawMatch{{
+		HomeEntry: &EntryRef{EntryID: "a", DisplayName: "Tom & <Jerry>"},
+	}}}}}
+	svg, err := ExportDrawSheetSVG(draw)
+	if err != nil {
+		t.Fatalf("ExportDrawSheetSVG failed: %v", err)
+	}
+	checkWellFormed(t, svg)
+	if !strings.Contains(string(svg), "Tom &amp; &lt;Jerry&gt;") {
+		t.Error("Names should be escaped")
+	}
+}
+
+func TestExportDrawSheetSVG_Limits(t *testing.T) {
+	if _, err := ExportDrawSheetSVG(&DrawSheet{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for empty draw, got %v", err)
+	}
+	if _, err := ExportDrawSheetSVG(newTestDrawSheet(256)); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for 256-entry draw, got %v", err)
+	}
+	if _, err := ExportDrawSheetSVG(newTestDrawSheet(128)); err != nil {
+		t.Errorf("128-entry draw should be supported: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package ptd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ELO rating defaults
+const (
+	DefaultELOKFactor = 32   // Rating change for a fully unexpected result
+	DefaultELORating  = 1500 // Rating of players without one
+	ELORatingSystem   = "ELO"
+)
+
+// eloScaleSystems are the rating systems whose ratings share the Elo scale
+var eloScaleSystems = map[string]bool{"FIDE": true, "ELO": true, "USATT": true}
+
+// ELOCalculator computes Elo rating changes after matches
+type ELOCalculator struct {
+	KFactor float64 // Maximum change per match; DefaultELOKFactor if not positive
+}
+
+// ExpectedScore returns the probability, from 0 to 1, that a player rated
+// ratingA beats a player rated ratingB
+func (c *ELOCalculator) ExpectedScore(ratingA, ratingB float64) float64 {
+	return 1 / (1 + math.Pow(10, (ratingB-ratingA)/400))
+}
+
+// UpdateRatings applies the result of a match between playerA (home) and
+// playerB (away) to their ratings and returns the new ratings. Ratings of
+// Elo-scale systems (ELO, FIDE and USATT) are used as they are; players
+// without one, such as those with ITTF ranking points, start at
+// DefaultELORating. The new ratings are stored rounded to whole points
+// with the system "ELO".
+func (c *ELOCalculator) UpdateRatings(playerA, playerB *Player, homeWon bool) (newRatingA, newRatingB float64) {
+	ratingA, ratingB := eloRating(playerA), eloRating(playerB)
+	deltaA := c.delta(ratingA, ratingB, homeWon)
+	newRatingA, newRatingB = ratingA+deltaA, ratingB-deltaA
+
+	now := time.Now()
+	setELORating(playerA, newRatingA, now)
+	setELORating(playerB, newRatingB, now)
+	return newRatingA, newRatingB
+}
+
+// delta returns the rating change of player A after a match against B;
+// B's rating changes by the same amount in the other direction
+func (c *ELOCalculator) delta(ratingA, ratingB float64, aWon bool) float64 {
+	k := c.KFactor
+	if k <= 0 {
+		k = DefaultELOKFactor
+	}
+	var score float64
+	if aWon {
+		score = 1
+	}
+	return k * (score - c.ExpectedScore(ratingA, ratingB))
+}
+
+// ApplyELOUpdates applies the results of the completed matches, in the
+// order they were played, to the ratings of the players and returns the
+// updated player envelopes. Players are found through the players of each
+// match's entries, matched by PlayerID or by name.
+//
+// A doubles or team entry is rated by the average of its players, and each
+// of its players gains or loses the entry's rating change. Players of an
+// entry that are not among players count toward the average with their own
+// rating but are not returned. Walkovers and matches without a winner are
+// skipped. Matches are ordered by EndedAt, StartedAt or ScheduledAt,
+// whichever is set first; matches with none are applied last, in the
+// order given.
+//
+// Players without an Elo-scale rating start at DefaultELORating, as in
+// UpdateRatings. Ratings are carried between matches unrounded and stored
+// rounded to whole points with the system "ELO", bumping the version of
+// each updated player.
+// Returns ErrValidation if kFactor is not positive and ErrInvalidID if a
+// match refers to an entry that is not in entries.
+func ApplyELOUpdates(matches []Envelope[Match], entries []Envelope[Entry], players []Envelope[Player], kFactor float64) ([]Envelope[Player], error) {
+	if kFactor <= 0 {
+		return nil, fmt.Errorf("%w: K-factor must be positive, got %v", ErrValidation, kFactor)
+	}
+	calculator := &ELOCalculator{KFactor: kFactor}
+
+	entryByID := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		entryByID[entry.ID] = entry.Spec
+	}
+	byIdentity := make(map[string]int, len(players)) // player identity -> index into players
+	ratings := make([]float64, len(players))
+	for i, player := range players {
+		byIdentity[playerIdentity(player.Spec)] = i
+		ratings[i] = eloRating(&player.Spec)
+	}
+
+	completed := make([]Match, 0, len(matches))
+	for _, match := range matches {
+		if match.Spec.Status == "completed" {
+			completed = append(completed, match.Spec)
+		}
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		a, b := matchPlayedAt(completed[i]), matchPlayedAt(completed[j])
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.Before(*b)
+	})
+
+	// Average rating of an entry, and the indexes of its known players
+	entryRating := func(ref *EntryRef) (float64, []int, error) {
+		entry, ok := entryByID[ref.EntryID]
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: entry %s not found", ErrInvalidID, ref.EntryID)
+		}
+		if len(entry.Players) == 0 {
+			return DefaultELORating, nil, nil
+		}
+		var total float64
+		var known []int
+		for _, player := range entry.Players {
+			if i, ok := byIdentity[playerIdentity(player)]; ok {
+				total += ratings[i]
+				known = append(known, i)
+			} else {
+				total += eloRating(&player)
+			}
+		}
+		return total / float64(len(entry.Players)), known, nil
+	}
+
+	updatedAt := make(map[int]time.Time)
+	for _, m := range completed {
+		if m.HomeEntry == nil || m.AwayEntry == nil || (m.Score != nil && m.Score.Walkover) {
+			continue
+		}
+		winner, err := m.winnerRef()
+		if err != nil {
+			continue
+		}
+		homeRating, homePlayers, err := entryRating(m.HomeEntry)
+		if err != nil {
+			return nil, err
+		}
+		awayRating, awayPlayers, err := entryRating(m.AwayEntry)
+		if err != nil {
+			return nil, err
+		}
+
+		delta := calculator.delta(homeRating, awayRating, winner.EntryID == m.HomeEntry.EntryID)
+		playedAt := time.Now()
+		if at := matchPlayedAt(m); at != nil {
+			playedAt = *at
+		}
+		for _, i := range homePlayers {
+			ratings[i] += delta
+			updatedAt[i] = playedAt
+		}
+		for _, i := range awayPlayers {
+			ratings[i] -= delta
+			updatedAt[i] = playedAt
+		}
+	}
+
+	updated := make([]Envelope[Player], len(players))
+	for i, player := range players {
+		at, ok := updatedAt[i]
+		if !ok {
+			updated[i] = player
+			continue
+		}
+		bumped := player.BumpVersion()
+		setELORating(&bumped.Spec, ratings[i], at)
+		updated[i] = *bumped
+	}
+	return updated, nil
+}
+
+// isELOScale reports whether a rating is on the Elo scale
+func isELOScale(rating *Rating) bool {
+	return rating != nil && eloScaleSystems[strings.ToUpper(rating.System)]
+}
+
+// eloRating returns a player's Elo-scale rating, or DefaultELORating if
+// the player has none
+func eloRating(player *Player) float64 {
+	if player == nil || !isELOScale(player.Rating) {
+		return DefaultELORating
+	}
+	return float64(player.Rating.Value)
+}
+
+// setELORating stores an Elo rating on a player, rounded to whole points
+func setELORating(player *Player, rating float64, at time.Time) {
+	if player == nil {
+		return
+	}
+	player.Rating = &Rating{Value: int(math.Round(rating)), System: ELORatingSystem, UpdatedAt: at}
+}
+
+// matchPlayedAt returns when a match was played, or nil if unknown
+func matchPlayedAt(m Match) *time.Time {
+	switch {
+	case m.EndedAt != nil:
+		return m.EndedAt
+	case m.StartedAt != nil:
+		return m.StartedAt
+	default:
+		return m.ScheduledAt
+	}
+}
@@ -0,0 +1,152 @@
+package ptd
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestELOCalculator_ExpectedScore(t *testing.T) {
+	c := &ELOCalculator{KFactor: 32}
+	if got := c.ExpectedScore(1500, 1500); got != 0.5 {
+		t.Errorf("ExpectedScore(equal) = %v, want 0.5", got)
+	}
+	if got := c.ExpectedScore(1900, 1500); math.Abs(got-10.0/11) > 1e-9 {
+		t.Errorf("ExpectedScore(+400) = %v, want %v", got, 10.0/11)
+	}
+	if sum := c.ExpectedScore(1620, 1480) + c.ExpectedScore(1480, 1620); math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected scores sum to %v, want 1", sum)
+	}
+}
+
+func TestELOCalculator_UpdateRatings(t *testing.T) {
+	c := &ELOCalculator{KFactor: 32}
+	home := &Player{LastName: "Home"}
+	away := &Player{LastName: "Away", Rating: &Rating{Value: 1500, System: "USATT"}}
+
+	newHome, newAway := c.UpdateRatings(home, away, true)
+	if newHome != 1516 || newAway != 1484 {
+		t.Errorf("UpdateRatings() = %v, %v; want 1516, 1484", newHome, newAway)
+	}
+	if home.Rating == nil || home.Rating.Value != 1516 || home.Rating.System != ELORatingSystem {
+		t.Errorf("home rating = %+v, want 1516 ELO", home.Rating)
+	}
+	if away.Rating.Value != 1484 || away.Rating.System != ELORatingSystem {
+		t.Errorf("away rating = %+v, want 1484 ELO", away.Rating)
+	}
+
+	// Ratings on another scale are not Elo ratings
+	ranked := &Player{LastName: "Ranked", Rating: &Rating{Value: 9000, System: "ITTF"}}
+	rated := &Player{LastName: "Rated", Rating: &Rating{Value: 1500, System: "FIDE"}}
+	newRanked, _ := c.UpdateRatings(ranked, rated, true)
+	if newRanked != 1516 || ranked.Rating.Value != 1516 || ranked.Rating.System != ELORatingSystem {
+		t.Errorf("ITTF-ranked player = %v, %+v; want 1516 ELO from the default rating", newRanked, ranked.Rating)
+	}
+
+	// An upset moves ratings further than an expected result
+	c = &ELOCalculator{}
+	strong := &Player{Rating: &Rating{Value: 1800, System: ELORatingSystem}}
+	weak := &Player{Rating: &Rating{Value: 1400, System: ELORatingSystem}}
+	_, upset := c.UpdateRatings(strong, weak, false)
+	if gain := upset - 1400; gain <= 16 || gain >= 32 {
+		t.Errorf("Upset gain = %v, want between 16 and 32", gain)
+	}
+}
+
+func TestApplyELOUpdates(t *testing.T) {
+	player := func(id, name string, rating int) Envelope[Player] {
+		p := Player{LastName: name, PlayerID: id}
+		if rating > 0 {
+			p.Rating = &Rating{Value: rating, System: ELORatingSystem}
+		}
+		return Envelope[Player]{ID: "ptd:player:" + id, Type: TypePlayer, Spec: p, Meta: Meta{Version: 1}}
+	}
+	players := []Envelope[Player]{
+		player("a", "Alpha", 1500),
+		player("b", "Bravo", 0),
+		player("c", "Charlie", 1600),
+		player("d", "Delta", 1700),
+	}
+	entry := func(id string, members ...Envelope[Player]) Envelope[Entry] {
+		e := Entry{EventID: "ptd:event:1"}
+		for _, m := range members {
+			e.Players = append(e.Players, m.Spec)
+		}
+		return Envelope[Entry]{ID: id, Type: TypeEntry, Spec: e}
+	}
+	guest := Player{LastName: "Guest", Rating: &Rating{Value: 1400, System: "FIDE"}}
+	entries := []Envelope[Entry]{
+		entry("ptd:entry:a", players[0]),
+		entry("ptd:entry:b", players[1]),
+		entry("ptd:entry:c", players[2]),
+		{ID: "ptd:entry:dg", Spec: Entry{Players: []Player{players[3].Spec, guest}}},
+	}
+
+	day := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	match := func(home, away, winner string, hour int) Envelope[Match] {
+		ended := day.Add(time.Duration(hour) * time.Hour)
+		return Envelope[Match]{Type: TypeMatch, Spec: Match{
+			Status: "completed", EndedAt: &ended, Winner: winner,
+			HomeEntry: &EntryRef{EntryID: home}, AwayEntry: &EntryRef{EntryID: away},
+		}}
+	}
+	walkover := match("ptd:entry:c", "ptd:entry:b", "ptd:entry:c", 12)
+	walkover.Spec.Score = &Score{Walkover: true}
+	scheduled := match("ptd:entry:a", "ptd:entry:c", "", 13)
+	scheduled.Spec.Status = "scheduled"
+	matches := []Envelope[Match]{
+		match("ptd:entry:a", "ptd:entry:c", "ptd:entry:a", 11), // Played second
+		match("ptd:entry:b", "ptd:entry:a", "ptd:entry:b", 10), // Played first
+		match("ptd:entry:dg", "ptd:entry:b", "ptd:entry:b", 14),
+		walkover,
+		scheduled,
+	}
+
+	updated, err := ApplyELOUpdates(matches, entries, players, 32)
+	if err != nil {
+		t.Fatalf("ApplyELOUpdates() error = %v", err)
+	}
+
+	// Replay the expected sequence with the calculator
+	c := &ELOCalculator{KFactor: 32}
+	a, b, cr, d := 1500.0, 1500.0, 1600.0, 1700.0
+	delta := c.delta(b, a, true)
+	b, a = b+delta, a-delta
+	delta = c.delta(a, cr, true)
+	a, cr = a+delta, cr-delta
+	delta = c.delta((d+1400)/2, b, false)
+	d, b = d+delta, b-delta
+
+	want := map[string]float64{"a": a, "b": b, "c": cr, "d": d}
+	for _, p := range updated {
+		id := p.Spec.PlayerID
+		if p.Spec.Rating == nil || p.Spec.Rating.Value != int(math.Round(want[id])) {
+			t.Errorf("%s rating = %+v, want %v", id, p.Spec.Rating, math.Round(want[id]))
+			continue
+		}
+		if p.Meta.Version != 2 {
+			t.Errorf("%s version = %d, want 2", id, p.Meta.Version)
+		}
+	}
+	if got := updated[3].Spec.Rating.UpdatedAt; !got.Equal(day.Add(14 * time.Hour)) {
+		t.Errorf("Delta's rating updated at %v, want the match end time", got)
+	}
+	if players[0].Spec.Rating.Value != 1500 || players[1].Spec.Rating != nil {
+		t.Error("ApplyELOUpdates() modified its input")
+	}
+}
+
+func TestApplyELOUpdates_Errors(t *testing.T) {
+	if _, err := ApplyELOUpdates(nil, nil, nil, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("ApplyELOUpdates(k=0) error = %v, want ErrValidation", err)
+	}
+
+	matches := []Envelope[Match]{{Spec: Match{
+		Status: "completed", Winner: "ptd:entry:x",
+		HomeEntry: &EntryRef{EntryID: "ptd:entry:x"}, AwayEntry: &EntryRef{EntryID: "ptd:entry:y"},
+	}}}
+	if _, err := ApplyELOUpdates(matches, nil, nil, 32); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("ApplyELOUpdates(unknown entry) error = %v, want ErrInvalidID", err)
+	}
+}
@@ -0,0 +1,160 @@
+package ptd
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// DefaultCheckInLead is how long before an event starts players must check in
+const DefaultCheckInLead = 30 * time.Minute
+
+// EntryConfirmation is the data available to entry confirmation templates
+type EntryConfirmation struct {
+	PlayerName      string
+	EventName       string
+	TournamentName  string
+	StartDate       time.Time
+	EndDate         time.Time
+	Venue           string
+	EntryType       string
+	Seed            *int
+	CheckInDeadline time.Time
+}
+
+// EmailTemplate overrides the default subject and body of a generated email.
+// Templates use text/template syntax with EntryConfirmation as data; for
+// HTML emails the body is executed with html/template, so values are escaped.
+type EmailTemplate struct {
+	subject  *texttemplate.Template
+	textBody *texttemplate.Template
+	htmlBody *htmltemplate.Template
+}
+
+// NewEmailTemplate parses a subject and body template
+func NewEmailTemplate(subject, body string) (*EmailTemplate, error) {
+	s, err := texttemplate.New("subject").Parse(subject)
+	if err != nil {
+		return nil, err
+	}
+	t, err := texttemplate.New("body").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	h, err := htmltemplate.New("body").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	return &EmailTemplate{subject: s, textBody: t, htmlBody: h}, nil
+}
+
+const defaultEntrySubject = `Entry confirmed: {{.EventName}}{{if .TournamentName}} - {{.TournamentName}}{{end}}`
+
+const defaultEntryTextBody = `Dear {{.PlayerName}},
+
+Your {{.EntryType}} entry for {{.EventName}} has been received.
+
+Tournament: {{.TournamentName}}
+Dates: {{.StartDate.Format "Mon 2 Jan 2006"}}{{if not (.EndDate.Equal .StartDate)}} - {{.EndDate.Format "Mon 2 Jan 2006"}}{{end}}
+{{- if .Venue}}
+Venue: {{.Venue}}
+{{- end}}
+Entry type: {{.EntryType}}
+{{- if .Seed}}
+Seed: {{.Seed}}
+{{- end}}
+
+Please check in by {{.CheckInDeadline.Format "Mon 2 Jan 2006 15:04 MST"}}.
+
+Good luck!
+`
+
+const defaultEntryHTMLBody = `<p>Dear {{.PlayerName}},</p>
+<p>Your {{.EntryType}} entry for <strong>{{.EventName}}</strong> has been received.</p>
+<dl>
+  <dt>Tournament</dt><dd>{{.TournamentName}}</dd>
+  <dt>Dates</dt><dd>{{.StartDate.Format "Mon 2 Jan 2006"}}{{if not (.EndDate.Equal .StartDate)}} &ndash; {{.EndDate.Format "Mon 2 Jan 2006"}}{{end}}</dd>
+{{- if .Venue}}
+  <dt>Venue</dt><dd>{{.Venue}}</dd>
+{{- end}}
+  <dt>Entry type</dt><dd>{{.EntryType}}</dd>
+{{- if .Seed}}
+  <dt>Seed</dt><dd>{{.Seed}}</dd>
+{{- end}}
+</dl>
+<p>Please check in by <strong>{{.CheckInDeadline.Format "Mon 2 Jan 2006 15:04 MST"}}</strong>.</p>
+<p>Good luck!</p>
+`
+
+// defaultEntryTemplate is the built-in entry confirmation template
+var defaultEntryTemplate = &EmailTemplate{
+	subject:  texttemplate.Must(texttemplate.New("subject").Parse(defaultEntrySubject)),
+	textBody: texttemplate.Must(texttemplate.New("body").Parse(defaultEntryTextBody)),
+	htmlBody: htmltemplate.Must(htmltemplate.New("body").Parse(defaultEntryHTMLBody)),
+}
+
+// NewEntryConfirmation collects the template data for an entry confirmation.
+// The event's dates are used when set, otherwise the tournament's; the
+// check-in deadline is DefaultCheckInLead before the start.
+func NewEntryConfirmation(entry Envelope[Entry], event Envelope[Event], tournament Envelope[Tournament]) EntryConfirmation {
+	start, end := event.Spec.StartDate, event.Spec.EndDate
+	if start.IsZero() {
+		start, end = tournament.Spec.StartDate, tournament.Spec.EndDate
+	}
+	if end.IsZero() {
+		end = start
+	}
+
+	var seed *int
+	if entry.Spec.Seed != nil {
+		s := *entry.Spec.Seed
+		seed = &s
+	}
+
+	return EntryConfirmation{
+		PlayerName:      entryName(entry.Spec),
+		EventName:       event.Spec.Name.String(),
+		TournamentName:  tournament.Spec.Name.String(),
+		StartDate:       start,
+		EndDate:         end,
+		Venue:           venueLocation(tournament.Spec.Venue),
+		EntryType:       entry.Spec.EntryType,
+		Seed:            seed,
+		CheckInDeadline: start.Add(-DefaultCheckInLead),
+	}
+}
+
+// GenerateEntryConfirmationEmail returns a plain text subject and body
+// confirming an entry. An optional template replaces the default one; if it
+// fails to execute, the default template is used.
+func GenerateEntryConfirmationEmail(entry Envelope[Entry], event Envelope[Event], tournament Envelope[Tournament], override ...*EmailTemplate) (subject, body string) {
+	data := NewEntryConfirmation(entry, event, tournament)
+	for _, tmpl := range append(override, defaultEntryTemplate) {
+		if tmpl == nil {
+			continue
+		}
+		var s, b bytes.Buffer
+		if tmpl.subject.Execute(&s, data) == nil && tmpl.textBody.Execute(&b, data) == nil {
+			return strings.TrimSpace(s.String()), b.String()
+		}
+	}
+	return "", ""
+}
+
+// GenerateEntryConfirmationHTML is GenerateEntryConfirmationEmail with an
+// HTML body. All values in the body are HTML-escaped.
+func GenerateEntryConfirmationHTML(entry Envelope[Entry], event Envelope[Event], tournament Envelope[Tournament], override ...*EmailTemplate) (subject, body string) {
+	data := NewEntryConfirmation(entry, event, tournament)
+	for _, tmpl := range append(override, defaultEntryTemplate) {
+		if tmpl == nil {
+			continue
+		}
+		var s, b bytes.Buffer
+		if tmpl.subject.Execute(&s, data) == nil && tmpl.htmlBody.Execute(&b, data) == nil {
+			return strings.TrimSpace(s.String()), b.String()
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,97 @@
+package ptd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestConfirmation() (Envelope[Entry], Envelope[Event], Envelope[Tournament]) {
+	seed := 2
+	entry := Envelope[Entry]{ID: GenerateID(TypeEntry), Spec: Entry{
+		EntryType: "doubles",
+		Seed:      &seed,
+		Players:   []Player{{FirstName: "Ma", LastName: "Long"}, {FirstName: "Xu", LastName: "Xin"}},
+	}}
+	event := Envelope[Event]{ID: GenerateID(TypeEvent), Spec: Event{
+		Name:      MultiName{Default: "Men's Doubles"},
+		StartDate: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2025, 7, 2, 18, 0, 0, 0, time.UTC),
+	}}
+	tournament := Envelope[Tournament]{ID: GenerateID(TypeTournament), Spec: Tournament{
+		Name:  MultiName{Default: "Summer Open"},
+		Venue: &Venue{Name: MultiName{Default: "Sports <Hall>"}, City: "Springfield"},
+	}}
+	return entry, event, tournament
+}
+
+func TestGenerateEntryConfirmationEmail(t *testing.T) {
+	entry, event, tournament := newTestConfirmation()
+
+	subject, body := GenerateEntryConfirmationEmail(entry, event, tournament)
+	if subject != "Entry confirmed: Men's Doubles - Summer Open" {
+		t.Errorf("Unexpected subject: %s", subject)
+	}
+	for _, want := range []string{
+		"Dear Ma Long / Xu Xin,",
+		"Tournament: Summer Open",
+		"Dates: Tue 1 Jul 2025 - Wed 2 Jul 2025",
+		"Venue: Sports <Hall>, Springfield",
+		"Entry type: doubles",
+		"Seed: 2",
+		"check in by Tue 1 Jul 2025 08:30 UTC",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body missing %q:\n%s", want, body)
+		}
+	}
+
+	entry.Spec.Seed = nil
+	if _, body := GenerateEntryConfirmationEmail(entry, event, tournament); strings.Contains(body, "Seed:") {
+		t.Error("Unseeded entry should not show a seed")
+	}
+}
+
+func TestGenerateEntryConfirmationHTML(t *testing.T) {
+	entry, event, tournament := newTestConfirmation()
+
+	_, body := GenerateEntryConfirmationHTML(entry, event, tournament)
+	if !strings.Contains(body, "<dt>Venue</dt><dd>Sports &lt;Hall&gt;, Springfield</dd>") {
+		t.Errorf("Venue should be escaped:\n%s", body)
+	}
+	if !strings.Contains(body, "<dd>2</dd>") {
+		t.Errorf("Seed missing:\n%s", body)
+	}
+}
+
+func TestEntryConfirmation_OverrideTemplate(t *testing.T) {
+	entry, event, tournament := newTestConfirmation()
+
+	tmpl, err := NewEmailTemplate("You're in: {{.EventName}}", "Hi {{.PlayerName}}, see you at {{.Venue}}.")
+	if err != nil {
+		t.Fatalf("NewEmailTemplate failed: %v", err)
+	}
+
+	subject, body := GenerateEntryConfirmationEmail(entry, event, tournament, tmpl)
+	if subject != "You're in: Men's Doubles" || body != "Hi Ma Long / Xu Xin, see you at Sports <Hall>, Springfield." {
+		t.Errorf("Override not applied: %q / %q", subject, body)
+	}
+
+	_, body = GenerateEntryConfirmationHTML(entry, event, tournament, tmpl)
+	if body != "Hi Ma Long / Xu Xin, see you at Sports &lt;Hall&gt;, Springfield." {
+		t.Errorf("HTML override should escape values: %q", body)
+	}
+
+	if _, err := NewEmailTemplate("{{.EventName", "body"); err == nil {
+		t.Error("Expected parse error for invalid template")
+	}
+
+	// Templates that fail to execute fall back to the default
+	broken, err := NewEmailTemplate("{{.Missing}}", "body")
+	if err != nil {
+		t.Fatalf("NewEmailTemplate failed: %v", err)
+	}
+	if subject, _ := GenerateEntryConfirmationEmail(entry, event, tournament, broken); !strings.HasPrefix(subject, "Entry confirmed") {
+		t.Errorf("Expected default subject, got %q", subject)
+	}
+}
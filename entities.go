@@ -6,63 +6,106 @@ import (
 
 // Tournament represents a tournament entity
 type Tournament struct {
-	Name        string     `json:"name"`
-	Description string     `json:"description,omitempty"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     time.Time  `json:"end_date"`
-	TimeZone    string     `json:"time_zone,omitempty"`
-	Status      string     `json:"status"` // draft, published, in_progress, completed
-	Venue       *Venue     `json:"venue,omitempty"`
-	Organizer   *Organizer `json:"organizer,omitempty"`
-	Format      string     `json:"format,omitempty"` // single_elimination, round_robin, etc.
-	Rules       *Rules     `json:"rules,omitempty"`
-	Website     string     `json:"website,omitempty"`
-	ContactInfo *Contact   `json:"contact_info,omitempty"`
+	Name               MultiName           `json:"name"`
+	Description        string              `json:"description,omitempty"`
+	StartDate          time.Time           `json:"start_date"`
+	EndDate            time.Time           `json:"end_date"`
+	TimeZone           string              `json:"time_zone,omitempty"`
+	Status             string              `json:"status"` // draft, published, in_progress, completed
+	Venue              *Venue              `json:"venue,omitempty"`
+	Organizer          *Organizer          `json:"organizer,omitempty"`
+	Format             string              `json:"format,omitempty"` // single_elimination, round_robin, etc.
+	Rules              *Rules              `json:"rules,omitempty"`
+	Website            string              `json:"website,omitempty"`
+	ContactInfo        *Contact            `json:"contact_info,omitempty"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
 }
 
 // Event represents an event within a tournament
 type Event struct {
-	TournamentID string    `json:"tournament_id"`
-	Name         string    `json:"name"`
-	EventCode    string    `json:"event_code"`       // e.g., "MS", "WD", "XD"
-	EventType    string    `json:"event_type"`       // singles, doubles, team
-	Gender       string    `json:"gender,omitempty"` // male, female, mixed
-	AgeGroup     *AgeGroup `json:"age_group,omitempty"`
-	Format       string    `json:"format,omitempty"` // Can override tournament format
-	MaxEntries   int       `json:"max_entries,omitempty"`
-	EntryFee     *Money    `json:"entry_fee,omitempty"`
-	StartDate    time.Time `json:"start_date"`
-	EndDate      time.Time `json:"end_date"`
-	Status       string    `json:"status"`
+	TournamentID         string     `json:"tournament_id"`
+	Name                 MultiName  `json:"name"`
+	EventCode            string     `json:"event_code"`       // e.g., "MS", "WD", "XD"
+	EventType            string     `json:"event_type"`       // singles, doubles, team
+	Gender               string     `json:"gender,omitempty"` // male, female, mixed
+	AgeGroup             *AgeGroup  `json:"age_group,omitempty"`
+	Format               string     `json:"format,omitempty"` // Can override tournament format
+	Rules                *Rules     `json:"rules,omitempty"`  // Overrides fields of the tournament rules
+	MaxEntries           int        `json:"max_entries,omitempty"`
+	EntryFee             *Money     `json:"entry_fee,omitempty"`
+	LateEntryFee         *Money     `json:"late_entry_fee,omitempty"`         // Charged for entries registered after RegistrationDeadline
+	DoublesFeeMultiplier float64    `json:"doubles_fee_multiplier,omitempty"` // Applied to the fee of doubles entries, e.g. 2 for a fee per player
+	RegistrationDeadline *time.Time `json:"registration_deadline,omitempty"`
+	RegistrationOpen     *time.Time `json:"registration_open,omitempty"`
+	RegistrationClose    *time.Time `json:"registration_close,omitempty"` // Exclusive; must be before StartDate
+	StartDate            time.Time  `json:"start_date"`
+	EndDate              time.Time  `json:"end_date"`
+	Status               string     `json:"status"`
 }
 
 // Match represents a match in a tournament
 type Match struct {
-	EventID      string     `json:"event_id"`
-	RoundID      string     `json:"round_id,omitempty"`
-	BracketID    string     `json:"bracket_id,omitempty"`
-	MatchNumber  string     `json:"match_number"`
-	ScheduledAt  *time.Time `json:"scheduled_at,omitempty"`
-	Court        string     `json:"court,omitempty"`
-	Status       string     `json:"status"` // scheduled, in_progress, completed, cancelled
-	HomeEntry    *EntryRef  `json:"home_entry,omitempty"`
-	AwayEntry    *EntryRef  `json:"away_entry,omitempty"`
-	Winner       string     `json:"winner,omitempty"` // entry_id of winner
-	Score        *Score     `json:"score,omitempty"`
-	Officials    []Official `json:"officials,omitempty"`
-	StreamingURL string     `json:"streaming_url,omitempty"`
-	Notes        string     `json:"notes,omitempty"`
+	EventID             string          `json:"event_id"`
+	RoundID             string          `json:"round_id,omitempty"`
+	BracketID           string          `json:"bracket_id,omitempty"`
+	MatchNumber         string          `json:"match_number"`
+	ScheduledAt         *time.Time      `json:"scheduled_at,omitempty"`
+	StartedAt           *time.Time      `json:"started_at,omitempty"`
+	EndedAt             *time.Time      `json:"ended_at,omitempty"`
+	Court               string          `json:"court,omitempty"`
+	Status              string          `json:"status"` // scheduled, in_progress, completed, cancelled
+	HomeEntry           *EntryRef       `json:"home_entry,omitempty"`
+	AwayEntry           *EntryRef       `json:"away_entry,omitempty"`
+	Winner              string          `json:"winner,omitempty"`                  // entry_id of winner
+	WinnerGoesToMatchID string          `json:"winner_goes_to_match_id,omitempty"` // Next bracket match for the winner
+	DrawPosition        int             `json:"draw_position,omitempty"`           // 1-based slot within the round of a bracket diagram
+	Score               *Score          `json:"score,omitempty"`
+	ScoreHistory        []ScoreRevision `json:"score_history,omitempty"`
+	Officials           []Official      `json:"officials,omitempty"`
+	StreamingURL        string          `json:"streaming_url,omitempty"`
+	Notes               string          `json:"notes,omitempty"`
+}
+
+// Round represents a round within an event (e.g., "Quarterfinals", "Group A - Round 1")
+type Round struct {
+	EventID     string    `json:"event_id"`
+	Name        string    `json:"name"`
+	RoundNumber int       `json:"round_number"` // 1-based order within the event
+	StartDate   time.Time `json:"start_date,omitempty"`
+	EndDate     time.Time `json:"end_date,omitempty"`
+	Status      string    `json:"status,omitempty"` // scheduled, in_progress, completed
+}
+
+// TournamentPhase groups the rounds of an event into a stage such as
+// qualifying, the main draw or a consolation bracket
+type TournamentPhase struct {
+	EventID          string   `json:"event_id"`
+	PhaseName        string   `json:"phase_name"`                  // e.g., "qualifying", "main_draw", "consolation"
+	PhaseOrder       int      `json:"phase_order"`                 // 1-based order within the event
+	RoundIDs         []string `json:"round_ids,omitempty"`         // Rounds played in this phase
+	AdvancementCount int      `json:"advancement_count,omitempty"` // Entries advancing to the next phase
+	Status           string   `json:"status,omitempty"`            // scheduled, in_progress, completed
+}
+
+// Bracket represents a draw within an event
+type Bracket struct {
+	EventID string `json:"event_id"`
+	PhaseID string `json:"phase_id,omitempty"` // Phase the bracket belongs to
+	Name    string `json:"name"`
+	Format  string `json:"format,omitempty"` // single_elimination, round_robin, etc.
+	Size    int    `json:"size,omitempty"`   // Number of draw positions
 }
 
 // Entry represents a participant entry in an event
 type Entry struct {
-	EventID      string        `json:"event_id"`
-	EntryType    string        `json:"entry_type"` // individual, doubles, team
-	Status       string        `json:"status"`     // registered, confirmed, withdrawn
-	Seed         *int          `json:"seed,omitempty"`
-	Players      []Player      `json:"players"`
-	Team         *Team         `json:"team,omitempty"`
-	Registration *Registration `json:"registration,omitempty"`
+	EventID        string         `json:"event_id"`
+	EntryType      string         `json:"entry_type"` // individual, doubles, team
+	Status         string         `json:"status"`     // registered, confirmed, withdrawn
+	Seed           *int           `json:"seed,omitempty"`
+	Players        []Player       `json:"players"`
+	Team           *Team          `json:"team,omitempty"`
+	Registration   *Registration  `json:"registration,omitempty"`
+	SeedingHistory []SeedRevision `json:"seeding_history,omitempty"`
 }
 
 // Player represents an individual player
@@ -78,6 +121,28 @@ type Player struct {
 	Email       string    `json:"email,omitempty"`
 	Phone       string    `json:"phone,omitempty"`
 	PlayerID    string    `json:"player_id,omitempty"` // External ID (e.g., ITTF ID)
+	CoachID     string    `json:"coach_id,omitempty"`  // ID of the player's coach entity
+}
+
+// Coach represents a coach responsible for players, e.g. in youth tournaments
+type Coach struct {
+	CoachID        string   `json:"coach_id,omitempty"` // External ID (e.g., federation licence number)
+	FirstName      string   `json:"first_name"`
+	LastName       string   `json:"last_name"`
+	Club           string   `json:"club,omitempty"`
+	Certifications []string `json:"certifications,omitempty"` // e.g., "ITTF Level 2"
+	ContactInfo    *Contact `json:"contact_info,omitempty"`
+	Players        []string `json:"players,omitempty"` // IDs of the players coached
+}
+
+// Ranking represents a player's position in a published ranking list
+type Ranking struct {
+	Player      Player    `json:"player"`
+	System      string    `json:"system"` // e.g., "ITTF"
+	Rank        int       `json:"rank"`
+	Points      int       `json:"points"`
+	Trend       string    `json:"trend,omitempty"` // Movement since the previous list, e.g., "+2", "-1", "="
+	PublishedAt time.Time `json:"published_at,omitempty"`
 }
 
 // Score represents match score
@@ -90,6 +155,23 @@ type Score struct {
 	Disqualify bool       `json:"disqualify,omitempty"`
 }
 
+// ScoreRevision records a correction to a completed match's score
+type ScoreRevision struct {
+	OldScore  Score     `json:"old_score"`
+	NewScore  Score     `json:"new_score"`
+	Reason    string    `json:"reason"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SeedRevision records a change to an entry's seed
+type SeedRevision struct {
+	OldSeed     int       `json:"old_seed"`
+	NewSeed     int       `json:"new_seed"`
+	SwappedWith string    `json:"swapped_with,omitempty"` // entry_id that took the old seed
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // SetScore represents score for a single set/game
 type SetScore struct {
 	SetNumber int    `json:"set_number"`
@@ -103,14 +185,14 @@ type SetScore struct {
 
 // Venue represents a competition venue
 type Venue struct {
-	Name     string   `json:"name"`
-	Address  string   `json:"address,omitempty"`
-	City     string   `json:"city,omitempty"`
-	State    string   `json:"state,omitempty"`
-	Country  string   `json:"country,omitempty"`
-	PostCode string   `json:"post_code,omitempty"`
-	Courts   []string `json:"courts,omitempty"`
-	Capacity int      `json:"capacity,omitempty"`
+	Name     MultiName `json:"name"`
+	Address  string    `json:"address,omitempty"`
+	City     string    `json:"city,omitempty"`
+	State    string    `json:"state,omitempty"`
+	Country  string    `json:"country,omitempty"`
+	PostCode string    `json:"post_code,omitempty"`
+	Courts   []string  `json:"courts,omitempty"`
+	Capacity int       `json:"capacity,omitempty"`
 }
 
 // Organizer represents tournament organizer
@@ -141,6 +223,19 @@ type Rules struct {
 	CustomRules   string `json:"custom_rules,omitempty"`
 }
 
+// CancellationPolicy defines the refund of entry fees by how long before
+// the tournament start an entry is cancelled
+type CancellationPolicy struct {
+	Tiers []CancellationTier `json:"tiers"`
+}
+
+// CancellationTier refunds RefundPercent of the entry fee for cancellations
+// at least DaysBeforeStart days before the tournament starts
+type CancellationTier struct {
+	DaysBeforeStart int     `json:"days_before_start"`
+	RefundPercent   float64 `json:"refund_percent"` // 0-100
+}
+
 // Contact represents contact information
 type Contact struct {
 	Name  string `json:"name,omitempty"`
@@ -2,6 +2,8 @@ package ptd
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -9,7 +11,7 @@ import (
 func TestTournament_JSON(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
 	tournament := Tournament{
-		Name:        "Summer Championship 2025",
+		Name:        MultiName{Default: "Summer Championship 2025"},
 		Description: "Annual summer championship",
 		StartDate:   now,
 		EndDate:     now.Add(48 * time.Hour),
@@ -18,7 +20,7 @@ func TestTournament_JSON(t *testing.T) {
 		Format:      "round_robin",
 		Website:     "https://example.com",
 		Venue: &Venue{
-			Name:     "Sports Complex",
+			Name:     MultiName{Default: "Sports Complex"},
 			Address:  "123 Main St",
 			City:     "New York",
 			State:    "NY",
@@ -54,7 +56,7 @@ func TestTournament_JSON(t *testing.T) {
 	}
 
 	// Check key fields
-	if decoded.Name != tournament.Name {
+	if decoded.Name.Default != tournament.Name.Default {
 		t.Errorf("Name mismatch: got %s, want %s", decoded.Name, tournament.Name)
 	}
 
@@ -64,7 +66,7 @@ func TestTournament_JSON(t *testing.T) {
 
 	if decoded.Venue == nil {
 		t.Error("Venue should not be nil")
-	} else if decoded.Venue.Name != tournament.Venue.Name {
+	} else if decoded.Venue.Name.Default != tournament.Venue.Name.Default {
 		t.Errorf("Venue name mismatch: got %s, want %s", decoded.Venue.Name, tournament.Venue.Name)
 	}
 }
@@ -73,7 +75,7 @@ func TestEvent_JSON(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Second)
 	event := Event{
 		TournamentID: GenerateID(TypeTournament),
-		Name:         "Men's Singles",
+		Name:         MultiName{Default: "Men's Singles"},
 		EventCode:    "MS",
 		EventType:    "singles",
 		Gender:       "male",
@@ -361,7 +363,7 @@ func TestScore_SpecialCases(t *testing.T) {
 
 func TestVenue_Validation(t *testing.T) {
 	venue := Venue{
-		Name:     "Olympic Stadium",
+		Name:     MultiName{Default: "Olympic Stadium"},
 		Address:  "1 Olympic Way",
 		City:     "Los Angeles",
 		State:    "CA",
@@ -436,3 +438,50 @@ func TestMoney_Currencies(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestEntityTagConsistency(t *testing.T) {
+	entities := map[string]interface{}{
+		TypeTournament: Tournament{},
+		TypeEvent:      Event{},
+		TypeMatch:      Match{},
+		TypeEntry:      Entry{},
+		TypePlayer:     Player{},
+		TypePhase:      TournamentPhase{},
+		TypeCoach:      Coach{},
+	}
+
+	for entityType, entity := range entities {
+		required := requiredFields[entityType]
+		seen := make(map[string]bool)
+
+		typ := reflect.TypeOf(entity)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			tag, ok := field.Tag.Lookup("json")
+			if !ok {
+				t.Errorf("%s.%s has no json tag", typ.Name(), field.Name)
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			omitempty := strings.Contains(","+options+",", ",omitempty,")
+			seen[name] = true
+
+			if contains(required, name) && omitempty {
+				t.Errorf("%s.%s is required but tagged omitempty", typ.Name(), field.Name)
+			}
+			if !contains(required, name) && !omitempty {
+				t.Errorf("%s.%s is optional but not tagged omitempty", typ.Name(), field.Name)
+			}
+		}
+
+		for _, name := range required {
+			if !seen[name] {
+				t.Errorf("%s: required field %s has no struct field", typ.Name(), name)
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package ptd
+
+import "time"
+
+// PartnerOf returns the partner of the given player in a doubles entry.
+// playerID is matched against Player.PlayerID or Player.DisplayName.
+// It returns nil, false if the entry is not a two-player doubles entry
+// or the player is not part of it.
+func (e *Entry) PartnerOf(playerID string) (*Player, bool) {
+	if e.EntryType != "doubles" || len(e.Players) != 2 || playerID == "" {
+		return nil, false
+	}
+
+	for i := range e.Players {
+		if e.Players[i].matches(playerID) {
+			return &e.Players[1-i], true
+		}
+	}
+
+	return nil, false
+}
+
+// ContainsPlayer reports whether the entry includes the given player, matched
+// against Player.PlayerID, Player.DisplayName or the team's player IDs.
+func (e *Entry) ContainsPlayer(playerID string) bool {
+	if playerID == "" {
+		return false
+	}
+
+	for i := range e.Players {
+		if e.Players[i].matches(playerID) {
+			return true
+		}
+	}
+
+	if e.Team != nil {
+		return contains(e.Team.Players, playerID)
+	}
+
+	return false
+}
+
+// matches reports whether id identifies the player by PlayerID or DisplayName
+func (p *Player) matches(id string) bool {
+	return p.PlayerID == id || p.DisplayName == id
+}
+
+// EntryStatusSnapshot summarizes the registration state of an event
+type EntryStatusSnapshot struct {
+	EventID          string         `json:"event_id"`
+	MaxEntries       int            `json:"max_entries"`     // 0 means unlimited
+	ByStatus         map[string]int `json:"by_status"`       // e.g., "registered": 12, "confirmed": 30
+	AvailableSlots   int            `json:"available_slots"` // -1 when unlimited
+	IsOverSubscribed bool           `json:"is_oversubscribed"`
+	WaitlistCount    int            `json:"waitlist_count"` // Active entries beyond MaxEntries
+}
+
+// GetEntryStatusSnapshot counts the event's entries by status. Entries for
+// other events are ignored. Withdrawn and cancelled entries do not take up
+// a slot; active entries beyond the event's MaxEntries are counted as
+// waitlisted.
+func GetEntryStatusSnapshot(event Envelope[Event], entries []Envelope[Entry]) *EntryStatusSnapshot {
+	snapshot := &EntryStatusSnapshot{
+		EventID:    event.ID,
+		MaxEntries: event.Spec.MaxEntries,
+		ByStatus:   make(map[string]int),
+	}
+
+	active := 0
+	for _, entry := range entries {
+		if entry.Spec.EventID != event.ID {
+			continue
+		}
+		snapshot.ByStatus[entry.Spec.Status]++
+		if entry.Spec.Status != "withdrawn" && entry.Spec.Status != "cancelled" {
+			active++
+		}
+	}
+
+	if snapshot.MaxEntries <= 0 {
+		snapshot.AvailableSlots = -1
+		return snapshot
+	}
+	snapshot.AvailableSlots = max(snapshot.MaxEntries-active, 0)
+	snapshot.WaitlistCount = max(active-snapshot.MaxEntries, 0)
+	snapshot.IsOverSubscribed = active > snapshot.MaxEntries
+	return snapshot
+}
+
+// IsLate reports whether the entry was registered after the deadline
+func (r *Registration) IsLate(deadline time.Time) bool {
+	return r != nil && r.RegisteredAt.After(deadline)
+}
+
+// IsRegistrationOpen reports whether at falls within the registration
+// window [RegistrationOpen, RegistrationClose). An unset bound leaves the
+// window open on that side.
+func (e *Event) IsRegistrationOpen(at time.Time) bool {
+	if e.RegistrationOpen != nil && at.Before(*e.RegistrationOpen) {
+		return false
+	}
+	return e.RegistrationClose == nil || at.Before(*e.RegistrationClose)
+}
+
+// FeeForEntry returns the fee due for an entry: LateEntryFee if the entry
+// was registered after RegistrationDeadline, otherwise EntryFee. Events
+// without a late fee or deadline charge EntryFee to every entry, and events
+// without an entry fee are free: the fee is zero, in the currency of the
+// late fee if there is one.
+//
+// Returns a *ValidationError wrapping ErrMissingField if a late fee applies
+// and the entry has no registration time to compare with the deadline.
+func (e *Event) FeeForEntry(entry Envelope[Entry]) (Money, error) {
+	var fee Money
+	if e.EntryFee != nil {
+		fee = *e.EntryFee
+	}
+	if e.LateEntryFee == nil || e.RegistrationDeadline == nil {
+		return fee, nil
+	}
+	if e.EntryFee == nil {
+		fee.Currency = e.LateEntryFee.Currency
+	}
+
+	registration := entry.Spec.Registration
+	if registration == nil {
+		return Money{}, newValidationError(ErrMissingField, TypeEntry, "entry.registration", "entry %s has no registration time", entry.ID)
+	}
+	if registration.IsLate(*e.RegistrationDeadline) {
+		return *e.LateEntryFee, nil
+	}
+	return fee, nil
+}
+
+// PaymentAmount returns the fee owed for the entry in the event: nothing
+// for withdrawn or cancelled entries, otherwise the fee from
+// Event.FeeForEntry, multiplied by the event's DoublesFeeMultiplier for
+// doubles entries. Returns ErrValidation if the entry is for another event.
+func (e *Entry) PaymentAmount(event Envelope[Event]) (Money, error) {
+	if e.EventID != event.ID {
+		return Money{}, newValidationError(ErrValidation, TypeEntry, "entry.event_id", "entry is for event %s, not %s", e.EventID, event.ID)
+	}
+
+	if e.Status == "withdrawn" || e.Status == "cancelled" {
+		var owed Money
+		if event.Spec.EntryFee != nil {
+			owed.Currency = event.Spec.EntryFee.Currency
+		}
+		return owed, nil
+	}
+	return e.entryFee(event)
+}
+
+// entryFee returns the fee for the entry in the event regardless of its status
+func (e *Entry) entryFee(event Envelope[Event]) (Money, error) {
+	fee, err := event.Spec.FeeForEntry(Envelope[Entry]{Type: TypeEntry, Spec: *e})
+	if err != nil {
+		return Money{}, err
+	}
+	if e.EntryType == "doubles" && event.Spec.DoublesFeeMultiplier > 0 {
+		fee.Amount *= event.Spec.DoublesFeeMultiplier
+	}
+
+	return fee, nil
+}
+
+// ComputeTournamentCapacityUtilization maps the ID of each of the
+// tournament's events to its confirmed entries as a percentage of
+// MaxEntries. Oversubscribed events exceed 100; events without an entry
+// limit report 0. Events of other tournaments are ignored.
+func ComputeTournamentCapacityUtilization(tournament Envelope[Tournament], events []Envelope[Event], entries []Envelope[Entry]) map[string]float64 {
+	confirmed := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Spec.Status == "confirmed" {
+			confirmed[entry.Spec.EventID]++
+		}
+	}
+
+	utilization := make(map[string]float64)
+	for _, event := range events {
+		if event.Spec.TournamentID != tournament.ID {
+			continue
+		}
+		if event.Spec.MaxEntries <= 0 {
+			utilization[event.ID] = 0
+			continue
+		}
+		utilization[event.ID] = float64(confirmed[event.ID]) / float64(event.Spec.MaxEntries) * 100
+	}
+	return utilization
+}
@@ -0,0 +1,260 @@
+package ptd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEntry_PartnerOf(t *testing.T) {
+	entry := Entry{
+		EntryType: "doubles",
+		Players: []Player{
+			{FirstName: "Ma", LastName: "Long", DisplayName: "Ma Long", PlayerID: "ittf-1"},
+			{FirstName: "Xu", LastName: "Xin", DisplayName: "Xu Xin", PlayerID: "ittf-2"},
+		},
+	}
+
+	partner, ok := entry.PartnerOf("ittf-1")
+	if !ok || partner.PlayerID != "ittf-2" {
+		t.Errorf("Expected Xu Xin as partner, got %v", partner)
+	}
+
+	partner, ok = entry.PartnerOf("Xu Xin")
+	if !ok || partner.PlayerID != "ittf-1" {
+		t.Errorf("Expected Ma Long as partner by display name, got %v", partner)
+	}
+
+	if _, ok := entry.PartnerOf("unknown"); ok {
+		t.Error("Unknown player should have no partner")
+	}
+
+	singles := Entry{EntryType: "individual", Players: entry.Players}
+	if _, ok := singles.PartnerOf("ittf-1"); ok {
+		t.Error("Non-doubles entry should have no partner")
+	}
+
+	incomplete := Entry{EntryType: "doubles", Players: entry.Players[:1]}
+	if _, ok := incomplete.PartnerOf("ittf-1"); ok {
+		t.Error("Doubles entry with one player should have no partner")
+	}
+}
+
+func TestEntry_ContainsPlayer(t *testing.T) {
+	entry := Entry{
+		Players: []Player{{DisplayName: "Ma Long", PlayerID: "ittf-1"}},
+	}
+
+	if !entry.ContainsPlayer("ittf-1") || !entry.ContainsPlayer("Ma Long") {
+		t.Error("Entry should contain Ma Long")
+	}
+	if entry.ContainsPlayer("ittf-2") || entry.ContainsPlayer("") {
+		t.Error("Entry should not contain unknown player")
+	}
+
+	team := Entry{Team: &Team{Name: "China", Players: []string{"ittf-3"}}}
+	if !team.ContainsPlayer("ittf-3") {
+		t.Error("Team entry should contain its players")
+	}
+}
+
+func TestGetEntryStatusSnapshot(t *testing.T) {
+	eventID := GenerateID(TypeEvent)
+	event := Envelope[Event]{ID: eventID, Type: TypeEvent, Spec: Event{MaxEntries: 3}}
+
+	var entries []Envelope[Entry]
+	for _, status := range []string{"registered", "confirmed", "confirmed", "withdrawn", "registered"} {
+		entries = append(entries, Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry, Spec: Entry{EventID: eventID, Status: status}})
+	}
+	entries = append(entries, Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry, Spec: Entry{EventID: GenerateID(TypeEvent), Status: "confirmed"}})
+
+	snapshot := GetEntryStatusSnapshot(event, entries)
+	if snapshot.EventID != eventID || snapshot.MaxEntries != 3 {
+		t.Errorf("Unexpected snapshot header: %+v", snapshot)
+	}
+	if snapshot.ByStatus["registered"] != 2 || snapshot.ByStatus["confirmed"] != 2 || snapshot.ByStatus["withdrawn"] != 1 {
+		t.Errorf("Unexpected status counts: %v", snapshot.ByStatus)
+	}
+	if snapshot.AvailableSlots != 0 || !snapshot.IsOverSubscribed || snapshot.WaitlistCount != 1 {
+		t.Errorf("Expected oversubscribed by 1, got %+v", snapshot)
+	}
+
+	event.Spec.MaxEntries = 8
+	snapshot = GetEntryStatusSnapshot(event, entries)
+	if snapshot.AvailableSlots != 4 || snapshot.IsOverSubscribed || snapshot.WaitlistCount != 0 {
+		t.Errorf("Expected 4 available slots, got %+v", snapshot)
+	}
+
+	event.Spec.MaxEntries = 0
+	if snapshot = GetEntryStatusSnapshot(event, entries); snapshot.AvailableSlots != -1 || snapshot.IsOverSubscribed {
+		t.Errorf("Expected unlimited slots, got %+v", snapshot)
+	}
+}
+
+func TestRegistration_IsLate(t *testing.T) {
+	deadline := time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		registration *Registration
+		want         bool
+	}{
+		{"before deadline", &Registration{RegisteredAt: deadline.Add(-time.Hour)}, false},
+		{"at deadline", &Registration{RegisteredAt: deadline}, false},
+		{"after deadline", &Registration{RegisteredAt: deadline.Add(time.Minute)}, true},
+		{"no registration", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.registration.IsLate(deadline); got != tt.want {
+				t.Errorf("IsLate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvent_IsRegistrationOpen(t *testing.T) {
+	opens := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	closes := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		event Event
+		at    time.Time
+		want  bool
+	}{
+		{"before opening", Event{RegistrationOpen: &opens, RegistrationClose: &closes}, opens.Add(-time.Second), false},
+		{"at opening", Event{RegistrationOpen: &opens, RegistrationClose: &closes}, opens, true},
+		{"during window", Event{RegistrationOpen: &opens, RegistrationClose: &closes}, opens.Add(24 * time.Hour), true},
+		{"at closing", Event{RegistrationOpen: &opens, RegistrationClose: &closes}, closes, false},
+		{"no opening", Event{RegistrationClose: &closes}, opens.AddDate(-1, 0, 0), true},
+		{"no closing", Event{RegistrationOpen: &opens}, closes.AddDate(1, 0, 0), true},
+		{"no window", Event{}, opens, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.event.IsRegistrationOpen(tt.at); got != tt.want {
+				t.Errorf("IsRegistrationOpen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvent_FeeForEntry(t *testing.T) {
+	deadline := time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC)
+	regular := Money{Amount: 40, Currency: "USD"}
+	late := Money{Amount: 60, Currency: "USD"}
+	event := &Event{EntryFee: &regular, LateEntryFee: &late, RegistrationDeadline: &deadline}
+
+	entryAt := func(registeredAt time.Time) Envelope[Entry] {
+		return Envelope[Entry]{ID: "ptd:entry:1", Spec: Entry{Registration: &Registration{RegisteredAt: registeredAt}}}
+	}
+
+	if fee, err := event.FeeForEntry(entryAt(deadline.Add(-24 * time.Hour))); err != nil || fee != regular {
+		t.Errorf("FeeForEntry(on time) = %v, %v; want %v", fee, err, regular)
+	}
+	if fee, err := event.FeeForEntry(entryAt(deadline.Add(time.Hour))); err != nil || fee != late {
+		t.Errorf("FeeForEntry(late) = %v, %v; want %v", fee, err, late)
+	}
+	if _, err := event.FeeForEntry(Envelope[Entry]{ID: "ptd:entry:2"}); !errors.Is(err, ErrMissingField) {
+		t.Errorf("FeeForEntry(no registration) error = %v, want ErrMissingField", err)
+	}
+
+	// Without a late fee every entry pays the regular fee
+	noLateFee := &Event{EntryFee: &regular, RegistrationDeadline: &deadline}
+	if fee, err := noLateFee.FeeForEntry(entryAt(deadline.Add(time.Hour))); err != nil || fee != regular {
+		t.Errorf("FeeForEntry(no late fee) = %v, %v; want %v", fee, err, regular)
+	}
+
+	// Events without an entry fee are free, except for late entries
+	if fee, err := (&Event{}).FeeForEntry(Envelope[Entry]{}); err != nil || fee != (Money{}) {
+		t.Errorf("FeeForEntry(free event) = %v, %v; want zero", fee, err)
+	}
+	lateOnly := &Event{LateEntryFee: &late, RegistrationDeadline: &deadline}
+	if fee, err := lateOnly.FeeForEntry(entryAt(deadline.Add(-time.Hour))); err != nil || fee != (Money{Currency: "USD"}) {
+		t.Errorf("FeeForEntry(free on time) = %v, %v; want 0 USD", fee, err)
+	}
+	if fee, err := lateOnly.FeeForEntry(entryAt(deadline.Add(time.Hour))); err != nil || fee != late {
+		t.Errorf("FeeForEntry(late only) = %v, %v; want %v", fee, err, late)
+	}
+}
+
+func TestEntry_PaymentAmount(t *testing.T) {
+	deadline := time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC)
+	event := Envelope[Event]{
+		ID:   "ptd:event:1",
+		Type: TypeEvent,
+		Spec: Event{
+			EntryFee:             &Money{Amount: 40, Currency: "EUR"},
+			LateEntryFee:         &Money{Amount: 60, Currency: "EUR"},
+			RegistrationDeadline: &deadline,
+			DoublesFeeMultiplier: 2,
+		},
+	}
+	onTime := &Registration{RegisteredAt: deadline.Add(-48 * time.Hour)}
+	late := &Registration{RegisteredAt: deadline.Add(time.Hour)}
+
+	tests := []struct {
+		name  string
+		entry Entry
+		want  float64
+	}{
+		{"singles on time", Entry{EventID: event.ID, EntryType: "individual", Status: "confirmed", Registration: onTime}, 40},
+		{"singles late", Entry{EventID: event.ID, EntryType: "individual", Status: "registered", Registration: late}, 60},
+		{"doubles on time", Entry{EventID: event.ID, EntryType: "doubles", Status: "confirmed", Registration: onTime}, 80},
+		{"doubles late", Entry{EventID: event.ID, EntryType: "doubles", Status: "confirmed", Registration: late}, 120},
+		{"withdrawn", Entry{EventID: event.ID, EntryType: "doubles", Status: "withdrawn", Registration: late}, 0},
+		{"cancelled without registration", Entry{EventID: event.ID, Status: "cancelled"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.entry.PaymentAmount(event)
+			if err != nil {
+				t.Fatalf("PaymentAmount() error = %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != "EUR" {
+				t.Errorf("PaymentAmount() = %+v, want %v EUR", got, tt.want)
+			}
+		})
+	}
+
+	free := Envelope[Event]{ID: "ptd:event:3", Type: TypeEvent}
+	if got, err := (&Entry{EventID: free.ID, EntryType: "doubles", Status: "confirmed"}).PaymentAmount(free); err != nil || got.Amount != 0 {
+		t.Errorf("PaymentAmount(free event) = %+v, %v; want 0", got, err)
+	}
+
+	other := Entry{EventID: "ptd:event:2", Status: "confirmed", Registration: onTime}
+	if _, err := other.PaymentAmount(event); !errors.Is(err, ErrValidation) {
+		t.Errorf("PaymentAmount(other event) error = %v, want ErrValidation", err)
+	}
+}
+
+func TestComputeTournamentCapacityUtilization(t *testing.T) {
+	tournament := Envelope[Tournament]{ID: "ptd:tournament:1", Type: TypeTournament}
+	events := []Envelope[Event]{
+		{ID: "ptd:event:ms", Spec: Event{TournamentID: tournament.ID, MaxEntries: 4}},
+		{ID: "ptd:event:ws", Spec: Event{TournamentID: tournament.ID, MaxEntries: 2}},
+		{ID: "ptd:event:open", Spec: Event{TournamentID: tournament.ID}},
+		{ID: "ptd:event:other", Spec: Event{TournamentID: "ptd:tournament:2", MaxEntries: 4}},
+	}
+	entry := func(eventID, status string) Envelope[Entry] {
+		return Envelope[Entry]{Spec: Entry{EventID: eventID, Status: status}}
+	}
+	entries := []Envelope[Entry]{
+		entry("ptd:event:ms", "confirmed"),
+		entry("ptd:event:ms", "registered"),
+		entry("ptd:event:ms", "withdrawn"),
+		entry("ptd:event:ws", "confirmed"),
+		entry("ptd:event:ws", "confirmed"),
+		entry("ptd:event:ws", "confirmed"),
+		entry("ptd:event:open", "confirmed"),
+	}
+
+	got := ComputeTournamentCapacityUtilization(tournament, events, entries)
+	want := map[string]float64{"ptd:event:ms": 25, "ptd:event:ws": 150, "ptd:event:open": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeTournamentCapacityUtilization() = %v, want %v", got, want)
+	}
+}
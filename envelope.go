@@ -3,6 +3,7 @@ package ptd
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -23,10 +24,11 @@ type Meta struct {
 	Source    string    `json:"source"`     // Source system (e.g., "icc:prod-us-west")
 
 	// Optional metadata fields
-	Tags       []string               `json:"tags,omitempty"`       // User-defined tags
-	Extensions map[string]interface{} `json:"extensions,omitempty"` // Vendor-specific extensions
-	Signature  *Signature             `json:"signature,omitempty"`  // Digital signature
-	Provenance *Provenance            `json:"provenance,omitempty"` // Data lineage
+	Tags            []string               `json:"tags,omitempty"`             // User-defined tags
+	Extensions      map[string]interface{} `json:"extensions,omitempty"`       // Vendor-specific extensions
+	Signature       *Signature             `json:"signature,omitempty"`        // Digital signature
+	PriorSignatures []PriorSignature       `json:"prior_signatures,omitempty"` // Signatures over earlier versions
+	Provenance      *Provenance            `json:"provenance,omitempty"`       // Data lineage
 }
 
 // Signature contains digital signature information
@@ -38,6 +40,16 @@ type Signature struct {
 	SignedBy    string    `json:"signed_by"`     // Identity of signer
 }
 
+// PriorSignature is a signature over an earlier version of an envelope,
+// kept when the envelope is upgraded. It records the metadata the upgrade
+// changed, so the signed content can be reconstructed and verified.
+type PriorSignature struct {
+	Signature Signature `json:"signature"`
+	Schema    string    `json:"schema"`
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // Provenance tracks the origin and history of the data
 type Provenance struct {
 	OriginalSource  string      `json:"original_source"`         // Original data source
@@ -81,3 +93,64 @@ func (e *Envelope[T]) Validate() error {
 	}
 	return nil
 }
+
+// Version returns the entity version, Meta.Version
+func (e *Envelope[T]) Version() int {
+	return e.Meta.Version
+}
+
+// BumpVersion returns a shallow copy of the envelope with Meta.Version
+// incremented and Meta.UpdatedAt set to now. The envelope is not modified.
+func (e *Envelope[T]) BumpVersion() *Envelope[T] {
+	bumped := *e
+	bumped.Meta.Version++
+	bumped.Meta.UpdatedAt = time.Now()
+	return &bumped
+}
+
+// ToJSON returns the JSON encoding of the envelope
+func (e *Envelope[T]) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// ToJSONIndent returns the indented JSON encoding of the envelope
+func (e *Envelope[T]) ToJSONIndent(prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(e, prefix, indent)
+}
+
+// parseEnvelope decodes an envelope and checks that its type, when set, is entityType
+func parseEnvelope[T any](data []byte, entityType string) (Envelope[T], error) {
+	var envelope Envelope[T]
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Envelope[T]{}, fmt.Errorf("%w: failed to parse %s envelope: %v", ErrInvalidFormat, entityType, err)
+	}
+	if envelope.Type != "" && envelope.Type != entityType {
+		return Envelope[T]{}, fmt.Errorf("%w: expected %s envelope, got %s", ErrInvalidType, entityType, envelope.Type)
+	}
+	return envelope, nil
+}
+
+// ParseTournamentEnvelope decodes a tournament envelope
+func ParseTournamentEnvelope(data []byte) (Envelope[Tournament], error) {
+	return parseEnvelope[Tournament](data, TypeTournament)
+}
+
+// ParseEventEnvelope decodes an event envelope
+func ParseEventEnvelope(data []byte) (Envelope[Event], error) {
+	return parseEnvelope[Event](data, TypeEvent)
+}
+
+// ParseMatchEnvelope decodes a match envelope
+func ParseMatchEnvelope(data []byte) (Envelope[Match], error) {
+	return parseEnvelope[Match](data, TypeMatch)
+}
+
+// ParseEntryEnvelope decodes an entry envelope
+func ParseEntryEnvelope(data []byte) (Envelope[Entry], error) {
+	return parseEnvelope[Entry](data, TypeEntry)
+}
+
+// ParsePlayerEnvelope decodes a player envelope
+func ParsePlayerEnvelope(data []byte) (Envelope[Player], error) {
+	return parseEnvelope[Player](data, TypePlayer)
+}
@@ -2,6 +2,8 @@ package ptd
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -17,7 +19,7 @@ func TestEnvelope_Validate(t *testing.T) {
 			envelope: Envelope[Tournament]{
 				ID:   "ptd:tournament:01ABC123",
 				Type: TypeTournament,
-				Spec: Tournament{Name: "Test Tournament"},
+				Spec: Tournament{Name: MultiName{Default: "Test Tournament"}},
 				Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 			},
 			wantErr: nil,
@@ -26,7 +28,7 @@ func TestEnvelope_Validate(t *testing.T) {
 			name: "missing ID",
 			envelope: Envelope[Tournament]{
 				Type: TypeTournament,
-				Spec: Tournament{Name: "Test Tournament"},
+				Spec: Tournament{Name: MultiName{Default: "Test Tournament"}},
 				Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 			},
 			wantErr: ErrInvalidID,
@@ -35,7 +37,7 @@ func TestEnvelope_Validate(t *testing.T) {
 			name: "missing type",
 			envelope: Envelope[Tournament]{
 				ID:   "ptd:tournament:01ABC123",
-				Spec: Tournament{Name: "Test Tournament"},
+				Spec: Tournament{Name: MultiName{Default: "Test Tournament"}},
 				Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 			},
 			wantErr: ErrInvalidType,
@@ -45,7 +47,7 @@ func TestEnvelope_Validate(t *testing.T) {
 			envelope: Envelope[Tournament]{
 				ID:   "ptd:tournament:01ABC123",
 				Type: TypeTournament,
-				Spec: Tournament{Name: "Test Tournament"},
+				Spec: Tournament{Name: MultiName{Default: "Test Tournament"}},
 				Meta: Meta{},
 			},
 			wantErr: ErrMissingSchema,
@@ -65,7 +67,7 @@ func TestEnvelope_Validate(t *testing.T) {
 func TestEnvelope_CanonicalJSON(t *testing.T) {
 	now := time.Now()
 	tournament := Tournament{
-		Name:      "Test Tournament",
+		Name:      MultiName{Default: "Test Tournament"},
 		StartDate: now,
 		EndDate:   now.Add(2 * 24 * time.Hour),
 		Status:    "draft",
@@ -242,3 +244,63 @@ func TestSignature(t *testing.T) {
 		t.Errorf("Expected signed by 'suparena-signer', got %s", decoded.SignedBy)
 	}
 }
+
+func TestEnvelope_ToJSON(t *testing.T) {
+	envelope := Envelope[Match]{
+		ID:   GenerateID(TypeMatch),
+		Type: TypeMatch,
+		Spec: Match{EventID: "ptd:event:1", MatchNumber: "M1", Status: "scheduled"},
+		Meta: Meta{Schema: "ptd.v1.match@1.0.0", Version: 1},
+	}
+
+	data, err := envelope.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	decoded, err := ParseMatchEnvelope(data)
+	if err != nil {
+		t.Fatalf("ParseMatchEnvelope failed: %v", err)
+	}
+	if decoded.ID != envelope.ID || decoded.Spec.MatchNumber != "M1" {
+		t.Errorf("Round trip mismatch: %+v", decoded)
+	}
+
+	indented, err := envelope.ToJSONIndent("", "  ")
+	if err != nil {
+		t.Fatalf("ToJSONIndent failed: %v", err)
+	}
+	if !strings.Contains(string(indented), "\n  \"id\"") {
+		t.Errorf("Expected indented JSON, got %s", indented)
+	}
+
+	if _, err := ParseTournamentEnvelope(data); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType for match parsed as tournament, got %v", err)
+	}
+	if _, err := ParseEntryEnvelope([]byte("{")); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat for bad JSON, got %v", err)
+	}
+}
+
+func TestEnvelope_BumpVersion(t *testing.T) {
+	updated := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	envelope := &Envelope[Player]{
+		ID:   "ptd:player:1",
+		Type: TypePlayer,
+		Spec: Player{FirstName: "Ma", LastName: "Long"},
+		Meta: Meta{Schema: "ptd.v1.player@1.0.0", Version: 3, UpdatedAt: updated},
+	}
+
+	bumped := envelope.BumpVersion()
+	if bumped.Version() != 4 {
+		t.Errorf("Version() = %d, want 4", bumped.Version())
+	}
+	if !bumped.Meta.UpdatedAt.After(updated) {
+		t.Errorf("UpdatedAt = %v, want after %v", bumped.Meta.UpdatedAt, updated)
+	}
+	if envelope.Version() != 3 || !envelope.Meta.UpdatedAt.Equal(updated) {
+		t.Error("BumpVersion() modified the original envelope")
+	}
+	if bumped.ID != envelope.ID || bumped.Spec.LastName != "Long" {
+		t.Errorf("BumpVersion() = %+v, want a copy of the envelope", bumped)
+	}
+}
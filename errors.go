@@ -1,6 +1,9 @@
 package ptd
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Common PTD errors
 var (
@@ -26,6 +29,10 @@ var (
 	ErrManifestMissing = errors.New("ptd: manifest.json not found")
 	ErrManifestInvalid = errors.New("ptd: invalid manifest")
 	ErrHashMismatch    = errors.New("ptd: file hash mismatch")
+	ErrVersionConflict = errors.New("ptd: entity version conflict")
+	ErrEncrypted       = errors.New("ptd: package is encrypted")
+	ErrDecryption      = errors.New("ptd: package decryption failed")
+	ErrPackageNotFound = errors.New("ptd: package not found")
 
 	// Import/Export errors
 	ErrImportFailed       = errors.New("ptd: import failed")
@@ -33,3 +40,68 @@ var (
 	ErrUnsupportedVersion = errors.New("ptd: unsupported PTD version")
 	ErrDuplicateEntity    = errors.New("ptd: duplicate entity detected")
 )
+
+// ValidationError describes a validation failure with the context of the
+// entity that triggered it. It wraps one of the sentinel errors above, so
+// errors.Is(err, ErrMissingField) and friends keep working.
+type ValidationError struct {
+	EntityID   string // ID of the offending entity, when known
+	EntityType string // Entity type (e.g., "match")
+	FieldPath  string // Path of the offending field (e.g., "match.event_id")
+	Message    string // Human-readable description
+	Err        error  // Underlying sentinel error
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	msg := e.Message
+	if e.Err != nil {
+		if msg == "" {
+			msg = e.Err.Error()
+		} else {
+			msg = fmt.Sprintf("%v: %s", e.Err, msg)
+		}
+	}
+
+	if e.EntityID != "" {
+		return fmt.Sprintf("%s (%s %s)", msg, e.EntityType, e.EntityID)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError creates a ValidationError for the given entity type and field
+func newValidationError(err error, entityType, fieldPath, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{
+		EntityType: entityType,
+		FieldPath:  fieldPath,
+		Message:    fmt.Sprintf(format, args...),
+		Err:        err,
+	}
+}
+
+// withEntityContext attaches the entity ID and type to a ValidationError
+// in err's chain. Errors without a ValidationError are wrapped in one.
+func withEntityContext(err error, entityID, entityType string) error {
+	if err == nil {
+		return nil
+	}
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		ve = &ValidationError{Err: err}
+		err = ve
+	}
+	if ve.EntityID == "" {
+		ve.EntityID = entityID
+	}
+	if ve.EntityType == "" {
+		ve.EntityType = entityType
+	}
+
+	return err
+}
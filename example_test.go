@@ -12,14 +12,14 @@ import (
 func TestCreateTournament(t *testing.T) {
 	// Create a tournament
 	tournament := ptd.Tournament{
-		Name:        "Summer Championship 2025",
+		Name:        ptd.MultiName{Default: "Summer Championship 2025"},
 		Description: "Annual summer table tennis championship",
 		StartDate:   time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
 		EndDate:     time.Date(2025, 7, 3, 18, 0, 0, 0, time.UTC),
 		Status:      "published",
 		Format:      "round_robin",
 		Venue: &ptd.Venue{
-			Name:    "Sports Complex",
+			Name:    ptd.MultiName{Default: "Sports Complex"},
 			City:    "San Francisco",
 			Country: "USA",
 		},
@@ -85,7 +85,7 @@ func TestCanonicalJSON(t *testing.T) {
 	// Create an event
 	event := ptd.Event{
 		TournamentID: ptd.GenerateID(ptd.TypeTournament),
-		Name:         "Men's Singles",
+		Name:         ptd.MultiName{Default: "Men's Singles"},
 		EventCode:    "MS",
 		EventType:    "singles",
 		Gender:       "male",
@@ -124,3 +124,22 @@ func TestCanonicalJSON(t *testing.T) {
 		t.Fatalf("Canonical JSON is not valid: %v", err)
 	}
 }
+
+// Extract the completed matches of one event
+func ExampleFilterEnvelopes() {
+	eventID := "ptd:event:ms"
+	matches := []ptd.Envelope[ptd.Match]{
+		{ID: "ptd:match:1", Spec: ptd.Match{EventID: eventID, MatchNumber: "1", Status: "completed"}},
+		{ID: "ptd:match:2", Spec: ptd.Match{EventID: eventID, MatchNumber: "2", Status: "scheduled"}},
+		{ID: "ptd:match:3", Spec: ptd.Match{EventID: "ptd:event:ws", MatchNumber: "1", Status: "completed"}},
+	}
+
+	completed := ptd.FilterEnvelopes(matches, func(m ptd.Envelope[ptd.Match]) bool {
+		return m.Spec.EventID == eventID && m.Spec.Status == "completed"
+	})
+
+	for _, m := range completed {
+		fmt.Println(m.ID)
+	}
+	// Output: ptd:match:1
+}
@@ -30,12 +30,12 @@ func main() {
 
 	// Create a tournament envelope
 	tournament := ptd.Tournament{
-		Name:      "World Championship 2025",
+		Name:      ptd.MultiName{Default: "World Championship 2025"},
 		StartDate: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
 		EndDate:   time.Date(2025, 7, 10, 18, 0, 0, 0, time.UTC),
 		Status:    "published",
 		Venue: &ptd.Venue{
-			Name:    "Tokyo Metropolitan Gymnasium",
+			Name:    ptd.MultiName{Default: "Tokyo Metropolitan Gymnasium"},
 			City:    "Tokyo",
 			Country: "Japan",
 		},
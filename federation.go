@@ -0,0 +1,129 @@
+package ptd
+
+import (
+	"sort"
+	"strings"
+)
+
+// CountryStats summarizes a country's participation in a package
+type CountryStats struct {
+	Country      string `json:"country"`
+	TotalPlayers int    `json:"total_players"` // Distinct players
+	TotalEntries int    `json:"total_entries"` // Entries with at least one player from the country
+}
+
+// ExtractPlayersByCountry groups the distinct players of a package by
+// Player.Country (upper-cased; players without a country are grouped under
+// ""). Players are gathered from player entities and from the players
+// embedded in entries. A player appearing in several entries, or both as an
+// entity and in entries, is listed once: players are matched by PlayerID,
+// or by name when they have none. Players known only from
+// entries are returned in envelopes without an ID.
+func ExtractPlayersByCountry(pkg *Package) (map[string][]Envelope[Player], error) {
+	players, _, err := collectCountryPlayers(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	byCountry := make(map[string][]Envelope[Player])
+	for _, player := range players {
+		country := playerCountry(player.Spec)
+		byCountry[country] = append(byCountry[country], player)
+	}
+	return byCountry, nil
+}
+
+// ExtractCountryStats returns the number of distinct players and entries
+// per country, keyed like ExtractPlayersByCountry
+func ExtractCountryStats(pkg *Package) (map[string]*CountryStats, error) {
+	players, entryCountries, err := collectCountryPlayers(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*CountryStats)
+	get := func(country string) *CountryStats {
+		if stats[country] == nil {
+			stats[country] = &CountryStats{Country: country}
+		}
+		return stats[country]
+	}
+	for _, player := range players {
+		get(playerCountry(player.Spec)).TotalPlayers++
+	}
+	for _, countries := range entryCountries {
+		for country := range countries {
+			get(country).TotalEntries++
+		}
+	}
+	return stats, nil
+}
+
+// CountriesRepresented returns the sorted country codes of the package's players
+func CountriesRepresented(pkg *Package) ([]string, error) {
+	byCountry, err := ExtractPlayersByCountry(pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make([]string, 0, len(byCountry))
+	for country := range byCountry {
+		if country != "" {
+			countries = append(countries, country)
+		}
+	}
+	sort.Strings(countries)
+	return countries, nil
+}
+
+// collectCountryPlayers returns the distinct players of a package, in the
+// order first seen, and the set of countries represented in each entry
+func collectCountryPlayers(pkg *Package) ([]Envelope[Player], []map[string]bool, error) {
+	playerEnvelopes, err := decodeEntityLines[Player](pkg, TypePlayer)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := decodeEntityLines[Entry](pkg, TypeEntry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var players []Envelope[Player]
+	seen := make(map[string]bool)
+	add := func(player Envelope[Player]) {
+		key := player.ID
+		if player.Spec.PlayerID != "" || playerName(player.Spec) != "" {
+			key = playerIdentity(player.Spec)
+		}
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		players = append(players, player)
+	}
+
+	for _, player := range playerEnvelopes {
+		add(player)
+	}
+
+	entryCountries := make([]map[string]bool, 0, len(entries))
+	for _, entry := range entries {
+		countries := make(map[string]bool)
+		for _, player := range entry.Spec.Players {
+			add(Envelope[Player]{Type: TypePlayer, Spec: player})
+			countries[playerCountry(player)] = true
+		}
+		entryCountries = append(entryCountries, countries)
+	}
+
+	return players, entryCountries, nil
+}
+
+// playerCountry returns a player's normalized country code: its ISO 3166-1
+// alpha-3 form if known, so that "DE", "DEU" and "GER" count as one country
+func playerCountry(player Player) string {
+	if country, err := NormalizeCountryCode(player.Country); err == nil {
+		return country
+	}
+	return strings.ToUpper(strings.TrimSpace(player.Country))
+}
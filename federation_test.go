@@ -0,0 +1,73 @@
+package ptd
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newFederationTestPackage creates a package with two player entities and
+// three entries sharing some of their players
+func newFederationTestPackage(t *testing.T) *Package {
+	t.Helper()
+	pkg := NewPackage("Federation test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	maLong := Player{FirstName: "Ma", LastName: "Long", Country: "CHN", PlayerID: "ITTF-1"}
+	fan := Player{FirstName: "Fan", LastName: "Zhendong", Country: "chn"}
+	harimoto := Player{FirstName: "Tomokazu", LastName: "Harimoto", Country: "JPN"}
+	unknown := Player{FirstName: "Guest", LastName: "Player"}
+
+	players := []interface{}{
+		Envelope[Player]{ID: GenerateID(TypePlayer), Type: TypePlayer, Spec: maLong, Meta: Meta{Schema: "ptd.v1.player@1.0.0"}},
+		Envelope[Player]{ID: GenerateID(TypePlayer), Type: TypePlayer, Spec: harimoto, Meta: Meta{Schema: "ptd.v1.player@1.0.0"}},
+	}
+	entries := []interface{}{
+		Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry, Spec: Entry{EntryType: "doubles", Players: []Player{maLong, fan}}, Meta: Meta{Schema: "ptd.v1.entry@1.0.0"}},
+		Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry, Spec: Entry{EntryType: "individual", Players: []Player{fan}}, Meta: Meta{Schema: "ptd.v1.entry@1.0.0"}},
+		Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry, Spec: Entry{EntryType: "doubles", Players: []Player{harimoto, unknown}}, Meta: Meta{Schema: "ptd.v1.entry@1.0.0"}},
+	}
+	if err := pkg.AddEntities(TypePlayer, players); err != nil {
+		t.Fatalf("Failed to add players: %v", err)
+	}
+	if err := pkg.AddEntities(TypeEntry, entries); err != nil {
+		t.Fatalf("Failed to add entries: %v", err)
+	}
+	return pkg
+}
+
+func TestExtractPlayersByCountry(t *testing.T) {
+	pkg := newFederationTestPackage(t)
+
+	byCountry, err := ExtractPlayersByCountry(pkg)
+	if err != nil {
+		t.Fatalf("ExtractPlayersByCountry failed: %v", err)
+	}
+	if len(byCountry["CHN"]) != 2 || len(byCountry["JPN"]) != 1 || len(byCountry[""]) != 1 {
+		t.Errorf("Unexpected grouping: CHN=%d JPN=%d none=%d", len(byCountry["CHN"]), len(byCountry["JPN"]), len(byCountry[""]))
+	}
+	if byCountry["CHN"][0].ID == "" {
+		t.Error("Expected player entity to be used for Ma Long")
+	}
+	if byCountry["CHN"][1].ID != "" || byCountry["CHN"][1].Spec.LastName != "Zhendong" {
+		t.Errorf("Expected entry-only player Fan Zhendong, got %+v", byCountry["CHN"][1])
+	}
+
+	stats, err := ExtractCountryStats(pkg)
+	if err != nil {
+		t.Fatalf("ExtractCountryStats failed: %v", err)
+	}
+	if got := *stats["CHN"]; got != (CountryStats{Country: "CHN", TotalPlayers: 2, TotalEntries: 2}) {
+		t.Errorf("Unexpected CHN stats: %+v", got)
+	}
+	if got := *stats["JPN"]; got != (CountryStats{Country: "JPN", TotalPlayers: 1, TotalEntries: 1}) {
+		t.Errorf("Unexpected JPN stats: %+v", got)
+	}
+
+	countries, err := CountriesRepresented(pkg)
+	if err != nil {
+		t.Fatalf("CountriesRepresented failed: %v", err)
+	}
+	if !reflect.DeepEqual(countries, []string{"CHN", "JPN"}) {
+		t.Errorf("Expected [CHN JPN], got %v", countries)
+	}
+}
@@ -0,0 +1,161 @@
+package ptd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// fideCSVHeaders are the columns written by ExportToFideCSV
+var fideCSVHeaders = []string{
+	"round", "match_number", "fide_id", "name", "rating", "federation",
+	"opponent_fide_id", "opponent_name", "opponent_rating", "result", "points",
+}
+
+// ExportToFideCSV writes the completed individual matches of a package as a
+// FIDE-style results CSV, one row per player and game, so that results can
+// be submitted through federation tools built for chess. Rows are ordered
+// by round number, then by the order of the matches in the package.
+//
+// PTD concepts map to the FIDE columns as follows:
+//
+//   - round: the RoundNumber of the match's round, empty if unknown.
+//   - fide_id: Player.PlayerID, the player's federation identifier (an ITTF
+//     ID or USATT membership number takes the place of the FIDE ID).
+//   - name: "LastName, FirstName", as in FIDE rating lists.
+//   - rating: Rating.Value for Elo-based systems (FIDE, ELO and USATT,
+//     whose ratings share the FIDE scale); ITTF ranking points are not a
+//     rating, so those players are exported as unrated with an empty value.
+//   - federation: the IOC code of Player.Country, which FIDE federations
+//     use ("DEU" is exported as "GER"); unknown codes are upper-cased.
+//   - result: "1" for a win and "0" for a loss, or FIDE's forfeit notation
+//     "+" and "-" for walkovers. Racquet sports have no drawn games.
+//   - points: the player's running total of game points after the match.
+//
+// FIDE results are for individual players, so matches between doubles or
+// team entries, and matches without a winner, are skipped.
+func ExportToFideCSV(pkg *Package, w io.Writer) error {
+	entries, err := decodeEntityLines[Entry](pkg, TypeEntry)
+	if err != nil {
+		return err
+	}
+	rounds, err := decodeEntityLines[Round](pkg, TypeRound)
+	if err != nil {
+		return err
+	}
+	matches, err := decodeEntityLines[Match](pkg, TypeMatch)
+	if err != nil {
+		return err
+	}
+
+	players := make(map[string]Player)
+	for _, entry := range entries {
+		if len(entry.Spec.Players) == 1 {
+			players[entry.ID] = entry.Spec.Players[0]
+		}
+	}
+	roundNumbers := make(map[string]int)
+	for _, round := range rounds {
+		roundNumbers[round.ID] = round.Spec.RoundNumber
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return roundNumbers[matches[i].Spec.RoundID] < roundNumbers[matches[j].Spec.RoundID]
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(fideCSVHeaders); err != nil {
+		return fmt.Errorf("failed to write FIDE CSV header: %w", err)
+	}
+
+	points := make(map[string]float64) // entry ID -> game points
+	for _, match := range matches {
+		m := match.Spec
+		if m.Status != "completed" || m.HomeEntry == nil || m.AwayEntry == nil {
+			continue
+		}
+		home, homeOK := players[m.HomeEntry.EntryID]
+		away, awayOK := players[m.AwayEntry.EntryID]
+		if !homeOK || !awayOK {
+			continue
+		}
+		winner, err := m.winnerRef()
+		if err != nil {
+			continue
+		}
+
+		var round string
+		if number, ok := roundNumbers[m.RoundID]; ok {
+			round = strconv.Itoa(number)
+		}
+		walkover := m.Score != nil && m.Score.Walkover
+
+		sides := []struct {
+			entryID        string
+			player, versus Player
+		}{
+			{m.HomeEntry.EntryID, home, away},
+			{m.AwayEntry.EntryID, away, home},
+		}
+		for _, side := range sides {
+			won := side.entryID == winner.EntryID
+			if won {
+				points[side.entryID]++
+			}
+			record := []string{
+				round, m.MatchNumber,
+				side.player.PlayerID, fideName(side.player), fideRating(side.player), fideFederation(side.player),
+				side.versus.PlayerID, fideName(side.versus), fideRating(side.versus),
+				fideResult(won, walkover), strconv.FormatFloat(points[side.entryID], 'f', -1, 64),
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write FIDE CSV row: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write FIDE CSV: %w", err)
+	}
+	return nil
+}
+
+// fideName formats a player's name as "LastName, FirstName"
+func fideName(player Player) string {
+	if player.FirstName == "" {
+		return player.LastName
+	}
+	return player.LastName + ", " + player.FirstName
+}
+
+// fideRating returns a player's rating on the FIDE scale, or "" if unrated
+func fideRating(player Player) string {
+	if player.Rating == nil || !isELOScale(player.Rating) {
+		return ""
+	}
+	return strconv.Itoa(player.Rating.Value)
+}
+
+// fideFederation returns the FIDE federation code of a player's country
+func fideFederation(player Player) string {
+	if federation, err := IOCCountryCode(player.Country); err == nil {
+		return federation
+	}
+	return playerCountry(player)
+}
+
+// fideResult returns the FIDE result notation of a game
+func fideResult(won, forfeit bool) string {
+	switch {
+	case forfeit && won:
+		return "+"
+	case forfeit:
+		return "-"
+	case won:
+		return "1"
+	default:
+		return "0"
+	}
+}
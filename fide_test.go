@@ -0,0 +1,112 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+)
+
+func TestExportToFideCSV(t *testing.T) {
+	pkg := NewPackage("FIDE test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	maLong := Player{FirstName: "Long", LastName: "Ma", Country: "chn", PlayerID: "ITTF-1", Rating: &Rating{Value: 2850, System: "USATT"}}
+	fan := Player{FirstName: "Zhendong", LastName: "Fan", Country: "CHN", PlayerID: "ITTF-2", Rating: &Rating{Value: 9000, System: "ITTF"}}
+	harimoto := Player{FirstName: "Tomokazu", LastName: "Harimoto", Country: "JPN", PlayerID: "ITTF-3"}
+
+	entry := func(id string, players ...Player) interface{} {
+		return Envelope[Entry]{ID: id, Type: TypeEntry, Spec: Entry{EventID: "ptd:event:1", EntryType: "individual", Players: players}}
+	}
+	ref := func(id string) *EntryRef { return &EntryRef{EntryID: id, DisplayName: id} }
+	match := func(id, round, number, home, away string, score *Score) interface{} {
+		return Envelope[Match]{ID: id, Type: TypeMatch, Spec: Match{
+			EventID: "ptd:event:1", RoundID: round, MatchNumber: number, Status: "completed",
+			HomeEntry: ref(home), AwayEntry: ref(away), Score: score,
+		}}
+	}
+	walkover := match("ptd:match:wo", "ptd:round:1", "SF2", "ptd:entry:harimoto", "ptd:entry:fan", &Score{Final: "W/O", Walkover: true}).(Envelope[Match])
+	walkover.Spec.Winner = "ptd:entry:fan"
+
+	entities := map[string][]interface{}{
+		TypeEntry: {
+			entry("ptd:entry:ma", maLong),
+			entry("ptd:entry:fan", fan),
+			entry("ptd:entry:harimoto", harimoto),
+			entry("ptd:entry:pair", maLong, fan),
+		},
+		TypeRound: {
+			Envelope[Round]{ID: "ptd:round:2", Type: TypeRound, Spec: Round{EventID: "ptd:event:1", Name: "Final", RoundNumber: 2}},
+			Envelope[Round]{ID: "ptd:round:1", Type: TypeRound, Spec: Round{EventID: "ptd:event:1", Name: "Semifinal", RoundNumber: 1}},
+		},
+		TypeMatch: {
+			match("ptd:match:f", "ptd:round:2", "F", "ptd:entry:ma", "ptd:entry:fan", newTestScore("1-3", [2]int{11, 9}, [2]int{5, 11}, [2]int{7, 11}, [2]int{9, 11})),
+			match("ptd:match:sf", "ptd:round:1", "SF1", "ptd:entry:ma", "ptd:entry:harimoto", newTestScore("3-0", [2]int{11, 9}, [2]int{11, 5}, [2]int{11, 7})),
+			walkover,
+			match("ptd:match:doubles", "ptd:round:1", "D1", "ptd:entry:pair", "ptd:entry:fan", newTestScore("3-0")),
+		},
+	}
+	for _, entityType := range []string{TypeEntry, TypeRound, TypeMatch} {
+		if err := pkg.AddEntities(entityType, entities[entityType]); err != nil {
+			t.Fatalf("Failed to add %s: %v", entityType, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := ExportToFideCSV(pkg, &buf); err != nil {
+		t.Fatalf("ExportToFideCSV() error = %v", err)
+	}
+
+	// Rows round trip through encoding/csv
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read exported CSV: %v", err)
+	}
+	want := [][]string{
+		fideCSVHeaders,
+		{"1", "SF1", "ITTF-1", "Ma, Long", "2850", "CHN", "ITTF-3", "Harimoto, Tomokazu", "", "1", "1"},
+		{"1", "SF1", "ITTF-3", "Harimoto, Tomokazu", "", "JPN", "ITTF-1", "Ma, Long", "2850", "0", "0"},
+		{"1", "SF2", "ITTF-3", "Harimoto, Tomokazu", "", "JPN", "ITTF-2", "Fan, Zhendong", "", "-", "0"},
+		{"1", "SF2", "ITTF-2", "Fan, Zhendong", "", "CHN", "ITTF-3", "Harimoto, Tomokazu", "", "+", "1"},
+		{"2", "F", "ITTF-1", "Ma, Long", "2850", "CHN", "ITTF-2", "Fan, Zhendong", "", "0", "1"},
+		{"2", "F", "ITTF-2", "Fan, Zhendong", "", "CHN", "ITTF-1", "Ma, Long", "2850", "1", "2"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("ExportToFideCSV() records =\n%v\nwant\n%v", records, want)
+	}
+}
+
+func TestFideResult(t *testing.T) {
+	tests := []struct {
+		won, forfeit bool
+		want         string
+	}{
+		{true, false, "1"},
+		{false, false, "0"},
+		{true, true, "+"},
+		{false, true, "-"},
+	}
+	for _, tt := range tests {
+		if got := fideResult(tt.won, tt.forfeit); got != tt.want {
+			t.Errorf("fideResult(%v, %v) = %q, want %q", tt.won, tt.forfeit, got, tt.want)
+		}
+	}
+}
+
+func TestFideFederation(t *testing.T) {
+	tests := []struct {
+		country string
+		want    string
+	}{
+		{"DEU", "GER"},
+		{"ger", "GER"},
+		{"de", "GER"},
+		{"CHN", "CHN"},
+		{"kos", "KOS"},
+	}
+	for _, tt := range tests {
+		if got := fideFederation(Player{Country: tt.country}); got != tt.want {
+			t.Errorf("fideFederation(%q) = %q, want %q", tt.country, got, tt.want)
+		}
+	}
+}
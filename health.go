@@ -0,0 +1,166 @@
+package ptd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Package health score weights. They add up to 100.
+const (
+	healthManifest   = 10 // Manifest present
+	healthHashes     = 20 // Every file matches its manifest hash
+	healthValidation = 20 // Every entity passes strict validation
+	healthSignatures = 20 // Every entity carries a signature
+	healthReferences = 15 // Every cross-reference resolves within the package
+	healthExpiry     = 15 // No entity is past its expiry
+)
+
+// ExtensionExpiresAt is the Meta.Extensions key holding an entity's expiry
+// time in RFC 3339 format
+const ExtensionExpiresAt = "expires_at"
+
+// PackageHealthScore rates a package from 0 to 100. Each check below earns
+// its points only if it passes for the whole package:
+//
+//	manifest present                        +10
+//	all file hashes match the manifest      +20
+//	all entities pass strict validation     +20
+//	all entities are signed                 +20
+//	cross-references resolve                +15
+//	no entity is expired                    +15
+//
+// Cross-references are the tournament_id, event_id and home/away entry IDs
+// of events, entries and matches. An entity is expired when its
+// Meta.Extensions["expires_at"] lies in the past. Signatures are checked for
+// presence only, since verifying them needs the signers' public keys.
+//
+// Entities are read from the package's working directory or, for packages
+// loaded with OpenPackage and similar, from the archive. Hashes are compared
+// only for files in a working directory: archives are verified when opened,
+// or as entities are read if verification was deferred, in which case a
+// mismatch is returned as an error wrapping ErrHashMismatch.
+//
+// The returned deductions explain every check that failed. A missing
+// manifest is fatal and returns ErrManifestMissing.
+func PackageHealthScore(pkg *Package) (int, []string, error) {
+	if pkg == nil || pkg.Manifest == nil {
+		return 0, []string{fmt.Sprintf("manifest missing (-%d)", healthManifest)}, ErrManifestMissing
+	}
+
+	score := healthManifest
+	var deductions []string
+	check := func(points int, failures []string, what string) {
+		if len(failures) == 0 {
+			score += points
+			return
+		}
+		deductions = append(deductions, fmt.Sprintf("%s (-%d): %s", what, points, failures[0]))
+		for _, failure := range failures[1:] {
+			deductions = append(deductions, fmt.Sprintf("%s: %s", what, failure))
+		}
+	}
+
+	hashFailures, err := pkg.hashFailures()
+	if err != nil {
+		return 0, nil, err
+	}
+	check(healthHashes, hashFailures, "file hashes")
+
+	var invalid, unsigned, unresolved, expired []string
+	ids := make(map[string]bool)
+	var entities []Envelope[map[string]interface{}]
+	for _, entityType := range pkg.entityTypes() {
+		envelopes, err := decodeEntityLines[map[string]interface{}](pkg, entityType)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, envelope := range envelopes {
+			ids[envelope.ID] = true
+		}
+		entities = append(entities, envelopes...)
+	}
+
+	validator := NewSchemaValidator(true)
+	now := time.Now()
+	for _, envelope := range entities {
+		if err := validator.ValidateEnvelope(envelope); err != nil {
+			invalid = append(invalid, err.Error())
+		}
+		if envelope.Meta.Signature == nil {
+			unsigned = append(unsigned, fmt.Sprintf("%s %s is not signed", envelope.Type, envelope.ID))
+		}
+		for _, ref := range entityReferences(envelope.Spec) {
+			if !ids[ref] {
+				unresolved = append(unresolved, fmt.Sprintf("%s %s references missing %s", envelope.Type, envelope.ID, ref))
+			}
+		}
+		if raw, ok := envelope.Meta.Extensions[ExtensionExpiresAt].(string); ok {
+			if expiresAt, err := time.Parse(time.RFC3339, raw); err == nil && expiresAt.Before(now) {
+				expired = append(expired, fmt.Sprintf("%s %s expired at %s", envelope.Type, envelope.ID, raw))
+			}
+		}
+	}
+
+	check(healthValidation, invalid, "validation")
+	check(healthSignatures, unsigned, "signatures")
+	check(healthReferences, unresolved, "references")
+	check(healthExpiry, expired, "expiry")
+
+	return score, deductions, nil
+}
+
+// hashFailures compares the manifest's file entries with the files in the
+// package's working directory
+func (p *Package) hashFailures() ([]string, error) {
+	if p.tempDir == "" {
+		return nil, nil
+	}
+
+	paths := make([]string, 0, len(p.Manifest.Files))
+	for path := range p.Manifest.Files {
+		if path != "manifest.json" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var failures []string
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(p.tempDir, path))
+		if err != nil {
+			if os.IsNotExist(err) {
+				failures = append(failures, fmt.Sprintf("%s is missing", path))
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != p.Manifest.Files[path].Hash {
+			failures = append(failures, fmt.Sprintf("%s does not match its manifest hash", path))
+		}
+	}
+	return failures, nil
+}
+
+// entityReferences returns the IDs of other entities referenced by a spec
+func entityReferences(spec map[string]interface{}) []string {
+	var refs []string
+	for _, key := range []string{"tournament_id", "event_id"} {
+		if id, ok := spec[key].(string); ok && id != "" {
+			refs = append(refs, id)
+		}
+	}
+	for _, key := range []string{"home_entry", "away_entry"} {
+		if ref, ok := spec[key].(map[string]interface{}); ok {
+			if id, ok := ref["entry_id"].(string); ok && id != "" {
+				refs = append(refs, id)
+			}
+		}
+	}
+	return refs
+}
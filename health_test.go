@@ -0,0 +1,137 @@
+package ptd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newHealthyPackage creates a package whose entities are valid, signed and cross-referenced
+func newHealthyPackage(t *testing.T) *Package {
+	t.Helper()
+	signer, err := NewSigner("test-key", "test")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	tournamentID := GenerateID(TypeTournament)
+	eventID := GenerateID(TypeEvent)
+	tournament := &Envelope[Tournament]{ID: tournamentID, Type: TypeTournament, Spec: Tournament{Name: MultiName{Default: "Open"}, Status: "published"}, Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"}}
+	event := &Envelope[Event]{ID: eventID, Type: TypeEvent, Spec: Event{TournamentID: tournamentID, Name: MultiName{Default: "MS"}}, Meta: Meta{Schema: "ptd.v1.event@1.0.0"}}
+	for _, e := range []interface{}{tournament, event} {
+		if err := signer.Sign(e); err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+	}
+
+	pkg := NewPackage("Health test")
+	t.Cleanup(func() { pkg.Cleanup() })
+	if err := pkg.AddEntities(TypeTournament, []interface{}{tournament}); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	if err := pkg.AddEntities(TypeEvent, []interface{}{event}); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	return pkg
+}
+
+func TestPackageHealthScore(t *testing.T) {
+	pkg := newHealthyPackage(t)
+
+	score, deductions, err := PackageHealthScore(pkg)
+	if err != nil {
+		t.Fatalf("PackageHealthScore failed: %v", err)
+	}
+	if score != 100 || len(deductions) != 0 {
+		t.Fatalf("Expected perfect score, got %d: %v", score, deductions)
+	}
+
+	// Unsigned entity with a dangling reference and an expiry in the past
+	expired := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	match := Envelope[Match]{
+		ID:   GenerateID(TypeMatch),
+		Type: TypeMatch,
+		Spec: Match{EventID: GenerateID(TypeEvent), MatchNumber: "1", Status: "scheduled"},
+		Meta: Meta{Schema: "ptd.v1.match@1.0.0", Extensions: map[string]interface{}{ExtensionExpiresAt: expired}},
+	}
+	if err := pkg.AddEntities(TypeMatch, []interface{}{match}); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	score, deductions, err = PackageHealthScore(pkg)
+	if err != nil {
+		t.Fatalf("PackageHealthScore failed: %v", err)
+	}
+	if score != 50 {
+		t.Errorf("Expected 50, got %d: %v", score, deductions)
+	}
+	joined := strings.Join(deductions, "\n")
+	for _, want := range []string{"signatures (-20)", "references (-15)", "expiry (-15)"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Deductions missing %q: %v", want, deductions)
+		}
+	}
+}
+
+func TestPackageHealthScore_OpenedArchive(t *testing.T) {
+	pkg := newHealthyPackage(t)
+	match := Envelope[Match]{
+		ID:   GenerateID(TypeMatch),
+		Type: TypeMatch,
+		Spec: Match{EventID: GenerateID(TypeEvent), MatchNumber: "1", Status: "scheduled"},
+		Meta: Meta{Schema: "ptd.v1.match@1.0.0"},
+	}
+	if err := pkg.AddEntities(TypeMatch, []interface{}{match}); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "health.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	// Entities are read from the archive, so the unsigned match with a
+	// dangling reference costs points just as in the working directory
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to open package: %v", err)
+	}
+	score, deductions, err := PackageHealthScore(opened)
+	if err != nil {
+		t.Fatalf("PackageHealthScore failed: %v", err)
+	}
+	if score != 65 {
+		t.Errorf("Expected 65, got %d: %v", score, deductions)
+	}
+}
+
+func TestPackageHealthScore_Hashes(t *testing.T) {
+	pkg := newHealthyPackage(t)
+	archivePath := filepath.Join(t.TempDir(), "health.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	// Tamper with a file after its hash was recorded
+	path := filepath.Join(pkg.tempDir, entityFilePath(TypeEvent))
+	if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	score, deductions, err := PackageHealthScore(pkg)
+	if err != nil {
+		t.Fatalf("PackageHealthScore failed: %v", err)
+	}
+	if score > 80 || !strings.Contains(strings.Join(deductions, "\n"), "file hashes (-20)") {
+		t.Errorf("Expected hash deduction, got %d: %v", score, deductions)
+	}
+}
+
+func TestPackageHealthScore_MissingManifest(t *testing.T) {
+	score, deductions, err := PackageHealthScore(&Package{})
+	if !errors.Is(err, ErrManifestMissing) || score != 0 || len(deductions) != 1 {
+		t.Errorf("Expected fatal missing manifest, got %d, %v, %v", score, deductions, err)
+	}
+}
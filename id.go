@@ -87,9 +87,12 @@ const (
 	TypeMatch      = "match"
 	TypeEntry      = "entry"
 	TypePlayer     = "player"
+	TypeCoach      = "coach"
 	TypeRound      = "round"
+	TypePhase      = "phase"
 	TypeBracket    = "bracket"
 	TypeVenue      = "venue"
 	TypeOrganizer  = "organizer"
 	TypeOfficial   = "official"
+	TypeRanking    = "ranking"
 )
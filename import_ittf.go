@@ -0,0 +1,174 @@
+package ptd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ittfRankingSource is the provenance source of imported ITTF world rankings
+const ittfRankingSource = "ittf:world_ranking"
+
+// ImportITTFWorldRankingCSV parses an ITTF world ranking CSV with the columns
+// Rank, Name, Association, Points and (optionally) Trend. Columns are matched
+// by header name, so their order does not matter. Names in ITTF form
+// ("FAN Zhendong") are split into last and first name, and the association
+// becomes the player's country, converted from the ITTF's IOC code to ISO
+// 3166-1 alpha-3 ("GER" becomes "DEU"). Associations without an ISO code
+// are kept as they are.
+func ImportITTFWorldRankingCSV(r io.Reader) ([]Envelope[Ranking], error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: ranking CSV is empty", ErrImportFailed)
+		}
+		return nil, fmt.Errorf("%w: failed to read ranking header: %v", ErrImportFailed, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"rank", "name", "association", "points"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: ranking CSV missing %s column", ErrImportFailed, required)
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	now := time.Now()
+	var rankings []Envelope[Ranking]
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read ranking CSV: %v", ErrImportFailed, err)
+		}
+
+		name := field(record, "name")
+		if !utf8.ValidString(name) {
+			return nil, fmt.Errorf("%w: line %d: name is not valid UTF-8", ErrImportFailed, line)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%w: line %d: missing name", ErrImportFailed, line)
+		}
+
+		rank, err := strconv.Atoi(field(record, "rank"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid rank %q", ErrImportFailed, line, field(record, "rank"))
+		}
+		points, err := strconv.Atoi(strings.ReplaceAll(field(record, "points"), ",", ""))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid points %q", ErrImportFailed, line, field(record, "points"))
+		}
+
+		lastName, firstName := splitITTFName(name)
+		importedAt := now
+		rankings = append(rankings, Envelope[Ranking]{
+			ID:   GenerateID(TypeRanking),
+			Type: TypeRanking,
+			Spec: Ranking{
+				Player: Player{
+					FirstName:   firstName,
+					LastName:    lastName,
+					DisplayName: name,
+					Country:     ittfCountry(field(record, "association")),
+				},
+				System: "ITTF",
+				Rank:   rank,
+				Points: points,
+				Trend:  field(record, "trend"),
+			},
+			Meta: Meta{
+				Schema:    "ptd.v1.ranking@1.0.0",
+				Version:   1,
+				CreatedAt: now,
+				UpdatedAt: now,
+				Source:    "ittf",
+				Provenance: &Provenance{
+					OriginalSource: ittfRankingSource,
+					ImportedAt:     &importedAt,
+				},
+			},
+		})
+	}
+
+	return rankings, nil
+}
+
+// splitITTFName splits an ITTF-style name, where the family name is written
+// in capitals before the given names ("MOREGÅRD Truls"), into last and first
+// name. The family name is returned in title case. Names without a capitalized
+// family name are returned whole as the last name.
+func splitITTFName(name string) (lastName, firstName string) {
+	words := strings.Fields(name)
+
+	split := 0
+	for split < len(words) && isUpperWord(words[split]) {
+		split++
+	}
+	if split == 0 || split == len(words) {
+		return name, ""
+	}
+
+	family := make([]string, split)
+	for i, word := range words[:split] {
+		family[i] = titleWord(word)
+	}
+	return strings.Join(family, " "), strings.Join(words[split:], " ")
+}
+
+// isUpperWord reports whether every letter in word is upper case
+func isUpperWord(word string) bool {
+	letters := false
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters = true
+		}
+	}
+	return letters
+}
+
+// titleWord converts an upper-case word to title case, keeping hyphenated
+// parts capitalized ("JEAN-LUC" becomes "Jean-Luc")
+func titleWord(word string) string {
+	parts := strings.Split(word, "-")
+	for i, part := range parts {
+		r, size := utf8.DecodeRuneInString(part)
+		if r == utf8.RuneError {
+			continue
+		}
+		parts[i] = string(unicode.ToUpper(r)) + strings.ToLower(part[size:])
+	}
+	return strings.Join(parts, "-")
+}
+
+// ittfCountry returns the PTD country code of an ITTF association
+func ittfCountry(association string) string {
+	if country, err := NormalizeCountryCode(association); err == nil {
+		return country
+	}
+	return strings.ToUpper(association)
+}
@@ -0,0 +1,74 @@
+package ptd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleITTFRanking = "\ufeffRank,Name,Association,Points,Trend\n" +
+	"1,WANG Chuqin,CHN,\"9,875\",=\n" +
+	"2,FAN Zhendong,CHN,7200,+1\n" +
+	"3,LIN Shidong,CHN,6550,-1\n" +
+	"4,HARIMOTO Tomokazu,JPN,5500,=\n" +
+	"5,MOREGÅRD Truls,SWE,4750,+3\n" +
+	"6,CALDERANO Hugo,BRA,4625,=\n" +
+	"7,LEBRUN Félix,FRA,4200,-2\n" +
+	"8,JANG Woojin,KOR,3400,+1\n" +
+	"9,OVTCHAROV Dimitrij,GER,3250,-1\n" +
+	"10,DE NODREST Léo,fra,1450,+12\n"
+
+func TestImportITTFWorldRankingCSV(t *testing.T) {
+	rankings, err := ImportITTFWorldRankingCSV(strings.NewReader(sampleITTFRanking))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if len(rankings) != 10 {
+		t.Fatalf("Expected 10 rankings, got %d", len(rankings))
+	}
+
+	first := rankings[0]
+	if first.Type != TypeRanking || !ValidateID(first.ID) {
+		t.Errorf("Unexpected envelope: %s %s", first.Type, first.ID)
+	}
+	if first.Spec.Rank != 1 || first.Spec.Points != 9875 || first.Spec.Trend != "=" || first.Spec.System != "ITTF" {
+		t.Errorf("Unexpected ranking: %+v", first.Spec)
+	}
+	if first.Meta.Provenance == nil || first.Meta.Provenance.OriginalSource != "ittf:world_ranking" {
+		t.Errorf("Missing provenance: %+v", first.Meta.Provenance)
+	}
+
+	tests := []struct {
+		index            int
+		last, first, cty string
+	}{
+		{1, "Fan", "Zhendong", "CHN"},
+		{4, "Moregård", "Truls", "SWE"},
+		{6, "Lebrun", "Félix", "FRA"},
+		{8, "Ovtcharov", "Dimitrij", "DEU"},
+		{9, "De Nodrest", "Léo", "FRA"},
+	}
+	for _, tt := range tests {
+		p := rankings[tt.index].Spec.Player
+		if p.LastName != tt.last || p.FirstName != tt.first || p.Country != tt.cty {
+			t.Errorf("Row %d: got %q %q %q", tt.index+1, p.LastName, p.FirstName, p.Country)
+		}
+	}
+	if rankings[4].Spec.Player.DisplayName != "MOREGÅRD Truls" {
+		t.Errorf("Display name should keep the original form: %s", rankings[4].Spec.Player.DisplayName)
+	}
+}
+
+func TestImportITTFWorldRankingCSV_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"Rank,Name,Points\n1,FAN Zhendong,7200\n",
+		"Rank,Name,Association,Points\nfirst,FAN Zhendong,CHN,7200\n",
+		"Rank,Name,Association,Points\n1,FAN Zhendong,CHN,many\n",
+	}
+	for _, input := range tests {
+		if _, err := ImportITTFWorldRankingCSV(strings.NewReader(input)); !errors.Is(err, ErrImportFailed) {
+			t.Errorf("Expected ErrImportFailed for %q, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// jsonSchemaURL is the URL under which schema documents are compiled; it
+// only appears in errors from the compiler
+const jsonSchemaURL = "ptd:schema.json"
+
+// jsonSchemaPrinter formats the messages of schema violations
+var jsonSchemaPrinter = message.NewPrinter(language.English)
+
+// jsonSchema is a compiled JSON Schema. Schemas are compiled and validated
+// by github.com/santhosh-tekuri/jsonschema, following draft 2020-12 unless
+// they declare another draft with $schema.
+type jsonSchema struct {
+	schema *jsonschema.Schema
+}
+
+// compileJSONSchema parses and compiles a JSON Schema document
+func compileJSONSchema(schemaJSON []byte) (*jsonSchema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON Schema: %v", ErrInvalidSchema, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(jsonSchemaURL, doc); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON Schema: %v", ErrInvalidSchema, err)
+	}
+	schema, err := compiler.Compile(jsonSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON Schema: %v", ErrInvalidSchema, err)
+	}
+	return &jsonSchema{schema: schema}, nil
+}
+
+// validate checks a decoded JSON value against the schema. Violations are
+// reported as a *ValidationError for the first of them, with its field
+// path below path: ErrMissingField for a missing required property and
+// ErrValidation otherwise.
+func (s *jsonSchema) validate(value interface{}, path string) error {
+	err := s.schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	// Report the first leaf of the error tree: the keyword that failed
+	for len(verr.Causes) > 0 {
+		verr = verr.Causes[0]
+	}
+	field := instanceFieldPath(value, verr.InstanceLocation, path)
+	if required, ok := verr.ErrorKind.(*kind.Required); ok && len(required.Missing) > 0 {
+		field = joinFieldPath(field, required.Missing[0])
+		return newValidationError(ErrMissingField, "", field, "%s is required", field)
+	}
+	return newValidationError(ErrValidation, "", field, "%s: %s", jsonSchemaPath(field), verr.ErrorKind.LocalizedString(jsonSchemaPrinter))
+}
+
+// instanceFieldPath converts the location of a value within a JSON
+// instance into a field path below path, such as "contacts[1].email"
+func instanceFieldPath(value interface{}, location []string, path string) string {
+	for _, token := range location {
+		if items, ok := value.([]interface{}); ok {
+			path = fmt.Sprintf("%s[%s]", path, token)
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(items) {
+				value = items[i]
+			}
+			continue
+		}
+		path = joinFieldPath(path, token)
+		if object, ok := value.(map[string]interface{}); ok {
+			value = object[token]
+		}
+	}
+	return path
+}
+
+// ValidateAgainstJSONSchema validates data against a JSON Schema document.
+// Data is compared in its JSON form, so structs are checked by their json
+// tags. Returns ErrInvalidSchema if the schema cannot be compiled, and a
+// *ValidationError wrapping ErrMissingField or ErrValidation if data does
+// not conform.
+func ValidateAgainstJSONSchema(schemaJSON []byte, data interface{}) error {
+	schema, err := compileJSONSchema(schemaJSON)
+	if err != nil {
+		return err
+	}
+	value, err := toJSONValue(data)
+	if err != nil {
+		return err
+	}
+	return schema.validate(value, "")
+}
+
+// jsonSchemaRegistry holds JSON Schemas registered for custom entity types,
+// compiled and as the documents they were registered with
+var jsonSchemaRegistry = struct {
+	sync.RWMutex
+	schemas   map[string]*jsonSchema
+	documents map[string][]byte
+}{schemas: make(map[string]*jsonSchema), documents: make(map[string][]byte)}
+
+// RegisterJSONSchema registers a JSON Schema for the specs of a custom
+// entity type. SchemaValidator then accepts the type in strict mode and
+// validates its specs against the schema, after any validator registered
+// with RegisterEntityType. Validation follows the JSON Schema standard in
+// both modes: undeclared properties are rejected only where the schema sets
+// additionalProperties to false.
+func RegisterJSONSchema(typeName string, schemaJSON []byte) error {
+	if typeName == "" {
+		return fmt.Errorf("%w: entity type name is required", ErrInvalidType)
+	}
+	if contains(builtinTypes, typeName) {
+		return fmt.Errorf("%w: %s is a built-in entity type", ErrInvalidType, typeName)
+	}
+
+	schema, err := compileJSONSchema(schemaJSON)
+	if err != nil {
+		return err
+	}
+
+	jsonSchemaRegistry.Lock()
+	defer jsonSchemaRegistry.Unlock()
+
+	if _, exists := jsonSchemaRegistry.schemas[typeName]; exists {
+		return fmt.Errorf("%w: JSON Schema for entity type %s is already registered", ErrDuplicateEntity, typeName)
+	}
+	jsonSchemaRegistry.schemas[typeName] = schema
+	jsonSchemaRegistry.documents[typeName] = bytes.Clone(schemaJSON)
+
+	return nil
+}
+
+// registeredJSONSchema returns the JSON Schema registered for a custom entity type
+func registeredJSONSchema(typeName string) (*jsonSchema, bool) {
+	jsonSchemaRegistry.RLock()
+	defer jsonSchemaRegistry.RUnlock()
+
+	schema, ok := jsonSchemaRegistry.schemas[typeName]
+	return schema, ok
+}
+
+// toJSONValue converts data to its generic decoded JSON form
+func toJSONValue(data interface{}) (interface{}, error) {
+	raw, ok := data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+		}
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFormat, err)
+	}
+	return value, nil
+}
+
+// joinFieldPath appends a property name to a field path
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonSchemaPath names a field path in error messages
+func jsonSchemaPath(path string) string {
+	if path == "" {
+		return "value"
+	}
+	return path
+}
@@ -0,0 +1,123 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+)
+
+const testSponsorSchema = `{
+	"type": "object",
+	"required": ["name", "tier"],
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"tier": {"enum": ["gold", "silver", "bronze"]},
+		"amount": {"type": "integer", "minimum": 0},
+		"website": {"type": "string", "pattern": "^https://"},
+		"contacts": {
+			"type": "array",
+			"maxItems": 2,
+			"items": {"type": "object", "required": ["email"], "properties": {"email": {"type": "string"}}}
+		}
+	}
+}`
+
+func TestValidateAgainstJSONSchema(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  interface{}
+		want  error
+		field string
+	}{
+		{"valid", map[string]interface{}{"name": "Butterfly", "tier": "gold", "amount": 5000, "extra": true}, nil, ""},
+		{"missing required", map[string]interface{}{"name": "Butterfly"}, ErrMissingField, "tier"},
+		{"wrong type", map[string]interface{}{"name": 42, "tier": "gold"}, ErrValidation, "name"},
+		{"not integer", map[string]interface{}{"name": "B", "tier": "gold", "amount": 1.5}, ErrValidation, "amount"},
+		{"below minimum", map[string]interface{}{"name": "B", "tier": "gold", "amount": -1}, ErrValidation, "amount"},
+		{"enum", map[string]interface{}{"name": "B", "tier": "platinum"}, ErrValidation, "tier"},
+		{"min length", map[string]interface{}{"name": "", "tier": "gold"}, ErrValidation, "name"},
+		{"pattern", map[string]interface{}{"name": "B", "tier": "gold", "website": "http://x"}, ErrValidation, "website"},
+		{"too many items", map[string]interface{}{"name": "B", "tier": "gold",
+			"contacts": []map[string]string{{"email": "a"}, {"email": "b"}, {"email": "c"}}}, ErrValidation, "contacts"},
+		{"nested item", map[string]interface{}{"name": "B", "tier": "gold",
+			"contacts": []map[string]string{{"email": "a"}, {}}}, ErrMissingField, "contacts[1].email"},
+		{"not an object", []string{"x"}, ErrValidation, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstJSONSchema([]byte(testSponsorSchema), tt.data)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Expected valid, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) || ve.FieldPath != tt.field {
+				t.Errorf("Expected field path %q, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestValidateAgainstJSONSchema_InvalidSchema(t *testing.T) {
+	for _, schema := range []string{`not json`, `[]`, `{"type": 5}`, `{"pattern": "("}`, `{"minLength": -1}`} {
+		if err := ValidateAgainstJSONSchema([]byte(schema), "x"); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("Schema %s: expected ErrInvalidSchema, got %v", schema, err)
+		}
+	}
+}
+
+func TestRegisterJSONSchema(t *testing.T) {
+	if err := RegisterJSONSchema("test_json_sponsor", []byte(testSponsorSchema)); err != nil && !errors.Is(err, ErrDuplicateEntity) {
+		t.Fatalf("Failed to register JSON Schema: %v", err)
+	}
+	if err := RegisterJSONSchema("test_json_sponsor", []byte(testSponsorSchema)); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity, got %v", err)
+	}
+	if err := RegisterJSONSchema(TypeMatch, []byte(testSponsorSchema)); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType for built-in type, got %v", err)
+	}
+	if err := RegisterJSONSchema("test_json_bad", []byte(`{`)); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema, got %v", err)
+	}
+
+	spec := map[string]interface{}{"name": "Butterfly", "tier": "gold"}
+	strict, lenient := NewSchemaValidator(true), NewSchemaValidator(false)
+	if err := strict.ValidateEntity("test_json_sponsor", spec); err != nil {
+		t.Errorf("Registered type should validate in strict mode: %v", err)
+	}
+
+	err := strict.ValidateEntity("test_json_sponsor", map[string]interface{}{"name": "Butterfly"})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || !errors.Is(err, ErrMissingField) || ve.FieldPath != "test_json_sponsor.tier" || ve.EntityType != "test_json_sponsor" {
+		t.Errorf("Expected missing test_json_sponsor.tier, got %v", err)
+	}
+
+	// Undeclared properties follow the schema in both modes: allowed unless
+	// additionalProperties is false
+	spec["logo"] = "logo.png"
+	if err := lenient.ValidateEntity("test_json_sponsor", spec); err != nil {
+		t.Errorf("Expected extra property to be allowed in lenient mode: %v", err)
+	}
+	if err := strict.ValidateEntity("test_json_sponsor", spec); err != nil {
+		t.Errorf("Expected extra property to be allowed in strict mode: %v", err)
+	}
+
+	closed := `{"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": false}`
+	if err := RegisterJSONSchema("test_json_closed", []byte(closed)); err != nil && !errors.Is(err, ErrDuplicateEntity) {
+		t.Fatalf("Failed to register JSON Schema: %v", err)
+	}
+	for _, validator := range []*SchemaValidator{strict, lenient} {
+		if err := validator.ValidateEntity("test_json_closed", map[string]interface{}{"name": "Acme"}); err != nil {
+			t.Errorf("Expected declared property to be allowed: %v", err)
+		}
+		err := validator.ValidateEntity("test_json_closed", map[string]interface{}{"name": "Acme", "logo": "logo.png"})
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected extra property to be rejected with additionalProperties false, got %v", err)
+		}
+	}
+}
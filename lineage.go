@@ -0,0 +1,224 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// packageIDPrefix is the optional prefix on package references in Provenance.ImportedFrom
+const packageIDPrefix = "ptd:package:"
+
+// LineageNode is a package in a lineage graph
+type LineageNode struct {
+	PackageID       string    `json:"package_id"`
+	Description     string    `json:"description,omitempty"`
+	Created         time.Time `json:"created"`
+	OriginalSources []string  `json:"original_sources,omitempty"` // Provenance.OriginalSource values of its entities
+	ImportedFrom    []string  `json:"imported_from,omitempty"`    // All referenced parent packages, including unknown ones
+}
+
+// LineageEdge links a package to a package it imported data from
+type LineageEdge struct {
+	From string `json:"from"` // Child package ID
+	To   string `json:"to"`   // Parent package ID
+}
+
+// LineageGraph is the provenance DAG of a set of packages
+type LineageGraph struct {
+	Nodes []LineageNode
+	Edges []LineageEdge
+}
+
+// BuildLineageGraph builds the provenance graph of the given packages from
+// the Provenance.ImportedFrom references of their entities. References to
+// packages outside the set are kept on the node but produce no edge.
+// Returns ErrValidation if the references form a cycle.
+func BuildLineageGraph(packages []*Package) (*LineageGraph, error) {
+	graph := &LineageGraph{}
+	index := make(map[string]int, len(packages))
+
+	for _, pkg := range packages {
+		if _, exists := index[pkg.ID]; exists {
+			return nil, fmt.Errorf("%w: package %s listed twice", ErrDuplicateEntity, pkg.ID)
+		}
+
+		node, err := lineageNode(pkg)
+		if err != nil {
+			return nil, err
+		}
+		index[pkg.ID] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	for _, node := range graph.Nodes {
+		for _, parent := range node.ImportedFrom {
+			parentID := strings.TrimPrefix(parent, packageIDPrefix)
+			if _, known := index[parentID]; known {
+				graph.Edges = append(graph.Edges, LineageEdge{From: node.PackageID, To: parentID})
+			}
+		}
+	}
+
+	if cycle := graph.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("%w: lineage cycle %s", ErrValidation, strings.Join(cycle, " -> "))
+	}
+
+	return graph, nil
+}
+
+// Roots returns the original source packages, those that import from no
+// other package in the graph
+func (g *LineageGraph) Roots() []LineageNode {
+	hasParent := make(map[string]bool)
+	for _, edge := range g.Edges {
+		hasParent[edge.From] = true
+	}
+
+	var roots []LineageNode
+	for _, node := range g.Nodes {
+		if !hasParent[node.PackageID] {
+			roots = append(roots, node)
+		}
+	}
+	return roots
+}
+
+// MarshalJSON encodes the graph in Cytoscape.js elements format
+func (g *LineageGraph) MarshalJSON() ([]byte, error) {
+	type element struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	doc := struct {
+		Elements struct {
+			Nodes []element `json:"nodes"`
+			Edges []element `json:"edges"`
+		} `json:"elements"`
+	}{}
+	doc.Elements.Nodes = []element{}
+	doc.Elements.Edges = []element{}
+
+	for _, node := range g.Nodes {
+		label := node.Description
+		if label == "" {
+			label = node.PackageID
+		}
+		data := map[string]interface{}{
+			"id":      node.PackageID,
+			"label":   label,
+			"created": node.Created,
+		}
+		if len(node.OriginalSources) > 0 {
+			data["original_sources"] = node.OriginalSources
+		}
+		doc.Elements.Nodes = append(doc.Elements.Nodes, element{Data: data})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Elements.Edges = append(doc.Elements.Edges, element{Data: map[string]interface{}{
+			"id":     edge.From + "->" + edge.To,
+			"source": edge.From,
+			"target": edge.To,
+		}})
+	}
+
+	return json.Marshal(doc)
+}
+
+// findCycle returns the package IDs along a cycle, or nil if the graph is acyclic
+func (g *LineageGraph) findCycle() []string {
+	parents := make(map[string][]string)
+	for _, edge := range g.Edges {
+		parents[edge.From] = append(parents[edge.From], edge.To)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = visiting
+		path = append(path, id)
+		for _, parent := range parents[id] {
+			switch state[parent] {
+			case visiting:
+				for i, p := range path {
+					if p == parent {
+						return append(append([]string{}, path[i:]...), parent)
+					}
+				}
+			case unvisited:
+				if cycle := visit(parent); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	for _, node := range g.Nodes {
+		if state[node.PackageID] == unvisited {
+			if cycle := visit(node.PackageID); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// lineageNode collects the provenance of every entity in the package
+func lineageNode(pkg *Package) (LineageNode, error) {
+	node := LineageNode{PackageID: pkg.ID, Created: pkg.Created}
+	if pkg.Manifest != nil {
+		node.Description = pkg.Manifest.Description
+	}
+
+	sources := make(map[string]bool)
+	imports := make(map[string]bool)
+	if pkg.Manifest != nil {
+		for _, entityType := range pkg.entityTypes() {
+			envelopes, err := decodeEntityLines[json.RawMessage](pkg, entityType)
+			if err != nil {
+				return LineageNode{}, err
+			}
+			for _, envelope := range envelopes {
+				provenance := envelope.Meta.Provenance
+				if provenance == nil {
+					continue
+				}
+				if provenance.OriginalSource != "" {
+					sources[provenance.OriginalSource] = true
+				}
+				if provenance.ImportedFrom != "" {
+					imports[provenance.ImportedFrom] = true
+				}
+			}
+		}
+	}
+
+	node.OriginalSources = sortedKeys(sources)
+	node.ImportedFrom = sortedKeys(imports)
+	return node, nil
+}
+
+// sortedKeys returns the keys of a set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package ptd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// newLineagePackage creates a package with one tournament imported from the given packages
+func newLineagePackage(t *testing.T, description, source string, importedFrom ...string) *Package {
+	t.Helper()
+	pkg := NewPackage(description)
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	var tournaments []interface{}
+	for _, parent := range append([]string{""}, importedFrom...) {
+		tournaments = append(tournaments, Envelope[Tournament]{
+			ID:   GenerateID(TypeTournament),
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: description}},
+			Meta: Meta{
+				Schema:     "ptd.v1.tournament@1.0.0",
+				Provenance: &Provenance{OriginalSource: source, ImportedFrom: parent},
+			},
+		})
+	}
+	if err := pkg.AddEntities(TypeTournament, tournaments); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	return pkg
+}
+
+func TestBuildLineageGraph(t *testing.T) {
+	origin := newLineagePackage(t, "Origin", "tournament-software")
+	merged := newLineagePackage(t, "Merged", "tournament-software", "ptd:package:"+origin.ID, "ptd:package:external")
+	derived := newLineagePackage(t, "Derived", "tournament-software", merged.ID)
+
+	graph, err := BuildLineageGraph([]*Package{derived, merged, origin})
+	if err != nil {
+		t.Fatalf("BuildLineageGraph failed: %v", err)
+	}
+
+	if len(graph.Nodes) != 3 {
+		t.Errorf("Expected 3 nodes, got %d", len(graph.Nodes))
+	}
+	if len(graph.Edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d: %v", len(graph.Edges), graph.Edges)
+	}
+	if graph.Edges[0] != (LineageEdge{From: merged.ID, To: origin.ID}) && graph.Edges[1] != (LineageEdge{From: merged.ID, To: origin.ID}) {
+		t.Errorf("Missing merged -> origin edge: %v", graph.Edges)
+	}
+	if len(graph.Nodes[1].ImportedFrom) != 2 {
+		t.Errorf("Unknown parents should be kept on the node: %v", graph.Nodes[1].ImportedFrom)
+	}
+
+	roots := graph.Roots()
+	if len(roots) != 1 || roots[0].PackageID != origin.ID {
+		t.Errorf("Expected origin as only root, got %v", roots)
+	}
+	if len(roots[0].OriginalSources) != 1 || roots[0].OriginalSources[0] != "tournament-software" {
+		t.Errorf("Unexpected original sources: %v", roots[0].OriginalSources)
+	}
+
+	data, err := json.Marshal(graph)
+	if err != nil {
+		t.Fatalf("Failed to marshal graph: %v", err)
+	}
+	var doc struct {
+		Elements struct {
+			Nodes []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to unmarshal graph JSON: %v", err)
+	}
+	if len(doc.Elements.Nodes) != 3 || len(doc.Elements.Edges) != 2 {
+		t.Fatalf("Unexpected Cytoscape elements: %s", data)
+	}
+	if doc.Elements.Nodes[0].Data["label"] != "Derived" {
+		t.Errorf("Unexpected node label: %v", doc.Elements.Nodes[0].Data)
+	}
+	if doc.Elements.Edges[0].Data["source"] == nil || doc.Elements.Edges[0].Data["target"] == nil {
+		t.Errorf("Edges need source and target: %v", doc.Elements.Edges[0].Data)
+	}
+}
+
+func TestBuildLineageGraph_Cycle(t *testing.T) {
+	a := newLineagePackage(t, "A", "src")
+	b := newLineagePackage(t, "B", "src", a.ID)
+
+	// Make A import from B
+	cyclic := []interface{}{
+		Envelope[Tournament]{
+			ID:   GenerateID(TypeTournament),
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "A"}},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0", Provenance: &Provenance{ImportedFrom: b.ID}},
+		},
+	}
+	if err := a.AddEntities(TypeTournament, cyclic); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	if _, err := BuildLineageGraph([]*Package{a, b}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for cycle, got %v", err)
+	}
+
+	if _, err := BuildLineageGraph([]*Package{b, b}); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity for repeated package, got %v", err)
+	}
+}
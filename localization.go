@@ -0,0 +1,76 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// LocalizedString maps language tags to text (e.g., {"en": "Men's Singles", "ja": "男子シングルス"})
+type LocalizedString map[string]string
+
+// MultiName is a name with optional translations.
+//
+// A MultiName without translations is encoded as a plain JSON string, so
+// single-language packages keep their original shape; with translations it
+// is encoded as {"default": "...", "translations": {...}}. Both forms are
+// accepted when decoding.
+type MultiName struct {
+	Default      string          `json:"default"`
+	Translations LocalizedString `json:"translations,omitempty"`
+}
+
+// Get returns the translation for lang, falling back to Default
+func (m MultiName) Get(lang string) string {
+	if name, ok := m.Translations[lang]; ok && name != "" {
+		return name
+	}
+	return m.Default
+}
+
+// String returns the default name
+func (m MultiName) String() string {
+	return m.Default
+}
+
+// MarshalJSON encodes the name as a string when it has no translations
+func (m MultiName) MarshalJSON() ([]byte, error) {
+	if len(m.Translations) == 0 {
+		return json.Marshal(m.Default)
+	}
+
+	type multiName MultiName
+	return json.Marshal(multiName(m))
+}
+
+// UnmarshalJSON decodes the name from either a string or an object
+func (m *MultiName) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*m = MultiName{}
+		return json.Unmarshal(data, &m.Default)
+	}
+
+	type multiName MultiName
+	var decoded multiName
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: name must be a string or object: %v", ErrInvalidFormat, err)
+	}
+	*m = MultiName(decoded)
+
+	return nil
+}
+
+// multiNameDefault returns the default name from a decoded JSON value,
+// which may be a plain string or a {"default": ...} object
+func multiNameDefault(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		name, _ := v["default"].(string)
+		return name
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,76 @@
+package ptd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMultiName_Get(t *testing.T) {
+	name := MultiName{
+		Default:      "Men's Singles",
+		Translations: LocalizedString{"ja": "男子シングルス"},
+	}
+
+	if got := name.Get("ja"); got != "男子シングルス" {
+		t.Errorf("Get(ja) = %s", got)
+	}
+	if got := name.Get("fr"); got != "Men's Singles" {
+		t.Errorf("Get(fr) should fall back to default, got %s", got)
+	}
+}
+
+func TestMultiName_JSON(t *testing.T) {
+	// Plain names stay plain strings
+	data, err := json.Marshal(Event{Name: MultiName{Default: "Men's Singles"}})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var raw map[string]interface{}
+	json.Unmarshal(data, &raw)
+	if raw["name"] != "Men's Singles" {
+		t.Errorf("Expected plain string name, got %v", raw["name"])
+	}
+
+	// Translated names round trip as objects
+	event := Event{Name: MultiName{Default: "Men's Singles", Translations: LocalizedString{"ja": "男子シングルス"}}}
+	data, err = json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	var decoded Event
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if decoded.Name.Default != "Men's Singles" || decoded.Name.Get("ja") != "男子シングルス" {
+		t.Errorf("Translated name did not round trip: %+v", decoded.Name)
+	}
+
+	// Existing string-form data still decodes
+	var legacy Tournament
+	if err := json.Unmarshal([]byte(`{"name":"Summer Open"}`), &legacy); err != nil {
+		t.Fatalf("Failed to unmarshal legacy name: %v", err)
+	}
+	if legacy.Name.Default != "Summer Open" {
+		t.Errorf("Legacy name = %q", legacy.Name.Default)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":42}`), &legacy); err == nil {
+		t.Error("Non-string, non-object name should fail to decode")
+	}
+}
+
+func TestValidateMultiNameDefault(t *testing.T) {
+	validator := NewSchemaValidator(false)
+
+	translatedOnly := Tournament{Name: MultiName{Translations: LocalizedString{"en": "Open"}}}
+	if err := validator.validateTournament(translatedOnly); err == nil {
+		t.Error("Tournament without default name should fail validation")
+	}
+
+	if err := validator.validateTournamentMap(map[string]interface{}{"name": map[string]interface{}{"default": "Open"}}); err != nil {
+		t.Errorf("Object-form name should validate: %v", err)
+	}
+	if err := validator.validateEventMap(map[string]interface{}{"tournament_id": "ptd:tournament:1", "name": map[string]interface{}{"default": ""}}); err == nil {
+		t.Error("Event with empty default name should fail validation")
+	}
+}
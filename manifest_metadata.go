@@ -0,0 +1,102 @@
+package ptd
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// sportPattern matches sport names in lower snake case, e.g. "table_tennis"
+var sportPattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// Publisher identifies who publishes a package, for catalogs listing it
+type Publisher struct {
+	Name    string   `json:"name"`
+	Contact *Contact `json:"contact,omitempty"`
+	Website string   `json:"website,omitempty"`
+}
+
+// clone returns a deep copy of the publisher
+func (p *Publisher) clone() *Publisher {
+	c := *p
+	if p.Contact != nil {
+		contact := *p.Contact
+		c.Contact = &contact
+	}
+	return &c
+}
+
+// AddTags adds tags to the manifest, normalized to lower case without
+// surrounding space. Tags are kept sorted and unique; empty tags are ignored.
+func (m *Manifest) AddTags(tags ...string) {
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			m.Tags = append(m.Tags, tag)
+		}
+	}
+	slices.Sort(m.Tags)
+	m.Tags = slices.Compact(m.Tags)
+}
+
+// HasTag reports whether the manifest has the tag, compared after normalization
+func (m *Manifest) HasTag(tag string) bool {
+	tag = normalizeTag(tag)
+	for _, t := range m.Tags {
+		if normalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeTag returns the canonical form of a tag
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// ValidateMetadata checks the manifest's catalog metadata: the sport must
+// be in lower snake case, a publisher needs a name, contact emails and
+// websites must be well formed, and tags must be normalized and unique as
+// AddTags leaves them. All failures are returned, joined.
+func (m *Manifest) ValidateMetadata() error {
+	var errs []error
+	fail := func(err error, field, format string, args ...interface{}) {
+		errs = append(errs, newValidationError(err, "manifest", field, format, args...))
+	}
+
+	if m.Sport != "" && !sportPattern.MatchString(m.Sport) {
+		fail(ErrInvalidFormat, "manifest.sport", "sport must be lower snake case, got %q", m.Sport)
+	}
+
+	if p := m.Publisher; p != nil {
+		if strings.TrimSpace(p.Name) == "" {
+			fail(ErrMissingField, "manifest.publisher.name", "publisher name is required")
+		}
+		if p.Contact != nil && p.Contact.Email != "" {
+			if _, err := mail.ParseAddress(p.Contact.Email); err != nil {
+				fail(ErrInvalidFormat, "manifest.publisher.contact.email", "invalid publisher email %q", p.Contact.Email)
+			}
+		}
+		if p.Website != "" {
+			if u, err := url.Parse(p.Website); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				fail(ErrInvalidFormat, "manifest.publisher.website", "invalid publisher website %q", p.Website)
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range m.Tags {
+		switch {
+		case tag == "" || tag != normalizeTag(tag):
+			fail(ErrInvalidFormat, "manifest.tags", "tag %q is not normalized", tag)
+		case seen[tag]:
+			fail(ErrValidation, "manifest.tags", "duplicate tag %q", tag)
+		}
+		seen[tag] = true
+	}
+
+	return errors.Join(errs...)
+}
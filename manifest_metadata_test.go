@@ -0,0 +1,94 @@
+package ptd
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifest_Metadata(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	pkg.Manifest.Sport = "table_tennis"
+	pkg.Manifest.GoverningBody = "ITTF"
+	pkg.Manifest.License = "CC-BY-4.0"
+	pkg.Manifest.Publisher = &Publisher{
+		Name:    "Example Federation",
+		Contact: &Contact{Email: "results@example.org"},
+		Website: "https://example.org",
+	}
+	pkg.Manifest.AddTags(" Juniors", "national", "juniors", "")
+	if err := pkg.Manifest.ValidateMetadata(); err != nil {
+		t.Fatalf("ValidateMetadata() error = %v", err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "metadata.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	// The catalog metadata is read without the entity data
+	opened, err := OpenPackageWithOptions(archivePath, OpenOptions{Verification: VerifyDeferred})
+	if err != nil {
+		t.Fatalf("OpenPackageWithOptions() error = %v", err)
+	}
+	m := opened.Manifest
+	if m.Sport != "table_tennis" || m.GoverningBody != "ITTF" || m.License != "CC-BY-4.0" {
+		t.Errorf("metadata = %q, %q, %q; want it preserved", m.Sport, m.GoverningBody, m.License)
+	}
+	if !reflect.DeepEqual(m.Publisher, pkg.Manifest.Publisher) {
+		t.Errorf("Publisher = %+v, want %+v", m.Publisher, pkg.Manifest.Publisher)
+	}
+	if !reflect.DeepEqual(m.Tags, []string{"juniors", "national"}) {
+		t.Errorf("Tags = %v, want [juniors national]", m.Tags)
+	}
+	if !m.HasTag("NATIONAL") || m.HasTag("seniors") {
+		t.Error("HasTag() does not match normalized tags")
+	}
+	if m.FormatVersion != "1.2.0" {
+		t.Errorf("FormatVersion = %q, want 1.2.0", m.FormatVersion)
+	}
+
+	// Metadata is package content, covered by the signature
+	before, _ := m.CanonicalJSON()
+	m.License = "CC0-1.0"
+	if after, _ := m.CanonicalJSON(); string(after) == string(before) {
+		t.Error("CanonicalJSON() does not cover the license")
+	}
+
+	// Clones do not share the publisher or tags
+	c := m.clone()
+	c.Publisher.Contact.Email = "other@example.org"
+	c.Tags[0] = "changed"
+	if m.Publisher.Contact.Email != "results@example.org" || m.Tags[0] != "juniors" {
+		t.Error("clone shares metadata with the original")
+	}
+}
+
+func TestManifest_ValidateMetadata_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(m *Manifest)
+		want   error
+	}{
+		{"sport case", func(m *Manifest) { m.Sport = "Table Tennis" }, ErrInvalidFormat},
+		{"publisher name", func(m *Manifest) { m.Publisher = &Publisher{Website: "https://example.org"} }, ErrMissingField},
+		{"publisher email", func(m *Manifest) {
+			m.Publisher = &Publisher{Name: "Pub", Contact: &Contact{Email: "not an email"}}
+		}, ErrInvalidFormat},
+		{"publisher website", func(m *Manifest) { m.Publisher = &Publisher{Name: "Pub", Website: "example.org"} }, ErrInvalidFormat},
+		{"unnormalized tag", func(m *Manifest) { m.Tags = []string{"Juniors"} }, ErrInvalidFormat},
+		{"duplicate tag", func(m *Manifest) { m.Tags = []string{"a", "a"} }, ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{}
+			tt.modify(m)
+			err := m.ValidateMetadata()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ValidateMetadata() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
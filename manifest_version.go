@@ -0,0 +1,121 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ManifestFormatVersion is the version of the manifest format written by
+// this package, recorded in Manifest.FormatVersion.
+//
+// The format evolves by semantic versioning. A minor version adds optional
+// fields, appended after the existing ones: older readers keep such fields
+// when they rewrite a manifest, even though they do not understand them. A
+// major version changes the meaning of existing fields, and readers refuse
+// manifests of a newer major version with ErrUnsupportedVersion.
+const ManifestFormatVersion = "1.2.0"
+
+// manifestFields is Manifest without its JSON methods
+type manifestFields Manifest
+
+// manifestJSONNames are the lower-cased JSON names of the known manifest fields
+var manifestJSONNames = func() map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeOf(manifestFields{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[strings.ToLower(name)] = true
+		}
+	}
+	return names
+}()
+
+// checkFormatVersion returns ErrUnsupportedVersion if the manifest is of a
+// newer major format version than this package reads. Manifests written
+// before format versions were recorded have none and are read as 1.x.
+func (m *Manifest) checkFormatVersion() error {
+	if m.FormatVersion == "" {
+		return nil
+	}
+	version, ok := parseSemver(m.FormatVersion)
+	if !ok {
+		return fmt.Errorf("%w: format version must be semantic (major.minor.patch): %s", ErrManifestInvalid, m.FormatVersion)
+	}
+	current, _ := parseSemver(ManifestFormatVersion)
+	if version[0] > current[0] {
+		return fmt.Errorf("%w: manifest format %s is newer than %s", ErrUnsupportedVersion, m.FormatVersion, ManifestFormatVersion)
+	}
+	return nil
+}
+
+// stampFormatVersion raises the manifest's format version to the one this
+// package writes, keeping a newer minor version read from an archive
+func (m *Manifest) stampFormatVersion() {
+	version, ok := parseSemver(m.FormatVersion)
+	current, _ := parseSemver(ManifestFormatVersion)
+	if !ok || semverLess(version, current) {
+		m.FormatVersion = ManifestFormatVersion
+	}
+}
+
+// MarshalJSON encodes the manifest, followed by any fields of a newer
+// format version it was decoded with, in sorted order
+func (m Manifest) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(manifestFields(m))
+	if err != nil || len(m.unknown) == 0 {
+		return data, err
+	}
+
+	keys := make([]string, 0, len(m.unknown))
+	for key := range m.unknown {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(data, []byte("}")))
+	for i, key := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(m.unknown[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the manifest, keeping fields it does not know so
+// that they survive a round trip
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	var fields manifestFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	*m = Manifest(fields)
+	for key, value := range all {
+		if manifestJSONNames[strings.ToLower(key)] {
+			continue
+		}
+		if m.unknown == nil {
+			m.unknown = make(map[string]json.RawMessage)
+		}
+		m.unknown[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// editManifestArchive writes a copy of the archive at archivePath with its
+// manifest fields changed by edit
+func editManifestArchive(t *testing.T, archivePath string, edit func(fields map[string]interface{})) string {
+	t.Helper()
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := rewriteZip(t, data, func(name string, data []byte) []byte {
+		if name != "manifest.json" {
+			return data
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatal(err)
+		}
+		edit(fields)
+		data, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	})
+	editedPath := filepath.Join(t.TempDir(), "edited.ptd")
+	if err := os.WriteFile(editedPath, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return editedPath
+}
+
+func TestManifest_FormatVersion(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 1)
+	pkg, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if pkg.Manifest.FormatVersion != ManifestFormatVersion {
+		t.Errorf("FormatVersion = %q, want %q", pkg.Manifest.FormatVersion, ManifestFormatVersion)
+	}
+
+	tests := []struct {
+		version string
+		want    error
+	}{
+		{"", nil},
+		{"1.7.0", nil},
+		{"2.0.0", ErrUnsupportedVersion},
+		{"next", ErrManifestInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			path := editManifestArchive(t, archivePath, func(fields map[string]interface{}) {
+				fields["format_version"] = tt.version
+			})
+			_, err := OpenPackage(path)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("OpenPackage() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	doc := []byte(`{"manifest": {"format_version": "2.0.0", "version": "1.0.0"}, "entities": {}}`)
+	if _, err := PackageFromJSON(doc); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("PackageFromJSON() error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestManifest_UnknownFields(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 1)
+	futurePath := editManifestArchive(t, archivePath, func(fields map[string]interface{}) {
+		fields["format_version"] = "1.3.0"
+		fields["zz_future"] = map[string]interface{}{"enabled": true}
+		fields["a_future"] = "value"
+	})
+
+	// Unknown fields survive rewriting the package
+	pkg, err := OpenPackageForAppend(futurePath)
+	if err != nil {
+		t.Fatalf("OpenPackageForAppend() error = %v", err)
+	}
+	defer pkg.Cleanup()
+	if err := pkg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	saved, err := OpenPackage(futurePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+
+	data, err := json.Marshal(saved.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if string(fields["zz_future"]) != `{"enabled":true}` || string(fields["a_future"]) != `"value"` {
+		t.Errorf("unknown fields = %s, %s; want them preserved", fields["zz_future"], fields["a_future"])
+	}
+	if string(fields["format_version"]) != `"1.3.0"` {
+		t.Errorf("format_version = %s, want the original 1.3.0", fields["format_version"])
+	}
+
+	// Unknown fields follow the known ones and are covered by signatures
+	if !bytes.HasSuffix(data, []byte(`,"a_future":"value","zz_future":{"enabled":true}}`)) {
+		t.Errorf("manifest JSON = %s, want unknown fields last in sorted order", data)
+	}
+	canonical, err := saved.Manifest.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(canonical, []byte(`"zz_future"`)) || bytes.Contains(canonical, []byte(`"format_version"`)) {
+		t.Errorf("CanonicalJSON() = %s, want unknown fields without the format version", canonical)
+	}
+
+	// A clone keeps them too
+	if cloned, _ := json.Marshal(saved.Manifest.clone()); !bytes.Equal(cloned, data) {
+		t.Errorf("clone JSON = %s, want %s", cloned, data)
+	}
+}
@@ -0,0 +1,49 @@
+package ptd
+
+// Official roles with shorthand accessors on Match
+const (
+	RoleReferee = "referee"
+	RoleUmpire  = "umpire"
+)
+
+// Referee returns the match's first official with the referee role, or nil
+func (m *Match) Referee() *Official {
+	return m.official(RoleReferee)
+}
+
+// Umpire returns the match's first official with the umpire role, or nil
+func (m *Match) Umpire() *Official {
+	return m.official(RoleUmpire)
+}
+
+// SetReferee sets the name of the match referee, adding a referee to
+// Officials if the match has none
+func (m *Match) SetReferee(name string) {
+	m.setOfficial(RoleReferee, name)
+}
+
+// SetUmpire sets the name of the match umpire, adding an umpire to
+// Officials if the match has none
+func (m *Match) SetUmpire(name string) {
+	m.setOfficial(RoleUmpire, name)
+}
+
+// official returns the first official with the given role. The pointer
+// refers into Officials, so changes to it update the match.
+func (m *Match) official(role string) *Official {
+	for i := range m.Officials {
+		if m.Officials[i].Role == role {
+			return &m.Officials[i]
+		}
+	}
+	return nil
+}
+
+// setOfficial updates the first official with the given role, or appends one
+func (m *Match) setOfficial(role, name string) {
+	if official := m.official(role); official != nil {
+		official.Name = name
+		return
+	}
+	m.Officials = append(m.Officials, Official{Name: name, Role: role})
+}
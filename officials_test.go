@@ -0,0 +1,65 @@
+package ptd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatch_Officials(t *testing.T) {
+	// No officials
+	var m Match
+	if m.Referee() != nil || m.Umpire() != nil {
+		t.Error("Match without officials should have no referee or umpire")
+	}
+
+	// One referee
+	m = Match{Officials: []Official{{Name: "Alice", Role: "referee"}}}
+	if got := m.Referee(); got == nil || got.Name != "Alice" {
+		t.Errorf("Referee() = %v, want Alice", got)
+	}
+	if m.Umpire() != nil {
+		t.Error("Umpire() should be nil when the match only has a referee")
+	}
+
+	// Mixed roles: the first official of each role is returned
+	m = Match{Officials: []Official{
+		{Name: "Carol", Role: "line_judge"},
+		{Name: "Dave", Role: "umpire"},
+		{Name: "Erin", Role: "referee"},
+		{Name: "Frank", Role: "umpire"},
+	}}
+	if got := m.Referee(); got == nil || got.Name != "Erin" {
+		t.Errorf("Referee() = %v, want Erin", got)
+	}
+	if got := m.Umpire(); got == nil || got.Name != "Dave" {
+		t.Errorf("Umpire() = %v, want Dave", got)
+	}
+}
+
+func TestMatch_SetOfficials(t *testing.T) {
+	m := Match{Officials: []Official{
+		{Name: "Carol", Role: "line_judge"},
+		{Name: "Dave", Role: "umpire"},
+		{Name: "Frank", Role: "umpire"},
+	}}
+
+	m.SetUmpire("Grace")
+	m.SetReferee("Heidi")
+	m.SetReferee("Ivan")
+
+	want := []Official{
+		{Name: "Carol", Role: "line_judge"},
+		{Name: "Grace", Role: "umpire"},
+		{Name: "Frank", Role: "umpire"},
+		{Name: "Ivan", Role: "referee"},
+	}
+	if !reflect.DeepEqual(m.Officials, want) {
+		t.Errorf("Officials = %+v, want %+v", m.Officials, want)
+	}
+
+	var empty Match
+	empty.SetUmpire("Judy")
+	if got := empty.Umpire(); got == nil || got.Name != "Judy" || len(empty.Officials) != 1 {
+		t.Errorf("SetUmpire() on a match without officials: Officials = %+v", empty.Officials)
+	}
+}
@@ -1,7 +1,8 @@
 package ptd
 
 import (
-	"archive/zip"
+	"bytes"
+	"context"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/base64"
@@ -11,36 +12,66 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 )
 
 // Package represents a PTD package containing tournament data
 type Package struct {
-	ID       string    `json:"id"`
+	ID       string    `json:"id"` // Stable package ID, recorded in Manifest.PackageID when archived
 	Created  time.Time `json:"created"`
 	Version  string    `json:"version"`
 	Manifest *Manifest `json:"-"`
 	tempDir  string
+	archive  *archiveSource // Archive of an opened package, which has no working directory
+	lock     *packageLock
+
+	compression      Compression // Compression of ZIP archives, DEFLATE if empty
+	compressionLevel int         // Level of compression on its method's scale
+	indexEntities    bool        // Write an entity index into archives
+	embedSchemas     bool        // Write the JSON Schemas of entity types into archives
+
+	appendPath     string // Archive that Save rewrites, for packages opened for append
+	appendFormat   ArchiveFormat
+	signedManifest []byte // Canonical manifest covered by the signature
 }
 
 // Manifest describes the contents of a PTD package
 type Manifest struct {
-	Version     string                 `json:"version"`             // PTD version (e.g., "1.0.0")
-	Created     time.Time              `json:"created"`             // Package creation time
-	Creator     string                 `json:"creator"`             // System that created package
-	Description string                 `json:"description"`         // Human-readable description
-	Files       map[string]*FileEntry  `json:"files"`               // All files in package
-	Entities    map[string]EntityCount `json:"entities"`            // Count of each entity type
-	Signature   *Signature             `json:"signature,omitempty"` // Package signature
+	FormatVersion string                 `json:"format_version,omitempty"` // Manifest format version (see ManifestFormatVersion)
+	Version       string                 `json:"version"`                  // PTD version (e.g., "1.0.0")
+	Created       time.Time              `json:"created"`                  // Package creation time
+	Creator       string                 `json:"creator"`                  // System that created package
+	Description   string                 `json:"description"`              // Human-readable description
+	Languages     []string               `json:"languages,omitempty"`      // Languages of localized names (e.g., ["en", "ja"])
+	Files         map[string]*FileEntry  `json:"files"`                    // All files in package
+	Entities      map[string]EntityCount `json:"entities"`                 // Count of each entity type
+	Signature     *Signature             `json:"signature,omitempty"`      // Package signature
+	Compression   string                 `json:"compression,omitempty"`    // Compression of archived files when not DEFLATE (e.g., "zstd")
+	Split         *SplitInfo             `json:"split,omitempty"`          // Set on the parts of a split package
+
+	// Catalog metadata, for indexing packages without reading their entities
+	Sport         string     `json:"sport,omitempty"`          // Sport of the package (e.g., "table_tennis")
+	GoverningBody string     `json:"governing_body,omitempty"` // Body sanctioning the tournaments (e.g., "ITTF")
+	License       string     `json:"license,omitempty"`        // SPDX license identifier of the data (e.g., "CC-BY-4.0")
+	Publisher     *Publisher `json:"publisher,omitempty"`      // Who publishes the package
+	Tags          []string   `json:"tags,omitempty"`           // Free-form tags, normalized by AddTags
+
+	PackageID string `json:"package_id,omitempty"` // Package.ID, so that it survives opening the archive
+
+	unknown map[string]json.RawMessage // Fields of a newer format version, kept on round trip
 }
 
 // CanonicalJSON returns the canonical JSON representation of manifest for signing
 func (m *Manifest) CanonicalJSON() ([]byte, error) {
-	// Create a copy without signature and files (files are archive metadata, not package content)
+	// Create a copy without signature, files, compression, format version and package ID (archive metadata, not package content)
 	temp := *m
 	temp.Signature = nil
 	temp.Files = nil // Exclude files from signature - they're archive metadata
+	temp.Compression = ""
+	temp.FormatVersion = ""
+	temp.PackageID = ""
 
 	// Use deterministic JSON encoding
 	return json.Marshal(temp)
@@ -55,6 +86,40 @@ type FileEntry struct {
 	Type     string    `json:"type"`     // MIME type or content type
 }
 
+// newFileEntry describes a package file with the given contents
+func newFileEntry(relPath string, data []byte, modified time.Time) *FileEntry {
+	hash := sha256.Sum256(data)
+	return &FileEntry{
+		Path:     relPath,
+		Size:     int64(len(data)),
+		Hash:     hex.EncodeToString(hash[:]),
+		Modified: modified,
+		Type:     detectContentType(relPath),
+	}
+}
+
+// hashFileEntry describes the file at path, reading it as a stream
+func hashFileEntry(relPath, path string, modified time.Time) (*FileEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return nil, err
+	}
+	return &FileEntry{
+		Path:     relPath,
+		Size:     size,
+		Hash:     hex.EncodeToString(hasher.Sum(nil)),
+		Modified: modified,
+		Type:     detectContentType(relPath),
+	}, nil
+}
+
 // EntityCount tracks the number of entities by type
 type EntityCount struct {
 	Type  string `json:"type"`  // Entity type
@@ -71,24 +136,35 @@ func NewPackage(description string) *Package {
 		os.MkdirAll(tempDir, 0755)
 	}
 
+	id := GenerateULID()
 	return &Package{
-		ID:      GenerateULID(),
+		ID:      id,
 		Created: time.Now(),
 		Version: "1.0.0",
 		tempDir: tempDir,
 		Manifest: &Manifest{
-			Version:     "1.0.0",
-			Created:     time.Now(),
-			Creator:     "ptd-go",
-			Description: description,
-			Files:       make(map[string]*FileEntry),
-			Entities:    make(map[string]EntityCount),
+			FormatVersion: ManifestFormatVersion,
+			PackageID:     id,
+			Version:       "1.0.0",
+			Created:       time.Now(),
+			Creator:       "ptd-go",
+			Description:   description,
+			Files:         make(map[string]*FileEntry),
+			Entities:      make(map[string]EntityCount),
 		},
 	}
 }
 
-// AddEntities adds entities to the package
+// AddEntities adds entities to the package, replacing any of the same
+// type. Returns ErrInvalidType if entityType is not a valid type name.
 func (p *Package) AddEntities(entityType string, entities []interface{}) error {
+	if err := checkEntityType(entityType); err != nil {
+		return err
+	}
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+
 	// Create directory for entity type if needed
 	dir := filepath.Join(p.tempDir, entityType)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -96,8 +172,7 @@ func (p *Package) AddEntities(entityType string, entities []interface{}) error {
 	}
 
 	// Write entities to NDJSON file
-	filename := fmt.Sprintf("%ss.ndjson", entityType)
-	filepath := filepath.Join(dir, filename)
+	filepath := filepath.Join(dir, entityFileName(entityType))
 
 	file, err := os.Create(filepath)
 	if err != nil {
@@ -126,21 +201,131 @@ func (p *Package) AddEntities(entityType string, entities []interface{}) error {
 	return nil
 }
 
+// entityTypePattern matches entity type names, which name directories of
+// a package
+var entityTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// checkEntityType returns ErrInvalidType unless entityType is a valid
+// entity type name, one that cannot reach outside the package's directory
+func checkEntityType(entityType string) error {
+	if !entityTypePattern.MatchString(entityType) || !filepath.IsLocal(entityType) {
+		return fmt.Errorf("%w: invalid entity type name %q", ErrInvalidType, entityType)
+	}
+	return nil
+}
+
+// entityFileName returns the NDJSON file name for an entity type
+func entityFileName(entityType string) string {
+	return fmt.Sprintf("%ss.ndjson", entityType)
+}
+
+// entityFilePath returns the package-relative path of the NDJSON file for an entity type
+func entityFilePath(entityType string) string {
+	return filepath.Join(entityType, entityFileName(entityType))
+}
+
+// readEntityLines reads the raw NDJSON lines stored for an entity type.
+// A missing file yields no lines.
+func (p *Package) readEntityLines(entityType string) ([]json.RawMessage, error) {
+	var lines []json.RawMessage
+	err := p.scanEntityLines(entityType, func(_ int, line []byte) bool {
+		lines = append(lines, json.RawMessage(bytes.Clone(line)))
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
 // Cleanup removes the temporary directory
 func (p *Package) Cleanup() error {
+	if p.archive != nil && p.archive.temporary {
+		os.Remove(p.archive.path)
+	}
 	if p.tempDir != "" && p.tempDir != "." {
+		os.Remove(lockFilePath(p.tempDir))
 		return os.RemoveAll(p.tempDir)
 	}
 	return nil
 }
 
-// CreateArchive creates a ZIP archive of the package
+// CreateArchive creates an archive of the package: a tar.gz archive if
+// outputPath ends in ".tar.gz" or ".tgz", otherwise a ZIP archive. Returns
+// ErrInvalidPackage if the package has no working directory, such as one
+// loaded with OpenPackage.
 func (p *Package) CreateArchive(outputPath string) error {
+	return p.CreateArchiveWithProgress(outputPath, nil)
+}
+
+// CreateArchiveTo writes the package as a ZIP archive to w, such as an
+// HTTP response or an upload stream. Like CreateArchive it updates the
+// manifest's file entries; w need not be seekable.
+func (p *Package) CreateArchiveTo(w io.Writer) error {
+	return p.writeArchive(w, nil)
+}
+
+// CreateArchiveToFormat is CreateArchiveTo for an archive of the given format
+func (p *Package) CreateArchiveToFormat(w io.Writer, format ArchiveFormat) error {
+	return p.writeArchiveFormat(context.Background(), w, format, nil)
+}
+
+// ArchiveProgress reports the progress of an archive operation. When an
+// archive is opened, files and bytes "written" are those read and verified.
+type ArchiveProgress struct {
+	FilesTotal   int   // Number of files to write, including the manifest
+	FilesWritten int   // Number of files written so far
+	BytesTotal   int64 // Uncompressed bytes to write
+	BytesWritten int64 // Uncompressed bytes written so far
+}
+
+// CreateArchiveWithProgress creates an archive of the package like
+// CreateArchive, calling onProgress after each file is written.
+// onProgress may be nil.
+func (p *Package) CreateArchiveWithProgress(outputPath string, onProgress func(ArchiveProgress)) error {
+	return p.CreateArchiveContext(context.Background(), outputPath, onProgress)
+}
+
+// writeArchive updates the manifest's file entries, writes manifest.json to
+// the working directory and writes the ZIP archive to w
+func (p *Package) writeArchive(w io.Writer, onProgress func(ArchiveProgress)) error {
+	return p.writeArchiveFormat(context.Background(), w, ArchiveZIP, onProgress)
+}
+
+// writeArchiveFormat is writeArchive for an archive of the given format
+func (p *Package) writeArchiveFormat(ctx context.Context, w io.Writer, format ArchiveFormat, onProgress func(ArchiveProgress)) error {
+	// The index, schemas and manifest are written to the working directory
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+
+	archive, err := newArchiveWriter(w, format, p.compression, p.compressionLevel)
+	if err != nil {
+		return err
+	}
+
+	// Readers need to know of compression beyond DEFLATE
+	p.Manifest.Compression = ""
+	if format != ArchiveTarGz && p.compression == CompressionZstd {
+		p.Manifest.Compression = string(CompressionZstd)
+	}
+
+	if err := p.writeEntityIndex(); err != nil {
+		return err
+	}
+	if err := p.writeEmbeddedSchemas(); err != nil {
+		return err
+	}
+	if zipArchive, ok := archive.(*zipArchiveWriter); ok && p.indexEntities {
+		// Stored entity files let GetEntity read a record in place
+		zipArchive.stored = p.indexedFiles()
+	}
+
 	// First collect all files and their hashes
 	filesToArchive := make(map[string]string) // path -> hash
 
 	// Walk directory and calculate hashes
-	err := filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -156,26 +341,19 @@ func (p *Package) CreateArchive(outputPath string) error {
 			return err
 		}
 
-		// Read file and calculate hash
-		data, err := os.ReadFile(path)
+		// Calculate hash, streaming so large files are not held in memory
+		entry, err := hashFileEntry(relPath, path, info.ModTime())
 		if err != nil {
 			return err
 		}
-
-		hasher := sha256.New()
-		hasher.Write(data)
-		hash := hex.EncodeToString(hasher.Sum(nil))
-
-		filesToArchive[relPath] = hash
+		// Keep content types given when files were added, as for attachments
+		if previous := p.Manifest.Files[relPath]; previous != nil && previous.Type != "" {
+			entry.Type = previous.Type
+		}
+		filesToArchive[relPath] = entry.Hash
 
 		// Add to manifest
-		p.Manifest.Files[relPath] = &FileEntry{
-			Path:     relPath,
-			Size:     int64(len(data)),
-			Hash:     hash,
-			Modified: info.ModTime(),
-			Type:     detectContentType(relPath),
-		}
+		p.Manifest.Files[relPath] = entry
 
 		return nil
 	})
@@ -184,6 +362,9 @@ func (p *Package) CreateArchive(outputPath string) error {
 		return fmt.Errorf("failed to walk directory: %w", err)
 	}
 
+	p.Manifest.stampFormatVersion()
+	p.Manifest.PackageID = p.ID
+
 	// Create manifest file
 	manifestPath := filepath.Join(p.tempDir, "manifest.json")
 	manifestData, err := json.MarshalIndent(p.Manifest, "", "  ")
@@ -195,18 +376,17 @@ func (p *Package) CreateArchive(outputPath string) error {
 		return fmt.Errorf("failed to write manifest: %w", err)
 	}
 
-	// Create ZIP archive
-	archive, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create archive: %w", err)
+	// Totals for progress reporting
+	progress := ArchiveProgress{FilesTotal: 1, BytesTotal: int64(len(manifestData))}
+	for relPath, entry := range p.Manifest.Files {
+		if _, ok := filesToArchive[relPath]; ok && relPath != "manifest.json" {
+			progress.FilesTotal++
+			progress.BytesTotal += entry.Size
+		}
 	}
-	defer archive.Close()
-
-	zipWriter := zip.NewWriter(archive)
-	defer zipWriter.Close()
 
 	// Add all files including the manifest
-	return filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -222,9 +402,7 @@ func (p *Package) CreateArchive(outputPath string) error {
 			return err
 		}
 
-		// Create ZIP entry
-		writer, err := zipWriter.Create(relPath)
-		if err != nil {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
@@ -235,84 +413,171 @@ func (p *Package) CreateArchive(outputPath string) error {
 		}
 		defer file.Close()
 
-		_, err = io.Copy(writer, file)
-		return err
+		n, err := archive.WriteFile(relPath, info.Size(), info.ModTime(), &contextReader{ctx: ctx, r: file})
+		if err != nil {
+			return err
+		}
+
+		if onProgress != nil {
+			progress.FilesWritten++
+			progress.BytesWritten += n
+			onProgress(progress)
+		}
+
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
 }
 
-// OpenPackage opens and validates a PTD package
+// WriteTo streams the package as a ZIP archive to w, implementing
+// io.WriterTo. The archive is produced in a separate goroutine through a
+// pipe, so w need not be seekable and no temporary archive file is created.
+func (p *Package) WriteTo(w io.Writer) (int64, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(p.writeArchive(pw, nil))
+	}()
+
+	n, err := io.Copy(w, pr)
+	// Unblock the writer if copying to w failed
+	pr.CloseWithError(err)
+	if err != nil {
+		return n, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return n, nil
+}
+
+// OpenPackage opens and validates a PTD package. ZIP and tar.gz archives
+// are told apart by their contents, whatever the file name.
 func OpenPackage(archivePath string) (*Package, error) {
-	// Open ZIP file
-	reader, err := zip.OpenReader(archivePath)
+	return openPackagePath(context.Background(), archivePath, true, nil)
+}
+
+// openPackagePath opens the package archive at archivePath, verifying the
+// hashes of its files up front if verify is set
+func openPackagePath(ctx context.Context, archivePath string, verify bool, onProgress func(ArchiveProgress)) (*Package, error) {
+	file, err := os.Open(archivePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open archive: %w", err)
 	}
-	defer reader.Close()
-
-	// Look for manifest
-	var manifest *Manifest
-	for _, file := range reader.File {
-		if file.Name == "manifest.json" {
-			rc, err := file.Open()
-			if err != nil {
-				return nil, fmt.Errorf("failed to open manifest: %w", err)
-			}
-			defer rc.Close()
+	defer file.Close()
 
-			data, err := io.ReadAll(rc)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read manifest: %w", err)
-			}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
 
-			manifest = &Manifest{}
-			if err := json.Unmarshal(data, manifest); err != nil {
-				return nil, fmt.Errorf("failed to parse manifest: %w", err)
-			}
-			break
-		}
+	pkg, format, err := openArchiveContext(ctx, file, info.Size(), verify, onProgress)
+	if err != nil {
+		return nil, err
+	}
+	if absPath, err := filepath.Abs(archivePath); err == nil {
+		archivePath = absPath
 	}
+	pkg.archive = &archiveSource{path: archivePath, format: format, deferred: !verify}
+	return pkg, nil
+}
 
-	if manifest == nil {
-		return nil, ErrManifestMissing
+// openArchive reads the manifest of an archive and checks that it lists
+// every file. If verify is set the hashes of the files are checked too;
+// otherwise they are left for openPackageFile and VerifyAll.
+func openArchive(r io.ReaderAt, size int64, verify bool) (*Package, ArchiveFormat, error) {
+	return openArchiveContext(context.Background(), r, size, verify, nil)
+}
+
+// openArchiveContext is openArchive, stopping when ctx is done and calling
+// onProgress, if non-nil, after each file is read
+func openArchiveContext(ctx context.Context, r io.ReaderAt, size int64, verify bool, onProgress func(ArchiveProgress)) (*Package, ArchiveFormat, error) {
+	format, err := detectArchiveFormat(r, size)
+	if err != nil {
+		return nil, "", err
 	}
+	progress := newOpenProgress(r, size, format, onProgress)
+	r = progress.source
 
-	// Validate file hashes
-	for _, file := range reader.File {
-		if file.Name == "manifest.json" {
-			continue
+	type fileHash struct {
+		name string
+		hash string
+	}
+	var manifestData []byte
+	var hashes []fileHash
+	err = forEachArchiveFile(r, size, format, func(name string, file io.Reader) error {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		file = &contextReader{ctx: ctx, r: file}
 
-		entry, exists := manifest.Files[file.Name]
-		if !exists {
-			return nil, fmt.Errorf("unexpected file in package: %s", file.Name)
+		if name == "manifest.json" {
+			data, err := io.ReadAll(file)
+			if err != nil {
+				return fmt.Errorf("failed to read manifest: %w", err)
+			}
+			manifestData = data
+			progress.fileRead(name)
+			return nil
 		}
-
-		// Verify hash
-		rc, err := file.Open()
-		if err != nil {
-			return nil, fmt.Errorf("failed to open file %s: %w", file.Name, err)
+		if !verify {
+			hashes = append(hashes, fileHash{name: name})
+			progress.fileRead(name)
+			return nil
 		}
-		defer rc.Close()
 
 		hasher := sha256.New()
-		if _, err := io.Copy(hasher, rc); err != nil {
-			return nil, fmt.Errorf("failed to read file %s: %w", file.Name, err)
+		if _, err := io.Copy(hasher, file); err != nil {
+			return fmt.Errorf("failed to read file %s: %w", name, err)
 		}
+		hashes = append(hashes, fileHash{name: name, hash: hex.EncodeToString(hasher.Sum(nil))})
+		progress.fileRead(name)
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	if manifestData == nil {
+		return nil, "", ErrManifestMissing
+	}
+	manifest := &Manifest{}
+	if err := json.Unmarshal(manifestData, manifest); err != nil {
+		return nil, "", fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if err := manifest.checkFormatVersion(); err != nil {
+		return nil, "", err
+	}
 
-		hash := hex.EncodeToString(hasher.Sum(nil))
-		if hash != entry.Hash {
-			return nil, fmt.Errorf("%w for file %s", ErrHashMismatch, file.Name)
+	// Validate file hashes
+	for _, file := range hashes {
+		entry, exists := manifest.Files[file.name]
+		if !exists {
+			return nil, "", fmt.Errorf("unexpected file in package: %s", file.name)
+		}
+		if verify && file.hash != entry.Hash {
+			return nil, "", fmt.Errorf("%w for file %s", ErrHashMismatch, file.name)
 		}
 	}
 
 	pkg := &Package{
-		ID:       GenerateULID(),
+		ID:       manifest.packageID(),
 		Created:  manifest.Created,
 		Version:  manifest.Version,
 		Manifest: manifest,
 	}
 
-	return pkg, nil
+	progress.done()
+	return pkg, format, nil
+}
+
+// packageID returns the package ID recorded in the manifest, or a new one
+// for manifests written before package IDs were recorded
+func (m *Manifest) packageID() string {
+	if m.PackageID != "" {
+		return m.PackageID
+	}
+	return GenerateULID()
 }
 
 // detectContentType determines the content type based on file extension
@@ -351,6 +616,7 @@ func (p *Package) SignPackage(signer *Signer) error {
 	signatureB64 := base64.StdEncoding.EncodeToString(signature)
 
 	// Create signature object
+	p.signedManifest = canonical
 	p.Manifest.Signature = &Signature{
 		Algorithm:   "ed25519",
 		PublicKeyID: signer.publicKeyID,
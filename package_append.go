@@ -0,0 +1,142 @@
+package ptd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// OpenPackageForAppend opens the package archive at archivePath, verifying
+// it like OpenPackage, and extracts it into a working directory so that
+// entities can be added with AppendEntities or AddEntities. Save then
+// replaces the archive with the changed package, in its original format,
+// compression and indexing.
+func OpenPackageForAppend(archivePath string) (*Package, error) {
+	if absPath, err := filepath.Abs(archivePath); err == nil {
+		archivePath = absPath
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := NewPackage(opened.Manifest.Description)
+	if err := opened.archive.extract(pkg.tempDir); err != nil {
+		pkg.Cleanup()
+		return nil, err
+	}
+
+	pkg.setState(&Package{
+		ID:       opened.ID,
+		Created:  opened.Created,
+		Version:  opened.Version,
+		Manifest: opened.Manifest,
+		tempDir:  pkg.tempDir,
+	})
+	pkg.appendPath = archivePath
+	pkg.appendFormat = opened.archive.format
+	pkg.useArchiveSettings()
+	if pkg.Manifest.Signature != nil {
+		pkg.signedManifest, err = pkg.Manifest.CanonicalJSON()
+		if err != nil {
+			pkg.Cleanup()
+			return nil, fmt.Errorf("failed to get canonical JSON: %w", err)
+		}
+	}
+	return pkg, nil
+}
+
+// AppendEntities adds entities of a type after those the package already
+// holds. Unlike AddEntities, the type's existing entities are kept.
+func (p *Package) AppendEntities(entityType string, entities []interface{}) error {
+	lines, err := p.readEntityLines(entityType)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		line, err := json.Marshal(entity)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entity: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	return p.writeEntityLines(entityType, lines)
+}
+
+// Save replaces the archive of a package opened with OpenPackageForAppend
+// with its current contents. The new archive is written beside the old one
+// and renamed over it, so readers see either the old or the new archive.
+//
+// A manifest signature that no longer covers the changed manifest is
+// removed; to keep the package signed, call SignPackage before Save.
+func (p *Package) Save() error {
+	if p.appendPath == "" {
+		return fmt.Errorf("%w: package was not opened for append", ErrInvalidPackage)
+	}
+
+	if p.Manifest.Signature != nil {
+		canonical, err := p.Manifest.CanonicalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to get canonical JSON: %w", err)
+		}
+		if !bytes.Equal(canonical, p.signedManifest) {
+			p.Manifest.Signature = nil
+		}
+	}
+
+	info, err := os.Stat(p.appendPath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.appendPath), ".ptd-append-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if err := p.writeArchiveFormat(context.Background(), tmp, p.appendFormat, nil); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, p.appendPath); err != nil {
+		return fmt.Errorf("failed to replace archive: %w", err)
+	}
+	return nil
+}
+
+// useArchiveSettings sets the compression, indexing and schema embedding
+// of a package read from an archive to those its manifest records, so that
+// archiving it again keeps them
+func (p *Package) useArchiveSettings() {
+	if p.Manifest.Compression == string(CompressionZstd) {
+		p.compression, p.compressionLevel = CompressionZstd, -1
+	}
+	_, p.indexEntities = p.Manifest.Files[entityIndexPath]
+	p.embedSchemas = p.Manifest.hasEmbeddedSchemas()
+}
+
+// extract writes the files of the archive into dir, refusing names that
+// would escape it
+func (s *archiveSource) extract(dir string) error {
+	r, size, closer, err := s.contents()
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	return forEachArchiveFile(r, size, s.format, func(name string, file io.Reader) error {
+		_, err := extractFile(dir, name, file)
+		return err
+	})
+}
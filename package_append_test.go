@@ -0,0 +1,180 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newAppendTestEvents returns n event envelopes
+func newAppendTestEvents(n int) []interface{} {
+	var events []interface{}
+	for i := 0; i < n; i++ {
+		events = append(events, Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Late Event"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+		})
+	}
+	return events
+}
+
+func TestOpenPackageForAppend(t *testing.T) {
+	for _, name := range []string{"package.ptd", "package.ptd.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			pkg, ids := newEditTestPackage(t, 2)
+			archivePath := filepath.Join(t.TempDir(), name)
+			if ArchiveFormatForPath(name) == ArchiveZIP {
+				if err := pkg.SetCompression(CompressionZstd, -1); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := pkg.CreateArchive(archivePath); err != nil {
+				t.Fatal(err)
+			}
+
+			appendable, err := OpenPackageForAppend(archivePath)
+			if err != nil {
+				t.Fatalf("OpenPackageForAppend() error = %v", err)
+			}
+			defer appendable.Cleanup()
+			if err := appendable.AppendEntities(TypeEvent, newAppendTestEvents(2)); err != nil {
+				t.Fatalf("AppendEntities() error = %v", err)
+			}
+			if err := appendable.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			opened, err := OpenPackage(archivePath)
+			if err != nil {
+				t.Fatalf("OpenPackage() after Save error = %v", err)
+			}
+			if !opened.Manifest.Created.Equal(pkg.Manifest.Created) {
+				t.Error("Save() changed the package creation time")
+			}
+			events, err := ExtractEntities[Event](opened, TypeEvent)
+			if err != nil || len(events) != 4 {
+				t.Fatalf("ExtractEntities() = %d events, %v; want 4", len(events), err)
+			}
+			if events[0].ID != ids[0] || events[1].ID != ids[1] || events[2].Spec.Name.Default != "Late Event" {
+				t.Error("Appended events are not after the original ones")
+			}
+			if got := opened.Manifest.Entities[TypeEvent].Count; got != 4 {
+				t.Errorf("Manifest counts %d events, want 4", got)
+			}
+			if got := opened.archive.format; got != ArchiveFormatForPath(name) {
+				t.Errorf("Saved archive format = %s, want %s", got, ArchiveFormatForPath(name))
+			}
+			if name == "package.ptd" && opened.Manifest.Compression != string(CompressionZstd) {
+				t.Errorf("Saved archive compression = %q, want zstd", opened.Manifest.Compression)
+			}
+
+			entries, _ := os.ReadDir(filepath.Dir(archivePath))
+			if len(entries) != 1 {
+				t.Errorf("Archive directory has %d entries, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestPackage_Save_Signature(t *testing.T) {
+	signer, err := NewSigner("append-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := newEditTestPackage(t, 1)
+	if err := pkg.SignPackage(signer); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "signed.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	reopen := func() *Package {
+		t.Helper()
+		appendable, err := OpenPackageForAppend(archivePath)
+		if err != nil {
+			t.Fatalf("OpenPackageForAppend() error = %v", err)
+		}
+		t.Cleanup(func() { appendable.Cleanup() })
+		return appendable
+	}
+	verify := func() error {
+		t.Helper()
+		opened, err := OpenPackage(archivePath)
+		if err != nil {
+			t.Fatalf("OpenPackage() error = %v", err)
+		}
+		return opened.VerifyPackageSignature(signer.publicKey)
+	}
+
+	// An unchanged package keeps its signature
+	if err := reopen().Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err != nil {
+		t.Errorf("Signature of an unchanged package: %v", err)
+	}
+
+	// A change invalidates it
+	appendable := reopen()
+	appendable.AppendEntities(TypeEvent, newAppendTestEvents(1))
+	if err := appendable.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); !errors.Is(err, ErrSignatureMissing) {
+		t.Errorf("Signature after a change: %v, want ErrSignatureMissing", err)
+	}
+
+	// Signing before Save refreshes it
+	appendable = reopen()
+	appendable.AppendEntities(TypeEvent, newAppendTestEvents(1))
+	if err := appendable.SignPackage(signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := appendable.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err != nil {
+		t.Errorf("Refreshed signature: %v", err)
+	}
+}
+
+func TestOpenPackageForAppend_Errors(t *testing.T) {
+	pkg := NewPackage("Not opened")
+	defer pkg.Cleanup()
+	if err := pkg.Save(); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("Save() of a new package error = %v, want ErrInvalidPackage", err)
+	}
+
+	// A file that would be extracted outside the working directory
+	name := "../escaped.txt"
+	manifest := &Manifest{Version: "1.0.0", Files: map[string]*FileEntry{
+		name: newFileEntry(name, []byte("x"), time.Time{}),
+	}}
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	w, _ := writer.Create(name)
+	w.Write([]byte("x"))
+	w, _ = writer.Create("manifest.json")
+	json.NewEncoder(w).Encode(manifest)
+	writer.Close()
+
+	archivePath := filepath.Join(t.TempDir(), "escape.ptd")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenPackageForAppend(archivePath); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("OpenPackageForAppend() error = %v, want ErrInvalidPackage", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(archivePath), "escaped.txt")); err == nil {
+		t.Error("File was extracted outside the working directory")
+	}
+}
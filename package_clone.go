@@ -0,0 +1,37 @@
+package ptd
+
+import "time"
+
+// CloneOptions controls Package.Clone
+type CloneOptions struct {
+	NewDescription string // Replaces the manifest description if set
+	ClearSignature bool   // Drop the manifest signature
+}
+
+// Clone returns an independent copy of the package in a new working
+// directory, with a new package ID and creation time. Entity files and
+// their hashes are copied unchanged. The manifest signature covers the
+// creation time, so a kept signature no longer verifies: set
+// ClearSignature, or re-sign the clone with SignPackage.
+func (p *Package) Clone(opts CloneOptions) (*Package, error) {
+	clone, err := p.workingCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	clone.ID = GenerateULID()
+	clone.Created = now
+	if clone.Manifest != nil {
+		clone.Manifest.PackageID = clone.ID
+		clone.Manifest.Created = now
+		if opts.NewDescription != "" {
+			clone.Manifest.Description = opts.NewDescription
+		}
+		if opts.ClearSignature {
+			clone.Manifest.Signature = nil
+		}
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,133 @@
+package ptd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackage_Clone(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 3)
+
+	signer, err := NewSigner("clone-key", "Clone Test")
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	if err := pkg.SignPackage(signer); err != nil {
+		t.Fatalf("SignPackage() error = %v", err)
+	}
+	// Archiving records the file hashes in the manifest
+	if err := pkg.CreateArchive(filepath.Join(t.TempDir(), "original.ptd")); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	clone, err := pkg.Clone(CloneOptions{NewDescription: "Cloned", ClearSignature: true})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	defer clone.Cleanup()
+
+	if clone.ID == pkg.ID {
+		t.Error("Clone() kept the package ID")
+	}
+	if clone.Manifest.Description != "Cloned" {
+		t.Errorf("Description = %q, want Cloned", clone.Manifest.Description)
+	}
+	if clone.Manifest.Signature != nil {
+		t.Error("Clone() kept the signature")
+	}
+	if pkg.Manifest.Signature == nil || pkg.Manifest.Description != "Edit test" {
+		t.Error("Clone() modified the original manifest")
+	}
+	if clone.Manifest.Created.Before(pkg.Manifest.Created) {
+		t.Error("Clone() did not update Manifest.Created")
+	}
+
+	path := entityFilePath(TypeEvent)
+	if clone.Manifest.Files[path] == nil || clone.Manifest.Files[path].Hash != pkg.Manifest.Files[path].Hash {
+		t.Error("Clone() changed the entity file hash")
+	}
+
+	// The clone is independent of the original
+	if _, err := clone.DeleteEntities(TypeEvent, ids[:1]); err != nil {
+		t.Fatalf("DeleteEntities() error = %v", err)
+	}
+	if lines, err := pkg.readEntityLines(TypeEvent); err != nil || len(lines) != 3 {
+		t.Errorf("original has %d events (err %v), want 3", len(lines), err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "clone.ptd")
+	if err := clone.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if opened.Manifest.Entities[TypeEvent].Count != 2 {
+		t.Errorf("opened clone has %d events, want 2", opened.Manifest.Entities[TypeEvent].Count)
+	}
+	if _, err := os.Stat(pkg.tempDir); err != nil {
+		t.Errorf("original working directory: %v", err)
+	}
+}
+
+func TestPackage_Clone_KeepSignature(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	signer, err := NewSigner("clone-key", "Clone Test")
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	if err := pkg.SignPackage(signer); err != nil {
+		t.Fatalf("SignPackage() error = %v", err)
+	}
+
+	clone, err := pkg.Clone(CloneOptions{})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	defer clone.Cleanup()
+
+	if clone.Manifest.Signature == nil || clone.Manifest.Signature == pkg.Manifest.Signature {
+		t.Error("Clone() should keep an independent copy of the signature")
+	}
+	if clone.Manifest.Description != "Edit test" {
+		t.Errorf("Description = %q, want Edit test", clone.Manifest.Description)
+	}
+}
+
+func TestPackage_Clone_Opened(t *testing.T) {
+	archivePath, ids := newEntitiesTestArchive(t, 2)
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Cleanup()
+
+	clone, err := opened.Clone(CloneOptions{})
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	defer clone.Cleanup()
+
+	// The clone holds the archive's entities and can be edited
+	if deleted, err := clone.DeleteEntities(TypeEvent, ids[:1]); err != nil || deleted != 1 {
+		t.Fatalf("DeleteEntities() on the clone = %d, %v; want 1 deleted", deleted, err)
+	}
+
+	clonePath := filepath.Join(t.TempDir(), "clone.ptd")
+	if err := clone.CreateArchive(clonePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	reopened, err := OpenPackageWithOptions(clonePath, OpenOptions{VerifyCounts: true})
+	if err != nil {
+		t.Fatalf("OpenPackage() of the clone error = %v", err)
+	}
+	lines, err := reopened.readEntityLines(TypeEvent)
+	if err != nil || len(lines) != 1 {
+		t.Errorf("clone archive has %d events (err %v), want 1", len(lines), err)
+	}
+	if _, ok := reopened.Manifest.Files["manifest.json"]; ok {
+		t.Error("clone archive lists its own manifest as a file")
+	}
+}
@@ -0,0 +1,175 @@
+package ptd
+
+import (
+	"archive/zip"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Compression is the method that compresses the files of a ZIP archive
+type Compression string
+
+const (
+	CompressionDeflate Compression = "deflate" // DEFLATE, the default, readable by any ZIP tool
+	CompressionZstd    Compression = "zstd"    // Zstandard, smaller and faster for large packages
+)
+
+// SetCompression selects the compression of the ZIP archives created from
+// the package. level is on the method's own scale: flate.HuffmanOnly (-2)
+// through flate.BestCompression (9) for DEFLATE, 1 through 22 for
+// Zstandard as in the zstd tool; flate.DefaultCompression (-1) selects the
+// method's default. Zstandard is recorded in the manifest and decompressed
+// transparently by OpenPackage, but other ZIP tools may not support it.
+// tar.gz archives are always gzip-compressed.
+func (p *Package) SetCompression(compression Compression, level int) error {
+	switch compression {
+	case CompressionDeflate:
+		if level < flate.HuffmanOnly || level > flate.BestCompression {
+			return fmt.Errorf("%w: compression level must be between %d and %d, got %d", ErrValidation, flate.HuffmanOnly, flate.BestCompression, level)
+		}
+	case CompressionZstd:
+		if level != flate.DefaultCompression && (level < 1 || level > 22) {
+			return fmt.Errorf("%w: compression level must be between 1 and 22, got %d", ErrValidation, level)
+		}
+	default:
+		return fmt.Errorf("%w: unknown compression %q", ErrValidation, compression)
+	}
+
+	p.compression = compression
+	p.compressionLevel = level
+	return nil
+}
+
+// Recompress rewrites the package archive at archivePath with the given
+// DEFLATE level, from flate.HuffmanOnly (-2) and flate.DefaultCompression
+// (-1) through flate.BestCompression (9). File names, order, contents and
+// modification times are kept, so the manifest and any signature stay
+// valid. Files compressed with Zstandard are rewritten with DEFLATE.
+//
+// Files are copied one at a time into a temporary file beside the
+// archive, which then replaces the original by renaming, so memory use does
+// not grow with the archive; if anything fails the original is left
+// untouched.
+func (p *Package) Recompress(archivePath string, newLevel int) error {
+	if newLevel < flate.HuffmanOnly || newLevel > flate.BestCompression {
+		return fmt.Errorf("%w: compression level must be between %d and %d, got %d", ErrValidation, flate.HuffmanOnly, flate.BestCompression, newLevel)
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	registerZipDecompressors(&reader.Reader)
+
+	tmp, err := os.CreateTemp(filepath.Dir(archivePath), ".ptd-recompress-*")
+	if err != nil {
+		reader.Close()
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	err = recompressFiles(&reader.Reader, tmp, newLevel)
+	reader.Close() // The original must be closed before it is replaced
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, archivePath); err != nil {
+		return fmt.Errorf("failed to replace archive: %w", err)
+	}
+	return nil
+}
+
+// recompressFiles copies the files of reader, in order, to a ZIP archive
+// written to w with the given DEFLATE level
+func recompressFiles(reader *zip.Reader, w io.Writer, level int) error {
+	zipWriter := zip.NewWriter(w)
+	zipWriter.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, level)
+	})
+
+	for _, f := range reader.File {
+		header := zip.FileHeader{
+			Name:     f.Name,
+			Comment:  f.Comment,
+			Method:   zip.Deflate,
+			Modified: f.Modified,
+		}
+		writer, err := zipWriter.CreateHeader(&header)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		if err := copyArchivedFile(writer, f); err != nil {
+			return err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// copyArchivedFile copies the contents of an archived file to w, clearing
+// the compression recorded in the manifest
+func copyArchivedFile(w io.Writer, f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if f.Name != "manifest.json" {
+		if _, err := io.Copy(w, rc); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", f.Name, err)
+		}
+		return nil
+	}
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if data, err = deflateManifest(data); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// deflateManifest clears the Zstandard compression recorded in manifest
+// data; a manifest without it is returned unchanged
+func deflateManifest(data []byte) ([]byte, error) {
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if manifest.Compression == "" {
+		return data, nil
+	}
+
+	manifest.Compression = ""
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,204 @@
+package ptd
+
+import (
+	"compress/flate"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newCompressTestArchive writes an archive of a package with n matches
+func newCompressTestArchive(tb testing.TB, n int) (*Package, string) {
+	tb.Helper()
+	pkg := NewPackage("Compression test")
+	tb.Cleanup(func() { pkg.Cleanup() })
+
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	matches := make([]interface{}, n)
+	for i := range matches {
+		scheduled := start.Add(time.Duration(i) * 20 * time.Minute)
+		matches[i] = Envelope[Match]{
+			ID:   GenerateID(TypeMatch),
+			Type: TypeMatch,
+			Spec: Match{
+				EventID:     "ptd:event:01H0000000000000000000000",
+				MatchNumber: fmt.Sprintf("M%d", i+1),
+				ScheduledAt: &scheduled,
+				Court:       fmt.Sprintf("%d", i%8+1),
+				Status:      "completed",
+				HomeEntry:   &EntryRef{EntryID: GenerateID(TypeEntry), DisplayName: fmt.Sprintf("Player %d", 2*i)},
+				AwayEntry:   &EntryRef{EntryID: GenerateID(TypeEntry), DisplayName: fmt.Sprintf("Player %d", 2*i+1)},
+				Score:       newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{11, 5}),
+			},
+			Meta: Meta{Schema: "ptd.v1.match@1.0.0", Version: 1, CreatedAt: start},
+		}
+	}
+	if err := pkg.AddEntities(TypeMatch, matches); err != nil {
+		tb.Fatalf("Failed to add matches: %v", err)
+	}
+
+	archivePath := filepath.Join(tb.TempDir(), "package.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		tb.Fatalf("Failed to create archive: %v", err)
+	}
+	return pkg, archivePath
+}
+
+func TestPackage_Recompress(t *testing.T) {
+	pkg, archivePath := newCompressTestArchive(t, 200)
+	original, err := os.Stat(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pkg.Recompress(archivePath, flate.NoCompression); err != nil {
+		t.Fatalf("Recompress(0) error = %v", err)
+	}
+	stored, _ := os.Stat(archivePath)
+	if stored.Size() <= original.Size() {
+		t.Errorf("Uncompressed archive is %d bytes, want more than %d", stored.Size(), original.Size())
+	}
+
+	if err := pkg.Recompress(archivePath, flate.BestCompression); err != nil {
+		t.Fatalf("Recompress(9) error = %v", err)
+	}
+	best, _ := os.Stat(archivePath)
+	if best.Size() > original.Size() {
+		t.Errorf("Best compression archive is %d bytes, want at most %d", best.Size(), original.Size())
+	}
+
+	// The recompressed archive opens and its file hashes still verify
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	defer opened.Cleanup()
+	if got := opened.Manifest.Entities[TypeMatch].Count; got != 200 {
+		t.Errorf("Opened package has %d matches, want 200", got)
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Dir(archivePath))
+	if len(entries) != 1 {
+		t.Errorf("Archive directory has %d entries, want 1", len(entries))
+	}
+}
+
+func TestPackage_Recompress_Errors(t *testing.T) {
+	pkg, archivePath := newCompressTestArchive(t, 1)
+	before, _ := os.ReadFile(archivePath)
+
+	if err := pkg.Recompress(archivePath, 10); !errors.Is(err, ErrValidation) {
+		t.Errorf("Recompress(10) error = %v, want ErrValidation", err)
+	}
+	if err := pkg.Recompress(filepath.Join(t.TempDir(), "missing.ptd"), 6); err == nil {
+		t.Error("Recompress() of a missing archive should fail")
+	}
+
+	notZip := filepath.Join(t.TempDir(), "bad.ptd")
+	os.WriteFile(notZip, []byte("not a zip"), 0644)
+	if err := pkg.Recompress(notZip, 6); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("Recompress() of a non-archive error = %v, want ErrInvalidPackage", err)
+	}
+
+	if after, _ := os.ReadFile(archivePath); string(after) != string(before) {
+		t.Error("Failed Recompress() modified the archive")
+	}
+}
+
+func TestPackage_SetCompression(t *testing.T) {
+	pkg, deflated := newCompressTestArchive(t, 200)
+	deflatedInfo, _ := os.Stat(deflated)
+
+	if err := pkg.SetCompression(CompressionZstd, 19); err != nil {
+		t.Fatalf("SetCompression() error = %v", err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "zstd.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	info, _ := os.Stat(archivePath)
+	if info.Size() >= deflatedInfo.Size() {
+		t.Errorf("Zstandard archive is %d bytes, want less than DEFLATE's %d", info.Size(), deflatedInfo.Size())
+	}
+
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if opened.Manifest.Compression != string(CompressionZstd) {
+		t.Errorf("Manifest compression = %q, want %q", opened.Manifest.Compression, CompressionZstd)
+	}
+	matches, err := ExtractEntities[Match](opened, TypeMatch)
+	if err != nil || len(matches) != 200 {
+		t.Errorf("ExtractEntities() = %d matches, %v; want 200", len(matches), err)
+	}
+
+	// Compression is archive metadata and leaves signatures intact
+	canonical, _ := opened.Manifest.CanonicalJSON()
+	if want, _ := pkg.Manifest.CanonicalJSON(); string(canonical) != string(want) {
+		t.Error("Compression changed the canonical manifest")
+	}
+
+	// Recompressing converts the archive to DEFLATE
+	if err := opened.Recompress(archivePath, flate.DefaultCompression); err != nil {
+		t.Fatalf("Recompress() error = %v", err)
+	}
+	recompressed, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if recompressed.Manifest.Compression != "" {
+		t.Errorf("Recompressed manifest compression = %q, want none", recompressed.Manifest.Compression)
+	}
+
+	// tar.gz archives have their own compression
+	if err := pkg.CreateArchive(filepath.Join(t.TempDir(), "zstd.ptd.tar.gz")); !errors.Is(err, ErrValidation) {
+		t.Errorf("CreateArchive(tar.gz) with Zstandard error = %v, want ErrValidation", err)
+	}
+}
+
+func TestPackage_SetCompression_Errors(t *testing.T) {
+	pkg := NewPackage("Compression test")
+	defer pkg.Cleanup()
+
+	tests := []struct {
+		compression Compression
+		level       int
+	}{
+		{CompressionDeflate, 10},
+		{CompressionDeflate, -3},
+		{CompressionZstd, 0},
+		{CompressionZstd, 23},
+		{"brotli", 5},
+	}
+	for _, tt := range tests {
+		if err := pkg.SetCompression(tt.compression, tt.level); !errors.Is(err, ErrValidation) {
+			t.Errorf("SetCompression(%q, %d) error = %v, want ErrValidation", tt.compression, tt.level, err)
+		}
+	}
+}
+
+// BenchmarkRecompress compares archive size and time across compression
+// levels on a package of 5,000 scored matches
+func BenchmarkRecompress(b *testing.B) {
+	pkg, archivePath := newCompressTestArchive(b, 5000)
+
+	for _, level := range []int{1, 6, 9} {
+		b.Run(fmt.Sprintf("level-%d", level), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := pkg.Recompress(archivePath, level); err != nil {
+					b.Fatal(err)
+				}
+			}
+			info, err := os.Stat(archivePath)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportMetric(float64(info.Size()), "archive-bytes")
+		})
+	}
+}
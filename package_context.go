@@ -0,0 +1,139 @@
+package ptd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CreateArchiveContext creates an archive of the package like CreateArchive,
+// calling onProgress, which may be nil, after each file is written. If ctx
+// is done before the archive is complete, the partial archive is removed
+// and ctx's error returned.
+func (p *Package) CreateArchiveContext(ctx context.Context, outputPath string, onProgress func(ArchiveProgress)) error {
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+	archive, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if err := p.writeArchiveFormat(ctx, archive, ArchiveFormatForPath(outputPath), onProgress); err != nil {
+		archive.Close()
+		os.Remove(outputPath)
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// OpenPackageContext opens and validates a package archive like
+// OpenPackage, stopping with ctx's error if ctx is done before the archive's
+// files are verified. onProgress, which may be nil, is called after each
+// file is read.
+//
+// A ZIP archive lists its files up front, so progress counts its files and
+// their uncompressed bytes. The files of a tar.gz archive are only known
+// once it has been read: its progress counts bytes of the compressed
+// archive, and FilesTotal is zero until the last update.
+func OpenPackageContext(ctx context.Context, archivePath string, onProgress func(ArchiveProgress)) (*Package, error) {
+	return openPackagePath(ctx, archivePath, true, onProgress)
+}
+
+// contextReader reads from r until ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read implements io.Reader
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// openProgress reports the progress of reading the files of an archive
+type openProgress struct {
+	onProgress func(ArchiveProgress)
+	progress   ArchiveProgress
+	source     io.ReaderAt       // the archive, counted for tar.gz archives
+	compressed *countingReaderAt // bytes read of a tar.gz archive
+	sizes      map[string]int64  // uncompressed file sizes of a ZIP archive
+}
+
+// newOpenProgress prepares progress reporting for the archive read from r.
+// The archive must then be read from the returned source.
+func newOpenProgress(r io.ReaderAt, size int64, format ArchiveFormat, onProgress func(ArchiveProgress)) *openProgress {
+	p := &openProgress{onProgress: onProgress, source: r}
+	if onProgress == nil {
+		return p
+	}
+
+	if format == ArchiveTarGz {
+		p.compressed = &countingReaderAt{r: r}
+		p.source = p.compressed
+		p.progress.BytesTotal = size
+		return p
+	}
+
+	reader, err := newZipReader(r, size)
+	if err != nil {
+		// Reported when the archive is read
+		return p
+	}
+	p.sizes = make(map[string]int64)
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		p.sizes[f.Name] = int64(f.UncompressedSize64)
+		p.progress.FilesTotal++
+		p.progress.BytesTotal += int64(f.UncompressedSize64)
+	}
+	return p
+}
+
+// fileRead reports that a file of the archive has been read
+func (p *openProgress) fileRead(name string) {
+	if p.onProgress == nil {
+		return
+	}
+	p.progress.FilesWritten++
+	if p.compressed != nil {
+		p.progress.BytesWritten = p.compressed.n
+	} else {
+		p.progress.BytesWritten += p.sizes[name]
+	}
+	p.onProgress(p.progress)
+}
+
+// done reports that a tar.gz archive has been read, now that its file count
+// is known
+func (p *openProgress) done() {
+	if p.onProgress == nil || p.compressed == nil {
+		return
+	}
+	p.progress.FilesTotal = p.progress.FilesWritten
+	p.progress.BytesWritten = p.progress.BytesTotal
+	p.onProgress(p.progress)
+}
+
+// countingReaderAt counts the bytes read from r
+type countingReaderAt struct {
+	r io.ReaderAt
+	n int64
+}
+
+// ReadAt implements io.ReaderAt
+func (c *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	n, err := c.r.ReadAt(p, off)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,97 @@
+package ptd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackage_CreateArchiveContext_Canceled(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 3)
+	archivePath := filepath.Join(t.TempDir(), "canceled.ptd")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := 0
+	err := pkg.CreateArchiveContext(ctx, archivePath, func(ArchiveProgress) {
+		updates++
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("CreateArchiveContext() error = %v, want context.Canceled", err)
+	}
+	if updates != 1 {
+		t.Errorf("got %d progress updates after cancel, want 1", updates)
+	}
+	if _, err := os.Stat(archivePath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("partial archive left behind: %v", err)
+	}
+
+	// The package can still be archived
+	if err := pkg.CreateArchiveContext(context.Background(), archivePath, nil); err != nil {
+		t.Fatalf("CreateArchiveContext() error = %v", err)
+	}
+}
+
+func TestOpenPackageContext(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 3)
+	dir := t.TempDir()
+
+	for _, name := range []string{"progress.ptd", "progress.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			archivePath := filepath.Join(dir, name)
+			if err := pkg.CreateArchive(archivePath); err != nil {
+				t.Fatalf("CreateArchive() error = %v", err)
+			}
+			info, err := os.Stat(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var updates []ArchiveProgress
+			if _, err := OpenPackageContext(context.Background(), archivePath, func(p ArchiveProgress) {
+				updates = append(updates, p)
+			}); err != nil {
+				t.Fatalf("OpenPackageContext() error = %v", err)
+			}
+
+			files := 1 // the manifest
+			for name := range pkg.Manifest.Files {
+				if name != "manifest.json" {
+					files++
+				}
+			}
+			if len(updates) < files {
+				t.Fatalf("got %d progress updates, want at least %d", len(updates), files)
+			}
+			last := updates[len(updates)-1]
+			if last.FilesWritten != files || last.FilesTotal != files || last.BytesWritten != last.BytesTotal {
+				t.Errorf("last update = %+v, want %d of %d files and all bytes", last, files, files)
+			}
+			for i := 1; i < len(updates); i++ {
+				if updates[i].BytesWritten < updates[i-1].BytesWritten {
+					t.Error("BytesWritten should be monotonic")
+				}
+			}
+
+			if name == "progress.tar.gz" {
+				if last.BytesTotal != info.Size() {
+					t.Errorf("BytesTotal = %d, want the archive size %d", last.BytesTotal, info.Size())
+				}
+				if updates[0].FilesTotal != 0 {
+					t.Errorf("FilesTotal = %d before the archive is read, want 0", updates[0].FilesTotal)
+				}
+			} else if updates[0].FilesTotal != files {
+				t.Errorf("FilesTotal = %d, want %d", updates[0].FilesTotal, files)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if _, err := OpenPackageContext(ctx, archivePath, nil); !errors.Is(err, context.Canceled) {
+				t.Errorf("OpenPackageContext() error = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
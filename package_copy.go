@@ -0,0 +1,124 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CopyOptions selects the entities CopyEntities copies. Entities must meet
+// every criterion given; with none, all entities are copied.
+type CopyOptions struct {
+	Types       []string                                      // Entity types to copy, all if empty
+	IDs         []string                                      // Entity IDs to copy, any if empty
+	Filter      func(envelope Envelope[json.RawMessage]) bool // Reports whether to copy an entity, if set
+	Description string                                        // Description of the new package, the source's if empty
+}
+
+// CopyEntities writes the entities of src selected by opts into a new
+// package in its own working directory, such as an extract of one club's
+// data from a master package. The new package keeps the source's PTD
+// version and catalog metadata. Each copied entity records src in its
+// Provenance: ImportedFrom is set to src.ID and ImportedAt to now, keeping
+// its original source. This changes what an entity signature covers, so
+// copied entities carry no signature. IDs that match no entity of the
+// selected types return an error wrapping ErrInvalidID. src is left
+// untouched; call Cleanup on the new package when done.
+func CopyEntities(src *Package, opts CopyOptions) (*Package, error) {
+	types := opts.Types
+	if len(types) == 0 {
+		types = src.entityTypes()
+	}
+	wanted := make(map[string]bool, len(opts.IDs))
+	for _, id := range opts.IDs {
+		wanted[id] = true
+	}
+	found := make(map[string]bool, len(opts.IDs))
+
+	description := opts.Description
+	if description == "" {
+		description = src.Manifest.Description
+	}
+	dst := NewPackage(description)
+	dst.copyMetadata(src)
+
+	now := time.Now()
+	for _, entityType := range types {
+		var lines []json.RawMessage
+		var copyErr error
+		err := src.scanEntityLines(entityType, func(n int, line []byte) bool {
+			var envelope Envelope[json.RawMessage]
+			if err := json.Unmarshal(line, &envelope); err != nil {
+				copyErr = fmt.Errorf("%w: %s line %d: %v", ErrInvalidFormat, entityFilePath(entityType), n, err)
+				return false
+			}
+			if len(wanted) > 0 && !wanted[envelope.ID] {
+				return true
+			}
+			if opts.Filter != nil && !opts.Filter(envelope) {
+				return true
+			}
+			found[envelope.ID] = true
+
+			provenance := &Provenance{}
+			if envelope.Meta.Provenance != nil {
+				*provenance = *envelope.Meta.Provenance
+			}
+			provenance.ImportedFrom = src.ID
+			provenance.ImportedAt = &now
+			envelope.Meta.Provenance = provenance
+			envelope.Meta.Signature = nil
+
+			copied, err := json.Marshal(envelope)
+			if err != nil {
+				copyErr = fmt.Errorf("failed to marshal entity %s: %w", envelope.ID, err)
+				return false
+			}
+			lines = append(lines, copied)
+			return true
+		})
+		if err == nil {
+			err = copyErr
+		}
+		if err == nil && len(lines) > 0 {
+			err = dst.writeEntityLines(entityType, lines)
+		}
+		if err != nil {
+			dst.Cleanup()
+			return nil, err
+		}
+	}
+
+	var missing []string
+	for id := range wanted {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		dst.Cleanup()
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w: entities not found: %s", ErrInvalidID, strings.Join(missing, ", "))
+	}
+
+	return dst, nil
+}
+
+// copyMetadata copies the PTD version and catalog metadata of src into
+// the manifest of a package derived from it
+func (p *Package) copyMetadata(src *Package) {
+	p.Version = src.Version
+	m, from := p.Manifest, src.Manifest
+	m.Version = from.Version
+	m.Creator = from.Creator
+	m.Languages = append([]string(nil), from.Languages...)
+	m.Sport = from.Sport
+	m.GoverningBody = from.GoverningBody
+	m.License = from.License
+	if from.Publisher != nil {
+		m.Publisher = from.Publisher.clone()
+	}
+	m.Tags = append([]string(nil), from.Tags...)
+}
@@ -0,0 +1,156 @@
+package ptd
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// newCopyTestPackage builds a master package with players of two clubs,
+// one of them signed with a provenance, and two events
+func newCopyTestPackage(t *testing.T) (*Package, []string) {
+	t.Helper()
+	pkg, _ := newEditTestPackage(t, 2)
+	pkg.Manifest.Sport = "table_tennis"
+	pkg.Manifest.Publisher = &Publisher{Name: "Example Federation"}
+	pkg.Manifest.AddTags("national")
+
+	signer, err := NewSigner("copy-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	var players []interface{}
+	for i, club := range []string{"North", "South", "North"} {
+		player := &Envelope[Player]{
+			ID:   GenerateID(TypePlayer),
+			Type: TypePlayer,
+			Spec: Player{FirstName: "Player", LastName: club, Club: club},
+			Meta: Meta{Schema: "ptd.v1.player@1.0.0", Version: 1},
+		}
+		if i == 0 {
+			player.Meta.Provenance = &Provenance{OriginalSource: "ittf", ImportedFrom: "older"}
+			if err := signer.Sign(player); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids = append(ids, player.ID)
+		players = append(players, player)
+	}
+	if err := pkg.AddEntities(TypePlayer, players); err != nil {
+		t.Fatal(err)
+	}
+	return pkg, ids
+}
+
+func TestCopyEntities(t *testing.T) {
+	src, playerIDs := newCopyTestPackage(t)
+
+	extract, err := CopyEntities(src, CopyOptions{
+		Types: []string{TypePlayer},
+		Filter: func(envelope Envelope[json.RawMessage]) bool {
+			var player Player
+			return json.Unmarshal(envelope.Spec, &player) == nil && player.Club == "North"
+		},
+		Description: "North club",
+	})
+	if err != nil {
+		t.Fatalf("CopyEntities() error = %v", err)
+	}
+	defer extract.Cleanup()
+
+	if extract.ID == src.ID {
+		t.Error("extract shares the source's package ID")
+	}
+	if got := extract.Manifest.Entities; len(got) != 1 || got[TypePlayer].Count != 2 {
+		t.Errorf("Entities = %v, want 2 players only", got)
+	}
+	m := extract.Manifest
+	if m.Description != "North club" || m.Sport != "table_tennis" || m.Publisher.Name != "Example Federation" || !m.HasTag("national") {
+		t.Errorf("manifest = %+v, want the source's catalog metadata", m)
+	}
+
+	players, err := ExtractEntities[Player](extract, TypePlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(players) != 2 || players[0].ID != playerIDs[0] || players[1].ID != playerIDs[2] {
+		t.Fatalf("copied players = %+v, want the North players in order", players)
+	}
+	for _, player := range players {
+		provenance := player.Meta.Provenance
+		if provenance == nil || provenance.ImportedFrom != src.ID || provenance.ImportedAt == nil {
+			t.Errorf("Provenance of %s = %+v, want imported from %s", player.ID, provenance, src.ID)
+		}
+		if player.Meta.Signature != nil {
+			t.Errorf("%s kept a signature its provenance broke", player.ID)
+		}
+	}
+	if players[0].Meta.Provenance.OriginalSource != "ittf" {
+		t.Errorf("OriginalSource = %q, want ittf kept", players[0].Meta.Provenance.OriginalSource)
+	}
+
+	// The source is untouched
+	original, err := ExtractEntities[Player](src, TypePlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original[0].Meta.Provenance.ImportedFrom != "older" || original[0].Meta.Signature == nil {
+		t.Error("CopyEntities() changed the source package")
+	}
+
+	// The extract archives like any package and traces back to the source
+	archivePath := filepath.Join(t.TempDir(), "north.ptd")
+	if err := extract.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	srcPath := filepath.Join(t.TempDir(), "master.ptd")
+	if err := src.CreateArchive(srcPath); err != nil {
+		t.Fatal(err)
+	}
+	master, err := OpenPackage(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph, err := BuildLineageGraph([]*Package{master, opened})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].To != src.ID {
+		t.Errorf("lineage edges = %v, want the extract pointing at the source", graph.Edges)
+	}
+}
+
+func TestCopyEntities_IDs(t *testing.T) {
+	src, playerIDs := newCopyTestPackage(t)
+
+	extract, err := CopyEntities(src, CopyOptions{IDs: []string{playerIDs[1]}})
+	if err != nil {
+		t.Fatalf("CopyEntities() error = %v", err)
+	}
+	defer extract.Cleanup()
+	if got := extract.Manifest.Entities; len(got) != 1 || got[TypePlayer].Count != 1 {
+		t.Errorf("Entities = %v, want a single player", got)
+	}
+
+	// An ID outside the selected types is not found
+	_, err = CopyEntities(src, CopyOptions{Types: []string{TypeEvent}, IDs: []string{playerIDs[0]}})
+	if !errors.Is(err, ErrInvalidID) {
+		t.Errorf("CopyEntities() error = %v, want ErrInvalidID", err)
+	}
+
+	// Without options everything is copied
+	all, err := CopyEntities(src, CopyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer all.Cleanup()
+	if all.Manifest.Entities[TypeEvent].Count != 2 || all.Manifest.Entities[TypePlayer].Count != 3 {
+		t.Errorf("Entities = %v, want all of the source's", all.Manifest.Entities)
+	}
+}
@@ -0,0 +1,275 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DeleteEntities removes the entities with the given IDs from the package,
+// rewriting the entity file and updating its manifest file entry and entity
+// count. It returns the number of entities actually deleted; IDs that are not
+// in the package are ignored. Returns ErrDuplicateEntity if an ID is listed
+// more than once.
+func (p *Package) DeleteEntities(entityType string, entityIDs []string) (int, error) {
+	if err := p.requireWorkingDir(); err != nil {
+		return 0, err
+	}
+	remove := make(map[string]bool, len(entityIDs))
+	for _, id := range entityIDs {
+		if remove[id] {
+			return 0, fmt.Errorf("%w: %s listed more than once", ErrDuplicateEntity, id)
+		}
+		remove[id] = true
+	}
+
+	lines, err := p.readEntityLines(entityType)
+	if err != nil {
+		return 0, err
+	}
+	if len(lines) == 0 {
+		return 0, nil
+	}
+
+	kept := make([]json.RawMessage, 0, len(lines))
+	for _, line := range lines {
+		id, err := envelopeID(line)
+		if err != nil {
+			return 0, err
+		}
+		if !remove[id] {
+			kept = append(kept, line)
+		}
+	}
+
+	deleted := len(lines) - len(kept)
+	if deleted == 0 {
+		return 0, nil
+	}
+
+	if err := p.writeEntityLines(entityType, kept); err != nil {
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+// ReplaceEntity replaces the stored entity with the same ID as envelope,
+// rewriting the entity file atomically. envelope is stored exactly as it
+// marshals, so a signature over it stays valid; its meta.version must
+// therefore already be one more than the stored version, or a
+// *ValidationError wrapping ErrValidation is returned. Returns ErrInvalidID
+// if no entity with that ID exists.
+func (p *Package) ReplaceEntity(entityType string, envelope interface{}) error {
+	return p.replaceEntity(entityType, envelope, nil)
+}
+
+// ReplaceEntityWithVersion replaces the stored entity like ReplaceEntity,
+// but only if its meta.version is still expectedVersion. Returns
+// ErrVersionConflict if the stored entity has been changed since.
+func (p *Package) ReplaceEntityWithVersion(entityType string, envelope interface{}, expectedVersion int) error {
+	return p.replaceEntity(entityType, envelope, &expectedVersion)
+}
+
+// replaceEntity replaces the stored entity with the same ID as envelope,
+// checking its version first if expectedVersion is set
+func (p *Package) replaceEntity(entityType string, envelope interface{}, expectedVersion *int) error {
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+	line, id, err := encodeEnvelope(envelope)
+	if err != nil {
+		return err
+	}
+
+	lines, err := p.readEntityLines(entityType)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range lines {
+		existingID, err := envelopeID(existing)
+		if err != nil {
+			return err
+		}
+		if existingID != id {
+			continue
+		}
+
+		if expectedVersion != nil {
+			version, err := entityVersion(existing)
+			if err != nil {
+				return err
+			}
+			if version != *expectedVersion {
+				return fmt.Errorf("%w: %s is at version %d, expected %d", ErrVersionConflict, id, version, *expectedVersion)
+			}
+		}
+
+		if err := checkNextVersion(entityType, id, line, existing); err != nil {
+			return err
+		}
+		lines[i] = line
+		return p.writeEntityLines(entityType, lines)
+	}
+
+	return fmt.Errorf("%w: no %s entity with ID %s", ErrInvalidID, entityType, id)
+}
+
+// UpsertEntity replaces the stored entity with the same ID as envelope, as
+// ReplaceEntity does, or appends envelope if there is none. It reports
+// whether a new entity was created.
+func (p *Package) UpsertEntity(entityType string, envelope interface{}) (created bool, err error) {
+	if err := p.requireWorkingDir(); err != nil {
+		return false, err
+	}
+	line, id, err := encodeEnvelope(envelope)
+	if err != nil {
+		return false, err
+	}
+
+	lines, err := p.readEntityLines(entityType)
+	if err != nil {
+		return false, err
+	}
+
+	for _, existing := range lines {
+		existingID, err := envelopeID(existing)
+		if err != nil {
+			return false, err
+		}
+		if existingID == id {
+			return false, p.ReplaceEntity(entityType, envelope)
+		}
+	}
+
+	if err := p.writeEntityLines(entityType, append(lines, line)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// requireWorkingDir returns ErrInvalidPackage if the package has no working
+// directory to write to, as for packages opened with OpenPackage
+func (p *Package) requireWorkingDir() error {
+	if p.tempDir == "" {
+		return fmt.Errorf("%w: package has no working directory; open it with OpenPackageForAppend to edit it", ErrInvalidPackage)
+	}
+	return nil
+}
+
+// writeEntityLines replaces the NDJSON file for an entity type and updates
+// the manifest's entity count and file entry
+func (p *Package) writeEntityLines(entityType string, lines []json.RawMessage) error {
+	if err := checkEntityType(entityType); err != nil {
+		return err
+	}
+	if err := p.requireWorkingDir(); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	relPath := entityFilePath(entityType)
+	path := filepath.Join(p.tempDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s entities: %w", entityType, err)
+	}
+
+	p.Manifest.Files[relPath] = newFileEntry(relPath, buf.Bytes(), time.Now())
+	p.Manifest.Entities[entityType] = EntityCount{
+		Type:  entityType,
+		Count: len(lines),
+	}
+
+	return nil
+}
+
+// encodeEnvelope marshals an envelope and returns it with its ID
+func encodeEnvelope(envelope interface{}) (json.RawMessage, string, error) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal entity: %w", err)
+	}
+	id, err := envelopeID(data)
+	if err != nil {
+		return nil, "", err
+	}
+	if id == "" {
+		return nil, "", ErrInvalidID
+	}
+	return data, id, nil
+}
+
+// checkNextVersion returns a *ValidationError if the meta.version of the
+// replacement line is not one more than that of the existing line
+func checkNextVersion(entityType, id string, line, existing json.RawMessage) error {
+	stored, err := entityVersion(existing)
+	if err != nil {
+		return err
+	}
+	version, err := entityVersion(line)
+	if err != nil {
+		return err
+	}
+	if version != stored+1 {
+		return newValidationError(ErrValidation, entityType, "meta.version", "replacement of %s has version %d, want %d", id, version, stored+1)
+	}
+	return nil
+}
+
+// entityVersion returns meta.version of a stored entity line
+func entityVersion(line json.RawMessage) (int, error) {
+	var entity struct {
+		Meta struct {
+			Version int `json:"version"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(line, &entity); err != nil {
+		return 0, fmt.Errorf("%w: failed to decode entity: %v", ErrInvalidPackage, err)
+	}
+	return entity.Meta.Version, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// envelopeID extracts the ID of an encoded envelope
+func envelopeID(line json.RawMessage) (string, error) {
+	var envelope struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return "", fmt.Errorf("%w: failed to decode entity: %v", ErrInvalidPackage, err)
+	}
+	return envelope.ID, nil
+}
@@ -0,0 +1,347 @@
+package ptd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newEditTestPackage creates a package holding the given number of events
+func newEditTestPackage(t *testing.T, n int) (*Package, []string) {
+	t.Helper()
+	pkg := NewPackage("Edit test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	var ids []string
+	var events []interface{}
+	for i := 0; i < n; i++ {
+		id := GenerateID(TypeEvent)
+		ids = append(ids, id)
+		events = append(events, Envelope[Event]{
+			ID:   id,
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Event"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+		})
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	return pkg, ids
+}
+
+func TestPackage_DeleteEntities(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 3)
+
+	deleted, err := pkg.DeleteEntities(TypeEvent, []string{ids[0], ids[2], "ptd:event:missing"})
+	if err != nil {
+		t.Fatalf("DeleteEntities failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 deleted, got %d", deleted)
+	}
+	if count := pkg.Manifest.Entities[TypeEvent].Count; count != 1 {
+		t.Errorf("Expected 1 remaining event, got %d", count)
+	}
+
+	lines, err := pkg.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to read entities: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line, got %d", len(lines))
+	}
+	if id, _ := envelopeID(lines[0]); id != ids[1] {
+		t.Errorf("Wrong entity kept: %s", id)
+	}
+
+	// File entry hash matches the rewritten file
+	relPath := entityFilePath(TypeEvent)
+	data, err := os.ReadFile(filepath.Join(pkg.tempDir, relPath))
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	entry := pkg.Manifest.Files[relPath]
+	if entry == nil || entry.Hash != hex.EncodeToString(sum[:]) || entry.Size != int64(len(data)) {
+		t.Errorf("File entry not updated: %+v", entry)
+	}
+
+	if _, err := pkg.DeleteEntities(TypeEvent, []string{ids[1], ids[1]}); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity, got %v", err)
+	}
+	if count := pkg.Manifest.Entities[TypeEvent].Count; count != 1 {
+		t.Error("Rejected delete should not change the package")
+	}
+
+	if deleted, err := pkg.DeleteEntities(TypeMatch, []string{ids[1]}); err != nil || deleted != 0 {
+		t.Errorf("Deleting from missing type = %d, %v", deleted, err)
+	}
+}
+
+// openEditTestArchive opens an archive of n events with OpenPackage, which
+// leaves the package without a working directory, and makes an empty
+// directory the current one so that stray writes can be detected
+func openEditTestArchive(t *testing.T, n int) (*Package, []string, string) {
+	t.Helper()
+	archivePath, ids := newEntitiesTestArchive(t, n)
+	pkg, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return pkg, ids, dir
+}
+
+// assertEmptyDir fails the test if anything was written to dir
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	if entries, _ := os.ReadDir(dir); len(entries) > 0 {
+		t.Errorf("files written outside the package: %v", entries)
+	}
+}
+
+func TestPackage_DeleteEntities_Opened(t *testing.T) {
+	pkg, ids, dir := openEditTestArchive(t, 2)
+
+	deleted, err := pkg.DeleteEntities(TypeEvent, []string{ids[0]})
+	if !errors.Is(err, ErrInvalidPackage) || deleted != 0 {
+		t.Errorf("DeleteEntities() on an opened package = %d, %v; want ErrInvalidPackage", deleted, err)
+	}
+	if count := pkg.Manifest.Entities[TypeEvent].Count; count != 2 {
+		t.Errorf("event count = %d, want 2", count)
+	}
+	assertEmptyDir(t, dir)
+}
+
+func TestPackage_ReplaceEntity(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 3)
+	hashBefore := pkg.Manifest.Files[entityFilePath(TypeEvent)]
+
+	replacement := Envelope[Event]{
+		ID:   ids[1],
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Renamed"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 2},
+	}
+	if err := pkg.ReplaceEntity(TypeEvent, replacement); err != nil {
+		t.Fatalf("ReplaceEntity failed: %v", err)
+	}
+
+	events, err := decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to decode entities: %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	if events[1].ID != ids[1] || events[1].Spec.Name.Default != "Renamed" {
+		t.Errorf("Entity not replaced in place: %+v", events[1])
+	}
+	if events[1].Meta.Version != 2 {
+		t.Errorf("Expected version 2, got %d", events[1].Meta.Version)
+	}
+	if events[0].Spec.Name.Default != "Event" || events[2].Spec.Name.Default != "Event" {
+		t.Error("Other entities should be unchanged")
+	}
+
+	entry := pkg.Manifest.Files[entityFilePath(TypeEvent)]
+	if entry == nil || (hashBefore != nil && entry.Hash == hashBefore.Hash) {
+		t.Error("Manifest hash should be updated")
+	}
+
+	// No temp files left behind
+	files, _ := os.ReadDir(filepath.Join(pkg.tempDir, TypeEvent))
+	if len(files) != 1 {
+		t.Errorf("Expected only the entity file, got %d files", len(files))
+	}
+
+	replacement.ID = GenerateID(TypeEvent)
+	if err := pkg.ReplaceEntity(TypeEvent, replacement); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID for unknown ID, got %v", err)
+	}
+}
+
+func TestPackage_ReplaceEntity_Signed(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 1)
+	signer, err := NewSigner("test-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replacement := &Envelope[Event]{
+		ID:   ids[0],
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Signed"}, Format: "single_elimination"},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 2},
+	}
+	if err := signer.Sign(replacement); err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(replacement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.ReplaceEntity(TypeEvent, replacement); err != nil {
+		t.Fatalf("ReplaceEntity failed: %v", err)
+	}
+
+	// The stored line is the caller's envelope byte for byte
+	lines, err := pkg.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || !bytes.Equal(lines[0], want) {
+		t.Errorf("Stored line = %s, want %s", lines[0], want)
+	}
+	events, err := decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(&events[0], signer.publicKey); err != nil {
+		t.Errorf("Signature of the stored entity does not verify: %v", err)
+	}
+
+	// A replacement that does not advance the version is refused
+	for _, version := range []int{2, 4} {
+		replacement.Meta.Version = version
+		var validationErr *ValidationError
+		if err := pkg.ReplaceEntity(TypeEvent, replacement); !errors.Is(err, ErrValidation) || !errors.As(err, &validationErr) {
+			t.Errorf("ReplaceEntity(version %d) error = %v, want *ValidationError wrapping ErrValidation", version, err)
+		}
+	}
+}
+
+func TestPackage_ReplaceEntityWithVersion(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 1)
+
+	events, err := decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to decode entities: %v", err)
+	}
+	read := events[0]
+	expected := read.Version()
+	read.Spec.Name.Default = "First edit"
+	read.Meta.Version = expected + 1
+	if err := pkg.ReplaceEntityWithVersion(TypeEvent, read, expected); err != nil {
+		t.Fatalf("ReplaceEntityWithVersion failed: %v", err)
+	}
+
+	// A second writer holding the same stale version is rejected
+	read.Spec.Name.Default = "Stale edit"
+	err = pkg.ReplaceEntityWithVersion(TypeEvent, read, expected)
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("Expected ErrVersionConflict, got %v", err)
+	}
+
+	events, err = decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to decode entities: %v", err)
+	}
+	if events[0].ID != ids[0] || events[0].Spec.Name.Default != "First edit" || events[0].Version() != 2 {
+		t.Errorf("Unexpected stored entity: %+v", events[0])
+	}
+
+	read.ID = GenerateID(TypeEvent)
+	if err := pkg.ReplaceEntityWithVersion(TypeEvent, read, 1); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID for unknown ID, got %v", err)
+	}
+}
+
+func TestPackage_UpsertEntity(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 1)
+
+	updated := Envelope[Event]{
+		ID:   ids[0],
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Updated"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 2},
+	}
+	created, err := pkg.UpsertEntity(TypeEvent, updated)
+	if err != nil || created {
+		t.Fatalf("Upsert of existing entity = %v, %v", created, err)
+	}
+
+	added := updated
+	added.ID = GenerateID(TypeEvent)
+	created, err = pkg.UpsertEntity(TypeEvent, added)
+	if err != nil || !created {
+		t.Fatalf("Upsert of new entity = %v, %v", created, err)
+	}
+
+	events, err := decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to decode entities: %v", err)
+	}
+	if len(events) != 2 || pkg.Manifest.Entities[TypeEvent].Count != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Spec.Name.Default != "Updated" || events[0].Meta.Version != 2 {
+		t.Errorf("Existing entity not replaced: %+v", events[0])
+	}
+	if events[1].ID != added.ID {
+		t.Errorf("New entity not appended: %s", events[1].ID)
+	}
+
+	// Upserting into an empty entity type creates its file
+	created, err = pkg.UpsertEntity(TypeMatch, Envelope[Match]{ID: GenerateID(TypeMatch), Type: TypeMatch})
+	if err != nil || !created || pkg.Manifest.Entities[TypeMatch].Count != 1 {
+		t.Errorf("Upsert into new type = %v, %v", created, err)
+	}
+
+	if _, err := pkg.UpsertEntity(TypeEvent, Envelope[Event]{}); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID for envelope without ID, got %v", err)
+	}
+}
+
+func TestPackage_ReplaceEntity_Opened(t *testing.T) {
+	pkg, ids, dir := openEditTestArchive(t, 1)
+	replacement := Envelope[Event]{
+		ID:   ids[0],
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Renamed"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+	}
+
+	if err := pkg.ReplaceEntity(TypeEvent, replacement); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("ReplaceEntity() on an opened package error = %v, want ErrInvalidPackage", err)
+	}
+	if err := pkg.ReplaceEntityWithVersion(TypeEvent, replacement, 1); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("ReplaceEntityWithVersion() on an opened package error = %v, want ErrInvalidPackage", err)
+	}
+	assertEmptyDir(t, dir)
+}
+
+func TestPackage_UpsertEntity_Opened(t *testing.T) {
+	pkg, _, dir := openEditTestArchive(t, 1)
+	envelope := Envelope[Event]{
+		ID:   GenerateID(TypeEvent),
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "New"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+	}
+
+	created, err := pkg.UpsertEntity(TypeEvent, envelope)
+	if !errors.Is(err, ErrInvalidPackage) || created {
+		t.Errorf("UpsertEntity() on an opened package = %v, %v; want ErrInvalidPackage", created, err)
+	}
+	if count := pkg.Manifest.Entities[TypeEvent].Count; count != 1 {
+		t.Errorf("event count = %d, want 1", count)
+	}
+	assertEmptyDir(t, dir)
+}
@@ -0,0 +1,276 @@
+package ptd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// An encrypted archive is a header followed by the ZIP archive sealed with
+// AES-256-GCM in chunks, so that neither side holds the whole archive in
+// memory. The header is
+//
+//	magic "PTDENC" | version | KDF | scrypt log2(N) | salt (16) | nonce prefix (7)
+//
+// and is authenticated as additional data of every chunk. A chunk's nonce
+// is the prefix, its 32-bit big-endian index and a byte marking the last
+// chunk, which detects reordered, dropped and truncated chunks.
+const (
+	encryptionMagic     = "PTDENC"
+	encryptionVersion   = 1
+	encryptedHeaderSize = 32
+	encryptedChunkSize  = 64 << 10
+)
+
+// Key derivation functions of encrypted archives
+const (
+	kdfKey    byte = 0 // EncryptionKey.Key is the AES key
+	kdfScrypt byte = 1 // The AES key is derived from EncryptionKey.Passphrase
+)
+
+// scryptLogN is the scrypt cost, log2(N), of newly encrypted archives
+const scryptLogN = 15
+
+// Bounds on the scrypt cost read from the header of an archive, which is
+// not authenticated until the key is derived: a larger cost would let an
+// archive make readers allocate gigabytes or run for hours
+const (
+	minScryptLogN = 10
+	maxScryptLogN = 20
+)
+
+// EncryptionKey is the secret that encrypts and decrypts a package: either
+// a passphrase, from which the AES-256 key is derived with scrypt, or a
+// 32-byte AES-256 key shared with the recipients
+type EncryptionKey struct {
+	Passphrase string // Used when non-empty
+	Key        []byte // 32-byte key, used when Passphrase is empty
+}
+
+// cipher returns the AEAD that seals the chunks of an archive with header
+func (k EncryptionKey) cipher(header []byte) (cipher.AEAD, error) {
+	key := k.Key
+	switch header[7] {
+	case kdfScrypt:
+		if k.Passphrase == "" {
+			return nil, fmt.Errorf("%w: package is encrypted with a passphrase", ErrDecryption)
+		}
+		if logN := header[8]; logN < minScryptLogN || logN > maxScryptLogN {
+			return nil, fmt.Errorf("%w: scrypt cost 2^%d outside 2^%d to 2^%d", ErrUnsupportedVersion, logN, minScryptLogN, maxScryptLogN)
+		}
+		derived, err := scrypt.Key([]byte(k.Passphrase), header[9:25], 1<<header[8], 8, 1, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrDecryption, err)
+		}
+		key = derived
+	case kdfKey:
+		if k.Passphrase != "" {
+			return nil, fmt.Errorf("%w: package is encrypted with a key, not a passphrase", ErrDecryption)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown key derivation %d", ErrUnsupportedVersion, header[7])
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: encryption key must be 32 bytes, got %d", ErrValidation, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk at index
+func chunkNonce(header []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, header[25:32])
+	binary.BigEndian.PutUint32(nonce[7:11], index)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// CreateEncryptedArchive creates a ZIP archive of the package like
+// CreateArchive, encrypted with key. The archive is readable only by
+// OpenPackageWithOptions or PackageFromReaderWithOptions given the same key.
+func (p *Package) CreateEncryptedArchive(outputPath string, key EncryptionKey) error {
+	archive, err := os.OpenFile(outputPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if err := p.CreateEncryptedArchiveTo(archive, key); err != nil {
+		archive.Close()
+		return err
+	}
+	return archive.Close()
+}
+
+// CreateEncryptedArchiveTo writes the package as an encrypted ZIP archive to w
+func (p *Package) CreateEncryptedArchiveTo(w io.Writer, key EncryptionKey) error {
+	encrypted, err := newEncryptingWriter(w, key)
+	if err != nil {
+		return err
+	}
+	if err := p.writeArchive(encrypted, nil); err != nil {
+		return err
+	}
+	if err := encrypted.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// encryptingWriter seals what is written to it in chunks. A full chunk is
+// only sealed once more data follows, so Close can mark the last one.
+type encryptingWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	index  uint32
+	buf    []byte
+}
+
+// newEncryptingWriter writes the header of an archive encrypted with key to w
+func newEncryptingWriter(w io.Writer, key EncryptionKey) (*encryptingWriter, error) {
+	header := make([]byte, encryptedHeaderSize)
+	copy(header, encryptionMagic)
+	header[6] = encryptionVersion
+	if key.Passphrase != "" {
+		header[7] = kdfScrypt
+		header[8] = scryptLogN
+	}
+	if _, err := rand.Read(header[9:]); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	aead, err := key.cipher(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return &encryptingWriter{w: w, aead: aead, header: header, buf: make([]byte, 0, encryptedChunkSize)}, nil
+}
+
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if len(e.buf) == encryptedChunkSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+		n := min(encryptedChunkSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close seals the last chunk; it does not close the underlying writer
+func (e *encryptingWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptingWriter) seal(last bool) error {
+	if e.index == math.MaxUint32 {
+		return fmt.Errorf("%w: archive too large to encrypt", ErrExportFailed)
+	}
+	sealed := e.aead.Seal(nil, chunkNonce(e.header, e.index, last), e.buf, e.header)
+	e.index++
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+// decryptingReader opens the chunks of an encrypted archive in turn
+type decryptingReader struct {
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	index  uint32
+	chunk  []byte
+	plain  []byte // Opened bytes not yet read
+	done   bool
+}
+
+// newDecryptingReader reads the header of an archive encrypted with key from r
+func newDecryptingReader(r io.Reader, key EncryptionKey) (*decryptingReader, error) {
+	header := make([]byte, encryptedHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	if !bytes.HasPrefix(header, []byte(encryptionMagic)) {
+		return nil, fmt.Errorf("%w: package is not encrypted", ErrInvalidPackage)
+	}
+	if header[6] != encryptionVersion {
+		return nil, fmt.Errorf("%w: encryption version %d", ErrUnsupportedVersion, header[6])
+	}
+
+	aead, err := key.cipher(header)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingReader{
+		r:      bufio.NewReaderSize(r, encryptedChunkSize+aead.Overhead()),
+		aead:   aead,
+		header: header,
+		chunk:  make([]byte, encryptedChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+// open reads and opens the next chunk
+func (d *decryptingReader) open() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := d.r.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := d.aead.Open(d.chunk[:0], chunkNonce(d.header, d.index, last), d.chunk[:n], d.header)
+	if err != nil {
+		return fmt.Errorf("%w: wrong key, or chunk %d is corrupt or missing", ErrDecryption, d.index)
+	}
+	d.plain = plain
+	d.index++
+	d.done = last
+	return nil
+}
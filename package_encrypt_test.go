@@ -0,0 +1,161 @@
+package ptd
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptingWriter_RoundTrip(t *testing.T) {
+	key := EncryptionKey{Key: bytes.Repeat([]byte{7}, 32)}
+
+	for _, size := range []int{0, 1, encryptedChunkSize - 1, encryptedChunkSize, encryptedChunkSize + 1, 2 * encryptedChunkSize} {
+		plain := make([]byte, size)
+		rand.Read(plain)
+
+		var sealed bytes.Buffer
+		w, err := newEncryptingWriter(&sealed, key)
+		if err != nil {
+			t.Fatalf("newEncryptingWriter() error = %v", err)
+		}
+		w.Write(plain)
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+
+		r, err := newDecryptingReader(bytes.NewReader(sealed.Bytes()), key)
+		if err != nil {
+			t.Fatalf("newDecryptingReader() error = %v", err)
+		}
+		opened, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%d bytes: ReadAll() error = %v", size, err)
+		}
+		if !bytes.Equal(opened, plain) {
+			t.Errorf("%d bytes: decrypted %d bytes that differ from the original", size, len(opened))
+		}
+
+		// Dropping the last chunk is detected
+		if size > encryptedChunkSize {
+			truncated := sealed.Bytes()[:encryptedHeaderSize+encryptedChunkSize+16]
+			r, _ := newDecryptingReader(bytes.NewReader(truncated), key)
+			if _, err := io.ReadAll(r); !errors.Is(err, ErrDecryption) {
+				t.Errorf("%d bytes: truncated ReadAll() error = %v, want ErrDecryption", size, err)
+			}
+		}
+	}
+}
+
+func TestPackage_CreateEncryptedArchive(t *testing.T) {
+	pkg, _ := newCompressTestArchive(t, 200)
+	// Store files uncompressed so the archive spans several chunks
+	if err := pkg.SetCompression(CompressionDeflate, flate.NoCompression); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := map[string]EncryptionKey{
+		"passphrase": {Passphrase: "correct horse battery staple"},
+		"key":        {Key: bytes.Repeat([]byte{1}, 32)},
+	}
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "encrypted.ptd")
+			if err := pkg.CreateEncryptedArchive(archivePath, key); err != nil {
+				t.Fatalf("CreateEncryptedArchive() error = %v", err)
+			}
+			data, err := os.ReadFile(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) < 2*encryptedChunkSize {
+				t.Fatalf("Archive is %d bytes, want several chunks", len(data))
+			}
+			if bytes.Contains(data, []byte("Player 1")) {
+				t.Error("Archive contains plain text")
+			}
+			if info, _ := os.Stat(archivePath); info.Mode().Perm() != 0600 {
+				t.Errorf("Archive mode = %v, want 0600", info.Mode().Perm())
+			}
+
+			for _, maxInMemory := range []int64{0, -1} {
+				opened, err := OpenPackageWithOptions(archivePath, OpenOptions{DecryptionKey: &key, MaxInMemoryBytes: maxInMemory})
+				if err != nil {
+					t.Fatalf("OpenPackageWithOptions() error = %v", err)
+				}
+				matches, err := ExtractEntities[Match](opened, TypeMatch)
+				if err != nil || len(matches) != 200 {
+					t.Errorf("ExtractEntities() = %d matches, %v; want 200", len(matches), err)
+				}
+				opened.Cleanup()
+			}
+
+			if _, err := OpenPackage(archivePath); !errors.Is(err, ErrEncrypted) {
+				t.Errorf("OpenPackage() error = %v, want ErrEncrypted", err)
+			}
+			if _, err := PackageFromReader(bytes.NewReader(data)); !errors.Is(err, ErrEncrypted) {
+				t.Errorf("PackageFromReader() error = %v, want ErrEncrypted", err)
+			}
+
+			// A flipped bit anywhere fails authentication
+			tampered := bytes.Clone(data)
+			tampered[len(tampered)/2] ^= 1
+			if _, err := PackageFromReaderWithOptions(bytes.NewReader(tampered), OpenOptions{DecryptionKey: &key}); !errors.Is(err, ErrDecryption) {
+				t.Errorf("Tampered archive error = %v, want ErrDecryption", err)
+			}
+		})
+	}
+}
+
+func TestOpenPackageWithOptions_WrongKey(t *testing.T) {
+	pkg, plainPath := newCompressTestArchive(t, 1)
+	archivePath := filepath.Join(t.TempDir(), "encrypted.ptd")
+	if err := pkg.CreateEncryptedArchive(archivePath, EncryptionKey{Passphrase: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	wrong := []EncryptionKey{
+		{Passphrase: "not the secret"},
+		{Key: bytes.Repeat([]byte{1}, 32)},
+	}
+	for _, key := range wrong {
+		if _, err := OpenPackageWithOptions(archivePath, OpenOptions{DecryptionKey: &key}); !errors.Is(err, ErrDecryption) {
+			t.Errorf("OpenPackageWithOptions(%+v) error = %v, want ErrDecryption", key, err)
+		}
+	}
+
+	if err := pkg.CreateEncryptedArchive(archivePath, EncryptionKey{Key: []byte("short")}); !errors.Is(err, ErrValidation) {
+		t.Errorf("CreateEncryptedArchive() with a short key error = %v, want ErrValidation", err)
+	}
+
+	// A key does not stop unencrypted archives from opening
+	key := EncryptionKey{Passphrase: "secret"}
+	opened, err := OpenPackageWithOptions(plainPath, OpenOptions{DecryptionKey: &key})
+	if err != nil {
+		t.Fatalf("OpenPackageWithOptions() of a plain archive error = %v", err)
+	}
+	opened.Cleanup()
+}
+
+func TestOpenPackageWithOptions_ScryptCost(t *testing.T) {
+	pkg, _ := newCompressTestArchive(t, 1)
+	var encrypted bytes.Buffer
+	if err := pkg.CreateEncryptedArchiveTo(&encrypted, EncryptionKey{Passphrase: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cost is read from the unauthenticated header, so it is bounded
+	// before any key is derived
+	key := EncryptionKey{Passphrase: "secret"}
+	for _, logN := range []byte{0, 9, 21, 40, 255} {
+		data := bytes.Clone(encrypted.Bytes())
+		data[8] = logN
+		if _, err := PackageFromReaderWithOptions(bytes.NewReader(data), OpenOptions{DecryptionKey: &key}); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("scrypt cost 2^%d: error = %v, want ErrUnsupportedVersion", logN, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package ptd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// archiveSource is the archive an opened package reads its files from
+type archiveSource struct {
+	path      string        // Archive file
+	readerAt  io.ReaderAt   // Archive contents, when path is empty
+	size      int64         // Size of the contents of readerAt
+	format    ArchiveFormat // Container format of the archive
+	temporary bool          // path is a spooled copy that Cleanup removes
+
+	deferred bool            // File hashes are checked as files are read
+	mu       sync.Mutex      // Guards verified
+	verified map[string]bool // Files whose hashes have been checked
+
+	index       map[string]indexEntry // Entity index, once loaded
+	indexLoaded bool                  // The index has been looked for
+}
+
+// Entities streams the envelopes of an entity type, decoding one NDJSON
+// line at a time. For an opened package the lines are read straight from
+// the archive, without extracting it. Specs are left undecoded; use
+// ExtractEntities or json.Unmarshal on Spec for typed access.
+//
+// A line that cannot be decoded yields an error wrapping ErrInvalidFormat
+// and iteration continues with the next line; a failure to read the
+// package yields a final error. An entity type the package does not hold
+// yields nothing.
+//
+//	for envelope, err := range pkg.Entities(ptd.TypeMatch) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (p *Package) Entities(entityType string) iter.Seq2[Envelope[json.RawMessage], error] {
+	return func(yield func(Envelope[json.RawMessage], error) bool) {
+		err := p.scanEntityLines(entityType, func(n int, line []byte) bool {
+			var envelope Envelope[json.RawMessage]
+			if err := json.Unmarshal(line, &envelope); err != nil {
+				return yield(Envelope[json.RawMessage]{}, fmt.Errorf("%w: %s line %d: %v", ErrInvalidFormat, entityFilePath(entityType), n, err))
+			}
+			return yield(envelope, nil)
+		})
+		if err != nil {
+			yield(Envelope[json.RawMessage]{}, err)
+		}
+	}
+}
+
+// ExtractEntities decodes every envelope of an entity type into
+// Envelope[T], reading an opened package straight from its archive:
+//
+//	matches, err := ptd.ExtractEntities[ptd.Match](pkg, ptd.TypeMatch)
+//
+// Unlike Entities, a line that cannot be decoded into T stops extraction
+// with an error wrapping ErrInvalidFormat. An entity type the package does
+// not hold yields no envelopes.
+func ExtractEntities[T any](pkg *Package, entityType string) ([]Envelope[T], error) {
+	var envelopes []Envelope[T]
+	var decodeErr error
+	err := pkg.scanEntityLines(entityType, func(n int, line []byte) bool {
+		var envelope Envelope[T]
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			decodeErr = fmt.Errorf("%w: %s line %d: %v", ErrInvalidFormat, entityFilePath(entityType), n, err)
+			return false
+		}
+		envelopes = append(envelopes, envelope)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return envelopes, nil
+}
+
+// scanEntityLines calls fn with each non-empty NDJSON line stored for an
+// entity type and its 1-based line number, until fn returns false. The
+// line is only valid until fn returns. A missing file has no lines; an
+// invalid type name, as from a hostile manifest, returns ErrInvalidType.
+func (p *Package) scanEntityLines(entityType string, fn func(n int, line []byte) bool) error {
+	if err := checkEntityType(entityType); err != nil {
+		return err
+	}
+	file, err := p.openPackageFile(entityFilePath(entityType))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s entities: %w", entityType, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for n := 1; ; n++ {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			if !fn(n, trimmed) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s entities: %w", entityType, err)
+		}
+	}
+}
+
+// openPackageFile opens a file of the package by its package-relative path,
+// from the working directory or, for an opened package, the archive.
+// Returns an error wrapping os.ErrNotExist if the package has no such file.
+func (p *Package) openPackageFile(relPath string) (io.ReadCloser, error) {
+	if p.tempDir != "" {
+		return os.Open(filepath.Join(p.tempDir, relPath))
+	}
+	if p.archive == nil {
+		return nil, fmt.Errorf("%s: %w", relPath, os.ErrNotExist)
+	}
+
+	file, err := p.archive.openFile(relPath)
+	if err != nil || !p.archive.needsVerification(relPath) {
+		return file, err
+	}
+	return p.verifyingReader(relPath, file), nil
+}
@@ -0,0 +1,144 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newEntitiesTestArchive writes an archive holding n events and returns its
+// path and the event IDs
+func newEntitiesTestArchive(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	pkg, ids := newEditTestPackage(t, n)
+	archivePath := filepath.Join(t.TempDir(), "entities.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	return archivePath, ids
+}
+
+func TestPackage_Entities(t *testing.T) {
+	archivePath, wantIDs := newEntitiesTestArchive(t, 3)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	inMemory, err := PackageFromReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PackageFromReader() error = %v", err)
+	}
+	spooled, err := PackageFromReaderWithOptions(bytes.NewReader(data), OpenOptions{MaxInMemoryBytes: -1})
+	if err != nil {
+		t.Fatalf("PackageFromReaderWithOptions() error = %v", err)
+	}
+
+	for name, pkg := range map[string]*Package{"file": opened, "memory": inMemory, "spooled": spooled} {
+		t.Run(name, func(t *testing.T) {
+			var ids []string
+			for envelope, err := range pkg.Entities(TypeEvent) {
+				if err != nil {
+					t.Fatalf("Entities() error = %v", err)
+				}
+				var event Event
+				if err := json.Unmarshal(envelope.Spec, &event); err != nil || event.Name.Default != "Event" {
+					t.Errorf("Spec did not decode into an event: %v", err)
+				}
+				ids = append(ids, envelope.ID)
+			}
+			if !reflect.DeepEqual(ids, wantIDs) {
+				t.Errorf("Entities() yielded %v, want %v", ids, wantIDs)
+			}
+
+			// Breaking out of the loop stops the stream
+			for envelope := range pkg.Entities(TypeEvent) {
+				if envelope.ID != wantIDs[0] {
+					t.Errorf("First envelope = %s, want %s", envelope.ID, wantIDs[0])
+				}
+				break
+			}
+
+			for range pkg.Entities(TypeMatch) {
+				t.Error("Entities() of a type the package does not hold yielded an envelope")
+			}
+
+			// Typed helpers read from the archive too
+			events, err := decodeEntityLines[Event](pkg, TypeEvent)
+			if err != nil || len(events) != 3 {
+				t.Errorf("decodeEntityLines() = %d events, %v", len(events), err)
+			}
+		})
+	}
+
+	// Cleanup removes the spooled copy of the archive
+	path := spooled.archive.path
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Spooled archive missing before Cleanup: %v", err)
+	}
+	spooled.Cleanup()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Spooled archive still exists after Cleanup: %v", err)
+	}
+}
+
+func TestPackage_Entities_InvalidLine(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 2)
+	lines, err := pkg.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = append(lines[:1], json.RawMessage("{not json"), lines[1])
+	if err := pkg.writeEntityLines(TypeEvent, lines); err != nil {
+		t.Fatal(err)
+	}
+
+	var valid, invalid int
+	for _, err := range pkg.Entities(TypeEvent) {
+		switch {
+		case errors.Is(err, ErrInvalidFormat):
+			invalid++
+		case err != nil:
+			t.Fatalf("Entities() error = %v", err)
+		default:
+			valid++
+		}
+	}
+	if valid != 2 || invalid != 1 {
+		t.Errorf("Entities() yielded %d envelopes and %d errors, want 2 and 1", valid, invalid)
+	}
+}
+
+func TestExtractEntities(t *testing.T) {
+	archivePath, wantIDs := newEntitiesTestArchive(t, 2)
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+
+	events, err := ExtractEntities[Event](opened, TypeEvent)
+	if err != nil {
+		t.Fatalf("ExtractEntities() error = %v", err)
+	}
+	if len(events) != 2 || events[0].ID != wantIDs[0] || events[1].Spec.Name.Default != "Event" || events[1].Meta.Version != 1 {
+		t.Errorf("ExtractEntities() = %+v", events)
+	}
+
+	matches, err := ExtractEntities[Match](opened, TypeMatch)
+	if err != nil || len(matches) != 0 {
+		t.Errorf("ExtractEntities(missing type) = %v, %v; want no envelopes", matches, err)
+	}
+
+	// Lines that do not decode into T are an error
+	if _, err := ExtractEntities[[]string](opened, TypeEvent); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ExtractEntities() into an incompatible type error = %v, want ErrInvalidFormat", err)
+	}
+}
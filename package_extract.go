@@ -0,0 +1,108 @@
+package ptd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ExtractTo unpacks the files of the package into dir, created if needed,
+// keeping their paths within the package: entity files under their type,
+// attachments, and manifest.json. Existing files are overwritten.
+//
+// Files of an opened package are checked against the hashes in its
+// manifest as they are written, whatever its verification mode; a file
+// that differs is removed again and an error wrapping ErrHashMismatch
+// returned. A package built in a working directory has its files copied as
+// they are, since their hashes are only recorded when it is archived.
+// Files extracted before a failure are left in dir.
+func (p *Package) ExtractTo(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if p.archive == nil {
+		return p.copyWorkingDir(dir)
+	}
+
+	r, size, closer, err := p.archive.contents()
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	return forEachArchiveFile(r, size, p.archive.format, func(name string, file io.Reader) error {
+		if name == "manifest.json" {
+			_, err := extractFile(dir, name, file)
+			return err
+		}
+		entry, ok := p.Manifest.Files[name]
+		if !ok {
+			return fmt.Errorf("%w: unexpected file in package: %s", ErrInvalidPackage, name)
+		}
+
+		hash, err := extractFile(dir, name, file)
+		if err != nil {
+			return err
+		}
+		if hash != entry.Hash {
+			os.Remove(filepath.Join(dir, filepath.FromSlash(name)))
+			return fmt.Errorf("%w for file %s", ErrHashMismatch, name)
+		}
+		p.archive.markVerified(name)
+		return nil
+	})
+}
+
+// copyWorkingDir copies the files of the package's working directory into dir
+func (p *Package) copyWorkingDir(dir string) error {
+	if p.tempDir == "" {
+		return fmt.Errorf("%w: package has no files", ErrInvalidPackage)
+	}
+	return filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(p.tempDir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = extractFile(dir, filepath.ToSlash(relPath), file)
+		return err
+	})
+}
+
+// extractFile writes the package file of the given slash-separated name
+// into dir, refusing names that would escape it, and returns the
+// hex-encoded SHA-256 hash of what was written
+func extractFile(dir, name string, r io.Reader) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("%w: file %s is outside the package", ErrInvalidPackage, name)
+	}
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, hasher), r); err != nil {
+		out.Close()
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	if err := out.Close(); err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", name, err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
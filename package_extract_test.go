@@ -0,0 +1,91 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackage_ExtractTo(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 2)
+	if err := pkg.AddAttachment("draws/main.txt", strings.NewReader("draw"), ""); err != nil {
+		t.Fatal(err)
+	}
+	events, err := os.ReadFile(filepath.Join(pkg.tempDir, entityFilePath(TypeEvent)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"extract.ptd", "extract.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), name)
+			if err := pkg.CreateArchive(archivePath); err != nil {
+				t.Fatal(err)
+			}
+			opened, err := OpenPackageWithOptions(archivePath, OpenOptions{Verification: VerifyDeferred})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer opened.Cleanup()
+
+			dir := filepath.Join(t.TempDir(), "out")
+			if err := opened.ExtractTo(dir); err != nil {
+				t.Fatalf("ExtractTo() error = %v", err)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dir, entityFilePath(TypeEvent))); !bytes.Equal(got, events) {
+				t.Errorf("extracted events = %q, want %q", got, events)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dir, attachmentsDir, "draws", "main.txt")); string(got) != "draw" {
+				t.Errorf("extracted attachment = %q, want draw", got)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "manifest.json")); err != nil {
+				t.Errorf("manifest.json not extracted: %v", err)
+			}
+			if opened.archive.needsVerification(entityFilePath(TypeEvent)) {
+				t.Error("extracted file not marked verified")
+			}
+		})
+	}
+
+	// A package still being built is copied from its working directory
+	dir := t.TempDir()
+	if err := pkg.ExtractTo(dir); err != nil {
+		t.Fatalf("ExtractTo() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, entityFilePath(TypeEvent))); !bytes.Equal(got, events) {
+		t.Errorf("copied events = %q, want %q", got, events)
+	}
+}
+
+func TestPackage_ExtractTo_HashMismatch(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 2)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventsPath := filepath.ToSlash(entityFilePath(TypeEvent))
+	tampered := rewriteZip(t, data, func(name string, data []byte) []byte {
+		if name == eventsPath {
+			return bytes.Replace(data, []byte("Event"), []byte("Evil!"), 1)
+		}
+		return data
+	})
+	os.WriteFile(archivePath, tampered, 0644)
+
+	opened, err := OpenPackageWithOptions(archivePath, OpenOptions{Verification: VerifyDeferred})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Cleanup()
+
+	dir := t.TempDir()
+	if err := opened.ExtractTo(dir); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("ExtractTo() error = %v, want ErrHashMismatch", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, entityFilePath(TypeEvent))); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("tampered file left in place: %v", err)
+	}
+}
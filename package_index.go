@@ -0,0 +1,290 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// entityIndexPath is the package-relative path of the entity index, an
+// NDJSON file with one indexEntry per entity
+const entityIndexPath = "index.ndjson"
+
+// indexEntry locates the NDJSON line of an entity within its file
+type indexEntry struct {
+	ID     string `json:"id"`
+	File   string `json:"file"`   // Package-relative path, with forward slashes
+	Offset int64  `json:"offset"` // Byte offset of the line
+	Length int64  `json:"length"` // Length of the line, without its newline
+}
+
+// SetEntityIndex selects whether archives created from the package carry
+// an index of entity IDs, so that GetEntity on the opened package reads
+// only the requested record. The entity files of an indexed ZIP archive
+// are stored uncompressed, trading archive size for lookups that read the
+// record in place.
+func (p *Package) SetEntityIndex(enabled bool) {
+	p.indexEntities = enabled
+}
+
+// GetEntity returns the envelope of the entity with the given ID, or an
+// error wrapping ErrInvalidID if the package holds none. A package opened
+// from an indexed archive reads the record directly; otherwise the file of
+// the entity type named by the ID is scanned.
+//
+// A record read through the index is not checked against its file's hash
+// in packages opened with VerifyDeferred; use VerifyAll.
+func (p *Package) GetEntity(id string) (Envelope[json.RawMessage], error) {
+	var envelope Envelope[json.RawMessage]
+
+	index, err := p.loadEntityIndex()
+	if err != nil {
+		return envelope, err
+	}
+	if index != nil {
+		entry, ok := index[id]
+		if !ok {
+			return envelope, fmt.Errorf("%w: entity %s not found", ErrInvalidID, id)
+		}
+		line, err := p.archive.readRange(filepath.FromSlash(entry.File), entry.Offset, entry.Length)
+		if err != nil {
+			return envelope, fmt.Errorf("failed to read entity %s: %w", id, err)
+		}
+		if err := json.Unmarshal(line, &envelope); err != nil || envelope.ID != id {
+			return envelope, fmt.Errorf("%w: index entry for %s does not point at the entity", ErrInvalidFormat, id)
+		}
+		return envelope, nil
+	}
+
+	_, entityType, _, err := ParseID(id)
+	if err != nil {
+		return envelope, err
+	}
+	found := false
+	var decodeErr error
+	err = p.scanEntityLines(entityType, func(n int, line []byte) bool {
+		var header struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(line, &header) != nil || header.ID != id {
+			return true
+		}
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			decodeErr = fmt.Errorf("%w: %s line %d: %v", ErrInvalidFormat, entityFilePath(entityType), n, err)
+		}
+		found = true
+		return false
+	})
+	switch {
+	case err != nil:
+		return envelope, err
+	case decodeErr != nil:
+		return envelope, decodeErr
+	case !found:
+		return envelope, fmt.Errorf("%w: entity %s not found", ErrInvalidID, id)
+	}
+	return envelope, nil
+}
+
+// loadEntityIndex returns the entity index of an opened package, reading
+// it on first use, or nil if the package has none. The index of a working
+// directory would go stale as entities change, so it is never used.
+func (p *Package) loadEntityIndex() (map[string]indexEntry, error) {
+	if p.archive == nil {
+		return nil, nil
+	}
+	p.archive.mu.Lock()
+	if p.archive.indexLoaded {
+		defer p.archive.mu.Unlock()
+		return p.archive.index, nil
+	}
+	p.archive.mu.Unlock()
+
+	file, err := p.openPackageFile(entityIndexPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read entity index: %w", err)
+	}
+	var index map[string]indexEntry
+	if err == nil {
+		defer file.Close()
+		index = make(map[string]indexEntry)
+		decoder := json.NewDecoder(file)
+		for {
+			var entry indexEntry
+			if err := decoder.Decode(&entry); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%w: entity index: %v", ErrInvalidFormat, err)
+			}
+			index[entry.ID] = entry
+		}
+	}
+
+	p.archive.mu.Lock()
+	defer p.archive.mu.Unlock()
+	p.archive.index, p.archive.indexLoaded = index, true
+	return index, nil
+}
+
+// indexedFiles returns the package-relative paths of the entity files
+func (p *Package) indexedFiles() map[string]bool {
+	files := make(map[string]bool, len(p.Manifest.Entities))
+	for entityType := range p.Manifest.Entities {
+		files[entityFilePath(entityType)] = true
+	}
+	return files
+}
+
+// writeEntityIndex writes the entity index to the working directory if
+// indexing is enabled, and otherwise removes any index left from before
+func (p *Package) writeEntityIndex() error {
+	indexPath := filepath.Join(p.tempDir, entityIndexPath)
+	if !p.indexEntities {
+		if err := os.Remove(indexPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove entity index: %w", err)
+		}
+		delete(p.Manifest.Files, entityIndexPath)
+		return nil
+	}
+
+	entityTypes := make([]string, 0, len(p.Manifest.Entities))
+	for entityType := range p.Manifest.Entities {
+		entityTypes = append(entityTypes, entityType)
+	}
+	sort.Strings(entityTypes)
+
+	file, err := os.Create(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to create entity index: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entityType := range entityTypes {
+		err := p.scanEntityOffsets(entityType, func(entry indexEntry) error {
+			return encoder.Encode(entry)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to index %s entities: %w", entityType, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write entity index: %w", err)
+	}
+	return file.Close()
+}
+
+// scanEntityOffsets calls fn with the location of each entity in the
+// working directory file of an entity type; lines without an ID are skipped
+func (p *Package) scanEntityOffsets(entityType string, fn func(indexEntry) error) error {
+	relPath := entityFilePath(entityType)
+	file, err := os.Open(filepath.Join(p.tempDir, relPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		start := offset
+		offset += int64(len(line))
+
+		content := bytes.TrimRight(line, "\r\n")
+		var header struct {
+			ID string `json:"id"`
+		}
+		if len(bytes.TrimSpace(content)) > 0 && json.Unmarshal(content, &header) == nil && header.ID != "" {
+			entry := indexEntry{ID: header.ID, File: filepath.ToSlash(relPath), Offset: start, Length: int64(len(content))}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readRange reads length bytes at offset of a file of the archive. A file
+// stored uncompressed in a ZIP archive is read in place; any other is
+// decompressed up to the range. The range comes from the archive's index,
+// so it is checked against the file's size before anything is allocated;
+// a range outside the file returns ErrInvalidFormat.
+func (s *archiveSource) readRange(relPath string, offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 0 {
+		return nil, fmt.Errorf("%w: invalid range %d+%d of %s", ErrInvalidFormat, offset, length, relPath)
+	}
+
+	if s.format == ArchiveZIP || s.format == "" {
+		r, size, closer, err := s.contents()
+		if err != nil {
+			return nil, err
+		}
+		defer closer.Close()
+
+		reader, err := newZipReader(r, size)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.ToSlash(relPath)
+		for _, f := range reader.File {
+			if f.Name != name {
+				continue
+			}
+			fileSize := int64(f.UncompressedSize64)
+			if fileSize < 0 || offset > fileSize || length > fileSize-offset {
+				return nil, fmt.Errorf("%w: range beyond end of %s", ErrInvalidFormat, relPath)
+			}
+			if f.Method != zip.Store {
+				break
+			}
+			dataOffset, err := f.DataOffset()
+			if err != nil {
+				return nil, err
+			}
+			data := make([]byte, length)
+			if _, err := r.ReadAt(data, dataOffset+offset); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return data, nil
+		}
+	}
+
+	file, err := s.openFile(relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := io.CopyN(io.Discard, file, offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: range beyond end of %s", ErrInvalidFormat, relPath)
+		}
+		return nil, err
+	}
+	// Read no more than the file holds, whatever length claims
+	data, err := io.ReadAll(io.LimitReader(file, length))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) < length {
+		return nil, fmt.Errorf("%w: range beyond end of %s", ErrInvalidFormat, relPath)
+	}
+	return data, nil
+}
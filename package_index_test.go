@@ -0,0 +1,145 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackage_GetEntity(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 50)
+	pkg.SetEntityIndex(true)
+
+	zipPath := filepath.Join(t.TempDir(), "indexed.ptd")
+	if err := pkg.CreateArchive(zipPath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	tarPath := filepath.Join(t.TempDir(), "indexed.ptd.tar.gz")
+	if err := pkg.CreateArchive(tarPath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	if _, ok := pkg.Manifest.Files[entityIndexPath]; !ok {
+		t.Error("Manifest does not list the entity index")
+	}
+
+	// Entity files of an indexed ZIP archive are stored for in-place reads
+	reader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range reader.File {
+		if f.Name == entityFilePath(TypeEvent) && f.Method != zip.Store {
+			t.Errorf("%s compression method = %d, want Store", f.Name, f.Method)
+		}
+	}
+	reader.Close()
+
+	for _, archivePath := range []string{zipPath, tarPath} {
+		opened, err := OpenPackage(archivePath)
+		if err != nil {
+			t.Fatalf("OpenPackage() error = %v", err)
+		}
+		for _, id := range []string{ids[0], ids[27], ids[49]} {
+			envelope, err := opened.GetEntity(id)
+			if err != nil {
+				t.Fatalf("%s: GetEntity(%s) error = %v", filepath.Base(archivePath), id, err)
+			}
+			if envelope.ID != id || envelope.Type != TypeEvent {
+				t.Errorf("GetEntity(%s) = %s %s", id, envelope.Type, envelope.ID)
+			}
+		}
+		if opened.archive.index == nil {
+			t.Errorf("%s: GetEntity() did not use the index", filepath.Base(archivePath))
+		}
+		if _, err := opened.GetEntity("ptd:event:missing"); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("GetEntity() of a missing entity error = %v, want ErrInvalidID", err)
+		}
+	}
+}
+
+func TestPackage_GetEntity_WithoutIndex(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 3)
+
+	// A working directory is scanned
+	if envelope, err := pkg.GetEntity(ids[1]); err != nil || envelope.ID != ids[1] {
+		t.Errorf("GetEntity() = %s, %v; want %s", envelope.ID, err, ids[1])
+	}
+
+	// Disabling the index removes one written before
+	pkg.SetEntityIndex(true)
+	archivePath := filepath.Join(t.TempDir(), "plain.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+	pkg.SetEntityIndex(false)
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if _, ok := opened.Manifest.Files[entityIndexPath]; ok {
+		t.Error("Archive without index lists an entity index")
+	}
+	if envelope, err := opened.GetEntity(ids[2]); err != nil || envelope.ID != ids[2] {
+		t.Errorf("GetEntity() = %s, %v; want %s", envelope.ID, err, ids[2])
+	}
+	if _, err := opened.GetEntity("ptd:match:missing"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("GetEntity() of a missing entity error = %v, want ErrInvalidID", err)
+	}
+	if _, err := opened.GetEntity("not-an-id"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("GetEntity() of a malformed ID error = %v, want ErrInvalidID", err)
+	}
+}
+
+func TestPackage_GetEntity_TamperedIndex(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 4)
+	pkg.SetEntityIndex(true)
+	archivePath := filepath.Join(t.TempDir(), "tampered.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	// Rewrite the index with ranges outside the entity file, and the
+	// manifest with the index's new hash so that the archive still opens
+	ranges := map[string][2]int64{
+		ids[0]: {0, -1},
+		ids[1]: {0, 1 << 50},
+		ids[2]: {-5, 10},
+		ids[3]: {1 << 62, 1 << 62},
+	}
+	rewriteArchiveFiles(t, archivePath, func(files map[string][]byte) {
+		var index bytes.Buffer
+		for id, r := range ranges {
+			line, _ := json.Marshal(indexEntry{ID: id, File: entityFilePath(TypeEvent), Offset: r[0], Length: r[1]})
+			index.Write(append(line, '\n'))
+		}
+		files[entityIndexPath] = index.Bytes()
+
+		var manifest Manifest
+		if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(index.Bytes())
+		manifest.Files[entityIndexPath].Hash = hex.EncodeToString(sum[:])
+		manifest.Files[entityIndexPath].Size = int64(index.Len())
+		files["manifest.json"], _ = json.Marshal(&manifest)
+	})
+
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	for id, r := range ranges {
+		if _, err := opened.GetEntity(id); !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("GetEntity() with range %d+%d error = %v, want ErrInvalidFormat", r[0], r[1], err)
+		}
+	}
+}
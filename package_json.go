@@ -0,0 +1,147 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// packageDocument is the single-document JSON form of a package
+type packageDocument struct {
+	Manifest *Manifest                    `json:"manifest"`
+	Entities map[string][]json.RawMessage `json:"entities"`
+}
+
+// ToJSON serializes the whole package (manifest and all entity data) into a
+// single JSON document:
+//
+//	{ "manifest": {...}, "entities": { "tournament": [...], "match": [...] } }
+//
+// This bypasses the ZIP format for in-memory transport, e.g. in environments
+// without a writable filesystem.
+func (p *Package) ToJSON() ([]byte, error) {
+	if p.Manifest == nil {
+		return nil, ErrManifestMissing
+	}
+
+	manifest := *p.Manifest
+	manifest.PackageID = p.ID
+	doc := packageDocument{
+		Manifest: &manifest,
+		Entities: make(map[string][]json.RawMessage, len(p.Manifest.Entities)),
+	}
+
+	for _, entityType := range p.entityTypes() {
+		lines, err := p.readEntityLines(entityType)
+		if err != nil {
+			return nil, err
+		}
+		if lines == nil {
+			lines = []json.RawMessage{}
+		}
+		doc.Entities[entityType] = lines
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal package: %w", err)
+	}
+
+	return data, nil
+}
+
+// PackageFromJSON creates a package from a document produced by ToJSON.
+// The returned package has its own working directory; call Cleanup when done.
+func PackageFromJSON(data []byte) (*Package, error) {
+	var doc packageDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+
+	if doc.Manifest == nil {
+		return nil, ErrManifestMissing
+	}
+	if err := doc.Manifest.checkFormatVersion(); err != nil {
+		return nil, err
+	}
+
+	pkg := NewPackage(doc.Manifest.Description)
+	pkg.ID = doc.Manifest.packageID()
+	pkg.Created = doc.Manifest.Created
+	pkg.Version = doc.Manifest.Version
+	pkg.Manifest = doc.Manifest
+	if pkg.Manifest.Files == nil {
+		pkg.Manifest.Files = make(map[string]*FileEntry)
+	}
+	if pkg.Manifest.Entities == nil {
+		pkg.Manifest.Entities = make(map[string]EntityCount)
+	}
+
+	for entityType, lines := range doc.Entities {
+		entities := make([]interface{}, len(lines))
+		for i, line := range lines {
+			entities[i] = line
+		}
+
+		if err := pkg.AddEntities(entityType, entities); err != nil {
+			pkg.Cleanup()
+			return nil, err
+		}
+	}
+
+	return pkg, nil
+}
+
+// FormatPackageForDebug writes a human-readable rendering of the package to w:
+// the manifest as indented JSON, followed by every entity file with one
+// indented JSON object per entity, separated by "//-- entity N --" comments.
+// The output is not a valid package and is intended for inspection only.
+func FormatPackageForDebug(pkg *Package, w io.Writer) error {
+	if pkg.Manifest == nil {
+		return ErrManifestMissing
+	}
+
+	manifestData, err := json.MarshalIndent(pkg.Manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "// manifest.json\n%s\n", manifestData); err != nil {
+		return err
+	}
+
+	for _, entityType := range pkg.entityTypes() {
+		lines, err := pkg.readEntityLines(entityType)
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "\n// %s (%d entities)\n", filepath.ToSlash(entityFilePath(entityType)), len(lines)); err != nil {
+			return err
+		}
+
+		for i, line := range lines {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, line, "", "  "); err != nil {
+				return fmt.Errorf("%w: %s entity %d: %v", ErrInvalidFormat, entityType, i+1, err)
+			}
+			if _, err := fmt.Fprintf(w, "//-- entity %d --\n%s\n", i+1, buf.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// entityTypes returns the entity types recorded in the manifest, sorted
+func (p *Package) entityTypes() []string {
+	types := make([]string, 0, len(p.Manifest.Entities))
+	for entityType := range p.Manifest.Entities {
+		types = append(types, entityType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,169 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackage_ToJSON_RoundTrip(t *testing.T) {
+	pkg := NewPackage("JSON round trip")
+	defer pkg.Cleanup()
+
+	tournamentID := GenerateID(TypeTournament)
+	tournaments := []interface{}{
+		Envelope[Tournament]{
+			ID:   tournamentID,
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "Tournament"}},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
+		},
+	}
+	matches := []interface{}{
+		Envelope[Match]{
+			ID:   GenerateID(TypeMatch),
+			Type: TypeMatch,
+			Spec: Match{EventID: GenerateID(TypeEvent), MatchNumber: "M1"},
+			Meta: Meta{Schema: "ptd.v1.match@1.0.0"},
+		},
+		Envelope[Match]{
+			ID:   GenerateID(TypeMatch),
+			Type: TypeMatch,
+			Spec: Match{EventID: GenerateID(TypeEvent), MatchNumber: "M2"},
+			Meta: Meta{Schema: "ptd.v1.match@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeTournament, tournaments); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	if err := pkg.AddEntities(TypeMatch, matches); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	data, err := pkg.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("ToJSON output is not valid JSON: %v", err)
+	}
+	if _, ok := doc["manifest"]; !ok {
+		t.Error("Document should have a manifest")
+	}
+	if _, ok := doc["entities"]; !ok {
+		t.Error("Document should have entities")
+	}
+
+	restored, err := PackageFromJSON(data)
+	if err != nil {
+		t.Fatalf("PackageFromJSON failed: %v", err)
+	}
+	defer restored.Cleanup()
+
+	if restored.Manifest.Description != "JSON round trip" {
+		t.Errorf("Description mismatch: got %s", restored.Manifest.Description)
+	}
+	if restored.Manifest.Entities[TypeMatch].Count != 2 {
+		t.Errorf("Expected 2 matches, got %d", restored.Manifest.Entities[TypeMatch].Count)
+	}
+
+	lines, err := restored.readEntityLines(TypeTournament)
+	if err != nil {
+		t.Fatalf("Failed to read tournaments: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 tournament line, got %d", len(lines))
+	}
+
+	var tournament Envelope[Tournament]
+	if err := json.Unmarshal(lines[0], &tournament); err != nil {
+		t.Fatalf("Failed to decode tournament: %v", err)
+	}
+	if tournament.ID != tournamentID {
+		t.Errorf("Tournament ID mismatch: got %s", tournament.ID)
+	}
+
+	// The restored package can be archived and reopened
+	tmpDir, err := os.MkdirTemp("", "ptd-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	archivePath := filepath.Join(tmpDir, "restored.ptd")
+	if err := restored.CreateArchive(archivePath); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	if _, err := OpenPackage(archivePath); err != nil {
+		t.Errorf("Failed to open restored archive: %v", err)
+	}
+}
+
+func TestPackageFromJSON_Invalid(t *testing.T) {
+	if _, err := PackageFromJSON([]byte("not json")); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("Expected ErrInvalidPackage, got %v", err)
+	}
+
+	if _, err := PackageFromJSON([]byte(`{"entities":{}}`)); !errors.Is(err, ErrManifestMissing) {
+		t.Errorf("Expected ErrManifestMissing, got %v", err)
+	}
+}
+
+func TestPackageFromJSON_InvalidEntityType(t *testing.T) {
+	for _, entityType := range []string{"../../escape", "/tmp/escape", "Event", ""} {
+		doc := fmt.Sprintf(`{"manifest":{"version":"1.0.0"},"entities":{%q:[{"id":"ptd:x:1"}]}}`, entityType)
+		if _, err := PackageFromJSON([]byte(doc)); !errors.Is(err, ErrInvalidType) {
+			t.Errorf("PackageFromJSON() with entity type %q error = %v, want ErrInvalidType", entityType, err)
+		}
+	}
+}
+
+func TestFormatPackageForDebug(t *testing.T) {
+	pkg := NewPackage("Debug output")
+	defer pkg.Cleanup()
+
+	events := []interface{}{
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Men's Singles"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Women's Singles"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := FormatPackageForDebug(pkg, &buf); err != nil {
+		t.Fatalf("FormatPackageForDebug failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"// manifest.json",
+		`"description": "Debug output"`,
+		"// event/events.ndjson (2 entities)",
+		"//-- entity 1 --",
+		"//-- entity 2 --",
+		`  "spec": {`,
+		"Women's Singles",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Debug output missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package ptd
+
+import (
+	"os"
+	"sync"
+)
+
+// packageLocks guards the lazy creation of package locks
+var packageLocks sync.Mutex
+
+// packageLock serializes writers of a package: a mutex for goroutines of
+// this process and, where the platform supports it, an advisory lock on a
+// file beside the working directory for other processes
+type packageLock struct {
+	mu   sync.Mutex
+	path string   // lock file; empty for packages without a working directory
+	file *os.File // open and locked while the lock is held
+}
+
+// Lock acquires the package's write lock, blocking until it is available.
+// The lock is advisory: package methods such as AddEntities do not take it
+// themselves, so every writer of a shared package should hold it for the
+// duration of its changes, e.g. around a call to Update. If the lock file
+// cannot be created or locked, only in-process callers are excluded.
+func (p *Package) Lock() {
+	l := p.locker()
+	l.mu.Lock()
+
+	file := l.openFile()
+	if file != nil && lockFile(file) != nil {
+		file.Close()
+		file = nil
+	}
+	l.file = file
+}
+
+// TryLock acquires the package's write lock if it is available and
+// reports whether it did
+func (p *Package) TryLock() bool {
+	l := p.locker()
+	if !l.mu.TryLock() {
+		return false
+	}
+
+	file := l.openFile()
+	if file != nil {
+		locked, err := tryLockFile(file)
+		if err == nil && !locked {
+			file.Close()
+			l.mu.Unlock()
+			return false
+		}
+		if err != nil {
+			file.Close()
+			file = nil
+		}
+	}
+	l.file = file
+	return true
+}
+
+// Unlock releases the package's write lock. It is a run-time error if the
+// lock is not held, as for sync.Mutex.
+func (p *Package) Unlock() {
+	l := p.locker()
+	if l.file != nil {
+		unlockFile(l.file)
+		l.file.Close()
+		l.file = nil
+	}
+	l.mu.Unlock()
+}
+
+// locker returns the package's lock, creating it on first use
+func (p *Package) locker() *packageLock {
+	packageLocks.Lock()
+	defer packageLocks.Unlock()
+
+	if p.lock == nil {
+		p.lock = &packageLock{}
+		if p.tempDir != "" {
+			p.lock.path = lockFilePath(p.tempDir)
+		}
+	}
+	return p.lock
+}
+
+// lockFilePath returns the path of the lock file of a working directory.
+// It lies outside the directory so that it is never archived.
+func lockFilePath(tempDir string) string {
+	return tempDir + ".lock"
+}
+
+// openFile opens the lock file, or returns nil if there is none or it
+// cannot be opened
+func (l *packageLock) openFile() *os.File {
+	if l.path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil
+	}
+	return file
+}
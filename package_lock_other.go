@@ -0,0 +1,18 @@
+//go:build !unix && !windows
+
+package ptd
+
+import (
+	"errors"
+	"os"
+)
+
+// errFileLockUnsupported reports that the platform has no file locks, so
+// packages are only locked within the process
+var errFileLockUnsupported = errors.New("ptd: file locking not supported")
+
+func lockFile(f *os.File) error { return errFileLockUnsupported }
+
+func tryLockFile(f *os.File) (bool, error) { return false, errFileLockUnsupported }
+
+func unlockFile(f *os.File) error { return errFileLockUnsupported }
@@ -0,0 +1,106 @@
+package ptd
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPackage_TryLockRace(t *testing.T) {
+	pkg := NewPackage("Lock test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	var wg sync.WaitGroup
+	var acquired atomic.Int32
+	start := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if pkg.TryLock() {
+				acquired.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := acquired.Load(); got != 1 {
+		t.Fatalf("%d goroutines acquired the lock, want exactly 1", got)
+	}
+	pkg.Unlock()
+
+	if !pkg.TryLock() {
+		t.Fatal("TryLock() failed after Unlock()")
+	}
+	pkg.Unlock()
+}
+
+func TestPackage_LockBlocks(t *testing.T) {
+	pkg := NewPackage("Lock test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	pkg.Lock()
+	locked := make(chan struct{})
+	go func() {
+		pkg.Lock()
+		close(locked)
+		pkg.Unlock()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("Lock() returned while the lock was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	pkg.Unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock() did not return after Unlock()")
+	}
+}
+
+func TestPackage_LockFile(t *testing.T) {
+	pkg := NewPackage("Lock test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	// A second package value on the same working directory stands in for
+	// another process: only the lock file is shared
+	other := &Package{tempDir: pkg.tempDir}
+
+	pkg.Lock()
+	if pkg.lock.file != nil && other.TryLock() {
+		t.Error("TryLock() on the same working directory succeeded while the file was locked")
+		other.Unlock()
+	}
+	pkg.Unlock()
+
+	if !other.TryLock() {
+		t.Fatal("TryLock() failed after the file lock was released")
+	}
+	other.Unlock()
+}
+
+func TestPackage_UpdateKeepsLock(t *testing.T) {
+	pkg := NewPackage("Lock test")
+	t.Cleanup(func() { pkg.Cleanup() })
+
+	pkg.Lock()
+	err := pkg.Update(func(p *Package) error {
+		p.Manifest.Description = "changed"
+		return ErrValidation
+	})
+	if err == nil || pkg.Manifest.Description != "Lock test" {
+		t.Fatalf("Update() = %v, description %q; want rollback", err, pkg.Manifest.Description)
+	}
+	pkg.Unlock()
+
+	if !pkg.TryLock() {
+		t.Fatal("TryLock() failed after a rolled back update")
+	}
+	pkg.Unlock()
+}
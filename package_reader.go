@@ -0,0 +1,176 @@
+package ptd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultMaxInMemoryBytes is the largest archive PackageFromReader buffers in memory
+const DefaultMaxInMemoryBytes = 64 << 20
+
+// OpenOptions controls how a package archive is read from a stream
+type OpenOptions struct {
+	// MaxInMemoryBytes is the largest archive buffered in memory. Larger
+	// archives are spooled to a temporary file. Zero means
+	// DefaultMaxInMemoryBytes; a negative value always uses a temporary file.
+	MaxInMemoryBytes int64
+
+	// DecryptionKey decrypts an archive created by CreateEncryptedArchive;
+	// archives that are not encrypted are read as is. A decrypted archive too large for memory is spooled to a temporary
+	// file in plain text until Cleanup.
+	DecryptionKey *EncryptionKey
+
+	// Verification selects when the hashes of the archive's files are
+	// checked: VerifyOnOpen, the default, or VerifyDeferred.
+	Verification VerificationMode
+
+	// Validator, if set, validates every entity of the package as it is
+	// opened, streaming through each entity file. Invalid entities fail
+	// the open with a *PackageValidationError locating each of them.
+	Validator *SchemaValidator
+
+	// VerifyCounts checks the manifest's entity counts with
+	// Package.VerifyCounts as the package is opened. A count that differs
+	// fails the open with an error wrapping ErrManifestInvalid.
+	VerifyCounts bool
+
+	// ValidateEmbeddedSchemas validates the spec of every entity against
+	// the JSON Schema embedded in the package for its type, if any (see
+	// Package.SetEmbeddedSchemas). Invalid entities fail the open with a
+	// *PackageValidationError.
+	ValidateEmbeddedSchemas bool
+}
+
+// verify reports whether file hashes are checked when the archive is opened
+func (o OpenOptions) verify() bool {
+	return o.Verification != VerifyDeferred
+}
+
+// PackageFromReader reads a package archive from r and validates it like
+// OpenPackage. Archives need random access, so the archive is buffered
+// in memory, or in a temporary file if it exceeds DefaultMaxInMemoryBytes.
+func PackageFromReader(r io.Reader) (*Package, error) {
+	return PackageFromReaderWithOptions(r, OpenOptions{})
+}
+
+// PackageFromReaderWithOptions is PackageFromReader with explicit options
+func PackageFromReaderWithOptions(r io.Reader, opts OpenOptions) (*Package, error) {
+	if opts.DecryptionKey != nil {
+		buffered := bufio.NewReader(r)
+		r = buffered
+		if magic, _ := buffered.Peek(len(encryptionMagic)); string(magic) == encryptionMagic {
+			decrypted, err := newDecryptingReader(buffered, *opts.DecryptionKey)
+			if err != nil {
+				return nil, err
+			}
+			r = decrypted
+		}
+	}
+	pkg, _, err := readPackage(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	return validateOnOpen(pkg, opts)
+}
+
+// OpenPackageWithOptions is OpenPackage with explicit options. An encrypted
+// archive is decrypted with opts.DecryptionKey and read like
+// PackageFromReaderWithOptions; other archives are opened in place.
+func OpenPackageWithOptions(archivePath string, opts OpenOptions) (*Package, error) {
+	if opts.DecryptionKey == nil {
+		pkg, err := openPackagePath(context.Background(), archivePath, opts.verify(), nil)
+		if err != nil {
+			return nil, err
+		}
+		return validateOnOpen(pkg, opts)
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	return PackageFromReaderWithOptions(file, opts)
+}
+
+// OpenPackageFrom opens and validates the package archive of the given
+// size read from r, such as a bytes.Reader or an object-storage client
+// supporting ranged reads. r must remain readable while the package is in
+// use, as entities are read from it on demand.
+func OpenPackageFrom(r io.ReaderAt, size int64) (*Package, error) {
+	pkg, format, err := openArchive(r, size, true)
+	if err != nil {
+		return nil, err
+	}
+	pkg.archive = &archiveSource{readerAt: r, size: size, format: format}
+	return pkg, nil
+}
+
+// ReadFrom replaces p with the package archive read from r, implementing
+// io.ReaderFrom. It returns the number of bytes read. Once the archive has
+// been read, p's previous working directory and temporary archive, if any,
+// are removed as by Cleanup; if reading fails, p is left unchanged.
+func (p *Package) ReadFrom(r io.Reader) (int64, error) {
+	pkg, n, err := readPackage(r, OpenOptions{})
+	if err != nil {
+		return n, err
+	}
+	if err := p.Cleanup(); err != nil {
+		pkg.Cleanup()
+		return n, fmt.Errorf("failed to remove previous package: %w", err)
+	}
+	p.setState(pkg)
+	return n, nil
+}
+
+// readPackage buffers the archive from r in memory or a temporary file and opens it
+func readPackage(r io.Reader, opts OpenOptions) (*Package, int64, error) {
+	limit := opts.MaxInMemoryBytes
+	if limit == 0 {
+		limit = DefaultMaxInMemoryBytes
+	}
+
+	var buf bytes.Buffer
+	if limit > 0 {
+		n, err := io.Copy(&buf, io.LimitReader(r, limit+1))
+		if err != nil {
+			return nil, n, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if n <= limit {
+			contents := bytes.NewReader(buf.Bytes())
+			pkg, format, err := openArchive(contents, n, opts.verify())
+			if err != nil {
+				return nil, n, err
+			}
+			pkg.archive = &archiveSource{readerAt: contents, size: n, format: format, deferred: !opts.verify()}
+			return pkg, n, nil
+		}
+	}
+
+	// Too large for memory: spool what was read so far and the rest to disk
+	file, err := os.CreateTemp("", "ptd-archive-*.ptd")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+
+	n, err := io.Copy(file, io.MultiReader(&buf, r))
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, n, fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	pkg, format, err := openArchive(file, n, opts.verify())
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, n, err
+	}
+	// Entities are read from the spooled copy until Cleanup removes it
+	pkg.archive = &archiveSource{path: file.Name(), format: format, temporary: true, deferred: !opts.verify()}
+	return pkg, n, nil
+}
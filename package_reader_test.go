@@ -0,0 +1,118 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newStreamedArchive returns the archive bytes of a package with one event
+func newStreamedArchive(t *testing.T) []byte {
+	t.Helper()
+	pkg, _ := newEditTestPackage(t, 1)
+	var buf bytes.Buffer
+	if _, err := pkg.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestPackageFromReader(t *testing.T) {
+	archive := newStreamedArchive(t)
+
+	for name, opts := range map[string]OpenOptions{
+		"in memory": {},
+		"temp file": {MaxInMemoryBytes: 16},
+		"always":    {MaxInMemoryBytes: -1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pkg, err := PackageFromReaderWithOptions(bytes.NewReader(archive), opts)
+			if err != nil {
+				t.Fatalf("Failed to read package: %v", err)
+			}
+			t.Cleanup(func() { pkg.Cleanup() })
+			if pkg.Manifest.Description != "Edit test" || pkg.Manifest.Entities[TypeEvent].Count != 1 {
+				t.Errorf("Unexpected manifest: %+v", pkg.Manifest)
+			}
+		})
+	}
+
+	if _, err := PackageFromReader(strings.NewReader("not a zip")); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("Expected ErrInvalidPackage, got %v", err)
+	}
+}
+
+func TestPackage_ReadFrom(t *testing.T) {
+	archive := newStreamedArchive(t)
+
+	var pkg Package
+	n, err := pkg.ReadFrom(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if n != int64(len(archive)) {
+		t.Errorf("ReadFrom read %d bytes, want %d", n, len(archive))
+	}
+	if pkg.Manifest == nil || pkg.Manifest.Description != "Edit test" {
+		t.Errorf("Package not loaded: %+v", pkg.Manifest)
+	}
+
+	// Reading into a package with a working directory or a spooled archive
+	// removes them
+	working := NewPackage("Working")
+	workingDir := working.tempDir
+	if _, err := working.ReadFrom(bytes.NewReader(archive)); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if _, err := os.Stat(workingDir); !os.IsNotExist(err) {
+		t.Errorf("Previous working directory was not removed: %v", err)
+	}
+
+	spooled, err := PackageFromReaderWithOptions(bytes.NewReader(archive), OpenOptions{MaxInMemoryBytes: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spoolPath := spooled.archive.path
+	if _, err := spooled.ReadFrom(bytes.NewReader(archive)); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if _, err := os.Stat(spoolPath); !os.IsNotExist(err) {
+		t.Errorf("Previous temporary archive was not removed: %v", err)
+	}
+	spooled.Cleanup()
+
+	// A failed read keeps the package as it was
+	if _, err := pkg.ReadFrom(strings.NewReader("not a zip")); err == nil || pkg.Manifest == nil {
+		t.Errorf("ReadFrom(invalid) = %v, manifest %v", err, pkg.Manifest)
+	}
+}
+
+func TestPackage_CreateArchiveTo(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 2)
+
+	var buf bytes.Buffer
+	if err := pkg.CreateArchiveTo(&buf); err != nil {
+		t.Fatalf("CreateArchiveTo() error = %v", err)
+	}
+	if len(pkg.Manifest.Files) == 0 {
+		t.Error("CreateArchiveTo() did not record the package files in the manifest")
+	}
+
+	opened, err := OpenPackageFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenPackageFrom() error = %v", err)
+	}
+	if opened.Manifest.Description != "Edit test" || opened.Manifest.Entities[TypeEvent].Count != 2 {
+		t.Errorf("Unexpected manifest: %+v", opened.Manifest)
+	}
+	events, err := ExtractEntities[Event](opened, TypeEvent)
+	if err != nil || len(events) != 2 || events[1].ID != ids[1] {
+		t.Errorf("ExtractEntities() = %v, %v", events, err)
+	}
+
+	if _, err := OpenPackageFrom(strings.NewReader("not a zip"), 9); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("OpenPackageFrom() error = %v, want ErrInvalidPackage", err)
+	}
+}
@@ -0,0 +1,169 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ExtensionDeleted is the Meta.Extensions key that marks an entity as a
+// tombstone. Repack drops every line with a tombstoned ID.
+const ExtensionDeleted = "deleted"
+
+// RepackOptions controls Package.Repack
+type RepackOptions struct {
+	CompressOutput   bool // Re-encode each line without insignificant whitespace
+	SortByCreatedAt  bool // Order by Meta.CreatedAt, then ID, instead of by ID alone
+	RemoveDuplicates bool // Keep only the last line written for each ID
+}
+
+// Repack returns a copy of the package in a new working directory with
+// every entity file rewritten: tombstoned entities removed and the rest
+// sorted by the ULID of their ID (or by creation time). Attachments and
+// other files are carried over, as are compression and indexing. The
+// original package is left untouched.
+func (p *Package) Repack(opts RepackOptions) (*Package, error) {
+	for _, entityType := range p.entityTypes() {
+		if err := checkEntityType(entityType); err != nil {
+			return nil, err
+		}
+	}
+
+	repacked, err := p.workingCopy()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entityType := range p.entityTypes() {
+		lines, err := p.readEntityLines(entityType)
+		if err != nil {
+			repacked.Cleanup()
+			return nil, err
+		}
+
+		lines, err = repackLines(lines, opts)
+		if err != nil {
+			repacked.Cleanup()
+			return nil, fmt.Errorf("failed to repack %s entities: %w", entityType, err)
+		}
+
+		if err := repacked.writeEntityLines(entityType, lines); err != nil {
+			repacked.Cleanup()
+			return nil, err
+		}
+	}
+
+	return repacked, nil
+}
+
+// workingCopy copies the package, including its working directory or, for
+// an opened package, the files of its archive, to a new working directory
+func (p *Package) workingCopy() (*Package, error) {
+	tempDir, err := os.MkdirTemp("", "ptd-package-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	switch {
+	case p.tempDir != "":
+		if err := copyDir(p.tempDir, tempDir); err != nil {
+			os.RemoveAll(tempDir)
+			return nil, fmt.Errorf("failed to copy package: %w", err)
+		}
+	case p.archive != nil:
+		if err := p.archive.extract(tempDir); err != nil {
+			os.RemoveAll(tempDir)
+			return nil, fmt.Errorf("failed to copy package: %w", err)
+		}
+		// The manifest is written afresh when the copy is archived
+		os.Remove(filepath.Join(tempDir, "manifest.json"))
+	}
+
+	c := &Package{
+		ID:               p.ID,
+		Created:          p.Created,
+		Version:          p.Version,
+		Manifest:         p.Manifest.clone(),
+		tempDir:          tempDir,
+		compression:      p.compression,
+		compressionLevel: p.compressionLevel,
+		indexEntities:    p.indexEntities,
+		embedSchemas:     p.embedSchemas,
+	}
+	if p.tempDir == "" && p.archive != nil {
+		c.useArchiveSettings()
+	}
+	return c, nil
+}
+
+// repackLines drops tombstones (and optionally duplicates) and sorts the lines
+func repackLines(lines []json.RawMessage, opts RepackOptions) ([]json.RawMessage, error) {
+	type keyed struct {
+		id        string
+		ulid      string
+		createdAt time.Time
+		line      json.RawMessage
+	}
+
+	var entities []keyed
+	latest := make(map[string]int) // ID -> index of its last line
+	deleted := make(map[string]bool)
+	for _, line := range lines {
+		var envelope struct {
+			ID   string `json:"id"`
+			Meta struct {
+				CreatedAt  time.Time              `json:"created_at"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			return nil, fmt.Errorf("%w: failed to decode entity: %v", ErrInvalidPackage, err)
+		}
+
+		if opts.CompressOutput {
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, line); err != nil {
+				return nil, fmt.Errorf("%w: failed to compact entity: %v", ErrInvalidPackage, err)
+			}
+			line = buf.Bytes()
+		}
+
+		latest[envelope.ID] = len(entities)
+		entities = append(entities, keyed{
+			id:        envelope.ID,
+			ulid:      idULID(envelope.ID),
+			createdAt: envelope.Meta.CreatedAt,
+			line:      line,
+		})
+		if tombstone, _ := envelope.Meta.Extensions[ExtensionDeleted].(bool); tombstone {
+			deleted[envelope.ID] = true
+		}
+	}
+
+	kept := entities[:0]
+	for i, entity := range entities {
+		if opts.RemoveDuplicates && latest[entity.id] != i {
+			continue
+		}
+		if deleted[entity.id] {
+			continue
+		}
+		kept = append(kept, entity)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		if opts.SortByCreatedAt && !kept[i].createdAt.Equal(kept[j].createdAt) {
+			return kept[i].createdAt.Before(kept[j].createdAt)
+		}
+		return kept[i].ulid < kept[j].ulid
+	})
+
+	result := make([]json.RawMessage, len(kept))
+	for i, entity := range kept {
+		result[i] = entity.line
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repackTestLine encodes an event envelope as an NDJSON line
+func repackTestLine(t *testing.T, id string, created time.Time, extensions map[string]interface{}) json.RawMessage {
+	t.Helper()
+	line, err := json.Marshal(Envelope[Event]{
+		ID:   id,
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Event"}},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1, CreatedAt: created, Extensions: extensions},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode envelope: %v", err)
+	}
+	return line
+}
+
+func TestPackage_Repack(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 3)
+
+	// Reverse the order on disk and append a tombstone for the middle event
+	lines, err := pkg.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to read entities: %v", err)
+	}
+	lines = []json.RawMessage{lines[2], lines[1], lines[0],
+		repackTestLine(t, ids[1], time.Time{}, map[string]interface{}{ExtensionDeleted: true})}
+	if err := pkg.writeEntityLines(TypeEvent, lines); err != nil {
+		t.Fatalf("Failed to write entities: %v", err)
+	}
+
+	repacked, err := pkg.Repack(RepackOptions{})
+	if err != nil {
+		t.Fatalf("Repack failed: %v", err)
+	}
+	defer repacked.Cleanup()
+
+	if repacked.ID != pkg.ID || repacked.tempDir == pkg.tempDir {
+		t.Errorf("Expected a copy with the same ID in a new directory")
+	}
+	got, err := repacked.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatalf("Failed to read repacked entities: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entities, got %d", len(got))
+	}
+	for i, want := range []string{ids[0], ids[2]} {
+		if id, _ := envelopeID(got[i]); id != want {
+			t.Errorf("Entity %d: expected %s, got %s", i, want, id)
+		}
+	}
+	if count := repacked.Manifest.Entities[TypeEvent].Count; count != 2 {
+		t.Errorf("Expected manifest count 2, got %d", count)
+	}
+
+	// The original is untouched
+	if original, _ := pkg.readEntityLines(TypeEvent); len(original) != 4 {
+		t.Errorf("Expected original to keep 4 lines, got %d", len(original))
+	}
+}
+
+func TestRepackLines_Options(t *testing.T) {
+	id1, id2 := GenerateID(TypeEvent), GenerateID(TypeEvent)
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	spaced := bytes.ReplaceAll(repackTestLine(t, id2, early, nil), []byte(`":`), []byte(`": `))
+	lines := []json.RawMessage{
+		repackTestLine(t, id1, late, map[string]interface{}{"rev": "a"}),
+		spaced,
+		repackTestLine(t, id1, late, map[string]interface{}{"rev": "b"}),
+	}
+
+	got, err := repackLines(lines, RepackOptions{})
+	if err != nil {
+		t.Fatalf("repackLines failed: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("Expected duplicates to be kept, got %d lines", len(got))
+	}
+
+	got, err = repackLines(lines, RepackOptions{RemoveDuplicates: true, SortByCreatedAt: true, CompressOutput: true})
+	if err != nil {
+		t.Fatalf("repackLines failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(got))
+	}
+	if id, _ := envelopeID(got[0]); id != id2 {
+		t.Errorf("Expected earliest created entity first, got %s", id)
+	}
+	if !bytes.Contains(got[1], []byte(`"rev":"b"`)) {
+		t.Errorf("Expected last duplicate to be kept, got %s", got[1])
+	}
+	if bytes.Contains(got[0], []byte(`": `)) {
+		t.Errorf("Expected compact output, got %s", got[0])
+	}
+
+	if _, err := repackLines([]json.RawMessage{json.RawMessage("{")}, RepackOptions{}); err == nil {
+		t.Error("Expected error for malformed line")
+	}
+}
+
+func TestPackage_Repack_Opened(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 2)
+	if err := pkg.AddAttachment("draw.txt", strings.NewReader("draw"), ""); err != nil {
+		t.Fatal(err)
+	}
+	pkg.SetEntityIndex(true)
+	archivePath := filepath.Join(t.TempDir(), "opened.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Cleanup()
+
+	repacked, err := opened.Repack(RepackOptions{})
+	if err != nil {
+		t.Fatalf("Repack() error = %v", err)
+	}
+	defer repacked.Cleanup()
+	repackedPath := filepath.Join(t.TempDir(), "repacked.ptd")
+	if err := repacked.CreateArchive(repackedPath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	reopened, err := OpenPackage(repackedPath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	file, err := reopened.OpenAttachment("draw.txt")
+	if err != nil {
+		t.Fatalf("OpenAttachment() after Repack error = %v", err)
+	}
+	data, _ := io.ReadAll(file)
+	file.Close()
+	if string(data) != "draw" {
+		t.Errorf("attachment = %q, want draw", data)
+	}
+	if _, ok := reopened.Manifest.Files[entityIndexPath]; !ok {
+		t.Error("Repack() dropped the entity index")
+	}
+	if lines, err := reopened.readEntityLines(TypeEvent); err != nil || len(lines) != 2 {
+		t.Errorf("repacked archive has %d events (err %v), want 2", len(lines), err)
+	}
+}
+
+func TestPackage_Repack_InvalidType(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	pkg.Manifest.Entities["../escape"] = EntityCount{Type: "../escape", Count: 1}
+
+	if _, err := pkg.Repack(RepackOptions{}); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Repack() error = %v, want ErrInvalidType", err)
+	}
+}
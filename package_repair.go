@@ -0,0 +1,185 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// RepairReport describes the changes RepairPackage made to a package
+type RepairReport struct {
+	ManifestRebuilt  bool               `json:"manifest_rebuilt"`         // The manifest was missing or unreadable and was rebuilt
+	FilesAdded       []string           `json:"files_added,omitempty"`    // Files the manifest did not list
+	FilesRemoved     []string           `json:"files_removed,omitempty"`  // Manifest entries without a file
+	FilesUpdated     []string           `json:"files_updated,omitempty"`  // Files whose size or hash was out of date
+	CountsChanged    []CountDiscrepancy `json:"counts_changed,omitempty"` // Entity counts corrected, from Manifest to Actual
+	SignatureRemoved bool               `json:"signature_removed"`        // The manifest signature no longer applied
+}
+
+// Changed reports whether the repair changed the package
+func (r *RepairReport) Changed() bool {
+	return r.ManifestRebuilt || len(r.FilesAdded) > 0 || len(r.FilesRemoved) > 0 ||
+		len(r.FilesUpdated) > 0 || len(r.CountsChanged) > 0 || r.SignatureRemoved
+}
+
+// RepairPackage rebuilds the manifest of the package archive at
+// archivePath from the files the archive holds: file entries are added,
+// removed and rehashed, and entity counts recounted from the NDJSON files.
+// A manifest that is missing or unreadable is replaced by a new one. The
+// archive is rewritten in place like Package.Save, in its original format,
+// only if something changed.
+//
+// A manifest signature that no longer covers the repaired manifest is
+// removed. A manifest of a newer major format version is not repaired and
+// returns ErrUnsupportedVersion.
+func RepairPackage(archivePath string) (*RepairReport, error) {
+	if absPath, err := filepath.Abs(archivePath); err == nil {
+		archivePath = absPath
+	}
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	format, err := detectArchiveFormat(file, info.Size())
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := NewPackage("")
+	defer pkg.Cleanup()
+	source := &archiveSource{path: archivePath, format: format}
+	if err := source.extract(pkg.tempDir); err != nil {
+		return nil, err
+	}
+
+	report := &RepairReport{}
+	if err := pkg.loadRepairManifest(report); err != nil {
+		return nil, err
+	}
+	var signed []byte
+	if pkg.Manifest.Signature != nil {
+		if signed, err = pkg.Manifest.CanonicalJSON(); err != nil {
+			return nil, fmt.Errorf("failed to get canonical JSON: %w", err)
+		}
+	}
+
+	if err := pkg.repairFileEntries(report); err != nil {
+		return nil, err
+	}
+	discrepancies, err := pkg.VerifyCounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range discrepancies {
+		pkg.Manifest.Entities[d.Type] = EntityCount{Type: d.Type, Count: d.Actual}
+	}
+	report.CountsChanged = discrepancies
+
+	if signed != nil {
+		canonical, err := pkg.Manifest.CanonicalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get canonical JSON: %w", err)
+		}
+		report.SignatureRemoved = !bytes.Equal(canonical, signed)
+	}
+	if !report.Changed() {
+		return report, nil
+	}
+
+	pkg.appendPath = archivePath
+	pkg.appendFormat = format
+	pkg.signedManifest = signed
+	pkg.useArchiveSettings()
+	if err := pkg.Save(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// loadRepairManifest reads the extracted manifest of a package being
+// repaired, keeping the new package's manifest if it cannot be read
+func (p *Package) loadRepairManifest(report *RepairReport) error {
+	manifestPath := filepath.Join(p.tempDir, "manifest.json")
+	data, err := os.ReadFile(manifestPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	os.Remove(manifestPath)
+
+	manifest := &Manifest{}
+	if err != nil || json.Unmarshal(data, manifest) != nil {
+		report.ManifestRebuilt = true
+		return nil
+	}
+	if err := manifest.checkFormatVersion(); err != nil {
+		return err
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]*FileEntry)
+	}
+	if manifest.Entities == nil {
+		manifest.Entities = make(map[string]EntityCount)
+	}
+	p.Created = manifest.Created
+	p.Version = manifest.Version
+	p.Manifest = manifest
+	return nil
+}
+
+// repairFileEntries brings the manifest's file entries in line with the
+// files of the package's working directory
+func (p *Package) repairFileEntries(report *RepairReport) error {
+	present := make(map[string]bool)
+	err := filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(p.tempDir, path)
+		if err != nil {
+			return err
+		}
+		present[relPath] = true
+
+		entry, err := hashFileEntry(relPath, path, info.ModTime())
+		if err != nil {
+			return err
+		}
+		previous, listed := p.Manifest.Files[relPath]
+		switch {
+		case !listed:
+			report.FilesAdded = append(report.FilesAdded, relPath)
+		case previous.Hash != entry.Hash || previous.Size != entry.Size:
+			report.FilesUpdated = append(report.FilesUpdated, relPath)
+		}
+		if listed && previous.Type != "" {
+			entry.Type = previous.Type
+		}
+		p.Manifest.Files[relPath] = entry
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk directory: %w", err)
+	}
+
+	for relPath := range p.Manifest.Files {
+		if !present[relPath] && relPath != "manifest.json" {
+			report.FilesRemoved = append(report.FilesRemoved, relPath)
+			delete(p.Manifest.Files, relPath)
+		}
+	}
+	sort.Strings(report.FilesAdded)
+	sort.Strings(report.FilesRemoved)
+	sort.Strings(report.FilesUpdated)
+	return nil
+}
@@ -0,0 +1,197 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// rewriteArchiveFiles rewrites the ZIP archive at archivePath with its files
+// changed by edit, which may add and remove files
+func rewriteArchiveFiles(t *testing.T, archivePath string, edit func(files map[string][]byte)) {
+	t.Helper()
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = data
+	}
+	reader.Close()
+
+	edit(files)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for _, name := range names {
+		w, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(files[name])
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(archivePath, out.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepairPackage(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 3)
+
+	// An intact archive is left alone
+	before, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := RepairPackage(archivePath)
+	if err != nil {
+		t.Fatalf("RepairPackage() error = %v", err)
+	}
+	if report.Changed() {
+		t.Errorf("RepairPackage() of an intact archive = %+v, want no changes", report)
+	}
+	if after, _ := os.ReadFile(archivePath); !bytes.Equal(after, before) {
+		t.Error("RepairPackage() rewrote an intact archive")
+	}
+
+	// A hand-edited archive: a truncated event file, an added file and a
+	// manifest entry whose file is gone
+	eventsPath := filepath.ToSlash(entityFilePath(TypeEvent))
+	rewriteArchiveFiles(t, archivePath, func(files map[string][]byte) {
+		first, _, _ := bytes.Cut(files[eventsPath], []byte("\n"))
+		files[eventsPath] = append(first, '\n')
+		files["notes/readme.txt"] = []byte("added by hand")
+	})
+	data, _ := os.ReadFile(archivePath)
+	edited := filepath.Join(t.TempDir(), "edited.ptd")
+	os.WriteFile(edited, data, 0644)
+	rewriteArchiveFiles(t, edited, func(files map[string][]byte) {
+		files["manifest.json"] = bytes.Replace(files["manifest.json"], []byte(`"files": {`), []byte(`"files": {"gone.txt": {"path": "gone.txt", "hash": "00"},`), 1)
+	})
+
+	if _, err := OpenPackage(edited); err == nil {
+		t.Fatal("OpenPackage() of the edited archive succeeded")
+	}
+	report, err = RepairPackage(edited)
+	if err != nil {
+		t.Fatalf("RepairPackage() error = %v", err)
+	}
+	want := &RepairReport{
+		FilesAdded:    []string{"notes/readme.txt"},
+		FilesRemoved:  []string{"gone.txt"},
+		FilesUpdated:  []string{eventsPath},
+		CountsChanged: []CountDiscrepancy{{Type: TypeEvent, Manifest: 3, Actual: 1}},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("RepairPackage() = %+v, want %+v", report, want)
+	}
+
+	pkg, err := OpenPackageWithOptions(edited, OpenOptions{VerifyCounts: true})
+	if err != nil {
+		t.Fatalf("OpenPackage() after repair error = %v", err)
+	}
+	if pkg.Manifest.Description != "Edit test" {
+		t.Errorf("Description = %q, want the original kept", pkg.Manifest.Description)
+	}
+	if report, err := RepairPackage(edited); err != nil || report.Changed() {
+		t.Errorf("second RepairPackage() = %+v, %v; want no changes", report, err)
+	}
+}
+
+func TestRepairPackage_MissingManifest(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 2)
+	rewriteArchiveFiles(t, archivePath, func(files map[string][]byte) {
+		delete(files, "manifest.json")
+	})
+	if _, err := OpenPackage(archivePath); !errors.Is(err, ErrManifestMissing) {
+		t.Fatalf("OpenPackage() error = %v, want ErrManifestMissing", err)
+	}
+
+	report, err := RepairPackage(archivePath)
+	if err != nil {
+		t.Fatalf("RepairPackage() error = %v", err)
+	}
+	if !report.ManifestRebuilt || len(report.FilesAdded) != 1 {
+		t.Errorf("RepairPackage() = %+v, want a rebuilt manifest listing the event file", report)
+	}
+
+	pkg, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() after repair error = %v", err)
+	}
+	if got := pkg.Manifest.Entities[TypeEvent].Count; got != 2 {
+		t.Errorf("event count = %d, want 2", got)
+	}
+}
+
+func TestRepairPackage_Signature(t *testing.T) {
+	signer, err := NewSigner("repair-key", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _ := newEditTestPackage(t, 2)
+	if err := pkg.SignPackage(signer); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "signed.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rehashed file keeps the signature, which does not cover file entries
+	rewriteArchiveFiles(t, archivePath, func(files map[string][]byte) {
+		files["notes.txt"] = []byte("notes")
+	})
+	report, err := RepairPackage(archivePath)
+	if err != nil {
+		t.Fatalf("RepairPackage() error = %v", err)
+	}
+	if report.SignatureRemoved {
+		t.Error("RepairPackage() removed a signature that still applies")
+	}
+
+	// A corrected entity count changes the signed manifest
+	eventsPath := filepath.ToSlash(entityFilePath(TypeEvent))
+	rewriteArchiveFiles(t, archivePath, func(files map[string][]byte) {
+		first, _, _ := bytes.Cut(files[eventsPath], []byte("\n"))
+		files[eventsPath] = append(first, '\n')
+	})
+	report, err = RepairPackage(archivePath)
+	if err != nil {
+		t.Fatalf("RepairPackage() error = %v", err)
+	}
+	if !report.SignatureRemoved {
+		t.Error("RepairPackage() kept a stale signature")
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opened.Manifest.Signature != nil {
+		t.Error("repaired archive is still signed")
+	}
+}
@@ -0,0 +1,269 @@
+package ptd
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// embeddedSchemaDir is the package-relative folder of the JSON Schemas
+// embedded in archives, one "<type>.schema.json" per entity type
+const embeddedSchemaDir = "schemas"
+
+// jsonSchemaDialect is the JSON Schema draft of generated schemas
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// entitySpecTypes are the Go types of the specs of the built-in entity
+// types with a schema
+var entitySpecTypes = map[string]reflect.Type{
+	TypeTournament: reflect.TypeOf(Tournament{}),
+	TypeEvent:      reflect.TypeOf(Event{}),
+	TypeMatch:      reflect.TypeOf(Match{}),
+	TypeEntry:      reflect.TypeOf(Entry{}),
+	TypePlayer:     reflect.TypeOf(Player{}),
+	TypePhase:      reflect.TypeOf(TournamentPhase{}),
+	TypeCoach:      reflect.TypeOf(Coach{}),
+}
+
+// embeddedSchemaPath returns the package-relative path of the JSON Schema
+// embedded for an entity type
+func embeddedSchemaPath(entityType string) string {
+	return filepath.Join(embeddedSchemaDir, entityType+".schema.json")
+}
+
+// hasEmbeddedSchemas reports whether the manifest lists embedded schemas
+func (m *Manifest) hasEmbeddedSchemas() bool {
+	for relPath := range m.Files {
+		if strings.HasPrefix(relPath, embeddedSchemaDir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEmbeddedSchemas selects whether archives created from the package
+// carry the JSON Schema of each entity type they hold, in the schemas/
+// folder, so consumers outside Go can validate the entities. Types without
+// a schema (see EntityJSONSchema) are left out. The schemas/ folder is
+// regenerated with every archive.
+func (p *Package) SetEmbeddedSchemas(enabled bool) {
+	p.embedSchemas = enabled
+}
+
+// EntityJSONSchema returns the JSON Schema document for the specs of an
+// entity type: generated from the Go struct of a built-in type, or the
+// document registered with RegisterJSONSchema for a custom type. Types
+// without a schema return ErrInvalidType.
+func EntityJSONSchema(entityType string) ([]byte, error) {
+	if specType, ok := entitySpecTypes[entityType]; ok {
+		schema := jsonSchemaOf(specType)
+		schema["$schema"] = jsonSchemaDialect
+		schema["title"] = entityType
+		schema["required"] = requiredFields[entityType]
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON Schema: %w", err)
+		}
+		return data, nil
+	}
+
+	jsonSchemaRegistry.RLock()
+	defer jsonSchemaRegistry.RUnlock()
+	if document, ok := jsonSchemaRegistry.documents[entityType]; ok {
+		return document, nil
+	}
+	return nil, fmt.Errorf("%w: no JSON Schema for entity type %s", ErrInvalidType, entityType)
+}
+
+// writeEmbeddedSchemas writes the JSON Schemas of the package's entity
+// types to the working directory if embedding is enabled, replacing any
+// written before
+func (p *Package) writeEmbeddedSchemas() error {
+	if err := os.RemoveAll(filepath.Join(p.tempDir, embeddedSchemaDir)); err != nil {
+		return fmt.Errorf("failed to remove embedded schemas: %w", err)
+	}
+	for relPath := range p.Manifest.Files {
+		if strings.HasPrefix(relPath, embeddedSchemaDir+string(filepath.Separator)) {
+			delete(p.Manifest.Files, relPath)
+		}
+	}
+	if !p.embedSchemas {
+		return nil
+	}
+
+	for _, entityType := range p.entityTypes() {
+		document, err := EntityJSONSchema(entityType)
+		if errors.Is(err, ErrInvalidType) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		schemaPath := filepath.Join(p.tempDir, embeddedSchemaPath(entityType))
+		if err := os.MkdirAll(filepath.Dir(schemaPath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(schemaPath, document, 0644); err != nil {
+			return fmt.Errorf("failed to write JSON Schema for %s: %w", entityType, err)
+		}
+	}
+	return nil
+}
+
+// validateEmbeddedSchemas validates the spec of each entity of the package
+// against the JSON Schema embedded for its type, streaming through the
+// entity files. Types without an embedded schema are not checked.
+func validateEmbeddedSchemas(pkg *Package) ([]InvalidEntity, error) {
+	var invalid []InvalidEntity
+	for _, entityType := range pkg.entityTypes() {
+		schema, err := pkg.embeddedSchema(entityType)
+		if err != nil {
+			return nil, err
+		}
+		if schema == nil {
+			continue
+		}
+
+		file := entityFilePath(entityType)
+		err = pkg.scanEntityLines(entityType, func(n int, line []byte) bool {
+			var envelope Envelope[json.RawMessage]
+			if err := json.Unmarshal(line, &envelope); err != nil {
+				invalid = append(invalid, InvalidEntity{File: file, Line: n, Err: &ValidationError{EntityType: entityType, Message: err.Error(), Err: ErrInvalidFormat}})
+				return true
+			}
+			value, err := toJSONValue(envelope.Spec)
+			if err == nil {
+				err = schema.validate(value, entityType)
+			}
+			if err != nil {
+				invalid = append(invalid, InvalidEntity{File: file, Line: n, Err: asValidationError(withEntityContext(err, envelope.ID, entityType))})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return invalid, nil
+}
+
+// embeddedSchema returns the compiled JSON Schema embedded in the package
+// for an entity type, or nil if it has none
+func (p *Package) embeddedSchema(entityType string) (*jsonSchema, error) {
+	file, err := p.openPackageFile(embeddedSchemaPath(entityType))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON Schema for %s: %w", entityType, err)
+	}
+	defer file.Close()
+
+	document, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON Schema for %s: %w", entityType, err)
+	}
+	schema, err := compileJSONSchema(document)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", embeddedSchemaPath(entityType), err)
+	}
+	return schema, nil
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	multiNameType     = reflect.TypeOf(MultiName{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonSchemaOf generates the JSON Schema of the JSON encoding of values of
+// type t. Only the fields of entity specs are required; nested objects
+// accept any subset of their properties.
+func jsonSchemaOf(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == multiNameType:
+		// A name without translations is encoded as a plain string
+		return map[string]interface{}{"type": []string{"string", "object"}}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return map[string]interface{}{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonSchemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "array", "items": jsonSchemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		addStructProperties(t, properties)
+		return map[string]interface{}{"type": "object", "properties": properties}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// addStructProperties adds the schemas of the JSON fields of struct type t
+// to properties, flattening embedded structs as encoding/json does
+func addStructProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructProperties(embedded, properties)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := jsonSchemaOf(field.Type)
+		omitempty := strings.Contains(","+options+",", ",omitempty,")
+		switch field.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			// Nil values of fields without omitempty are encoded as null
+			if typeName, ok := schema["type"].(string); ok && !omitempty {
+				schema["type"] = []string{typeName, "null"}
+			}
+		}
+		properties[name] = schema
+	}
+}
@@ -0,0 +1,177 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEntityJSONSchema(t *testing.T) {
+	for entityType := range entitySpecTypes {
+		document, err := EntityJSONSchema(entityType)
+		if err != nil {
+			t.Fatalf("EntityJSONSchema(%s) error = %v", entityType, err)
+		}
+		if _, err := compileJSONSchema(document); err != nil {
+			t.Errorf("EntityJSONSchema(%s) does not compile: %v", entityType, err)
+		}
+	}
+
+	document, _ := EntityJSONSchema(TypeEvent)
+	event := Event{
+		TournamentID: GenerateID(TypeTournament),
+		Name:         MultiName{Default: "Men's Singles", Translations: LocalizedString{"ja": "男子シングルス"}},
+		EventCode:    "MS",
+		EventType:    "singles",
+		AgeGroup:     &AgeGroup{},
+		EntryFee:     &Money{Amount: 1000, Currency: "USD"},
+		StartDate:    time.Now(),
+		EndDate:      time.Now(),
+		Status:       "published",
+	}
+	if err := ValidateAgainstJSONSchema(document, event); err != nil {
+		t.Errorf("event does not conform to its schema: %v", err)
+	}
+	invalid := map[string]interface{}{"name": "Event", "max_entries": "ten"}
+	if err := ValidateAgainstJSONSchema(document, invalid); !errors.Is(err, ErrMissingField) {
+		t.Errorf("ValidateAgainstJSONSchema() error = %v, want ErrMissingField", err)
+	}
+
+	if _, err := EntityJSONSchema("test_schema_unknown"); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("EntityJSONSchema() of an unknown type error = %v, want ErrInvalidType", err)
+	}
+}
+
+func TestPackage_SetEmbeddedSchemas(t *testing.T) {
+	if err := RegisterJSONSchema("test_schema_sponsor", []byte(testSponsorSchema)); err != nil && !errors.Is(err, ErrDuplicateEntity) {
+		t.Fatal(err)
+	}
+
+	pkg, _ := newEditTestPackage(t, 2)
+	sponsors := []interface{}{Envelope[map[string]interface{}]{
+		ID:   GenerateID("test_schema_sponsor"),
+		Type: "test_schema_sponsor",
+		Spec: map[string]interface{}{"name": "Acme", "tier": "gold"},
+		Meta: Meta{Schema: "ptd.v1.test_schema_sponsor@1.0.0", Version: 1},
+	}}
+	if err := pkg.AddEntities("test_schema_sponsor", sponsors); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.AddEntities("test_schema_plain", nil); err != nil {
+		t.Fatal(err)
+	}
+	pkg.SetEmbeddedSchemas(true)
+	archivePath := filepath.Join(t.TempDir(), "schemas.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	opened, err := OpenPackageWithOptions(archivePath, OpenOptions{ValidateEmbeddedSchemas: true})
+	if err != nil {
+		t.Fatalf("OpenPackageWithOptions() error = %v", err)
+	}
+	for _, entityType := range []string{TypeEvent, "test_schema_sponsor"} {
+		file, err := opened.openPackageFile(embeddedSchemaPath(entityType))
+		if err != nil {
+			t.Fatalf("schema of %s not embedded: %v", entityType, err)
+		}
+		embedded, _ := io.ReadAll(file)
+		file.Close()
+		want, _ := EntityJSONSchema(entityType)
+		if !bytes.Equal(embedded, want) {
+			t.Errorf("embedded schema of %s differs from EntityJSONSchema()", entityType)
+		}
+	}
+	if _, ok := opened.Manifest.Files[embeddedSchemaPath("test_schema_plain")]; ok {
+		t.Error("schema embedded for a type without one")
+	}
+
+	// Turning embedding off drops the schemas from the next archive
+	pkg.SetEmbeddedSchemas(false)
+	plainPath := filepath.Join(t.TempDir(), "plain.ptd")
+	if err := pkg.CreateArchive(plainPath); err != nil {
+		t.Fatal(err)
+	}
+	plain, err := OpenPackage(plainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Manifest.hasEmbeddedSchemas() {
+		t.Error("archive carries schemas with embedding disabled")
+	}
+}
+
+func TestOpenPackage_ValidateEmbeddedSchemas(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	events, err := pkg.readEntityLines(TypeEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid, _ := json.Marshal(Envelope[map[string]interface{}]{
+		ID:   GenerateID(TypeEvent),
+		Type: TypeEvent,
+		Spec: map[string]interface{}{"name": "Event", "max_entries": "ten"},
+		Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+	})
+	if err := pkg.writeEntityLines(TypeEvent, append(events, json.RawMessage(invalid))); err != nil {
+		t.Fatal(err)
+	}
+	pkg.SetEmbeddedSchemas(true)
+	archivePath := filepath.Join(t.TempDir(), "invalid.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the option the schemas are only carried along
+	if _, err := OpenPackage(archivePath); err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+
+	_, err = OpenPackageWithOptions(archivePath, OpenOptions{ValidateEmbeddedSchemas: true})
+	var validationErr *PackageValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("OpenPackageWithOptions() error = %v, want *PackageValidationError", err)
+	}
+	if len(validationErr.Entities) != 1 || validationErr.Entities[0].Line != 2 {
+		t.Fatalf("invalid entities = %+v, want line 2", validationErr.Entities)
+	}
+	if got := validationErr.Entities[0].Err; !errors.Is(got, ErrMissingField) || got.EntityType != TypeEvent {
+		t.Errorf("entity error = %v, want a missing event field", got)
+	}
+}
+
+func TestPackage_CreateArchive_Opened(t *testing.T) {
+	pkg, _, dir := openEditTestArchive(t, 2)
+	pkg.SetEntityIndex(true)
+	pkg.SetEmbeddedSchemas(true)
+
+	// Files in the current directory that share names with package files
+	userFile := filepath.Join(dir, embeddedSchemaDir, "keep", "user.txt")
+	indexFile := filepath.Join(dir, entityIndexPath)
+	if err := os.MkdirAll(filepath.Dir(userFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{userFile, indexFile} {
+		if err := os.WriteFile(path, []byte("user data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "opened.ptd")
+	if err := pkg.CreateArchive(archivePath); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("CreateArchive() on an opened package error = %v, want ErrInvalidPackage", err)
+	}
+	if err := pkg.CreateArchiveTo(io.Discard); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("CreateArchiveTo() on an opened package error = %v, want ErrInvalidPackage", err)
+	}
+	for _, path := range []string{userFile, indexFile} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != "user data" {
+			t.Errorf("%s was changed: %q, %v", path, data, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SplitOptions controls SplitPackage. At least one way of splitting must
+// be selected; with both, each entity type is split into parts by size.
+type SplitOptions struct {
+	ByEntityType bool  // Put each entity type in parts of its own
+	MaxPartBytes int64 // Cap on the entity data of each part, 0 for none
+}
+
+// SplitInfo records where a part of a split package belongs. Entities keep
+// their IDs, so references between parts resolve through Locations.
+type SplitInfo struct {
+	SourceID  string           `json:"source_id"` // ID of the package that was split
+	Part      int              `json:"part"`      // 1-based number of this part
+	Parts     int              `json:"parts"`     // Number of parts
+	Locations map[string][]int `json:"locations"` // Entity type -> parts holding entities of that type
+}
+
+// clone returns a deep copy of the split info
+func (s *SplitInfo) clone() *SplitInfo {
+	c := *s
+	c.Locations = make(map[string][]int, len(s.Locations))
+	for entityType, parts := range s.Locations {
+		c.Locations[entityType] = append([]int(nil), parts...)
+	}
+	return &c
+}
+
+// SplitPackage divides a package into parts for partial consumers: one
+// part per entity type, parts capped at a number of bytes of entity data,
+// or both. Each part is a new package in its own working directory with a
+// manifest counting its own entities and a SplitInfo locating the others;
+// entities keep their IDs, lines and order, so cross-references between
+// parts still resolve. A single entity larger than MaxPartBytes gets a part
+// to itself. The parts carry no signature. pkg is left untouched; call
+// Cleanup on each part when done.
+func SplitPackage(pkg *Package, opts SplitOptions) ([]*Package, error) {
+	if !opts.ByEntityType && opts.MaxPartBytes <= 0 {
+		return nil, fmt.Errorf("%w: split by entity type or by a positive maximum part size", ErrValidation)
+	}
+
+	split := &packageSplitter{source: pkg, opts: opts}
+	for _, entityType := range pkg.entityTypes() {
+		if opts.ByEntityType {
+			if err := split.finishPart(); err != nil {
+				split.cleanup()
+				return nil, err
+			}
+		}
+		err := pkg.scanEntityLines(entityType, func(_ int, line []byte) bool {
+			split.err = split.add(entityType, json.RawMessage(append([]byte(nil), line...)))
+			return split.err == nil
+		})
+		if err == nil {
+			err = split.err
+		}
+		if err != nil {
+			split.cleanup()
+			return nil, err
+		}
+	}
+	if err := split.finishPart(); err != nil {
+		split.cleanup()
+		return nil, err
+	}
+
+	locations := make(map[string][]int)
+	for i, part := range split.parts {
+		for _, entityType := range part.entityTypes() {
+			locations[entityType] = append(locations[entityType], i+1)
+		}
+	}
+	for i, part := range split.parts {
+		part.Manifest.Description = fmt.Sprintf("%s (part %d of %d)", pkg.Manifest.Description, i+1, len(split.parts))
+		part.Manifest.Split = &SplitInfo{
+			SourceID:  pkg.ID,
+			Part:      i + 1,
+			Parts:     len(split.parts),
+			Locations: locations,
+		}
+		if i > 0 {
+			part.Manifest.Split = part.Manifest.Split.clone()
+		}
+	}
+
+	return split.parts, nil
+}
+
+// packageSplitter accumulates the lines of the part being built
+type packageSplitter struct {
+	source *Package
+	opts   SplitOptions
+	parts  []*Package
+	types  []string                     // Entity types of the current part, in order
+	lines  map[string][]json.RawMessage // Lines of the current part by type
+	size   int64                        // Bytes of entity data in the current part
+	err    error                        // Error from within a scan
+}
+
+// add appends a line to the current part, starting a new part first if the
+// line would take it beyond the size cap
+func (s *packageSplitter) add(entityType string, line json.RawMessage) error {
+	lineSize := int64(len(line)) + 1
+	if s.opts.MaxPartBytes > 0 && s.size > 0 && s.size+lineSize > s.opts.MaxPartBytes {
+		if err := s.finishPart(); err != nil {
+			return err
+		}
+	}
+
+	if s.lines == nil {
+		s.lines = make(map[string][]json.RawMessage)
+	}
+	if _, ok := s.lines[entityType]; !ok {
+		s.types = append(s.types, entityType)
+	}
+	s.lines[entityType] = append(s.lines[entityType], line)
+	s.size += lineSize
+	return nil
+}
+
+// finishPart writes the current part, if it has any entities, to a new package
+func (s *packageSplitter) finishPart() error {
+	if len(s.types) == 0 {
+		return nil
+	}
+
+	part := NewPackage(s.source.Manifest.Description)
+	part.Version = s.source.Version
+	part.Manifest.Version = s.source.Manifest.Version
+	part.Manifest.Creator = s.source.Manifest.Creator
+	part.Manifest.Languages = append([]string(nil), s.source.Manifest.Languages...)
+	s.parts = append(s.parts, part)
+
+	sort.Strings(s.types)
+	for _, entityType := range s.types {
+		if err := part.writeEntityLines(entityType, s.lines[entityType]); err != nil {
+			return err
+		}
+	}
+
+	s.types, s.lines, s.size = nil, nil, 0
+	return nil
+}
+
+// cleanup removes the working directories of the parts written so far
+func (s *packageSplitter) cleanup() {
+	for _, part := range s.parts {
+		part.Cleanup()
+	}
+}
@@ -0,0 +1,160 @@
+package ptd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newSplitTestPackage returns a package of 3 events and 10 matches
+// referencing the first event, and the match IDs
+func newSplitTestPackage(t *testing.T) (*Package, []string) {
+	t.Helper()
+	pkg, eventIDs := newEditTestPackage(t, 3)
+
+	var matchIDs []string
+	var matches []interface{}
+	for i := 0; i < 10; i++ {
+		id := GenerateID(TypeMatch)
+		matchIDs = append(matchIDs, id)
+		matches = append(matches, Envelope[Match]{
+			ID:   id,
+			Type: TypeMatch,
+			Spec: Match{EventID: eventIDs[0], MatchNumber: fmt.Sprintf("M%02d", i+1), Status: "scheduled"},
+			Meta: Meta{Schema: "ptd.v1.match@1.0.0", Version: 1},
+		})
+	}
+	if err := pkg.AddEntities(TypeMatch, matches); err != nil {
+		t.Fatal(err)
+	}
+	return pkg, matchIDs
+}
+
+func TestSplitPackage_ByEntityType(t *testing.T) {
+	pkg, _ := newSplitTestPackage(t)
+
+	parts, err := SplitPackage(pkg, SplitOptions{ByEntityType: true})
+	if err != nil {
+		t.Fatalf("SplitPackage() error = %v", err)
+	}
+	for _, part := range parts {
+		t.Cleanup(func() { part.Cleanup() })
+	}
+	if len(parts) != 2 {
+		t.Fatalf("SplitPackage() returned %d parts, want 2", len(parts))
+	}
+
+	wantCounts := []map[string]int{{TypeEvent: 3}, {TypeMatch: 10}}
+	for i, part := range parts {
+		counts := make(map[string]int)
+		for entityType, count := range part.Manifest.Entities {
+			counts[entityType] = count.Count
+		}
+		if !reflect.DeepEqual(counts, wantCounts[i]) {
+			t.Errorf("Part %d counts = %v, want %v", i+1, counts, wantCounts[i])
+		}
+
+		split := part.Manifest.Split
+		if split == nil || split.SourceID != pkg.ID || split.Part != i+1 || split.Parts != 2 {
+			t.Errorf("Part %d split info = %+v", i+1, split)
+			continue
+		}
+		want := map[string][]int{TypeEvent: {1}, TypeMatch: {2}}
+		if !reflect.DeepEqual(split.Locations, want) {
+			t.Errorf("Part %d locations = %v, want %v", i+1, split.Locations, want)
+		}
+	}
+
+	// Each part is a complete package of its own
+	archivePath := filepath.Join(t.TempDir(), "matches.ptd")
+	if err := parts[1].CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	if opened.Manifest.Split == nil || opened.Manifest.Split.Part != 2 {
+		t.Errorf("Opened part split info = %+v", opened.Manifest.Split)
+	}
+	matches, err := ExtractEntities[Match](opened, TypeMatch)
+	if err != nil || len(matches) != 10 {
+		t.Fatalf("ExtractEntities() = %d matches, %v; want 10", len(matches), err)
+	}
+	// The event reference resolves through the part holding events
+	events, _ := ExtractEntities[Event](parts[splitPartOf(opened, TypeEvent)-1], TypeEvent)
+	if len(events) == 0 || events[0].ID != matches[0].Spec.EventID {
+		t.Error("Match event reference does not resolve in the events part")
+	}
+}
+
+// splitPartOf returns the first part that holds an entity type
+func splitPartOf(pkg *Package, entityType string) int {
+	return pkg.Manifest.Split.Locations[entityType][0]
+}
+
+func TestSplitPackage_BySize(t *testing.T) {
+	pkg, matchIDs := newSplitTestPackage(t)
+	archivePath := filepath.Join(t.TempDir(), "source.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines, _ := pkg.readEntityLines(TypeMatch)
+	maxBytes := int64(3 * (len(lines[0]) + 1))
+
+	for _, byType := range []bool{false, true} {
+		parts, err := SplitPackage(opened, SplitOptions{ByEntityType: byType, MaxPartBytes: maxBytes})
+		if err != nil {
+			t.Fatalf("SplitPackage() error = %v", err)
+		}
+
+		var gotIDs []string
+		total := 0
+		for _, part := range parts {
+			defer part.Cleanup()
+			size := int64(0)
+			for _, entry := range part.Manifest.Files {
+				size += entry.Size
+			}
+			if size > maxBytes {
+				t.Errorf("Part %d holds %d bytes, want at most %d", part.Manifest.Split.Part, size, maxBytes)
+			}
+			if byType && len(part.Manifest.Entities) != 1 {
+				t.Errorf("Part %d mixes entity types: %v", part.Manifest.Split.Part, part.Manifest.Entities)
+			}
+			matches, _ := ExtractEntities[Match](part, TypeMatch)
+			for _, match := range matches {
+				gotIDs = append(gotIDs, match.ID)
+			}
+			for _, count := range part.Manifest.Entities {
+				total += count.Count
+			}
+		}
+		if !reflect.DeepEqual(gotIDs, matchIDs) {
+			t.Errorf("byType=%v: matches across parts = %v, want %v", byType, gotIDs, matchIDs)
+		}
+		if total != 13 {
+			t.Errorf("byType=%v: parts hold %d entities, want 13", byType, total)
+		}
+		if len(parts[0].Manifest.Split.Locations[TypeMatch]) < 4 {
+			t.Errorf("byType=%v: matches located in %v, want at least 4 parts", byType, parts[0].Manifest.Split.Locations[TypeMatch])
+		}
+	}
+}
+
+func TestSplitPackage_Errors(t *testing.T) {
+	pkg, _ := newSplitTestPackage(t)
+	if _, err := SplitPackage(pkg, SplitOptions{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("SplitPackage() without options error = %v, want ErrValidation", err)
+	}
+	if _, err := SplitPackage(pkg, SplitOptions{MaxPartBytes: -1}); !errors.Is(err, ErrValidation) {
+		t.Errorf("SplitPackage() with negative size error = %v, want ErrValidation", err)
+	}
+}
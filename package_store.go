@@ -0,0 +1,268 @@
+package ptd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packageStoreExt is the extension of archives kept in a package store
+const packageStoreExt = ".ptd"
+
+// PackageStore keeps package archives by package ID. Get returns an error
+// wrapping ErrPackageNotFound for an unknown ID, as does Delete.
+type PackageStore interface {
+	// Put stores the package under pkg.ID, replacing any previous version
+	Put(ctx context.Context, pkg *Package) error
+
+	// Get opens the package stored under id. The package's ID is set to id.
+	Get(ctx context.Context, id string) (*Package, error)
+
+	// List returns the IDs of the stored packages in sorted order
+	List(ctx context.Context) ([]string, error)
+
+	// Delete removes the package stored under id
+	Delete(ctx context.Context, id string) error
+}
+
+// BlobStore is a flat key-value store of blobs, the extension point for
+// keeping packages in object storage: an adapter for S3, GCS or Azure Blob
+// Storage implements BlobStore on its client, and NewBlobPackageStore turns
+// it into a PackageStore. Get and Delete return an error wrapping
+// fs.ErrNotExist for a missing key.
+type BlobStore interface {
+	// Put stores the blob read from r under key
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the blob stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// List returns the keys starting with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Delete removes the blob stored under key
+	Delete(ctx context.Context, key string) error
+}
+
+// FilePackageStore is a PackageStore keeping each package as a ZIP archive
+// named "<id>.ptd" in a directory
+type FilePackageStore struct {
+	dir string
+}
+
+// NewFilePackageStore creates a store in dir, creating the directory if needed
+func NewFilePackageStore(dir string) (*FilePackageStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &FilePackageStore{dir: dir}, nil
+}
+
+// Put stores the package, replacing the previous archive atomically
+func (s *FilePackageStore) Put(ctx context.Context, pkg *Package) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name, err := packageStoreKey(pkg.ID)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".ptd-put-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := pkg.CreateArchiveTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(file.Name(), filepath.Join(s.dir, name)); err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+	return nil
+}
+
+// Get opens the stored archive in place, like OpenPackage
+func (s *FilePackageStore) Get(ctx context.Context, id string) (*Package, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	name, err := packageStoreKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(s.dir, name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, id)
+	}
+	pkg, err := OpenPackage(path)
+	if err != nil {
+		return nil, err
+	}
+	pkg.ID = id
+	return pkg, nil
+}
+
+// List returns the IDs of the archives in the store directory
+func (s *FilePackageStore) List(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list store directory: %w", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, packageStoreExt) {
+			ids = append(ids, strings.TrimSuffix(name, packageStoreExt))
+		}
+	}
+	return ids, nil
+}
+
+// Delete removes the stored archive
+func (s *FilePackageStore) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name, err := packageStoreKey(id)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(s.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrPackageNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete archive: %w", err)
+	}
+	return nil
+}
+
+// BlobPackageStore is a PackageStore keeping each package as a ZIP archive
+// in a BlobStore, under the key "<prefix><id>.ptd"
+type BlobPackageStore struct {
+	blobs  BlobStore
+	prefix string
+}
+
+// NewBlobPackageStore creates a store keeping packages in blobs under prefix,
+// such as "packages/"
+func NewBlobPackageStore(blobs BlobStore, prefix string) *BlobPackageStore {
+	return &BlobPackageStore{blobs: blobs, prefix: prefix}
+}
+
+// Put streams the package's archive to the blob store
+func (s *BlobPackageStore) Put(ctx context.Context, pkg *Package) error {
+	name, err := packageStoreKey(pkg.ID)
+	if err != nil {
+		return err
+	}
+
+	reader, writer := io.Pipe()
+	written := make(chan error, 1)
+	go func() {
+		err := pkg.CreateArchiveTo(writer)
+		writer.CloseWithError(err)
+		written <- err
+	}()
+
+	err = s.blobs.Put(ctx, s.prefix+name, reader)
+	// Unblock the archive writer if the upload stopped reading
+	reader.CloseWithError(io.ErrClosedPipe)
+	if writeErr := <-written; writeErr != nil && !errors.Is(writeErr, io.ErrClosedPipe) {
+		return writeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+	return nil
+}
+
+// Get downloads the package's archive and opens it like PackageFromReader
+func (s *BlobPackageStore) Get(ctx context.Context, id string) (*Package, error) {
+	name, err := packageStoreKey(id)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := s.blobs.Get(ctx, s.prefix+name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archive: %w", err)
+	}
+	defer blob.Close()
+
+	pkg, err := PackageFromReader(blob)
+	if err != nil {
+		return nil, err
+	}
+	pkg.ID = id
+	return pkg, nil
+}
+
+// List returns the IDs of the packages under the store's prefix
+func (s *BlobPackageStore) List(ctx context.Context) ([]string, error) {
+	keys, err := s.blobs.List(ctx, s.prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	var ids []string
+	for _, key := range keys {
+		id, ok := strings.CutPrefix(key, s.prefix)
+		if !ok || !strings.HasSuffix(id, packageStoreExt) {
+			continue
+		}
+		id = strings.TrimSuffix(id, packageStoreExt)
+		if _, err := packageStoreKey(id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Delete removes the package's archive from the blob store
+func (s *BlobPackageStore) Delete(ctx context.Context, id string) error {
+	name, err := packageStoreKey(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.blobs.Delete(ctx, s.prefix+name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrPackageNotFound, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete archive: %w", err)
+	}
+	return nil
+}
+
+// packageStoreKey returns the archive name of a package ID, which must be a
+// single path element not starting with a dot
+func packageStoreKey(id string) (string, error) {
+	if id == "" || strings.HasPrefix(id, ".") || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("%w: package ID %q cannot be stored", ErrInvalidID, id)
+	}
+	return id + packageStoreExt, nil
+}
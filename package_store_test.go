@@ -0,0 +1,156 @@
+package ptd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memoryBlobStore is a BlobStore holding blobs in memory
+type memoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *memoryBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryBlobStore) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for key := range s.blobs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *memoryBlobStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.blobs[key]; !ok {
+		return fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+	}
+	delete(s.blobs, key)
+	return nil
+}
+
+func TestPackageStore(t *testing.T) {
+	fileStore, err := NewFilePackageStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFilePackageStore() error = %v", err)
+	}
+	blobs := &memoryBlobStore{blobs: map[string][]byte{"other/x.ptd": nil, "packages/notes.txt": nil}}
+	stores := map[string]PackageStore{
+		"file": fileStore,
+		"blob": NewBlobPackageStore(blobs, "packages/"),
+	}
+
+	ctx := context.Background()
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			first, _ := newEditTestPackage(t, 3)
+			second, _ := newEditTestPackage(t, 1)
+			first.ID, second.ID = "b-package", "a-package"
+			for _, pkg := range []*Package{first, second} {
+				if err := store.Put(ctx, pkg); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+
+			ids, err := store.List(ctx)
+			if err != nil || !reflect.DeepEqual(ids, []string{"a-package", "b-package"}) {
+				t.Errorf("List() = %v, %v; want [a-package b-package]", ids, err)
+			}
+
+			pkg, err := store.Get(ctx, "b-package")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if pkg.ID != "b-package" {
+				t.Errorf("ID = %s, want b-package", pkg.ID)
+			}
+			if events, err := ExtractEntities[Event](pkg, TypeEvent); err != nil || len(events) != 3 {
+				t.Errorf("ExtractEntities() = %d events, %v; want 3", len(events), err)
+			}
+
+			// Putting a package under a stored ID replaces the previous version
+			replacement, _ := newEditTestPackage(t, 3)
+			replacement.ID = "a-package"
+			if err := store.Put(ctx, replacement); err != nil {
+				t.Fatalf("Put() of a replacement error = %v", err)
+			}
+			replaced, err := store.Get(ctx, "a-package")
+			if err != nil || replaced.Manifest.Entities[TypeEvent].Count != 3 {
+				t.Errorf("Get() after replace = %v, want 3 events", err)
+			}
+
+			if err := store.Delete(ctx, "a-package"); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := store.Get(ctx, "a-package"); !errors.Is(err, ErrPackageNotFound) {
+				t.Errorf("Get() of deleted package error = %v, want ErrPackageNotFound", err)
+			}
+			if err := store.Delete(ctx, "a-package"); !errors.Is(err, ErrPackageNotFound) {
+				t.Errorf("Delete() of deleted package error = %v, want ErrPackageNotFound", err)
+			}
+			if ids, _ := store.List(ctx); !reflect.DeepEqual(ids, []string{"b-package"}) {
+				t.Errorf("List() after Delete = %v, want [b-package]", ids)
+			}
+
+			for _, id := range []string{"", "../escape", `a\b`, ".hidden"} {
+				if _, err := store.Get(ctx, id); !errors.Is(err, ErrInvalidID) {
+					t.Errorf("Get(%q) error = %v, want ErrInvalidID", id, err)
+				}
+			}
+		})
+	}
+
+	if _, ok := blobs.blobs["packages/b-package.ptd"]; !ok {
+		t.Error("blob store has no packages/b-package.ptd")
+	}
+}
+
+func TestFilePackageStore_Canceled(t *testing.T) {
+	store, err := NewFilePackageStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pkg, _ := newEditTestPackage(t, 1)
+	if err := store.Put(ctx, pkg); !errors.Is(err, context.Canceled) {
+		t.Errorf("Put() error = %v, want context.Canceled", err)
+	}
+	if _, err := store.List(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("List() error = %v, want context.Canceled", err)
+	}
+}
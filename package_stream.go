@@ -0,0 +1,130 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+)
+
+// StreamingPackage builds a package archive in memory, without a working
+// directory, for environments where filesystem access is restricted. Each
+// entity type is compressed into the archive as it is added; Flush adds
+// the manifest and writes the finished archive.
+type StreamingPackage struct {
+	ID       string
+	Created  time.Time
+	Version  string
+	Manifest *Manifest
+
+	buf     bytes.Buffer
+	zip     *zip.Writer
+	flushed bool
+}
+
+// NewStreamingPackage creates an empty in-memory package
+func NewStreamingPackage(description string) (*StreamingPackage, error) {
+	now := time.Now()
+	id := GenerateULID()
+	sp := &StreamingPackage{
+		ID:      id,
+		Created: now,
+		Version: "1.0.0",
+		Manifest: &Manifest{
+			PackageID:     id,
+			FormatVersion: ManifestFormatVersion,
+			Version:       "1.0.0",
+			Created:       now,
+			Creator:       "ptd-go",
+			Description:   description,
+			Files:         make(map[string]*FileEntry),
+			Entities:      make(map[string]EntityCount),
+		},
+	}
+	sp.zip = zip.NewWriter(&sp.buf)
+	return sp, nil
+}
+
+// AddEntities adds the entities of a type to the archive. Unlike
+// Package.AddEntities, a type's file is written once and cannot be
+// replaced: adding the same type twice returns ErrDuplicateEntity.
+func (sp *StreamingPackage) AddEntities(entityType string, entities []interface{}) error {
+	if sp.flushed {
+		return fmt.Errorf("%w: package has already been flushed", ErrExportFailed)
+	}
+
+	relPath := filepath.ToSlash(entityFilePath(entityType))
+	if _, exists := sp.Manifest.Files[relPath]; exists {
+		return fmt.Errorf("%w: %s entities have already been added", ErrDuplicateEntity, entityType)
+	}
+
+	var data bytes.Buffer
+	for _, entity := range entities {
+		line, err := json.Marshal(entity)
+		if err != nil {
+			return fmt.Errorf("failed to marshal entity: %w", err)
+		}
+		data.Write(line)
+		data.WriteByte('\n')
+	}
+
+	if err := sp.writeFile(relPath, data.Bytes()); err != nil {
+		return err
+	}
+
+	sp.Manifest.Files[relPath] = newFileEntry(relPath, data.Bytes(), time.Now())
+	sp.Manifest.Entities[entityType] = EntityCount{
+		Type:  entityType,
+		Count: len(entities),
+	}
+
+	return nil
+}
+
+// SignPackage signs the package manifest with the given signer. Sign after
+// adding all entities and before Flush.
+func (sp *StreamingPackage) SignPackage(signer *Signer) error {
+	return (&Package{Manifest: sp.Manifest}).SignPackage(signer)
+}
+
+// Flush adds the manifest, finalizes the archive and writes it to w. The
+// package cannot be changed afterwards; flushing again writes the same
+// archive.
+func (sp *StreamingPackage) Flush(w io.Writer) (int64, error) {
+	if !sp.flushed {
+		sp.Manifest.stampFormatVersion()
+		sp.Manifest.PackageID = sp.ID
+		manifestData, err := json.MarshalIndent(sp.Manifest, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if err := sp.writeFile("manifest.json", manifestData); err != nil {
+			return 0, err
+		}
+		if err := sp.zip.Close(); err != nil {
+			return 0, fmt.Errorf("failed to finalize archive: %w", err)
+		}
+		sp.flushed = true
+	}
+
+	n, err := w.Write(sp.buf.Bytes())
+	if err != nil {
+		return int64(n), fmt.Errorf("failed to write archive: %w", err)
+	}
+	return int64(n), nil
+}
+
+// writeFile adds a file to the archive
+func (sp *StreamingPackage) writeFile(relPath string, data []byte) error {
+	writer, err := sp.zip.Create(relPath)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", relPath, err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", relPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStreamingPackage(t *testing.T) {
+	sp, err := NewStreamingPackage("Streaming test")
+	if err != nil {
+		t.Fatalf("NewStreamingPackage failed: %v", err)
+	}
+
+	events := []interface{}{
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Men's Singles"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+		},
+	}
+	if err := sp.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("AddEntities failed: %v", err)
+	}
+	if err := sp.AddEntities(TypeEvent, events); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity for a second add, got %v", err)
+	}
+
+	signer, err := NewSigner("stream-key", "Streaming Test")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	if err := sp.SignPackage(signer); err != nil {
+		t.Fatalf("SignPackage failed: %v", err)
+	}
+
+	var archive bytes.Buffer
+	n, err := sp.Flush(&archive)
+	if err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if n != int64(archive.Len()) {
+		t.Errorf("Flush reported %d bytes, wrote %d", n, archive.Len())
+	}
+
+	pkg, err := PackageFromReader(bytes.NewReader(archive.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to read streamed package: %v", err)
+	}
+	if pkg.Manifest.Description != "Streaming test" || pkg.Manifest.Entities[TypeEvent].Count != 1 {
+		t.Errorf("Unexpected manifest: %+v", pkg.Manifest)
+	}
+	if pkg.Manifest.FormatVersion != ManifestFormatVersion || pkg.Manifest.PackageID != sp.ID {
+		t.Errorf("Manifest format version = %q, package ID = %q", pkg.Manifest.FormatVersion, pkg.Manifest.PackageID)
+	}
+	if err := pkg.VerifyPackageSignature(signer.publicKey); err != nil {
+		t.Errorf("Signature does not verify: %v", err)
+	}
+
+	// The package is sealed after Flush; flushing again writes the same archive
+	if err := sp.AddEntities(TypeMatch, nil); !errors.Is(err, ErrExportFailed) {
+		t.Errorf("Expected ErrExportFailed after Flush, got %v", err)
+	}
+	var again bytes.Buffer
+	if _, err := sp.Flush(&again); err != nil || !bytes.Equal(again.Bytes(), archive.Bytes()) {
+		t.Errorf("Second Flush wrote a different archive (err %v)", err)
+	}
+}
@@ -2,6 +2,7 @@ package ptd
 
 import (
 	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"io"
 	"os"
@@ -56,7 +57,7 @@ func TestPackage_AddEntities(t *testing.T) {
 			ID:   GenerateID(TypeTournament),
 			Type: TypeTournament,
 			Spec: Tournament{
-				Name:      "Tournament 1",
+				Name:      MultiName{Default: "Tournament 1"},
 				StartDate: time.Now(),
 				EndDate:   time.Now().Add(24 * time.Hour),
 				Status:    "draft",
@@ -73,7 +74,7 @@ func TestPackage_AddEntities(t *testing.T) {
 			ID:   GenerateID(TypeTournament),
 			Type: TypeTournament,
 			Spec: Tournament{
-				Name:      "Tournament 2",
+				Name:      MultiName{Default: "Tournament 2"},
 				StartDate: time.Now().Add(7 * 24 * time.Hour),
 				EndDate:   time.Now().Add(9 * 24 * time.Hour),
 				Status:    "published",
@@ -142,7 +143,7 @@ func TestPackage_CreateArchive(t *testing.T) {
 		Envelope[Tournament]{
 			ID:   GenerateID(TypeTournament),
 			Type: TypeTournament,
-			Spec: Tournament{Name: "Test Tournament"},
+			Spec: Tournament{Name: MultiName{Default: "Test Tournament"}},
 			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 		},
 	}
@@ -235,7 +236,7 @@ func TestOpenPackage(t *testing.T) {
 			ID:   GenerateID(TypeEvent),
 			Type: TypeEvent,
 			Spec: Event{
-				Name:      "Men's Singles",
+				Name:      MultiName{Default: "Men's Singles"},
 				EventCode: "MS",
 			},
 			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
@@ -272,6 +273,30 @@ func TestOpenPackage(t *testing.T) {
 	} else if count.Count != 1 {
 		t.Errorf("Expected 1 event, got %d", count.Count)
 	}
+
+	// The package keeps its ID across archiving and opening
+	if openedPkg.ID != pkg.ID {
+		t.Errorf("Opened package ID = %s, want %s", openedPkg.ID, pkg.ID)
+	}
+	reopened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to reopen package: %v", err)
+	}
+	if reopened.ID != pkg.ID {
+		t.Errorf("Reopened package ID = %s, want %s", reopened.ID, pkg.ID)
+	}
+	fromJSON, err := openedPkg.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip, err := PackageFromJSON(fromJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer roundTrip.Cleanup()
+	if roundTrip.ID != pkg.ID {
+		t.Errorf("PackageFromJSON() ID = %s, want %s", roundTrip.ID, pkg.ID)
+	}
 }
 
 func TestOpenPackage_InvalidHash(t *testing.T) {
@@ -392,7 +417,7 @@ func BenchmarkPackageCreation(b *testing.B) {
 			tournaments[j] = Envelope[Tournament]{
 				ID:   GenerateID(TypeTournament),
 				Type: TypeTournament,
-				Spec: Tournament{Name: "Tournament"},
+				Spec: Tournament{Name: MultiName{Default: "Tournament"}},
 				Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 			}
 		}
@@ -406,3 +431,67 @@ func BenchmarkPackageCreation(b *testing.B) {
 		os.Remove(archivePath)
 	}
 }
+
+// failingWriter fails after accepting limit bytes
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		return 0, io.ErrShortWrite
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestPackage_WriteTo(t *testing.T) {
+	pkg := NewPackage("Streaming test")
+	defer pkg.Cleanup()
+
+	events := []interface{}{
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Men's Singles"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := pkg.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	if n != int64(buf.Len()) || n == 0 {
+		t.Errorf("WriteTo reported %d bytes, buffer has %d", n, buf.Len())
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Streamed archive is not a valid ZIP: %v", err)
+	}
+	names := make(map[string]bool)
+	for _, file := range reader.File {
+		names[file.Name] = true
+	}
+	if !names["manifest.json"] || !names["event/events.ndjson"] {
+		t.Errorf("Unexpected archive contents: %v", names)
+	}
+
+	// The streamed archive opens like a file archive
+	archivePath := filepath.Join(t.TempDir(), "streamed.ptd")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	if _, err := OpenPackage(archivePath); err != nil {
+		t.Errorf("Streamed archive failed to open: %v", err)
+	}
+
+	if _, err := pkg.WriteTo(&failingWriter{limit: 10}); err == nil {
+		t.Error("Expected error from failing writer")
+	}
+}
@@ -0,0 +1,161 @@
+package ptd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Update applies fn to the package and rolls back all changes if fn fails.
+//
+// Before fn runs, the package's working directory, manifest and settings
+// are snapshotted. If fn returns an error, the working directory is restored
+// from the snapshot and the manifest and settings, such as compression and
+// indexing, are reset; a lock taken with Lock is kept. A multi-entity change
+// (e.g. correcting a Match, its Round and a Standing) is either fully
+// applied or not at all. This is file-system-level transactionality,
+// not ACID: a crash while fn is running can still leave partial state.
+func (p *Package) Update(fn func(*Package) error) error {
+	if p.tempDir == "" {
+		return fmt.Errorf("%w: package has no working directory", ErrInvalidPackage)
+	}
+
+	// Snapshot the working directory
+	snapshotDir, err := os.MkdirTemp("", "ptd-snapshot-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	defer os.RemoveAll(snapshotDir)
+
+	if err := copyDir(p.tempDir, snapshotDir); err != nil {
+		return fmt.Errorf("failed to snapshot package: %w", err)
+	}
+
+	// Snapshot the in-memory state, including settings such as compression
+	saved := &Package{}
+	saved.setState(p)
+	saved.Manifest = p.Manifest.clone()
+
+	fnErr := fn(p)
+	if fnErr == nil {
+		return nil
+	}
+
+	// Roll back the working directory
+	tempDir := saved.tempDir
+	if err := os.RemoveAll(tempDir); err != nil {
+		return errors.Join(fnErr, fmt.Errorf("failed to roll back package: %w", err))
+	}
+	if err := copyDir(snapshotDir, tempDir); err != nil {
+		return errors.Join(fnErr, fmt.Errorf("failed to roll back package: %w", err))
+	}
+
+	// Roll back the in-memory state
+	p.setState(saved)
+
+	return fnErr
+}
+
+// setState replaces the contents of p, settings included, with those of
+// src. The package's lock is kept, so a caller holding it can still release
+// it.
+func (p *Package) setState(src *Package) {
+	p.ID = src.ID
+	p.Created = src.Created
+	p.Version = src.Version
+	p.Manifest = src.Manifest
+	p.tempDir = src.tempDir
+	p.archive = src.archive
+
+	p.compression = src.compression
+	p.compressionLevel = src.compressionLevel
+	p.indexEntities = src.indexEntities
+	p.embedSchemas = src.embedSchemas
+
+	p.appendPath = src.appendPath
+	p.appendFormat = src.appendFormat
+	p.signedManifest = src.signedManifest
+}
+
+// clone returns a deep copy of the manifest
+func (m *Manifest) clone() *Manifest {
+	if m == nil {
+		return nil
+	}
+
+	c := *m
+	c.Files = make(map[string]*FileEntry, len(m.Files))
+	for path, entry := range m.Files {
+		entryCopy := *entry
+		c.Files[path] = &entryCopy
+	}
+	c.Entities = make(map[string]EntityCount, len(m.Entities))
+	for entityType, count := range m.Entities {
+		c.Entities[entityType] = count
+	}
+	if m.Signature != nil {
+		sigCopy := *m.Signature
+		c.Signature = &sigCopy
+	}
+	if m.Split != nil {
+		c.Split = m.Split.clone()
+	}
+	if m.Publisher != nil {
+		c.Publisher = m.Publisher.clone()
+	}
+	c.Tags = slices.Clone(m.Tags)
+	if m.unknown != nil {
+		c.unknown = make(map[string]json.RawMessage, len(m.unknown))
+		for key, value := range m.unknown {
+			c.unknown[key] = value
+		}
+	}
+
+	return &c
+}
+
+// copyDir recursively copies the contents of src into dst
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+// copyFile copies a single file, creating or truncating the destination
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
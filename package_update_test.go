@@ -0,0 +1,127 @@
+package ptd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPackage_Update(t *testing.T) {
+	pkg := NewPackage("Update test")
+	defer pkg.Cleanup()
+
+	tournaments := []interface{}{
+		Envelope[Tournament]{
+			ID:   GenerateID(TypeTournament),
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "Tournament"}},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
+		},
+	}
+
+	err := pkg.Update(func(p *Package) error {
+		return p.AddEntities(TypeTournament, tournaments)
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if pkg.Manifest.Entities[TypeTournament].Count != 1 {
+		t.Errorf("Expected 1 tournament after update, got %d", pkg.Manifest.Entities[TypeTournament].Count)
+	}
+}
+
+func TestPackage_Update_Rollback(t *testing.T) {
+	pkg := NewPackage("Rollback test")
+	defer pkg.Cleanup()
+
+	tournaments := []interface{}{
+		Envelope[Tournament]{
+			ID:   GenerateID(TypeTournament),
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "Original"}},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeTournament, tournaments); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	tournamentFile := filepath.Join(pkg.tempDir, "tournament", "tournaments.ndjson")
+	original, err := os.ReadFile(tournamentFile)
+	if err != nil {
+		t.Fatalf("Failed to read tournament file: %v", err)
+	}
+
+	errBoom := errors.New("boom")
+	err = pkg.Update(func(p *Package) error {
+		// Overwrite tournaments and add events, then fail
+		if err := p.AddEntities(TypeTournament, append(tournaments, tournaments[0])); err != nil {
+			return err
+		}
+		events := []interface{}{
+			Envelope[Event]{
+				ID:   GenerateID(TypeEvent),
+				Type: TypeEvent,
+				Spec: Event{Name: MultiName{Default: "Event"}},
+				Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+			},
+		}
+		if err := p.AddEntities(TypeEvent, events); err != nil {
+			return err
+		}
+		p.Manifest.Description = "changed"
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected errBoom, got %v", err)
+	}
+
+	// Files restored
+	restored, err := os.ReadFile(tournamentFile)
+	if err != nil {
+		t.Fatalf("Failed to read restored file: %v", err)
+	}
+	if string(restored) != string(original) {
+		t.Error("Tournament file was not restored")
+	}
+	if _, err := os.Stat(filepath.Join(pkg.tempDir, "event")); !os.IsNotExist(err) {
+		t.Error("Event directory should have been removed by rollback")
+	}
+
+	// Manifest restored
+	if pkg.Manifest.Description != "Rollback test" {
+		t.Errorf("Description not restored: got %s", pkg.Manifest.Description)
+	}
+	if pkg.Manifest.Entities[TypeTournament].Count != 1 {
+		t.Errorf("Expected 1 tournament after rollback, got %d", pkg.Manifest.Entities[TypeTournament].Count)
+	}
+	if _, exists := pkg.Manifest.Entities[TypeEvent]; exists {
+		t.Error("Event count should not exist after rollback")
+	}
+}
+
+func TestPackage_Update_RollbackSettings(t *testing.T) {
+	pkg := NewPackage("Settings rollback test")
+	defer pkg.Cleanup()
+
+	errBoom := errors.New("boom")
+	err := pkg.Update(func(p *Package) error {
+		if err := p.SetCompression(CompressionZstd, 3); err != nil {
+			return err
+		}
+		p.SetEntityIndex(true)
+		p.SetEmbeddedSchemas(true)
+		p.appendPath = "elsewhere.ptd"
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected fn error, got %v", err)
+	}
+
+	if pkg.compression != "" || pkg.compressionLevel != 0 || pkg.indexEntities || pkg.embedSchemas || pkg.appendPath != "" {
+		t.Errorf("Settings not rolled back: compression=%q level=%d index=%v schemas=%v append=%q",
+			pkg.compression, pkg.compressionLevel, pkg.indexEntities, pkg.embedSchemas, pkg.appendPath)
+	}
+}
@@ -0,0 +1,222 @@
+package ptd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// urlReadAheadBytes is the size of each range request made by OpenPackageURL
+const urlReadAheadBytes = 256 << 10
+
+// URLOptions controls how OpenPackageURLWithOptions fetches a package
+type URLOptions struct {
+	OpenOptions
+
+	// Client makes the HTTP requests; nil means http.DefaultClient
+	Client *http.Client
+
+	// Cache keeps downloaded archives by URL. When set, the archive is
+	// requested with If-None-Match and the cached copy is used while the
+	// server reports it unchanged.
+	Cache URLCache
+}
+
+// URLCache stores package archives downloaded by OpenPackageURLWithOptions
+// under their URL and ETag, for example in memory or in a cache directory
+type URLCache interface {
+	// Get returns the ETag and contents of the archive cached for url,
+	// or ok false if there is none
+	Get(url string) (etag string, archive io.ReaderAt, size int64, ok bool)
+
+	// Put stores the archive read from r, downloaded from url with the
+	// given ETag, replacing any previous copy
+	Put(url, etag string, r io.Reader) error
+}
+
+// OpenPackageURL opens and verifies the package archive at an HTTP(S) URL
+// without downloading it to a file first. If the server supports range
+// requests the archive is read in place, otherwise it is buffered like
+// PackageFromReader. ctx governs the requests made while the package is in
+// use, as entities of a ranged archive are fetched on demand.
+func OpenPackageURL(ctx context.Context, url string) (*Package, error) {
+	return OpenPackageURLWithOptions(ctx, url, URLOptions{})
+}
+
+// OpenPackageURLWithOptions is OpenPackageURL with explicit options.
+// Combined with VerifyDeferred, only the parts of a ranged archive that are
+// read are fetched.
+func OpenPackageURLWithOptions(ctx context.Context, url string, opts URLOptions) (*Package, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	if opts.Cache == nil && opts.DecryptionKey == nil {
+		pkg, ok, err := openRangedURL(ctx, client, url, opts.OpenOptions)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return validateOnOpen(pkg, opts.OpenOptions)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archive: %w", err)
+	}
+	var cachedETag string
+	if opts.Cache != nil {
+		if etag, _, _, ok := opts.Cache.Get(url); ok && etag != "" {
+			cachedETag = etag
+			req.Header.Set("If-None-Match", etag)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archive: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cachedETag != "":
+		return openCachedURL(opts.Cache, url, opts.OpenOptions)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to fetch archive: %s", resp.Status)
+	}
+
+	if etag := resp.Header.Get("ETag"); opts.Cache != nil && etag != "" {
+		if err := opts.Cache.Put(url, etag, resp.Body); err != nil {
+			return nil, fmt.Errorf("failed to cache archive: %w", err)
+		}
+		return openCachedURL(opts.Cache, url, opts.OpenOptions)
+	}
+	return PackageFromReaderWithOptions(resp.Body, opts.OpenOptions)
+}
+
+// openRangedURL opens the archive at url in place if the server supports
+// range requests. It reports false, without error, if the archive must be
+// downloaded instead.
+func openRangedURL(ctx context.Context, client *http.Client, url string, opts OpenOptions) (*Package, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch archive: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch archive: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.ContentLength <= 0 || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return nil, false, nil
+	}
+
+	reader := &httpRangeReader{ctx: ctx, client: client, url: url, size: resp.ContentLength}
+	if etag := resp.Header.Get("ETag"); !strings.HasPrefix(etag, "W/") {
+		reader.etag = etag
+	}
+	pkg, format, err := openArchive(reader, reader.size, opts.verify())
+	if err != nil {
+		return nil, false, err
+	}
+	pkg.archive = &archiveSource{readerAt: reader, size: reader.size, format: format, deferred: !opts.verify()}
+	return pkg, true, nil
+}
+
+// openCachedURL opens the archive cached for url
+func openCachedURL(cache URLCache, url string, opts OpenOptions) (*Package, error) {
+	_, archive, size, ok := cache.Get(url)
+	if !ok {
+		return nil, fmt.Errorf("failed to open cached archive: %s not in cache", url)
+	}
+	if opts.DecryptionKey != nil {
+		return PackageFromReaderWithOptions(io.NewSectionReader(archive, 0, size), opts)
+	}
+
+	pkg, format, err := openArchive(archive, size, opts.verify())
+	if err != nil {
+		return nil, err
+	}
+	pkg.archive = &archiveSource{readerAt: archive, size: size, format: format, deferred: !opts.verify()}
+	return validateOnOpen(pkg, opts)
+}
+
+// httpRangeReader reads a remote archive with HTTP range requests,
+// fetching urlReadAheadBytes at a time and keeping the last block
+type httpRangeReader struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+	etag   string // strong ETag the archive must keep, if known
+	size   int64
+
+	mu     sync.Mutex
+	offset int64
+	block  []byte
+}
+
+// ReadAt implements io.ReaderAt
+func (r *httpRangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("failed to read archive: negative offset %d", off)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for n < len(p) && off < r.size {
+		if off < r.offset || off >= r.offset+int64(len(r.block)) {
+			if err := r.fetch(off); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.block[off-r.offset:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fetch reads the block of the archive starting at off
+func (r *httpRangeReader) fetch(off int64) error {
+	length := min(urlReadAheadBytes, r.size-off)
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+length-1))
+	if r.etag != "" {
+		// A changed archive is sent whole rather than as the range
+		req.Header.Set("If-Range", r.etag)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("%w: range request returned %s, the archive may have changed", ErrInvalidPackage, resp.Status)
+	}
+
+	if int64(cap(r.block)) < length {
+		r.block = make([]byte, length)
+	}
+	r.block = r.block[:length]
+	if _, err := io.ReadFull(resp.Body, r.block); err != nil {
+		r.block = r.block[:0]
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	r.offset = off
+	return nil
+}
@@ -0,0 +1,170 @@
+package ptd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryURLCache is a URLCache holding archives in memory
+type memoryURLCache struct {
+	etags    map[string]string
+	archives map[string][]byte
+}
+
+func (c *memoryURLCache) Get(url string) (string, io.ReaderAt, int64, bool) {
+	archive, ok := c.archives[url]
+	return c.etags[url], bytes.NewReader(archive), int64(len(archive)), ok
+}
+
+func (c *memoryURLCache) Put(url, etag string, r io.Reader) error {
+	archive, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c.etags[url], c.archives[url] = etag, archive
+	return nil
+}
+
+// urlTestServer serves an archive, recording the requests it receives
+type urlTestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	statuses []int
+}
+
+func newURLTestServer(t *testing.T, archive []byte, ranges bool) *urlTestServer {
+	t.Helper()
+	s := &urlTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		if ranges {
+			w.Header().Set("ETag", `"v1"`)
+			http.ServeContent(recorder, r, "package.ptd", time.Time{}, bytes.NewReader(archive))
+		} else {
+			w.Write(archive)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.statuses = append(s.statuses, recorder.status)
+		s.mu.Unlock()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func TestOpenPackageURL(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 3)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ranges := range []bool{true, false} {
+		server := newURLTestServer(t, data, ranges)
+		pkg, err := OpenPackageURL(context.Background(), server.URL+"/package.ptd")
+		if err != nil {
+			t.Fatalf("OpenPackageURL(ranges=%v) error = %v", ranges, err)
+		}
+		events, err := ExtractEntities[Event](pkg, TypeEvent)
+		if err != nil || len(events) != 3 {
+			t.Errorf("ExtractEntities(ranges=%v) = %d events, %v; want 3", ranges, len(events), err)
+		}
+
+		for i, req := range server.requests {
+			if ranges && req.Method == http.MethodGet && (req.Header.Get("Range") == "" || server.statuses[i] != http.StatusPartialContent) {
+				t.Errorf("request %d fetched the whole archive, want a range request", i)
+			}
+		}
+	}
+}
+
+func TestOpenPackageURL_Changed(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 3)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "package.ptd", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	pkg, err := OpenPackageURLWithOptions(context.Background(), server.URL, URLOptions{
+		OpenOptions: OpenOptions{Verification: VerifyDeferred},
+	})
+	if err != nil {
+		t.Fatalf("OpenPackageURLWithOptions() error = %v", err)
+	}
+	pkg.archive.readerAt.(*httpRangeReader).block = nil
+
+	etag = `"v2"`
+	if _, err := ExtractEntities[Event](pkg, TypeEvent); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("ExtractEntities() error = %v, want ErrInvalidPackage", err)
+	}
+}
+
+func TestOpenPackageURL_Cache(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 3)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newURLTestServer(t, data, true)
+	cache := &memoryURLCache{etags: map[string]string{}, archives: map[string][]byte{}}
+	opts := URLOptions{Cache: cache}
+
+	for i := 0; i < 2; i++ {
+		pkg, err := OpenPackageURLWithOptions(context.Background(), server.URL, opts)
+		if err != nil {
+			t.Fatalf("OpenPackageURLWithOptions() error = %v", err)
+		}
+		if events, err := ExtractEntities[Event](pkg, TypeEvent); err != nil || len(events) != 3 {
+			t.Errorf("ExtractEntities() = %d events, %v; want 3", len(events), err)
+		}
+	}
+
+	if cache.etags[server.URL] != `"v1"` || !bytes.Equal(cache.archives[server.URL], data) {
+		t.Errorf("cache holds %q and %d bytes, want the archive with its ETag", cache.etags[server.URL], len(cache.archives[server.URL]))
+	}
+	if len(server.statuses) != 2 || server.statuses[0] != http.StatusOK || server.statuses[1] != http.StatusNotModified {
+		t.Errorf("server responded %v, want [200 304]", server.statuses)
+	}
+	if got := server.requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q, want \"v1\"", got)
+	}
+}
+
+func TestOpenPackageURL_Errors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	if _, err := OpenPackageURL(context.Background(), server.URL); err == nil {
+		t.Error("OpenPackageURL() of a missing archive succeeded")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OpenPackageURL(ctx, server.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("OpenPackageURL() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,121 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// InvalidEntity locates an entity that failed validation on open
+type InvalidEntity struct {
+	File string           // Package-relative path of the entity file
+	Line int              // Line of the entity in the file, from 1
+	Err  *ValidationError // Why the entity is invalid
+}
+
+// PackageValidationError reports the invalid entities found when a package
+// is opened with OpenOptions.Validator. It wraps ErrValidation.
+type PackageValidationError struct {
+	Entities []InvalidEntity
+}
+
+// Error implements the error interface
+func (e *PackageValidationError) Error() string {
+	if len(e.Entities) == 0 {
+		return ErrValidation.Error()
+	}
+	first := e.Entities[0]
+	msg := fmt.Sprintf("%v: %s:%d: %v", ErrValidation, first.File, first.Line, first.Err)
+	if more := len(e.Entities) - 1; more > 0 {
+		msg += fmt.Sprintf(" (and %d more)", more)
+	}
+	return msg
+}
+
+// Unwrap returns ErrValidation
+func (e *PackageValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// validateOnOpen runs the checks opts asks for on a newly opened package:
+// entity counts, then entity validation, then the embedded schemas. The
+// package is cleaned up if a check fails.
+func validateOnOpen(pkg *Package, opts OpenOptions) (*Package, error) {
+	if err := checkOnOpen(pkg, opts); err != nil {
+		pkg.Cleanup()
+		return nil, err
+	}
+	return pkg, nil
+}
+
+// checkOnOpen returns the first failure of the checks of validateOnOpen
+func checkOnOpen(pkg *Package, opts OpenOptions) error {
+	if opts.VerifyCounts {
+		discrepancies, err := pkg.VerifyCounts()
+		if err != nil {
+			return err
+		}
+		if len(discrepancies) > 0 {
+			d := discrepancies[0]
+			return fmt.Errorf("%w: %s has %d entities, manifest lists %d", ErrManifestInvalid, d.Type, d.Actual, d.Manifest)
+		}
+	}
+
+	if opts.Validator != nil {
+		invalid, err := opts.Validator.validateEntityFiles(pkg)
+		if err != nil {
+			return err
+		}
+		if len(invalid) > 0 {
+			return &PackageValidationError{Entities: invalid}
+		}
+	}
+	if opts.ValidateEmbeddedSchemas {
+		invalid, err := validateEmbeddedSchemas(pkg)
+		if err != nil {
+			return err
+		}
+		if len(invalid) > 0 {
+			return &PackageValidationError{Entities: invalid}
+		}
+	}
+	return nil
+}
+
+// validateEntityFiles validates the envelopes of the package's entity files
+// line by line, without holding a whole file in memory. Entities must also
+// be of the type of their file.
+func (v *SchemaValidator) validateEntityFiles(pkg *Package) ([]InvalidEntity, error) {
+	var invalid []InvalidEntity
+	for _, entityType := range pkg.entityTypes() {
+		file := entityFilePath(entityType)
+		report := func(line int, err *ValidationError) {
+			invalid = append(invalid, InvalidEntity{File: file, Line: line, Err: err})
+		}
+
+		err := pkg.scanEntityLines(entityType, func(n int, line []byte) bool {
+			var envelope Envelope[map[string]interface{}]
+			if err := json.Unmarshal(line, &envelope); err != nil {
+				report(n, &ValidationError{EntityType: entityType, Message: err.Error(), Err: ErrInvalidFormat})
+				return true
+			}
+			if envelope.Type != entityType {
+				report(n, &ValidationError{
+					EntityID:   envelope.ID,
+					EntityType: envelope.Type,
+					FieldPath:  "type",
+					Message:    fmt.Sprintf("entity of type %q in %s", envelope.Type, file),
+					Err:        ErrInvalidType,
+				})
+				return true
+			}
+			if err := v.ValidateEnvelope(&envelope); err != nil {
+				report(n, asValidationError(err))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return invalid, nil
+}
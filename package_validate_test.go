@@ -0,0 +1,101 @@
+package ptd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newValidateTestArchive writes an archive whose event file holds the given lines
+func newValidateTestArchive(t *testing.T, lines ...[]byte) string {
+	t.Helper()
+	pkg, _ := newEditTestPackage(t, 1)
+	data := append(bytes.Join(lines, []byte("\n")), '\n')
+	if err := os.WriteFile(filepath.Join(pkg.tempDir, entityFilePath(TypeEvent)), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "validate.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	return archivePath
+}
+
+func TestOpenPackage_Validator(t *testing.T) {
+	event := func(id, tournamentID string) []byte {
+		data, err := json.Marshal(Envelope[Event]{
+			ID:   id,
+			Type: TypeEvent,
+			Spec: Event{TournamentID: tournamentID, Name: MultiName{Default: "Event"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0", Version: 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	tournamentID := GenerateID(TypeTournament)
+	valid := event(GenerateID(TypeEvent), tournamentID)
+	missingField := event(GenerateID(TypeEvent), "")
+	wrongType := bytes.Replace(event(GenerateID(TypeEvent), tournamentID), []byte(`"type":"event"`), []byte(`"type":"match"`), 1)
+
+	opts := OpenOptions{Validator: NewSchemaValidator(false)}
+
+	validPath := newValidateTestArchive(t, valid, valid)
+	pkg, err := OpenPackageWithOptions(validPath, opts)
+	if err != nil {
+		t.Fatalf("OpenPackageWithOptions() of a valid package error = %v", err)
+	}
+	pkg.Cleanup()
+
+	invalidPath := newValidateTestArchive(t, valid, nil, missingField, []byte("{not json"), wrongType)
+	data, err := os.ReadFile(invalidPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a validator the package opens
+	if _, err := OpenPackage(invalidPath); err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+
+	open := map[string]func() (*Package, error){
+		"file":   func() (*Package, error) { return OpenPackageWithOptions(invalidPath, opts) },
+		"reader": func() (*Package, error) { return PackageFromReaderWithOptions(bytes.NewReader(data), opts) },
+		"deferred": func() (*Package, error) {
+			return OpenPackageWithOptions(invalidPath, OpenOptions{Validator: opts.Validator, Verification: VerifyDeferred})
+		},
+	}
+	for name, open := range open {
+		t.Run(name, func(t *testing.T) {
+			pkg, err := open()
+			if pkg != nil {
+				t.Error("invalid package returned")
+			}
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("error = %v, want ErrValidation", err)
+			}
+			var report *PackageValidationError
+			if !errors.As(err, &report) {
+				t.Fatalf("error = %T, want *PackageValidationError", err)
+			}
+
+			want := []struct {
+				line int
+				err  error
+			}{{3, ErrMissingField}, {4, ErrInvalidFormat}, {5, ErrInvalidType}}
+			if len(report.Entities) != len(want) {
+				t.Fatalf("got %d invalid entities, want %d: %v", len(report.Entities), len(want), err)
+			}
+			for i, w := range want {
+				got := report.Entities[i]
+				if got.File != entityFilePath(TypeEvent) || got.Line != w.line || !errors.Is(got.Err, w.err) {
+					t.Errorf("entity %d = %s:%d %v, want line %d %v", i, got.File, got.Line, got.Err, w.line, w.err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package ptd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// VerificationMode selects when the hashes of an archive's files are checked
+type VerificationMode int
+
+const (
+	// VerifyOnOpen checks every file when the archive is opened
+	VerifyOnOpen VerificationMode = iota
+	// VerifyDeferred reads only the manifest when the archive is opened and
+	// checks each file when it is first read to its end. Data is returned
+	// before the check, whose failure surfaces as an error at the end of
+	// the file; call VerifyAll to check the whole package.
+	VerifyDeferred
+)
+
+// VerifyAll checks the hashes of all files of a package opened with
+// VerifyDeferred against its manifest, returning an error wrapping
+// ErrHashMismatch for the first file that differs. A package opened with
+// VerifyOnOpen was verified already, and a package built in a working
+// directory has no archive to verify; for both VerifyAll returns nil.
+func (p *Package) VerifyAll() error {
+	if p.archive == nil || !p.archive.deferred {
+		return nil
+	}
+
+	r, size, closer, err := p.archive.contents()
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	return forEachArchiveFile(r, size, p.archive.format, func(name string, file io.Reader) error {
+		if name == "manifest.json" || !p.archive.needsVerification(name) {
+			return nil
+		}
+		entry, ok := p.Manifest.Files[name]
+		if !ok {
+			return fmt.Errorf("unexpected file in package: %s", name)
+		}
+
+		hasher := sha256.New()
+		if _, err := io.Copy(hasher, file); err != nil {
+			return fmt.Errorf("failed to read file %s: %w", name, err)
+		}
+		if hex.EncodeToString(hasher.Sum(nil)) != entry.Hash {
+			return fmt.Errorf("%w for file %s", ErrHashMismatch, name)
+		}
+		p.archive.markVerified(name)
+		return nil
+	})
+}
+
+// needsVerification reports whether a file's hash is still to be checked
+func (s *archiveSource) needsVerification(relPath string) bool {
+	if !s.deferred {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.verified[filepath.ToSlash(relPath)]
+}
+
+// markVerified records that a file's hash matched the manifest
+func (s *archiveSource) markVerified(relPath string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.verified == nil {
+		s.verified = make(map[string]bool)
+	}
+	s.verified[filepath.ToSlash(relPath)] = true
+}
+
+// verifyingReader wraps a file of the archive so that its hash is checked
+// against the manifest once it has been read to its end
+func (p *Package) verifyingReader(relPath string, file io.ReadCloser) io.ReadCloser {
+	name := filepath.ToSlash(relPath)
+	var want string
+	if entry, ok := p.Manifest.Files[name]; ok {
+		want = entry.Hash
+	}
+	return &hashCheckingReader{ReadCloser: file, name: name, want: want, hasher: sha256.New(), archive: p.archive}
+}
+
+// hashCheckingReader hashes what is read and compares at the end of the file
+type hashCheckingReader struct {
+	io.ReadCloser
+	name    string
+	want    string
+	hasher  hash.Hash
+	archive *archiveSource
+}
+
+func (r *hashCheckingReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.hasher.Write(b[:n])
+	if errors.Is(err, io.EOF) {
+		if hex.EncodeToString(r.hasher.Sum(nil)) != r.want {
+			return n, fmt.Errorf("%w for file %s", ErrHashMismatch, r.name)
+		}
+		r.archive.markVerified(r.name)
+	}
+	return n, err
+}
+
+// CountDiscrepancy describes an entity type whose count in the manifest
+// differs from the number of entities in its NDJSON file
+type CountDiscrepancy struct {
+	Type     string `json:"type"`
+	Manifest int    `json:"manifest"` // Count in Manifest.Entities
+	Actual   int    `json:"actual"`   // Non-blank lines in the type's file
+}
+
+// VerifyCounts counts the NDJSON lines of each entity type and compares
+// them with Manifest.Entities, returning the types that disagree sorted by
+// type, or nil if all agree. Entity files the manifest lists without a
+// count are reported with a manifest count of 0. Hashes are checked as the
+// files are read if verification was deferred.
+func (p *Package) VerifyCounts() ([]CountDiscrepancy, error) {
+	if p.Manifest == nil {
+		return nil, ErrManifestMissing
+	}
+
+	types := make(map[string]bool)
+	for entityType := range p.Manifest.Entities {
+		types[entityType] = true
+	}
+	for path := range p.Manifest.Files {
+		if entityType := filepath.Dir(path); entityType != "." && path == entityFilePath(entityType) {
+			types[entityType] = true
+		}
+	}
+	sorted := make([]string, 0, len(types))
+	for entityType := range types {
+		sorted = append(sorted, entityType)
+	}
+	sort.Strings(sorted)
+
+	var discrepancies []CountDiscrepancy
+	for _, entityType := range sorted {
+		actual := 0
+		err := p.scanEntityLines(entityType, func(int, []byte) bool {
+			actual++
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if listed := p.Manifest.Entities[entityType].Count; listed != actual {
+			discrepancies = append(discrepancies, CountDiscrepancy{Type: entityType, Manifest: listed, Actual: actual})
+		}
+	}
+	return discrepancies, nil
+}
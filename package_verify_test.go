@@ -0,0 +1,184 @@
+package ptd
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// rewriteZip copies a ZIP archive, passing each file through edit
+func rewriteZip(t *testing.T, archive []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	writer := zip.NewWriter(&out)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := writer.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(edit(f.Name, data))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func TestOpenPackage_DeferredVerification(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 3)
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := rewriteZip(t, data, func(name string, data []byte) []byte {
+		if name == entityFilePath(TypeEvent) {
+			return bytes.ReplaceAll(data, []byte(`"Event"`), []byte(`"Evenx"`))
+		}
+		return data
+	})
+	tamperedPath := filepath.Join(t.TempDir(), "tampered.ptd")
+	if err := os.WriteFile(tamperedPath, tampered, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenPackage(tamperedPath); !errors.Is(err, ErrHashMismatch) {
+		t.Fatalf("OpenPackage() error = %v, want ErrHashMismatch", err)
+	}
+
+	deferred := OpenOptions{Verification: VerifyDeferred}
+	open := map[string]func(t *testing.T, data []byte, path string) *Package{
+		"file": func(t *testing.T, _ []byte, path string) *Package {
+			pkg, err := OpenPackageWithOptions(path, deferred)
+			if err != nil {
+				t.Fatalf("OpenPackageWithOptions() error = %v", err)
+			}
+			return pkg
+		},
+		"memory": func(t *testing.T, data []byte, _ string) *Package {
+			pkg, err := PackageFromReaderWithOptions(bytes.NewReader(data), deferred)
+			if err != nil {
+				t.Fatalf("PackageFromReaderWithOptions() error = %v", err)
+			}
+			return pkg
+		},
+	}
+	for name, open := range open {
+		t.Run(name, func(t *testing.T) {
+			// The manifest of a tampered archive is available, its files are not
+			pkg := open(t, tampered, tamperedPath)
+			if pkg.Manifest.Entities[TypeEvent].Count != 3 {
+				t.Errorf("Manifest counts %d events, want 3", pkg.Manifest.Entities[TypeEvent].Count)
+			}
+			if _, err := ExtractEntities[Event](pkg, TypeEvent); !errors.Is(err, ErrHashMismatch) {
+				t.Errorf("ExtractEntities() error = %v, want ErrHashMismatch", err)
+			}
+			if err := pkg.VerifyAll(); !errors.Is(err, ErrHashMismatch) {
+				t.Errorf("VerifyAll() error = %v, want ErrHashMismatch", err)
+			}
+
+			// An intact archive reads and verifies
+			pkg = open(t, data, archivePath)
+			if events, err := ExtractEntities[Event](pkg, TypeEvent); err != nil || len(events) != 3 {
+				t.Errorf("ExtractEntities() = %d events, %v; want 3", len(events), err)
+			}
+			if pkg.archive.needsVerification(entityFilePath(TypeEvent)) {
+				t.Error("File read to its end is still to be verified")
+			}
+			if err := pkg.VerifyAll(); err != nil {
+				t.Errorf("VerifyAll() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPackage_VerifyAll_NotDeferred(t *testing.T) {
+	archivePath, _ := newEntitiesTestArchive(t, 1)
+	pkg, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.VerifyAll(); err != nil {
+		t.Errorf("VerifyAll() of a verified package error = %v", err)
+	}
+
+	working := NewPackage("Working directory")
+	defer working.Cleanup()
+	if err := working.VerifyAll(); err != nil {
+		t.Errorf("VerifyAll() of a package without archive error = %v", err)
+	}
+}
+
+func TestPackage_VerifyCounts(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 2)
+	archivePath := filepath.Join(t.TempDir(), "counts.ptd")
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+	if discrepancies, err := pkg.VerifyCounts(); err != nil || discrepancies != nil {
+		t.Fatalf("VerifyCounts() = %v, %v; want no discrepancies", discrepancies, err)
+	}
+	if _, err := OpenPackageWithOptions(archivePath, OpenOptions{VerifyCounts: true}); err != nil {
+		t.Fatalf("OpenPackageWithOptions() error = %v", err)
+	}
+
+	// A truncated event file and a match file the manifest does not count
+	eventsPath := filepath.Join(pkg.tempDir, entityFilePath(TypeEvent))
+	events, err := os.ReadFile(eventsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _, _ := bytes.Cut(events, []byte("\n"))
+	if err := os.WriteFile(eventsPath, append(first, "\n\n"...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(pkg.tempDir, TypeMatch), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pkg.tempDir, entityFilePath(TypeMatch)), []byte("{}\n{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.CreateArchive(archivePath); err != nil {
+		t.Fatalf("CreateArchive() error = %v", err)
+	}
+
+	want := []CountDiscrepancy{
+		{Type: TypeEvent, Manifest: 2, Actual: 1},
+		{Type: TypeMatch, Manifest: 0, Actual: 2},
+	}
+	opened, err := OpenPackage(archivePath)
+	if err != nil {
+		t.Fatalf("OpenPackage() error = %v", err)
+	}
+	for name, pkg := range map[string]*Package{"working directory": pkg, "archive": opened} {
+		discrepancies, err := pkg.VerifyCounts()
+		if err != nil {
+			t.Fatalf("%s: VerifyCounts() error = %v", name, err)
+		}
+		if !reflect.DeepEqual(discrepancies, want) {
+			t.Errorf("%s: VerifyCounts() = %+v, want %+v", name, discrepancies, want)
+		}
+	}
+
+	if _, err := OpenPackageWithOptions(archivePath, OpenOptions{VerifyCounts: true}); !errors.Is(err, ErrManifestInvalid) {
+		t.Errorf("OpenPackageWithOptions() error = %v, want ErrManifestInvalid", err)
+	}
+}
@@ -0,0 +1,196 @@
+package ptd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A4 page size in PDF points
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 50
+)
+
+// GenerateMatchPDF renders a match report as a single-page A4 result sheet
+// with a header, the set-by-set score table, duration, officials, signature
+// lines and a generation timestamp. The PDF is written directly using the
+// standard Helvetica fonts, so no external library is needed.
+func GenerateMatchPDF(report *MatchReport) ([]byte, error) {
+	if report == nil {
+		return nil, fmt.Errorf("%w: match report is nil", ErrExportFailed)
+	}
+
+	page := &pdfPage{}
+	y := float64(pdfPageHeight - pdfMargin - 20)
+
+	// Header
+	header := report.Tournament
+	if header == "" {
+		header = report.EventName
+	}
+	page.text(pdfMargin, y, 18, true, header)
+	y -= 22
+
+	var subtitle []string
+	if report.Tournament != "" && report.EventName != "" {
+		subtitle = append(subtitle, report.EventName)
+	}
+	if report.RoundName != "" {
+		subtitle = append(subtitle, report.RoundName)
+	}
+	if report.MatchNumber != "" {
+		subtitle = append(subtitle, "Match "+report.MatchNumber)
+	}
+	if report.Court != "" {
+		subtitle = append(subtitle, "Court "+report.Court)
+	}
+	if len(subtitle) > 0 {
+		page.text(pdfMargin, y, 11, false, strings.Join(subtitle, " - "))
+		y -= 16
+	}
+	page.line(pdfMargin, y, pdfPageWidth-pdfMargin, y)
+	y -= 30
+
+	// Players
+	page.text(pdfMargin, y, 14, true, report.HomeName+" vs "+report.AwayName)
+	y -= 18
+	if report.WinnerName != "" {
+		page.text(pdfMargin, y, 11, false, "Winner: "+report.WinnerName)
+		y -= 16
+	}
+	y -= 14
+
+	// Score table
+	columns := []float64{pdfMargin, pdfMargin + 80, pdfMargin + 290}
+	rowHeight := 20.0
+	page.text(columns[0], y, 11, true, "Set")
+	page.text(columns[1], y, 11, true, report.HomeName)
+	page.text(columns[2], y, 11, true, report.AwayName)
+	page.line(pdfMargin, y-6, pdfPageWidth-pdfMargin, y-6)
+	y -= rowHeight
+
+	var homeSets, awaySets int
+	for i, set := range report.Sets {
+		number := set.SetNumber
+		if number == 0 {
+			number = i + 1
+		}
+		page.text(columns[0], y, 11, false, fmt.Sprintf("%d", number))
+		page.text(columns[1], y, 11, false, fmt.Sprintf("%d", set.HomeScore))
+		page.text(columns[2], y, 11, false, fmt.Sprintf("%d", set.AwayScore))
+		switch {
+		case set.HomeScore > set.AwayScore:
+			homeSets++
+		case set.AwayScore > set.HomeScore:
+			awaySets++
+		}
+		y -= rowHeight
+	}
+
+	page.line(pdfMargin, y+rowHeight-6, pdfPageWidth-pdfMargin, y+rowHeight-6)
+	page.text(columns[0], y, 11, true, "Total")
+	if len(report.Sets) > 0 {
+		page.text(columns[1], y, 11, true, fmt.Sprintf("%d", homeSets))
+		page.text(columns[2], y, 11, true, fmt.Sprintf("%d", awaySets))
+	} else {
+		page.text(columns[1], y, 11, true, report.ScoreString)
+	}
+	y -= 2 * rowHeight
+
+	if report.Duration != "" {
+		page.text(pdfMargin, y, 11, false, "Duration: "+report.Duration)
+		y -= 16
+	}
+	if len(report.Officials) > 0 {
+		page.text(pdfMargin, y, 11, false, "Officials: "+strings.Join(report.Officials, ", "))
+		y -= 16
+	}
+
+	// Signature lines
+	y -= 50
+	half := float64(pdfPageWidth) / 2
+	page.line(pdfMargin, y, half-20, y)
+	page.line(half+20, y, pdfPageWidth-pdfMargin, y)
+	page.text(pdfMargin, y-14, 9, false, "Umpire signature")
+	page.text(half+20, y-14, 9, false, "Referee signature")
+
+	// Footer
+	generatedAt := report.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+	page.text(pdfMargin, pdfMargin, 8, false, "Generated "+generatedAt.UTC().Format("2006-01-02 15:04:05 UTC"))
+
+	return page.document(), nil
+}
+
+// pdfPage accumulates the content stream of a single PDF page
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+// text draws a line of text with its baseline at (x, y)
+func (p *pdfPage) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(s))
+}
+
+// line draws a thin line from (x1, y1) to (x2, y2)
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// document assembles the page into a complete PDF file with a cross-reference table
+func (p *pdfPage) document() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pdfPageWidth, pdfPageHeight),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()),
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfEscape escapes a string for a PDF literal, replacing characters
+// outside Latin-1 with '?'
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package ptd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerateMatchPDF(t *testing.T) {
+	match, event, entries := newTestReportMatch()
+	match.Spec.MatchNumber = "M12"
+	report, err := GenerateMatchReport(match, event, entries)
+	if err != nil {
+		t.Fatalf("GenerateMatchReport failed: %v", err)
+	}
+	report.Tournament = "Summer Open (Finals)"
+
+	pdf, err := GenerateMatchPDF(report)
+	if err != nil {
+		t.Fatalf("GenerateMatchPDF failed: %v", err)
+	}
+	if len(pdf) == 0 {
+		t.Fatal("PDF should not be empty")
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("PDF should start with magic bytes, got %q", pdf[:8])
+	}
+	if !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Error("PDF should end with EOF marker")
+	}
+
+	out := string(pdf)
+	for _, want := range []string{
+		`(Summer Open \(Finals\)) Tj`,
+		"(Men's Singles - Match M12 - Court Table 1) Tj",
+		"(Ma Long vs Fan Zhendong) Tj",
+		"(Umpire signature) Tj",
+		"(Generated ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("PDF missing %q", want)
+		}
+	}
+
+	// The cross-reference table must point at the objects
+	xref := strings.LastIndex(out, "startxref\n")
+	if xref < 0 || !strings.Contains(out[xref:], "\n") {
+		t.Fatal("Missing startxref")
+	}
+	for i, obj := range []string{"1 0 obj", "6 0 obj"} {
+		if !strings.Contains(out, obj) {
+			t.Errorf("Missing object %d", i)
+		}
+	}
+
+	if _, err := GenerateMatchPDF(nil); err == nil {
+		t.Error("Expected error for nil report")
+	}
+}
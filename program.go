@@ -0,0 +1,129 @@
+package ptd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Program is the printed schedule of a tournament: its matches by time and court
+type Program struct {
+	Date      time.Time     `json:"date"`            // Day of the first scheduled match
+	Venue     string        `json:"venue,omitempty"` // Venue name for the program heading
+	TimeSlots []ProgramSlot `json:"time_slots"`
+}
+
+// ProgramSlot is a match on a court at a scheduled time
+type ProgramSlot struct {
+	Time      time.Time `json:"time"`
+	Court     string    `json:"court,omitempty"`
+	MatchInfo string    `json:"match_info"` // e.g., "Men's Singles Final: Ma Long vs Fan Zhendong"
+}
+
+// BuildEventProgram builds the program of the scheduled matches of the
+// given events, ordered by ScheduledAt and then court. Matches of other
+// events and matches without a scheduled time, or that were cancelled, are
+// left out.
+//
+// Each match is described as "EventName RoundName: HomeEntry vs AwayEntry".
+// Round names are taken from rounds; a match whose round is not given is
+// named by its match number instead, and an entry not yet known is shown
+// as "TBD". If venue lists its courts, every match must be on one of them,
+// otherwise ErrValidation is returned.
+func BuildEventProgram(events []Envelope[Event], matches []Envelope[Match], venue *Venue, rounds ...Envelope[Round]) (*Program, error) {
+	eventNames := make(map[string]string, len(events))
+	for _, event := range events {
+		eventNames[event.ID] = event.Spec.Name.Default
+	}
+	roundNames := make(map[string]string, len(rounds))
+	for _, round := range rounds {
+		roundNames[round.ID] = round.Spec.Name
+	}
+
+	program := &Program{}
+	if venue != nil {
+		program.Venue = venue.Name.Default
+	}
+
+	for _, match := range matches {
+		m := match.Spec
+		eventName, ok := eventNames[m.EventID]
+		if !ok || m.ScheduledAt == nil || m.Status == "cancelled" {
+			continue
+		}
+		if venue != nil && len(venue.Courts) > 0 && !contains(venue.Courts, m.Court) {
+			return nil, newValidationError(ErrValidation, TypeMatch, "match.court", "match %s is on court %q, which is not at venue %s", match.ID, m.Court, venue.Name.Default)
+		}
+
+		stage, ok := roundNames[m.RoundID]
+		if !ok {
+			stage = m.MatchNumber
+		}
+		program.TimeSlots = append(program.TimeSlots, ProgramSlot{
+			Time:      *m.ScheduledAt,
+			Court:     m.Court,
+			MatchInfo: fmt.Sprintf("%s: %s vs %s", strings.TrimSpace(eventName+" "+stage), programEntryName(m.HomeEntry), programEntryName(m.AwayEntry)),
+		})
+	}
+
+	sort.SliceStable(program.TimeSlots, func(i, j int) bool {
+		a, b := program.TimeSlots[i], program.TimeSlots[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		return courtLess(a.Court, b.Court)
+	})
+	if len(program.TimeSlots) > 0 {
+		program.Date = truncateToDay(program.TimeSlots[0].Time)
+	}
+
+	return program, nil
+}
+
+// MarshalText renders the program as plain text for printing: a heading
+// for each day, then one line per match with its time and court
+func (p *Program) MarshalText() ([]byte, error) {
+	courtWidth := 0
+	for _, slot := range p.TimeSlots {
+		courtWidth = max(courtWidth, len(slot.Court))
+	}
+
+	var b strings.Builder
+	if p.Venue != "" {
+		fmt.Fprintf(&b, "%s\n", p.Venue)
+	}
+	var day time.Time
+	for _, slot := range p.TimeSlots {
+		if slotDay := truncateToDay(slot.Time); !slotDay.Equal(day) {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s\n", slotDay.Format("Monday, 2 January 2006"))
+			day = slotDay
+		}
+		fmt.Fprintf(&b, "%s  %-*s  %s\n", slot.Time.Format("15:04"), courtWidth, slot.Court, slot.MatchInfo)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// programEntryName returns the display name of an entry, or "TBD"
+func programEntryName(ref *EntryRef) string {
+	if ref == nil || ref.DisplayName == "" {
+		return "TBD"
+	}
+	return ref.DisplayName
+}
+
+// courtLess orders court names numerically where both are numbers, so
+// that court 2 comes before court 10
+func courtLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil && x != y {
+		return x < y
+	}
+	return a < b
+}
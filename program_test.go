@@ -0,0 +1,109 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newProgramTestData() ([]Envelope[Event], []Envelope[Match], []Envelope[Round]) {
+	events := []Envelope[Event]{
+		{ID: "ptd:event:ms", Type: TypeEvent, Spec: Event{Name: MultiName{Default: "Men's Singles"}}},
+		{ID: "ptd:event:ws", Type: TypeEvent, Spec: Event{Name: MultiName{Default: "Women's Singles"}}},
+	}
+	rounds := []Envelope[Round]{
+		{ID: "ptd:round:ms-f", Type: TypeRound, Spec: Round{EventID: "ptd:event:ms", Name: "Final", RoundNumber: 2}},
+		{ID: "ptd:round:ws-sf", Type: TypeRound, Spec: Round{EventID: "ptd:event:ws", Name: "Semifinal", RoundNumber: 1}},
+	}
+
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 7, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	ref := func(name string) *EntryRef { return &EntryRef{EntryID: "ptd:entry:" + name, DisplayName: name} }
+	match := func(event, round, number, court string, scheduled *time.Time, home, away *EntryRef) Envelope[Match] {
+		return Envelope[Match]{ID: "ptd:match:" + number, Type: TypeMatch, Spec: Match{
+			EventID: event, RoundID: round, MatchNumber: number, Court: court, Status: "scheduled",
+			ScheduledAt: scheduled, HomeEntry: home, AwayEntry: away,
+		}}
+	}
+	matches := []Envelope[Match]{
+		match("ptd:event:ms", "ptd:round:ms-f", "MS-F", "1", at(6, 14), ref("Ma Long"), ref("Fan Zhendong")),
+		match("ptd:event:ws", "ptd:round:ws-sf", "WS-SF2", "10", at(5, 10), ref("Chen Meng"), nil),
+		match("ptd:event:ws", "ptd:round:ws-sf", "WS-SF1", "2", at(5, 10), ref("Sun Yingsha"), ref("Hina Hayata")),
+		match("ptd:event:ws", "ptd:round:ws-q", "WS-Q1", "2", at(5, 9), nil, nil),
+		match("ptd:event:ws", "ptd:round:ws-sf", "WS-SF3", "1", nil, nil, nil),
+		match("ptd:event:xd", "", "XD-1", "1", at(5, 9), nil, nil),
+	}
+	cancelled := match("ptd:event:ms", "ptd:round:ms-f", "MS-X", "1", at(6, 9), nil, nil)
+	cancelled.Spec.Status = "cancelled"
+	matches = append(matches, cancelled)
+
+	return events, matches, rounds
+}
+
+func TestBuildEventProgram(t *testing.T) {
+	events, matches, rounds := newProgramTestData()
+	venue := &Venue{Name: MultiName{Default: "Tokyo Metropolitan Gymnasium"}}
+
+	program, err := BuildEventProgram(events, matches, venue, rounds...)
+	if err != nil {
+		t.Fatalf("BuildEventProgram() error = %v", err)
+	}
+	if want := time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC); !program.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", program.Date, want)
+	}
+
+	want := []string{
+		"Women's Singles WS-Q1: TBD vs TBD",
+		"Women's Singles Semifinal: Sun Yingsha vs Hina Hayata",
+		"Women's Singles Semifinal: Chen Meng vs TBD",
+		"Men's Singles Final: Ma Long vs Fan Zhendong",
+	}
+	if len(program.TimeSlots) != len(want) {
+		t.Fatalf("TimeSlots = %+v, want %d slots", program.TimeSlots, len(want))
+	}
+	for i, slot := range program.TimeSlots {
+		if slot.MatchInfo != want[i] {
+			t.Errorf("TimeSlots[%d].MatchInfo = %q, want %q", i, slot.MatchInfo, want[i])
+		}
+	}
+
+	text, err := program.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+	wantText := `Tokyo Metropolitan Gymnasium
+
+Saturday, 5 July 2025
+09:00  2   Women's Singles WS-Q1: TBD vs TBD
+10:00  2   Women's Singles Semifinal: Sun Yingsha vs Hina Hayata
+10:00  10  Women's Singles Semifinal: Chen Meng vs TBD
+
+Sunday, 6 July 2025
+14:00  1   Men's Singles Final: Ma Long vs Fan Zhendong
+`
+	if string(text) != wantText {
+		t.Errorf("MarshalText() =\n%s\nwant\n%s", text, wantText)
+	}
+}
+
+func TestBuildEventProgram_Courts(t *testing.T) {
+	events, matches, rounds := newProgramTestData()
+
+	venue := &Venue{Name: MultiName{Default: "Hall"}, Courts: []string{"1", "2"}}
+	_, err := BuildEventProgram(events, matches, venue, rounds...)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("BuildEventProgram() error = %v, want ErrValidation for court 10", err)
+	}
+
+	venue.Courts = append(venue.Courts, "10")
+	if _, err := BuildEventProgram(events, matches, venue, rounds...); err != nil {
+		t.Errorf("BuildEventProgram() error = %v", err)
+	}
+
+	empty, err := BuildEventProgram(nil, matches, nil)
+	if err != nil || len(empty.TimeSlots) != 0 || !empty.Date.IsZero() {
+		t.Errorf("BuildEventProgram(no events) = %+v, %v", empty, err)
+	}
+}
@@ -0,0 +1,117 @@
+package ptd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive size estimation heuristics
+const (
+	estimatedManifestBytesPerFile = 256 // Manifest bytes per file entry
+	estimatedCompressionRatio     = 0.7 // Compressed size of NDJSON relative to its size
+)
+
+// NewTextProgressBar returns an ArchiveProgress callback that renders a
+// single-line text progress bar to w, suitable for terminal output:
+//
+//	[##########----------]  50% (3/6 files)
+//
+// The bar is redrawn in place using a carriage return; a newline is written
+// once the last file has been written. It also renders the progress of
+// OpenPackageContext.
+func NewTextProgressBar(w io.Writer, width int) func(ArchiveProgress) {
+	if width <= 0 {
+		width = 40
+	}
+
+	return func(p ArchiveProgress) {
+		ratio := 1.0
+		if p.BytesTotal > 0 {
+			ratio = float64(p.BytesWritten) / float64(p.BytesTotal)
+		} else if p.FilesTotal > 0 {
+			ratio = float64(p.FilesWritten) / float64(p.FilesTotal)
+		}
+		if ratio > 1 {
+			ratio = 1
+		}
+
+		filled := int(ratio * float64(width))
+		bar := strings.Repeat("#", filled) + strings.Repeat("-", width-filled)
+		fmt.Fprintf(w, "\r[%s] %3d%% (%d/%d files)", bar, int(ratio*100), p.FilesWritten, p.FilesTotal)
+
+		if p.FilesTotal > 0 && p.FilesWritten >= p.FilesTotal {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// EstimatedTransferTime estimates how long the archive at archivePath takes
+// to upload or download at the given bandwidth in bits per second
+func EstimatedTransferTime(archivePath string, bandwidthBitsPerSec int64) (time.Duration, error) {
+	if bandwidthBitsPerSec <= 0 {
+		return 0, fmt.Errorf("%w: bandwidth must be positive, got %d", ErrValidation, bandwidthBitsPerSec)
+	}
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat archive: %w", err)
+	}
+
+	seconds := float64(info.Size()) * 8 / float64(bandwidthBitsPerSec)
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// EstimatedUncompressedSize returns the total size of the files listed in
+// the package manifest, i.e. the space the package takes once extracted
+func EstimatedUncompressedSize(pkg *Package) int64 {
+	if pkg == nil || pkg.Manifest == nil {
+		return 0
+	}
+	var total int64
+	for _, file := range pkg.Manifest.Files {
+		if file != nil {
+			total += file.Size
+		}
+	}
+	return total
+}
+
+// SizeEstimate forecasts the size of the package archive before it is
+// created: the files in the working directory plus 256 bytes of manifest
+// per file, times an assumed compression ratio of 0.7. The estimate is
+// deliberately conservative and not a guarantee. A package without a
+// working directory, such as one opened with OpenPackage, is estimated
+// from its manifest.
+func (p *Package) SizeEstimate() (int64, error) {
+	var total int64
+	files := 0
+
+	if p.tempDir == "" {
+		total = EstimatedUncompressedSize(p)
+		if p.Manifest != nil {
+			files = len(p.Manifest.Files)
+		}
+	} else {
+		err := filepath.Walk(p.tempDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			// A manifest left by an earlier archive is replaced, so it is estimated below
+			if info.IsDir() || path == filepath.Join(p.tempDir, "manifest.json") {
+				return nil
+			}
+			total += info.Size()
+			files++
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to walk directory: %w", err)
+		}
+	}
+
+	total += int64(files+1) * estimatedManifestBytesPerFile
+	return int64(float64(total) * estimatedCompressionRatio), nil
+}
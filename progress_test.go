@@ -0,0 +1,177 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPackage_CreateArchiveWithProgress(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "ptd-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	pkg := NewPackage("Progress test")
+	defer pkg.Cleanup()
+
+	tournaments := []interface{}{
+		Envelope[Tournament]{
+			ID:   GenerateID(TypeTournament),
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "Tournament"}},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
+		},
+	}
+	events := []interface{}{
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{Name: MultiName{Default: "Event"}},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeTournament, tournaments); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	var updates []ArchiveProgress
+	archivePath := filepath.Join(tmpDir, "progress.ptd")
+	if err := pkg.CreateArchiveWithProgress(archivePath, func(p ArchiveProgress) {
+		updates = append(updates, p)
+	}); err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+
+	// Two entity files plus the manifest
+	if len(updates) != 3 {
+		t.Fatalf("Expected 3 progress updates, got %d", len(updates))
+	}
+
+	last := updates[len(updates)-1]
+	if last.FilesTotal != 3 || last.FilesWritten != 3 {
+		t.Errorf("Unexpected file counts: %+v", last)
+	}
+	if last.BytesWritten != last.BytesTotal {
+		t.Errorf("BytesWritten %d != BytesTotal %d", last.BytesWritten, last.BytesTotal)
+	}
+
+	for i := 1; i < len(updates); i++ {
+		if updates[i].BytesWritten < updates[i-1].BytesWritten {
+			t.Error("BytesWritten should be monotonic")
+		}
+	}
+
+	if _, err := OpenPackage(archivePath); err != nil {
+		t.Errorf("Archive should open cleanly: %v", err)
+	}
+}
+
+func TestNewTextProgressBar(t *testing.T) {
+	var buf bytes.Buffer
+	bar := NewTextProgressBar(&buf, 10)
+
+	bar(ArchiveProgress{FilesTotal: 2, FilesWritten: 1, BytesTotal: 100, BytesWritten: 50})
+	if !strings.Contains(buf.String(), "[#####-----]  50% (1/2 files)") {
+		t.Errorf("Unexpected progress output: %q", buf.String())
+	}
+	if strings.HasSuffix(buf.String(), "\n") {
+		t.Error("Progress bar should not end line before completion")
+	}
+
+	bar(ArchiveProgress{FilesTotal: 2, FilesWritten: 2, BytesTotal: 100, BytesWritten: 100})
+	if !strings.HasSuffix(buf.String(), "[##########] 100% (2/2 files)\n") {
+		t.Errorf("Unexpected final output: %q", buf.String())
+	}
+}
+
+func TestEstimatedTransferTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.ptd")
+	if err := os.WriteFile(path, make([]byte, 125_000), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	// 125 kB is 1 Mbit
+	got, err := EstimatedTransferTime(path, 2_000_000)
+	if err != nil {
+		t.Fatalf("EstimatedTransferTime failed: %v", err)
+	}
+	if got != 500*time.Millisecond {
+		t.Errorf("Expected 500ms, got %v", got)
+	}
+
+	if _, err := EstimatedTransferTime(path, 0); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for zero bandwidth, got %v", err)
+	}
+	if _, err := EstimatedTransferTime(filepath.Join(t.TempDir(), "missing.ptd"), 1000); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+}
+
+func TestEstimatedUncompressedSize(t *testing.T) {
+	pkg := NewPackage("Size test")
+	defer pkg.Cleanup()
+
+	pkg.Manifest.Files = map[string]*FileEntry{
+		"a.ndjson": {Size: 100},
+		"b.ndjson": {Size: 250},
+	}
+	if got := EstimatedUncompressedSize(pkg); got != 350 {
+		t.Errorf("Expected 350, got %d", got)
+	}
+	if got := EstimatedUncompressedSize(nil); got != 0 {
+		t.Errorf("Expected 0 for nil package, got %d", got)
+	}
+}
+
+func TestPackage_SizeEstimate(t *testing.T) {
+	small, _ := newEditTestPackage(t, 1)
+	large, _ := newEditTestPackage(t, 200)
+
+	smallEstimate, err := small.SizeEstimate()
+	if err != nil {
+		t.Fatalf("SizeEstimate failed: %v", err)
+	}
+	largeEstimate, err := large.SizeEstimate()
+	if err != nil {
+		t.Fatalf("SizeEstimate failed: %v", err)
+	}
+	if smallEstimate <= 0 || largeEstimate <= smallEstimate {
+		t.Errorf("Expected estimates to grow with content, got %d and %d", smallEstimate, largeEstimate)
+	}
+
+	// Repetitive NDJSON compresses well, so the estimate is an upper bound
+	path := filepath.Join(t.TempDir(), "large.ptd")
+	if err := large.CreateArchive(path); err != nil {
+		t.Fatalf("CreateArchive failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if largeEstimate < info.Size() {
+		t.Errorf("Estimate %d is below the actual size %d", largeEstimate, info.Size())
+	}
+
+	// The manifest written by CreateArchive does not change the estimate
+	again, err := large.SizeEstimate()
+	if err != nil || again != largeEstimate {
+		t.Errorf("Expected %d after archiving, got %d (err %v)", largeEstimate, again, err)
+	}
+
+	opened, err := OpenPackage(path)
+	if err != nil {
+		t.Fatalf("OpenPackage failed: %v", err)
+	}
+	if got, err := opened.SizeEstimate(); err != nil || got != largeEstimate {
+		t.Errorf("Expected %d for the opened package, got %d (err %v)", largeEstimate, got, err)
+	}
+}
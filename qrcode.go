@@ -0,0 +1,113 @@
+package ptd
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // Scanned QR codes may be JPEG photos
+	_ "image/png"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	gozxingqr "github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR code rendering defaults
+const (
+	QRCodeModuleSize = 8 // Pixels per module in PNG output
+	QRCodeQuietZone  = 4 // Light modules around the symbol
+)
+
+// GenerateQRCode returns a PNG image of a QR code encoding a PTD ID, e.g.
+// for an entry's check-in badge. The code uses error correction level M.
+func GenerateQRCode(id string) ([]byte, error) {
+	code, err := newQRCodeForID(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := code.PNG(-QRCodeModuleSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to encode QR code: %v", ErrExportFailed, err)
+	}
+	return data, nil
+}
+
+// GenerateQRCodeSVG is GenerateQRCode with scalable SVG output. One SVG
+// unit is one module.
+func GenerateQRCodeSVG(id string) ([]byte, error) {
+	code, err := newQRCodeForID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := code.Bitmap() // Includes the quiet zone
+	side := len(modules)
+	var path strings.Builder
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", side, side)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", side, side)
+	fmt.Fprintf(&buf, `<path d="%s" fill="#000000"/>`+"\n", path.String())
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+// ParseQRCodePayload returns the PTD ID encoded in a QR code. data is
+// either an image of the code, such as a PNG from GenerateQRCode or a JPEG
+// photo of a badge, which is decoded, or the text a scanner read from it.
+// Surrounding whitespace is ignored. Returns ErrInvalidFormat if an image
+// holds no readable QR code, and ErrInvalidID if the payload is not a PTD
+// ID.
+func ParseQRCodePayload(data []byte) (string, error) {
+	payload := string(data)
+	if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+		if payload, err = decodeQRCodeImage(img); err != nil {
+			return "", err
+		}
+	}
+
+	id := strings.TrimSpace(payload)
+	if !ValidateID(id) {
+		return "", fmt.Errorf("%w: QR code payload is not a PTD ID: %q", ErrInvalidID, id)
+	}
+	return id, nil
+}
+
+// decodeQRCodeImage returns the text encoded by the QR code in an image
+func decodeQRCodeImage(img image.Image) (string, error) {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to read QR code image: %v", ErrInvalidFormat, err)
+	}
+	reader := gozxingqr.NewQRCodeReader()
+	result, err := reader.Decode(bitmap, nil)
+	if err != nil {
+		// The finder pattern detector can misjudge the size of clean,
+		// computer-generated symbols; read those module by module instead
+		pure := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_PURE_BARCODE: true}
+		if result, err = reader.Decode(bitmap, pure); err != nil {
+			return "", fmt.Errorf("%w: no QR code found in image: %v", ErrInvalidFormat, err)
+		}
+	}
+	return result.GetText(), nil
+}
+
+// newQRCodeForID validates an ID and encodes it as a QR code
+func newQRCodeForID(id string) (*qrcode.QRCode, error) {
+	if !ValidateID(id) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidID, id)
+	}
+	code, err := qrcode.New(id, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to encode QR code: %v", ErrValidation, err)
+	}
+	return code, nil
+}
@@ -0,0 +1,89 @@
+package ptd
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestGenerateQRCode(t *testing.T) {
+	id := GenerateID(TypeEntry)
+
+	data, err := GenerateQRCode(id)
+	if err != nil {
+		t.Fatalf("GenerateQRCode failed: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("Expected non-empty PNG")
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Generated PNG does not decode: %v", err)
+	}
+	code, _ := newQRCodeForID(id)
+	if side := len(code.Bitmap()) * QRCodeModuleSize; img.Bounds().Dx() != side {
+		t.Errorf("Expected %dpx image, got %d", side, img.Bounds().Dx())
+	}
+
+	// An independent decoder reads the ID back
+	if got, err := ParseQRCodePayload(data); err != nil || got != id {
+		t.Errorf("ParseQRCodePayload(PNG) = %q, %v; want %q", got, err, id)
+	}
+
+	svg, err := GenerateQRCodeSVG(id)
+	if err != nil {
+		t.Fatalf("GenerateQRCodeSVG failed: %v", err)
+	}
+	if !bytes.HasPrefix(svg, []byte("<svg")) || !bytes.Contains(svg, []byte("h1v1h-1z")) {
+		t.Errorf("Unexpected SVG: %.100s", svg)
+	}
+
+	if _, err := GenerateQRCode("not an id"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+}
+
+func TestGenerateQRCode_RoundTrip(t *testing.T) {
+	for _, entityType := range []string{TypeMatch, TypeTournament, TypePlayer} {
+		id := GenerateID(entityType)
+		data, err := GenerateQRCode(id)
+		if err != nil {
+			t.Fatalf("GenerateQRCode(%s) failed: %v", id, err)
+		}
+		if got, err := ParseQRCodePayload(data); err != nil || got != id {
+			t.Errorf("ParseQRCodePayload() = %q, %v; want %q", got, err, id)
+		}
+	}
+
+	// IDs too long for any QR code version are rejected
+	if _, err := GenerateQRCode("ptd:match:" + strings.Repeat("x", 3000)); !errors.Is(err, ErrValidation) && !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected an error for an oversized ID, got %v", err)
+	}
+}
+
+func TestParseQRCodePayload(t *testing.T) {
+	id := GenerateID(TypeEntry)
+	if got, err := ParseQRCodePayload([]byte(" " + id + "\n")); err != nil || got != id {
+		t.Errorf("ParseQRCodePayload = %q, %v", got, err)
+	}
+	if _, err := ParseQRCodePayload([]byte("https://example.com")); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+
+	// An image without a QR code
+	blank := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range blank.Pix {
+		blank.Pix[i] = uint8(color.White.Y >> 8)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, blank); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseQRCodePayload(buf.Bytes()); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat for an image without a QR code, got %v", err)
+	}
+}
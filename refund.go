@@ -0,0 +1,58 @@
+package ptd
+
+import (
+	"math"
+	"time"
+)
+
+// RefundPercent returns the percentage of the entry fee refunded for a
+// cancellation at cancelledAt: that of the tier with the largest
+// DaysBeforeStart the cancellation meets, counting whole days before start.
+// Cancellations that meet no tier are not refunded.
+func (p *CancellationPolicy) RefundPercent(start, cancelledAt time.Time) float64 {
+	if p == nil {
+		return 0
+	}
+
+	days := int(math.Floor(start.Sub(cancelledAt).Hours() / 24))
+	best := -1
+	for i, tier := range p.Tiers {
+		if days >= tier.DaysBeforeStart && (best < 0 || tier.DaysBeforeStart > p.Tiers[best].DaysBeforeStart) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return 0
+	}
+	return p.Tiers[best].RefundPercent
+}
+
+// ComputeRefundAmount returns the refund due for an entry cancelled at
+// cancelledAt: the policy's refund percentage, by days before the
+// tournament's StartDate, of the fee the entry pays in the event (see
+// Entry.PaymentAmount). The fee is computed whatever the entry's status,
+// so a cancelled entry is refunded what it paid. If policy is nil, the
+// tournament's CancellationPolicy applies.
+//
+// Returns ErrValidation if the applicable refund percentage is outside 0-100.
+func ComputeRefundAmount(entry Envelope[Entry], event Envelope[Event], tournament Tournament, policy *CancellationPolicy, cancelledAt time.Time) (Money, error) {
+	if policy == nil {
+		policy = tournament.CancellationPolicy
+	}
+
+	if entry.Spec.EventID != event.ID {
+		return Money{}, newValidationError(ErrValidation, TypeEntry, "entry.event_id", "entry is for event %s, not %s", entry.Spec.EventID, event.ID)
+	}
+	fee, err := entry.Spec.entryFee(event)
+	if err != nil {
+		return Money{}, err
+	}
+
+	percent := policy.RefundPercent(tournament.StartDate, cancelledAt)
+	if percent < 0 || percent > 100 {
+		return Money{}, newValidationError(ErrValidation, TypeTournament, "tournament.cancellation_policy", "refund percent %v is outside 0-100", percent)
+	}
+
+	fee.Amount = fee.Amount * percent / 100
+	return fee, nil
+}
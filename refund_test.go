@@ -0,0 +1,76 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestComputeRefundAmount(t *testing.T) {
+	start := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	tournament := Tournament{
+		StartDate: start,
+		CancellationPolicy: &CancellationPolicy{Tiers: []CancellationTier{
+			{DaysBeforeStart: 15, RefundPercent: 50},
+			{DaysBeforeStart: 31, RefundPercent: 100},
+			{DaysBeforeStart: 0, RefundPercent: 0},
+		}},
+	}
+	event := Envelope[Event]{
+		ID:   "ptd:event:1",
+		Type: TypeEvent,
+		Spec: Event{EntryFee: &Money{Amount: 40, Currency: "USD"}, DoublesFeeMultiplier: 2},
+	}
+	entry := Envelope[Entry]{
+		ID:   "ptd:entry:1",
+		Type: TypeEntry,
+		Spec: Entry{EventID: event.ID, EntryType: "doubles", Status: "cancelled"},
+	}
+
+	days := func(n int) time.Time { return start.Add(-time.Duration(n) * 24 * time.Hour) }
+	tests := []struct {
+		name        string
+		cancelledAt time.Time
+		want        float64
+	}{
+		{"more than 30 days", days(45), 80},
+		{"exactly 31 days", days(31), 80},
+		{"30 days", days(30), 40},
+		{"15 days", days(15), 40},
+		{"14 days", days(14), 0},
+		{"after start", start.Add(time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeRefundAmount(entry, event, tournament, nil, tt.cancelledAt)
+			if err != nil {
+				t.Fatalf("ComputeRefundAmount() error = %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != "USD" {
+				t.Errorf("ComputeRefundAmount() = %+v, want %v USD", got, tt.want)
+			}
+		})
+	}
+
+	// An explicit policy overrides the tournament's
+	fullRefund := &CancellationPolicy{Tiers: []CancellationTier{{DaysBeforeStart: 0, RefundPercent: 100}}}
+	if got, err := ComputeRefundAmount(entry, event, tournament, fullRefund, days(1)); err != nil || got.Amount != 80 {
+		t.Errorf("ComputeRefundAmount(explicit policy) = %+v, %v; want 80", got, err)
+	}
+
+	invalid := &CancellationPolicy{Tiers: []CancellationTier{{DaysBeforeStart: 0, RefundPercent: 150}}}
+	if _, err := ComputeRefundAmount(entry, event, tournament, invalid, days(1)); !errors.Is(err, ErrValidation) {
+		t.Errorf("ComputeRefundAmount(150%%) error = %v, want ErrValidation", err)
+	}
+	if got, err := ComputeRefundAmount(entry, event, Tournament{StartDate: start}, nil, days(60)); err != nil || got.Amount != 0 {
+		t.Errorf("ComputeRefundAmount(no policy) = %+v, %v; want 0", got, err)
+	}
+
+	// Free events refund nothing
+	free := Envelope[Event]{ID: "ptd:event:2", Type: TypeEvent}
+	freeEntry := Envelope[Entry]{ID: "ptd:entry:2", Type: TypeEntry, Spec: Entry{EventID: free.ID, Status: "cancelled"}}
+	if got, err := ComputeRefundAmount(freeEntry, free, tournament, nil, days(45)); err != nil || got.Amount != 0 {
+		t.Errorf("ComputeRefundAmount(free event) = %+v, %v; want 0", got, err)
+	}
+}
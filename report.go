@@ -0,0 +1,200 @@
+package ptd
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// MatchReport is a public summary of a completed match
+type MatchReport struct {
+	MatchID      string     `json:"match_id"`
+	MatchNumber  string     `json:"match_number,omitempty"`
+	Tournament   string     `json:"tournament,omitempty"`
+	EventName    string     `json:"event_name"`
+	RoundName    string     `json:"round_name,omitempty"`
+	HomeName     string     `json:"home_name"`
+	AwayName     string     `json:"away_name"`
+	WinnerName   string     `json:"winner_name,omitempty"`
+	ScoreString  string     `json:"score"`
+	Sets         []SetScore `json:"sets,omitempty"`
+	Duration     string     `json:"duration,omitempty"`
+	Court        string     `json:"court,omitempty"`
+	Officials    []string   `json:"officials,omitempty"`
+	HighlightURL string     `json:"highlight_url,omitempty"`
+	GeneratedAt  time.Time  `json:"generated_at"`
+}
+
+// GenerateMatchReport summarizes a completed match. Entry names are taken
+// from the given entries, falling back to the match's EntryRef display
+// names. The match carries only event and round IDs, so Tournament and
+// RoundName are left for callers that have those entities.
+func GenerateMatchReport(match Envelope[Match], event Envelope[Event], entries []Envelope[Entry]) (*MatchReport, error) {
+	m := match.Spec
+	if m.Status != "completed" {
+		return nil, withEntityContext(newValidationError(ErrValidation, TypeMatch, "match.status", "cannot report on %s match", m.Status), match.ID, TypeMatch)
+	}
+	if m.EventID != "" && event.ID != "" && m.EventID != event.ID {
+		return nil, withEntityContext(newValidationError(ErrValidation, TypeMatch, "match.event_id", "match belongs to event %s, not %s", m.EventID, event.ID), match.ID, TypeMatch)
+	}
+
+	names := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if name := entryName(entry.Spec); name != "" {
+			names[entry.ID] = name
+		}
+	}
+	refName := func(ref *EntryRef) string {
+		if ref == nil {
+			return ""
+		}
+		if name, ok := names[ref.EntryID]; ok {
+			return name
+		}
+		if ref.DisplayName != "" {
+			return ref.DisplayName
+		}
+		return ref.EntryID
+	}
+
+	report := &MatchReport{
+		MatchID:      match.ID,
+		MatchNumber:  m.MatchNumber,
+		EventName:    event.Spec.Name.String(),
+		HomeName:     refName(m.HomeEntry),
+		AwayName:     refName(m.AwayEntry),
+		Court:        m.Court,
+		HighlightURL: m.StreamingURL,
+		GeneratedAt:  time.Now(),
+	}
+
+	switch {
+	case m.Winner == "":
+	case m.HomeEntry != nil && m.Winner == m.HomeEntry.EntryID:
+		report.WinnerName = report.HomeName
+	case m.AwayEntry != nil && m.Winner == m.AwayEntry.EntryID:
+		report.WinnerName = report.AwayName
+	default:
+		report.WinnerName = refName(&EntryRef{EntryID: m.Winner})
+	}
+
+	if m.Score != nil {
+		report.ScoreString = m.Score.String()
+		report.Sets = m.Score.Sets
+		if m.Score.Duration != nil {
+			report.Duration = m.Score.Duration.String()
+		}
+	}
+
+	for _, official := range m.Officials {
+		if official.Role != "" {
+			report.Officials = append(report.Officials, fmt.Sprintf("%s (%s)", official.Name, official.Role))
+		} else {
+			report.Officials = append(report.Officials, official.Name)
+		}
+	}
+
+	return report, nil
+}
+
+// matchReportTemplate renders a MatchReport as an embeddable HTML fragment
+var matchReportTemplate = template.Must(template.New("match-report").Parse(`<article class="ptd-match-report" data-match-id="{{.MatchID}}">
+  <header>
+    <h2>{{.HomeName}} vs {{.AwayName}}</h2>
+    <p class="ptd-event">{{.EventName}}{{if .RoundName}} &middot; {{.RoundName}}{{end}}</p>
+  </header>
+  <dl>
+{{- if .WinnerName}}
+    <dt>Winner</dt><dd class="ptd-winner">{{.WinnerName}}</dd>
+{{- end}}
+    <dt>Score</dt><dd class="ptd-score">{{.ScoreString}}</dd>
+{{- if .Duration}}
+    <dt>Duration</dt><dd>{{.Duration}}</dd>
+{{- end}}
+{{- if .Court}}
+    <dt>Court</dt><dd>{{.Court}}</dd>
+{{- end}}
+  </dl>
+{{- if .Officials}}
+  <section class="ptd-officials">
+    <h3>Officials</h3>
+    <ul>
+{{- range .Officials}}
+      <li>{{.}}</li>
+{{- end}}
+    </ul>
+  </section>
+{{- end}}
+  <footer>
+{{- if .HighlightURL}}
+    <a href="{{.HighlightURL}}">Watch highlights</a>
+{{- end}}
+    <time datetime="{{.GeneratedAt.UTC.Format "2006-01-02T15:04:05Z07:00"}}">{{.GeneratedAt.UTC.Format "2 Jan 2006 15:04 MST"}}</time>
+  </footer>
+</article>
+`))
+
+// MarshalHTML renders the report as a semantic HTML fragment suitable for
+// embedding in a web page. All values are HTML-escaped.
+func (r *MatchReport) MarshalHTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := matchReportTemplate.Execute(&buf, r); err != nil {
+		return nil, fmt.Errorf("%w: failed to render match report: %v", ErrExportFailed, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// String formats the score for display, e.g. "3-1 (11-9, 8-11, 11-7, 12-10)"
+// or "W/O"
+func (s *Score) String() string {
+	var sets []string
+	for _, set := range s.Sets {
+		sets = append(sets, fmt.Sprintf("%d-%d", set.HomeScore, set.AwayScore))
+	}
+
+	final := s.Final
+	switch {
+	case final != "":
+	case s.Walkover:
+		final = "W/O"
+	case s.Retirement:
+		final = "RET"
+	case s.Disqualify:
+		final = "DQ"
+	}
+
+	if len(sets) == 0 {
+		return final
+	}
+	if final == "" {
+		return strings.Join(sets, ", ")
+	}
+	return fmt.Sprintf("%s (%s)", final, strings.Join(sets, ", "))
+}
+
+// String formats the duration, e.g. "1h 05m" or "42m 30s"
+func (d *Duration) String() string {
+	if d.Minutes >= 60 {
+		return fmt.Sprintf("%dh %02dm", d.Minutes/60, d.Minutes%60)
+	}
+	if d.Seconds > 0 {
+		return fmt.Sprintf("%dm %02ds", d.Minutes, d.Seconds)
+	}
+	return fmt.Sprintf("%dm", d.Minutes)
+}
+
+// entryName returns the team name, or the entry's player names joined with " / "
+func entryName(entry Entry) string {
+	if entry.Team != nil && entry.Team.Name != "" {
+		return entry.Team.Name
+	}
+	var names []string
+	for _, player := range entry.Players {
+		if name := playerName(player); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, " / ")
+}
@@ -0,0 +1,115 @@
+package ptd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestReportMatch() (Envelope[Match], Envelope[Event], []Envelope[Entry]) {
+	event := Envelope[Event]{ID: GenerateID(TypeEvent), Spec: Event{Name: MultiName{Default: "Men's Singles"}}}
+	home := Envelope[Entry]{ID: GenerateID(TypeEntry), Spec: Entry{Players: []Player{{FirstName: "Ma", LastName: "Long"}}}}
+	away := Envelope[Entry]{ID: GenerateID(TypeEntry), Spec: Entry{Players: []Player{{FirstName: "Fan", LastName: "Zhendong"}}}}
+
+	score := newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10})
+	score.Duration = &Duration{Minutes: 65}
+	match := Envelope[Match]{
+		ID: GenerateID(TypeMatch),
+		Spec: Match{
+			EventID:      event.ID,
+			Status:       "completed",
+			Court:        "Table 1",
+			HomeEntry:    &EntryRef{EntryID: home.ID},
+			AwayEntry:    &EntryRef{EntryID: away.ID},
+			Winner:       home.ID,
+			Score:        score,
+			Officials:    []Official{{Name: "Jane <Ref>", Role: "umpire"}},
+			StreamingURL: "https://example.com/watch?m=1&t=2",
+		},
+	}
+	return match, event, []Envelope[Entry]{home, away}
+}
+
+func TestGenerateMatchReport(t *testing.T) {
+	match, event, entries := newTestReportMatch()
+
+	report, err := GenerateMatchReport(match, event, entries)
+	if err != nil {
+		t.Fatalf("GenerateMatchReport failed: %v", err)
+	}
+
+	if report.EventName != "Men's Singles" || report.HomeName != "Ma Long" || report.AwayName != "Fan Zhendong" {
+		t.Errorf("Unexpected names: %+v", report)
+	}
+	if report.WinnerName != "Ma Long" {
+		t.Errorf("Unexpected winner: %s", report.WinnerName)
+	}
+	if report.ScoreString != "3-1 (11-9, 8-11, 11-7, 12-10)" {
+		t.Errorf("Unexpected score: %s", report.ScoreString)
+	}
+	if report.Duration != "1h 05m" {
+		t.Errorf("Unexpected duration: %s", report.Duration)
+	}
+	if len(report.Officials) != 1 || report.Officials[0] != "Jane <Ref> (umpire)" {
+		t.Errorf("Unexpected officials: %v", report.Officials)
+	}
+
+	// Falls back to EntryRef display names
+	match.Spec.AwayEntry.DisplayName = "FAN Z."
+	report, err = GenerateMatchReport(match, event, entries[:1])
+	if err != nil {
+		t.Fatalf("GenerateMatchReport failed: %v", err)
+	}
+	if report.AwayName != "FAN Z." {
+		t.Errorf("Expected display name fallback, got %s", report.AwayName)
+	}
+
+	match.Spec.Status = "in_progress"
+	if _, err := GenerateMatchReport(match, event, entries); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for unfinished match, got %v", err)
+	}
+}
+
+func TestMatchReport_MarshalHTML(t *testing.T) {
+	match, event, entries := newTestReportMatch()
+	report, err := GenerateMatchReport(match, event, entries)
+	if err != nil {
+		t.Fatalf("GenerateMatchReport failed: %v", err)
+	}
+
+	html, err := report.MarshalHTML()
+	if err != nil {
+		t.Fatalf("MarshalHTML failed: %v", err)
+	}
+
+	out := string(html)
+	for _, want := range []string{
+		`<article class="ptd-match-report"`,
+		"<h2>Ma Long vs Fan Zhendong</h2>",
+		"Men&#39;s Singles",
+		`<dd class="ptd-winner">Ma Long</dd>`,
+		"Jane &lt;Ref&gt; (umpire)",
+		`href="https://example.com/watch?m=1&amp;t=2"`,
+		"<time datetime=",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestScore_String(t *testing.T) {
+	tests := []struct {
+		score Score
+		want  string
+	}{
+		{*newTestScore("3-0", [2]int{11, 5}, [2]int{11, 6}, [2]int{11, 7}), "3-0 (11-5, 11-6, 11-7)"},
+		{Score{Walkover: true}, "W/O"},
+		{Score{Final: "RET", Sets: []SetScore{{HomeScore: 11, AwayScore: 3}}}, "RET (11-3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.score.String(); got != tt.want {
+			t.Errorf("Score.String() = %q, want %q", got, tt.want)
+		}
+	}
+}
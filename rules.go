@@ -0,0 +1,44 @@
+package ptd
+
+// MergeRules returns a copy of base with every non-zero field of override
+// applied. Either may be nil; the result is nil only if both are.
+func MergeRules(base *Rules, override *Rules) *Rules {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	merged := &Rules{}
+	if base != nil {
+		*merged = *base
+	}
+	if override == nil {
+		return merged
+	}
+
+	if override.ScoringSystem != "" {
+		merged.ScoringSystem = override.ScoringSystem
+	}
+	if override.GamePoints != 0 {
+		merged.GamePoints = override.GamePoints
+	}
+	if override.TiebreakAt != 0 {
+		merged.TiebreakAt = override.TiebreakAt
+	}
+	if override.ServiceChange != 0 {
+		merged.ServiceChange = override.ServiceChange
+	}
+	if override.TimeLimit != "" {
+		merged.TimeLimit = override.TimeLimit
+	}
+	if override.CustomRules != "" {
+		merged.CustomRules = override.CustomRules
+	}
+
+	return merged
+}
+
+// EffectiveRules returns the rules that apply to the event: the
+// tournament's rules with the event's overrides applied
+func (e *Event) EffectiveRules(tournament Tournament) *Rules {
+	return MergeRules(tournament.Rules, e.Rules)
+}
@@ -0,0 +1,47 @@
+package ptd
+
+import "testing"
+
+func TestMergeRules(t *testing.T) {
+	base := &Rules{ScoringSystem: "best_of_5", GamePoints: 11, TiebreakAt: 10, ServiceChange: 2, TimeLimit: "10m"}
+	override := &Rules{ScoringSystem: "best_of_7", CustomRules: "Expedite after 10 minutes"}
+
+	merged := MergeRules(base, override)
+	want := Rules{ScoringSystem: "best_of_7", GamePoints: 11, TiebreakAt: 10, ServiceChange: 2, TimeLimit: "10m", CustomRules: "Expedite after 10 minutes"}
+	if *merged != want {
+		t.Errorf("MergeRules() = %+v, want %+v", *merged, want)
+	}
+	if base.ScoringSystem != "best_of_5" || merged == base {
+		t.Error("MergeRules() modified base")
+	}
+
+	if got := MergeRules(nil, override); got == nil || *got != *override || got == override {
+		t.Errorf("MergeRules(nil, override) = %+v, want a copy of override", got)
+	}
+	if got := MergeRules(base, nil); got == nil || *got != *base || got == base {
+		t.Errorf("MergeRules(base, nil) = %+v, want a copy of base", got)
+	}
+	if got := MergeRules(nil, nil); got != nil {
+		t.Errorf("MergeRules(nil, nil) = %+v, want nil", got)
+	}
+}
+
+func TestEvent_EffectiveRules(t *testing.T) {
+	tournament := Tournament{Rules: &Rules{ScoringSystem: "best_of_5", GamePoints: 11}}
+	finals := Event{Rules: &Rules{ScoringSystem: "best_of_7"}}
+
+	rules := finals.EffectiveRules(tournament)
+	if rules.BestOf() != 7 || rules.GamePoints != 11 {
+		t.Errorf("EffectiveRules() = %+v, want best of 7 to 11", rules)
+	}
+
+	// A 4-2 result is only valid in the best-of-7 final
+	score := newTestScore("4-2", [2]int{11, 5}, [2]int{11, 7}, [2]int{9, 11}, [2]int{11, 8}, [2]int{6, 11}, [2]int{11, 9})
+	if err := score.Validate(rules); err != nil {
+		t.Errorf("Validate(effective rules) error = %v", err)
+	}
+	group := Event{}
+	if err := score.Validate(group.EffectiveRules(tournament)); err == nil {
+		t.Error("Validate(tournament rules) accepted 6 sets in a best of 5")
+	}
+}
@@ -4,8 +4,21 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 )
 
+// requiredFields lists, per entity type, the JSON fields that are always
+// serialized. Every other field is optional and tagged omitempty.
+var requiredFields = map[string][]string{
+	TypeTournament: {"name", "start_date", "end_date", "status"},
+	TypeEvent:      {"tournament_id", "name", "event_code", "event_type", "start_date", "end_date", "status"},
+	TypeMatch:      {"event_id", "match_number", "status"},
+	TypeEntry:      {"event_id", "entry_type", "status", "players"},
+	TypePlayer:     {"first_name", "last_name"},
+	TypePhase:      {"event_id", "phase_name", "phase_order"},
+	TypeCoach:      {"first_name", "last_name"},
+}
+
 // SchemaValidator validates PTD entities against their schemas
 type SchemaValidator struct {
 	strictMode bool
@@ -31,17 +44,70 @@ func (v *SchemaValidator) ValidateEntity(entityType string, spec interface{}) er
 		return v.validateEntry(spec)
 	case TypePlayer:
 		return v.validatePlayer(spec)
+	case TypePhase:
+		return v.validatePhase(spec)
+	case TypeCoach:
+		return v.validateCoach(spec)
+	case TypeRound:
+		return v.validateRound(spec)
+	case TypeBracket:
+		return v.validateBracket(spec)
+	case TypeRanking:
+		return v.validateRanking(spec)
+	case TypeVenue:
+		return v.validateVenue(spec)
+	case TypeOrganizer:
+		return v.validateOrganizer(spec)
+	case TypeOfficial:
+		return v.validateOfficial(spec)
 	default:
+		// Custom entity types registered at runtime
+		validator, hasValidator := registeredValidator(entityType)
+		schema, hasSchema := registeredJSONSchema(entityType)
+		if hasValidator {
+			if err := validator.Validate(spec); err != nil || !hasSchema {
+				return withEntityContext(err, "", entityType)
+			}
+		}
+		if hasSchema {
+			value, err := toJSONValue(spec)
+			if err != nil {
+				return withEntityContext(err, "", entityType)
+			}
+			return withEntityContext(schema.validate(value, entityType), "", entityType)
+		}
+
 		// Unknown entity type - allow in non-strict mode
 		if v.strictMode {
-			return fmt.Errorf("%w: unknown entity type: %s", ErrValidation, entityType)
+			return newValidationError(ErrValidation, entityType, "type", "unknown entity type: %s", entityType)
 		}
 		return nil
 	}
 }
 
-// ValidateEnvelope validates an entire envelope (structure + spec)
+// ValidateEnvelope validates an entire envelope (structure + spec).
+// Failures are reported as *ValidationError carrying the envelope's ID and type.
 func (v *SchemaValidator) ValidateEnvelope(envelope interface{}) error {
+	val := reflect.ValueOf(envelope)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	var entityID, entityType string
+	if val.Kind() == reflect.Struct {
+		if f := val.FieldByName("ID"); f.IsValid() && f.Kind() == reflect.String {
+			entityID = f.String()
+		}
+		if f := val.FieldByName("Type"); f.IsValid() && f.Kind() == reflect.String {
+			entityType = f.String()
+		}
+	}
+
+	return withEntityContext(v.validateEnvelope(envelope), entityID, entityType)
+}
+
+// validateEnvelope performs the structural and spec checks for ValidateEnvelope
+func (v *SchemaValidator) validateEnvelope(envelope interface{}) error {
 	// Use reflection to extract fields
 	val := reflect.ValueOf(envelope)
 	if val.Kind() == reflect.Ptr {
@@ -51,45 +117,59 @@ func (v *SchemaValidator) ValidateEnvelope(envelope interface{}) error {
 	// Extract ID field
 	idField := val.FieldByName("ID")
 	if !idField.IsValid() || idField.String() == "" {
-		return fmt.Errorf("%w: missing ID field", ErrValidation)
+		return newValidationError(ErrValidation, "", "id", "missing ID field")
 	}
 
 	// Validate ID format
 	if !ValidateID(idField.String()) {
-		return fmt.Errorf("%w: invalid ID format: %s", ErrValidation, idField.String())
+		return newValidationError(ErrValidation, "", "id", "invalid ID format: %s", idField.String())
 	}
 
 	// Extract Type field
 	typeField := val.FieldByName("Type")
 	if !typeField.IsValid() || typeField.String() == "" {
-		return fmt.Errorf("%w: missing Type field", ErrValidation)
+		return newValidationError(ErrValidation, "", "type", "missing Type field")
 	}
 
 	// Extract Meta field
 	metaField := val.FieldByName("Meta")
 	if !metaField.IsValid() {
-		return fmt.Errorf("%w: missing Meta field", ErrValidation)
+		return newValidationError(ErrValidation, "", "meta", "missing Meta field")
 	}
 
 	// Validate Meta.Schema
 	schemaField := metaField.FieldByName("Schema")
 	if !schemaField.IsValid() || schemaField.String() == "" {
-		return fmt.Errorf("%w: missing Meta.Schema", ErrValidation)
+		return newValidationError(ErrValidation, "", "meta.schema", "missing Meta.Schema")
 	}
 
 	// Validate schema format
 	if err := validateSchemaVersion(schemaField.String()); err != nil {
-		return err
+		return &ValidationError{FieldPath: "meta.schema", Err: err}
+	}
+
+	// Strict mode rejects deprecated schema versions
+	if v.strictMode {
+		if status, err := defaultCompatibilityChecker.Check(schemaField.String()); err == nil && status == CompatibilityDeprecated {
+			return newValidationError(ErrUnsupportedVersion, "", "meta.schema", "schema %s is deprecated", schemaField.String())
+		}
 	}
 
 	// Extract and validate Spec
 	specField := val.FieldByName("Spec")
 	if !specField.IsValid() {
-		return fmt.Errorf("%w: missing Spec field", ErrValidation)
+		return newValidationError(ErrValidation, "", "spec", "missing Spec field")
 	}
 
-	// Validate spec content
-	return v.ValidateEntity(typeField.String(), specField.Interface())
+	// Validate spec content, through a pointer where the envelope was
+	// passed by one so that a player's country is stored in canonical form
+	spec := specField.Interface()
+	if specField.CanAddr() {
+		if player, ok := specField.Addr().Interface().(*Player); ok {
+			spec = player
+		}
+	}
+	return v.ValidateEntity(typeField.String(), spec)
 }
 
 // validateTournament validates a Tournament spec
@@ -101,20 +181,20 @@ func (v *SchemaValidator) validateTournament(spec interface{}) error {
 	}
 
 	// Required fields
-	if tournament.Name == "" {
-		return fmt.Errorf("%w: tournament.name is required", ErrMissingField)
+	if tournament.Name.Default == "" {
+		return newValidationError(ErrMissingField, TypeTournament, "tournament.name", "tournament.name is required")
 	}
 
 	// Validate status
 	validStatuses := []string{"draft", "published", "in_progress", "completed", "cancelled"}
 	if tournament.Status != "" && !contains(validStatuses, tournament.Status) {
-		return fmt.Errorf("%w: invalid tournament.status: %s", ErrValidation, tournament.Status)
+		return newValidationError(ErrValidation, TypeTournament, "tournament.status", "invalid tournament.status: %s", tournament.Status)
 	}
 
 	// Validate dates
 	if !tournament.StartDate.IsZero() && !tournament.EndDate.IsZero() {
 		if tournament.EndDate.Before(tournament.StartDate) {
-			return fmt.Errorf("%w: tournament.end_date must be after start_date", ErrValidation)
+			return newValidationError(ErrValidation, TypeTournament, "tournament.end_date", "tournament.end_date must be after start_date")
 		}
 	}
 
@@ -125,20 +205,19 @@ func (v *SchemaValidator) validateTournament(spec interface{}) error {
 func (v *SchemaValidator) validateTournamentMap(spec interface{}) error {
 	m, ok := spec.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("%w: tournament spec must be object", ErrInvalidFormat)
+		return newValidationError(ErrInvalidFormat, TypeTournament, "tournament", "tournament spec must be object")
 	}
 
 	// Required: name
-	name, ok := m["name"].(string)
-	if !ok || name == "" {
-		return fmt.Errorf("%w: tournament.name is required", ErrMissingField)
+	if multiNameDefault(m["name"]) == "" {
+		return newValidationError(ErrMissingField, TypeTournament, "tournament.name", "tournament.name is required")
 	}
 
 	// Validate status if present
-	if status, ok := m["status"].(string); ok {
+	if status, ok := m["status"].(string); ok && status != "" {
 		validStatuses := []string{"draft", "published", "in_progress", "completed", "cancelled"}
 		if !contains(validStatuses, status) {
-			return fmt.Errorf("%w: invalid tournament.status: %s", ErrValidation, status)
+			return newValidationError(ErrValidation, TypeTournament, "tournament.status", "invalid tournament.status: %s", status)
 		}
 	}
 
@@ -154,30 +233,42 @@ func (v *SchemaValidator) validateEvent(spec interface{}) error {
 
 	// Required fields
 	if event.TournamentID == "" {
-		return fmt.Errorf("%w: event.tournament_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeEvent, "event.tournament_id", "event.tournament_id is required")
 	}
 
-	if event.Name == "" {
-		return fmt.Errorf("%w: event.name is required", ErrMissingField)
+	if event.Name.Default == "" {
+		return newValidationError(ErrMissingField, TypeEvent, "event.name", "event.name is required")
 	}
 
 	// Validate tournament_id format
 	if !ValidateID(event.TournamentID) {
-		return fmt.Errorf("%w: invalid event.tournament_id format", ErrValidation)
+		return newValidationError(ErrValidation, TypeEvent, "event.tournament_id", "invalid event.tournament_id format")
 	}
 
 	// Validate event type
 	validTypes := []string{"singles", "doubles", "team", "mixed"}
 	if event.EventType != "" && !contains(validTypes, event.EventType) {
-		return fmt.Errorf("%w: invalid event.event_type: %s", ErrValidation, event.EventType)
+		return newValidationError(ErrValidation, TypeEvent, "event.event_type", "invalid event.event_type: %s", event.EventType)
 	}
 
 	// Validate gender
 	validGenders := []string{"male", "female", "mixed"}
 	if event.Gender != "" && !contains(validGenders, event.Gender) {
-		return fmt.Errorf("%w: invalid event.gender: %s", ErrValidation, event.Gender)
+		return newValidationError(ErrValidation, TypeEvent, "event.gender", "invalid event.gender: %s", event.Gender)
 	}
 
+	return validateRegistrationWindow(event.RegistrationOpen, event.RegistrationClose, event.StartDate)
+}
+
+// validateRegistrationWindow checks that registration opens before it
+// closes and closes before the event starts. Unset times are not checked.
+func validateRegistrationWindow(opens, closes *time.Time, start time.Time) error {
+	if opens != nil && closes != nil && !opens.Before(*closes) {
+		return newValidationError(ErrValidation, TypeEvent, "event.registration_open", "event.registration_open must be before event.registration_close")
+	}
+	if closes != nil && !start.IsZero() && !closes.Before(start) {
+		return newValidationError(ErrValidation, TypeEvent, "event.registration_close", "event.registration_close must be before event.start_date")
+	}
 	return nil
 }
 
@@ -185,22 +276,35 @@ func (v *SchemaValidator) validateEvent(spec interface{}) error {
 func (v *SchemaValidator) validateEventMap(spec interface{}) error {
 	m, ok := spec.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("%w: event spec must be object", ErrInvalidFormat)
+		return newValidationError(ErrInvalidFormat, TypeEvent, "event", "event spec must be object")
 	}
 
 	// Required: tournament_id
 	tournamentID, ok := m["tournament_id"].(string)
 	if !ok || tournamentID == "" {
-		return fmt.Errorf("%w: event.tournament_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeEvent, "event.tournament_id", "event.tournament_id is required")
 	}
 
 	// Required: name
-	name, ok := m["name"].(string)
-	if !ok || name == "" {
-		return fmt.Errorf("%w: event.name is required", ErrMissingField)
+	if multiNameDefault(m["name"]) == "" {
+		return newValidationError(ErrMissingField, TypeEvent, "event.name", "event.name is required")
 	}
 
-	return nil
+	var start time.Time
+	if startDate := mapTime(m, "start_date"); startDate != nil {
+		start = *startDate
+	}
+	return validateRegistrationWindow(mapTime(m, "registration_open"), mapTime(m, "registration_close"), start)
+}
+
+// mapTime returns the RFC 3339 time at key, or nil if it is absent or not a time
+func mapTime(m map[string]interface{}, key string) *time.Time {
+	value, _ := m[key].(string)
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 // validateMatch validates a Match spec
@@ -212,22 +316,22 @@ func (v *SchemaValidator) validateMatch(spec interface{}) error {
 
 	// Required fields
 	if match.EventID == "" {
-		return fmt.Errorf("%w: match.event_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeMatch, "match.event_id", "match.event_id is required")
 	}
 
 	if match.MatchNumber == "" {
-		return fmt.Errorf("%w: match.match_number is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeMatch, "match.match_number", "match.match_number is required")
 	}
 
 	// Validate status
 	validStatuses := []string{"scheduled", "in_progress", "completed", "cancelled"}
 	if match.Status != "" && !contains(validStatuses, match.Status) {
-		return fmt.Errorf("%w: invalid match.status: %s", ErrValidation, match.Status)
+		return newValidationError(ErrValidation, TypeMatch, "match.status", "invalid match.status: %s", match.Status)
 	}
 
 	// Validate winner if present
 	if match.Winner != "" && !ValidateID(match.Winner) {
-		return fmt.Errorf("%w: invalid match.winner format", ErrValidation)
+		return newValidationError(ErrValidation, TypeMatch, "match.winner", "invalid match.winner format")
 	}
 
 	return nil
@@ -237,13 +341,13 @@ func (v *SchemaValidator) validateMatch(spec interface{}) error {
 func (v *SchemaValidator) validateMatchMap(spec interface{}) error {
 	m, ok := spec.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("%w: match spec must be object", ErrInvalidFormat)
+		return newValidationError(ErrInvalidFormat, TypeMatch, "match", "match spec must be object")
 	}
 
 	// Required: event_id
 	eventID, ok := m["event_id"].(string)
 	if !ok || eventID == "" {
-		return fmt.Errorf("%w: match.event_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeMatch, "match.event_id", "match.event_id is required")
 	}
 
 	return nil
@@ -258,24 +362,46 @@ func (v *SchemaValidator) validateEntry(spec interface{}) error {
 
 	// Required fields
 	if entry.EventID == "" {
-		return fmt.Errorf("%w: entry.event_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeEntry, "entry.event_id", "entry.event_id is required")
 	}
 
 	// Validate entry type
 	validTypes := []string{"individual", "doubles", "team"}
 	if entry.EntryType != "" && !contains(validTypes, entry.EntryType) {
-		return fmt.Errorf("%w: invalid entry.entry_type: %s", ErrValidation, entry.EntryType)
+		return newValidationError(ErrValidation, TypeEntry, "entry.entry_type", "invalid entry.entry_type: %s", entry.EntryType)
 	}
 
 	// Validate status
 	validStatuses := []string{"registered", "confirmed", "withdrawn", "cancelled"}
 	if entry.Status != "" && !contains(validStatuses, entry.Status) {
-		return fmt.Errorf("%w: invalid entry.status: %s", ErrValidation, entry.Status)
+		return newValidationError(ErrValidation, TypeEntry, "entry.status", "invalid entry.status: %s", entry.Status)
 	}
 
 	// Validate players based on entry type
 	if len(entry.Players) == 0 && entry.Team == nil {
-		return fmt.Errorf("%w: entry must have players or team", ErrValidation)
+		return newValidationError(ErrValidation, TypeEntry, "entry.players", "entry must have players or team")
+	}
+
+	return nil
+}
+
+// ValidateEntryWithContext validates an entry against the state of its event.
+// In addition to the regular entry checks, a "confirmed" entry is rejected
+// when the event already holds MaxEntries entries. A MaxEntries of zero
+// means the event is unlimited.
+func (v *SchemaValidator) ValidateEntryWithContext(entry Envelope[Entry], existingEntries []Envelope[Entry], event Envelope[Event]) error {
+	if err := v.validateEntry(entry.Spec); err != nil {
+		return withEntityContext(err, entry.ID, TypeEntry)
+	}
+
+	if entry.Spec.Status != "confirmed" || event.Spec.MaxEntries <= 0 {
+		return nil
+	}
+
+	if len(existingEntries) >= event.Spec.MaxEntries {
+		err := newValidationError(ErrValidation, TypeEntry, "entry.status", "event %s is full (%d/%d entries)", event.ID, len(existingEntries), event.Spec.MaxEntries)
+		err.EntityID = entry.ID
+		return err
 	}
 
 	return nil
@@ -285,20 +411,32 @@ func (v *SchemaValidator) validateEntry(spec interface{}) error {
 func (v *SchemaValidator) validateEntryMap(spec interface{}) error {
 	m, ok := spec.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("%w: entry spec must be object", ErrInvalidFormat)
+		return newValidationError(ErrInvalidFormat, TypeEntry, "entry", "entry spec must be object")
 	}
 
 	// Required: event_id
 	eventID, ok := m["event_id"].(string)
 	if !ok || eventID == "" {
-		return fmt.Errorf("%w: entry.event_id is required", ErrMissingField)
+		return newValidationError(ErrMissingField, TypeEntry, "entry.event_id", "entry.event_id is required")
 	}
 
 	return nil
 }
 
-// validatePlayer validates a Player spec
+// validatePlayer validates a Player spec. The country must be a code
+// accepted by NormalizeCountryCode. The canonical alpha-3 form is stored
+// only when spec is a *Player, as for an *Envelope[Player] passed to
+// ValidateEnvelope; Player values and maps are validated as they are and
+// left unchanged.
 func (v *SchemaValidator) validatePlayer(spec interface{}) error {
+	if player, ok := spec.(*Player); ok && player != nil {
+		if err := v.validatePlayer(*player); err != nil {
+			return err
+		}
+		player.Country, _ = normalizePlayerCountry(player.Country)
+		return nil
+	}
+
 	player, ok := spec.(Player)
 	if !ok {
 		return v.validatePlayerMap(spec)
@@ -306,17 +444,34 @@ func (v *SchemaValidator) validatePlayer(spec interface{}) error {
 
 	// Required fields
 	if player.FirstName == "" && player.LastName == "" && player.DisplayName == "" {
-		return fmt.Errorf("%w: player must have at least one name field", ErrMissingField)
+		return newValidationError(ErrMissingField, TypePlayer, "player.first_name", "player must have at least one name field")
+	}
+
+	if _, err := normalizePlayerCountry(player.Country); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// normalizePlayerCountry returns the canonical form of a player's country,
+// which may be empty
+func normalizePlayerCountry(country string) (string, error) {
+	if country == "" {
+		return "", nil
+	}
+	normalized, err := NormalizeCountryCode(country)
+	if err != nil {
+		return "", newValidationError(ErrValidation, TypePlayer, "player.country", "invalid player.country: %s", country)
+	}
+	return normalized, nil
+}
+
 // validatePlayerMap validates a player from map[string]interface{}
 func (v *SchemaValidator) validatePlayerMap(spec interface{}) error {
 	m, ok := spec.(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("%w: player spec must be object", ErrInvalidFormat)
+		return newValidationError(ErrInvalidFormat, TypePlayer, "player", "player spec must be object")
 	}
 
 	// At least one name field required
@@ -325,7 +480,341 @@ func (v *SchemaValidator) validatePlayerMap(spec interface{}) error {
 	displayName, _ := m["display_name"].(string)
 
 	if firstName == "" && lastName == "" && displayName == "" {
-		return fmt.Errorf("%w: player must have at least one name field", ErrMissingField)
+		return newValidationError(ErrMissingField, TypePlayer, "player.first_name", "player must have at least one name field")
+	}
+
+	if country, ok := m["country"].(string); ok {
+		if _, err := normalizePlayerCountry(country); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePhase validates a TournamentPhase spec
+func (v *SchemaValidator) validatePhase(spec interface{}) error {
+	phase, ok := spec.(TournamentPhase)
+	if !ok {
+		return v.validatePhaseMap(spec)
+	}
+
+	// Required fields
+	if phase.EventID == "" {
+		return newValidationError(ErrMissingField, TypePhase, "phase.event_id", "phase.event_id is required")
+	}
+
+	if phase.PhaseName == "" {
+		return newValidationError(ErrMissingField, TypePhase, "phase.phase_name", "phase.phase_name is required")
+	}
+
+	if phase.PhaseOrder < 1 {
+		return newValidationError(ErrValidation, TypePhase, "phase.phase_order", "phase.phase_order must be at least 1")
+	}
+
+	if phase.AdvancementCount < 0 {
+		return newValidationError(ErrValidation, TypePhase, "phase.advancement_count", "phase.advancement_count cannot be negative")
+	}
+
+	// Validate status
+	validStatuses := []string{"scheduled", "in_progress", "completed"}
+	if phase.Status != "" && !contains(validStatuses, phase.Status) {
+		return newValidationError(ErrValidation, TypePhase, "phase.status", "invalid phase.status: %s", phase.Status)
+	}
+
+	return nil
+}
+
+// validatePhaseMap validates a phase from map[string]interface{}
+func (v *SchemaValidator) validatePhaseMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypePhase, "phase", "phase spec must be object")
+	}
+
+	// Required: event_id, phase_name
+	if eventID, _ := m["event_id"].(string); eventID == "" {
+		return newValidationError(ErrMissingField, TypePhase, "phase.event_id", "phase.event_id is required")
+	}
+	if name, _ := m["phase_name"].(string); name == "" {
+		return newValidationError(ErrMissingField, TypePhase, "phase.phase_name", "phase.phase_name is required")
+	}
+
+	return nil
+}
+
+// validateCoach validates a Coach spec
+func (v *SchemaValidator) validateCoach(spec interface{}) error {
+	coach, ok := spec.(Coach)
+	if !ok {
+		return v.validateCoachMap(spec)
+	}
+
+	// Required fields
+	if coach.FirstName == "" && coach.LastName == "" {
+		return newValidationError(ErrMissingField, TypeCoach, "coach.first_name", "coach must have a first or last name")
+	}
+
+	for i, playerID := range coach.Players {
+		if playerID == "" {
+			return newValidationError(ErrValidation, TypeCoach, fmt.Sprintf("coach.players[%d]", i), "coach.players[%d] is empty", i)
+		}
+	}
+
+	return nil
+}
+
+// validateCoachMap validates a coach from map[string]interface{}
+func (v *SchemaValidator) validateCoachMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeCoach, "coach", "coach spec must be object")
+	}
+
+	// At least one name field required
+	firstName, _ := m["first_name"].(string)
+	lastName, _ := m["last_name"].(string)
+	if firstName == "" && lastName == "" {
+		return newValidationError(ErrMissingField, TypeCoach, "coach.first_name", "coach must have a first or last name")
+	}
+
+	return nil
+}
+
+// validateRound validates a Round spec
+func (v *SchemaValidator) validateRound(spec interface{}) error {
+	round, ok := spec.(Round)
+	if !ok {
+		return v.validateRoundMap(spec)
+	}
+
+	// Required fields
+	if round.EventID == "" {
+		return newValidationError(ErrMissingField, TypeRound, "round.event_id", "round.event_id is required")
+	}
+
+	if round.Name == "" {
+		return newValidationError(ErrMissingField, TypeRound, "round.name", "round.name is required")
+	}
+
+	if round.RoundNumber < 1 {
+		return newValidationError(ErrValidation, TypeRound, "round.round_number", "round.round_number must be at least 1")
+	}
+
+	if !round.StartDate.IsZero() && !round.EndDate.IsZero() && round.EndDate.Before(round.StartDate) {
+		return newValidationError(ErrValidation, TypeRound, "round.end_date", "round.end_date must be after start_date")
+	}
+
+	// Validate status
+	validStatuses := []string{"scheduled", "in_progress", "completed"}
+	if round.Status != "" && !contains(validStatuses, round.Status) {
+		return newValidationError(ErrValidation, TypeRound, "round.status", "invalid round.status: %s", round.Status)
+	}
+
+	return nil
+}
+
+// validateRoundMap validates a round from map[string]interface{}
+func (v *SchemaValidator) validateRoundMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeRound, "round", "round spec must be object")
+	}
+
+	// Required: event_id, name
+	if eventID, _ := m["event_id"].(string); eventID == "" {
+		return newValidationError(ErrMissingField, TypeRound, "round.event_id", "round.event_id is required")
+	}
+	if name, _ := m["name"].(string); name == "" {
+		return newValidationError(ErrMissingField, TypeRound, "round.name", "round.name is required")
+	}
+
+	return nil
+}
+
+// validateBracket validates a Bracket spec
+func (v *SchemaValidator) validateBracket(spec interface{}) error {
+	bracket, ok := spec.(Bracket)
+	if !ok {
+		return v.validateBracketMap(spec)
+	}
+
+	// Required fields
+	if bracket.EventID == "" {
+		return newValidationError(ErrMissingField, TypeBracket, "bracket.event_id", "bracket.event_id is required")
+	}
+
+	if bracket.Name == "" {
+		return newValidationError(ErrMissingField, TypeBracket, "bracket.name", "bracket.name is required")
+	}
+
+	if bracket.Size < 0 {
+		return newValidationError(ErrValidation, TypeBracket, "bracket.size", "bracket.size cannot be negative")
+	}
+
+	return nil
+}
+
+// validateBracketMap validates a bracket from map[string]interface{}
+func (v *SchemaValidator) validateBracketMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeBracket, "bracket", "bracket spec must be object")
+	}
+
+	// Required: event_id, name
+	if eventID, _ := m["event_id"].(string); eventID == "" {
+		return newValidationError(ErrMissingField, TypeBracket, "bracket.event_id", "bracket.event_id is required")
+	}
+	if name, _ := m["name"].(string); name == "" {
+		return newValidationError(ErrMissingField, TypeBracket, "bracket.name", "bracket.name is required")
+	}
+
+	return nil
+}
+
+// validateRanking validates a Ranking spec
+func (v *SchemaValidator) validateRanking(spec interface{}) error {
+	ranking, ok := spec.(Ranking)
+	if !ok {
+		return v.validateRankingMap(spec)
+	}
+
+	// Required fields
+	player := ranking.Player
+	if player.FirstName == "" && player.LastName == "" && player.DisplayName == "" {
+		return newValidationError(ErrMissingField, TypeRanking, "ranking.player", "ranking.player must have at least one name field")
+	}
+
+	if ranking.System == "" {
+		return newValidationError(ErrMissingField, TypeRanking, "ranking.system", "ranking.system is required")
+	}
+
+	if ranking.Rank < 1 {
+		return newValidationError(ErrValidation, TypeRanking, "ranking.rank", "ranking.rank must be at least 1")
+	}
+
+	if ranking.Points < 0 {
+		return newValidationError(ErrValidation, TypeRanking, "ranking.points", "ranking.points cannot be negative")
+	}
+
+	return nil
+}
+
+// validateRankingMap validates a ranking from map[string]interface{}
+func (v *SchemaValidator) validateRankingMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeRanking, "ranking", "ranking spec must be object")
+	}
+
+	// Required: player, system
+	if _, ok := m["player"].(map[string]interface{}); !ok {
+		return newValidationError(ErrMissingField, TypeRanking, "ranking.player", "ranking.player is required")
+	}
+	if system, _ := m["system"].(string); system == "" {
+		return newValidationError(ErrMissingField, TypeRanking, "ranking.system", "ranking.system is required")
+	}
+
+	return nil
+}
+
+// validateVenue validates a Venue spec
+func (v *SchemaValidator) validateVenue(spec interface{}) error {
+	venue, ok := spec.(Venue)
+	if !ok {
+		return v.validateVenueMap(spec)
+	}
+
+	// Required fields
+	if venue.Name.Default == "" {
+		return newValidationError(ErrMissingField, TypeVenue, "venue.name", "venue.name is required")
+	}
+
+	if venue.Capacity < 0 {
+		return newValidationError(ErrValidation, TypeVenue, "venue.capacity", "venue.capacity cannot be negative")
+	}
+
+	return nil
+}
+
+// validateVenueMap validates a venue from map[string]interface{}
+func (v *SchemaValidator) validateVenueMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeVenue, "venue", "venue spec must be object")
+	}
+
+	// Required: name
+	if multiNameDefault(m["name"]) == "" {
+		return newValidationError(ErrMissingField, TypeVenue, "venue.name", "venue.name is required")
+	}
+
+	return nil
+}
+
+// validateOrganizer validates an Organizer spec
+func (v *SchemaValidator) validateOrganizer(spec interface{}) error {
+	organizer, ok := spec.(Organizer)
+	if !ok {
+		return v.validateOrganizerMap(spec)
+	}
+
+	// Required fields
+	if organizer.Name == "" {
+		return newValidationError(ErrMissingField, TypeOrganizer, "organizer.name", "organizer.name is required")
+	}
+
+	return nil
+}
+
+// validateOrganizerMap validates an organizer from map[string]interface{}
+func (v *SchemaValidator) validateOrganizerMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeOrganizer, "organizer", "organizer spec must be object")
+	}
+
+	// Required: name
+	if name, _ := m["name"].(string); name == "" {
+		return newValidationError(ErrMissingField, TypeOrganizer, "organizer.name", "organizer.name is required")
+	}
+
+	return nil
+}
+
+// validateOfficial validates an Official spec
+func (v *SchemaValidator) validateOfficial(spec interface{}) error {
+	official, ok := spec.(Official)
+	if !ok {
+		return v.validateOfficialMap(spec)
+	}
+
+	// Required fields
+	if official.Name == "" {
+		return newValidationError(ErrMissingField, TypeOfficial, "official.name", "official.name is required")
+	}
+
+	if official.Role == "" {
+		return newValidationError(ErrMissingField, TypeOfficial, "official.role", "official.role is required")
+	}
+
+	return nil
+}
+
+// validateOfficialMap validates an official from map[string]interface{}
+func (v *SchemaValidator) validateOfficialMap(spec interface{}) error {
+	m, ok := spec.(map[string]interface{})
+	if !ok {
+		return newValidationError(ErrInvalidFormat, TypeOfficial, "official", "official spec must be object")
+	}
+
+	// Required: name, role
+	if name, _ := m["name"].(string); name == "" {
+		return newValidationError(ErrMissingField, TypeOfficial, "official.name", "official.name is required")
+	}
+	if role, _ := m["role"].(string); role == "" {
+		return newValidationError(ErrMissingField, TypeOfficial, "official.role", "official.role is required")
 	}
 
 	return nil
@@ -372,7 +861,9 @@ func ValidateEnvelopeQuick(envelope interface{}) error {
 	return validator.ValidateEnvelope(envelope)
 }
 
-// ValidateEnvelopeStrict is a convenience function for strict envelope validation
+// ValidateEnvelopeStrict is a convenience function for strict envelope validation.
+// Besides rejecting unknown entity types, it rejects schema versions marked
+// deprecated with RegisterSchemaVersion.
 func ValidateEnvelopeStrict(envelope interface{}) error {
 	validator := NewSchemaValidator(true)
 	return validator.ValidateEnvelope(envelope)
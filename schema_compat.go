@@ -0,0 +1,155 @@
+package ptd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompatibilityStatus describes how a schema version relates to the versions this library knows
+type CompatibilityStatus string
+
+// Schema compatibility statuses
+const (
+	CompatibilityCurrent    CompatibilityStatus = "current"    // Latest known version
+	CompatibilityOutdated   CompatibilityStatus = "outdated"   // Older but still supported
+	CompatibilityDeprecated CompatibilityStatus = "deprecated" // No longer supported
+	CompatibilityUnknown    CompatibilityStatus = "unknown"    // Unknown type or version
+)
+
+// CurrentSchemaVersion is the current schema version of the built-in entity types
+const CurrentSchemaVersion = "1.0.0"
+
+// SchemaCompatibilityChecker tracks the status of schema versions per entity type.
+// It is safe for concurrent use.
+type SchemaCompatibilityChecker struct {
+	mu       sync.RWMutex
+	versions map[string]map[string]CompatibilityStatus // entity type -> version -> status
+}
+
+// NewSchemaCompatibilityChecker creates a checker that knows the current
+// version of every built-in entity type
+func NewSchemaCompatibilityChecker() *SchemaCompatibilityChecker {
+	c := &SchemaCompatibilityChecker{versions: make(map[string]map[string]CompatibilityStatus)}
+	for _, entityType := range builtinTypes {
+		c.versions[entityType] = map[string]CompatibilityStatus{CurrentSchemaVersion: CompatibilityCurrent}
+	}
+	return c
+}
+
+// RegisterSchemaVersion records the status of a schema version, e.g. to
+// mark a federation-specific version current or an old version deprecated
+func (c *SchemaCompatibilityChecker) RegisterSchemaVersion(entityType, version, status string) error {
+	if entityType == "" {
+		return fmt.Errorf("%w: entity type is required", ErrInvalidType)
+	}
+	if _, ok := parseSemver(version); !ok {
+		return fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, version)
+	}
+
+	s := CompatibilityStatus(status)
+	switch s {
+	case CompatibilityCurrent, CompatibilityOutdated, CompatibilityDeprecated:
+	default:
+		return fmt.Errorf("%w: unknown compatibility status %q", ErrValidation, status)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.versions[entityType] == nil {
+		c.versions[entityType] = make(map[string]CompatibilityStatus)
+	}
+	c.versions[entityType][version] = s
+	return nil
+}
+
+// Check returns the status of a schema such as "ptd.v1.match@1.0.0".
+// Registered versions report their registered status. An unregistered
+// version older than a current one is Outdated; anything else is Unknown.
+// Returns ErrInvalidSchema if the schema is malformed.
+func (c *SchemaCompatibilityChecker) Check(schema string) (CompatibilityStatus, error) {
+	entityType, version, err := parseSchema(schema)
+	if err != nil {
+		return CompatibilityUnknown, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	versions := c.versions[entityType]
+	if status, ok := versions[version]; ok {
+		return status, nil
+	}
+
+	target, _ := parseSemver(version)
+	for known, status := range versions {
+		if status != CompatibilityCurrent {
+			continue
+		}
+		if current, ok := parseSemver(known); ok && semverLess(target, current) {
+			return CompatibilityOutdated, nil
+		}
+	}
+
+	return CompatibilityUnknown, nil
+}
+
+// defaultCompatibilityChecker backs RegisterSchemaVersion and strict validation
+var defaultCompatibilityChecker = NewSchemaCompatibilityChecker()
+
+// RegisterSchemaVersion records the status of a schema version in the
+// checker used by strict validation
+func RegisterSchemaVersion(entityType, version, status string) error {
+	return defaultCompatibilityChecker.RegisterSchemaVersion(entityType, version, status)
+}
+
+// CheckSchemaCompatibility returns the status of a schema in the checker
+// used by strict validation
+func CheckSchemaCompatibility(schema string) (CompatibilityStatus, error) {
+	return defaultCompatibilityChecker.Check(schema)
+}
+
+// parseSchema splits "ptd.v1.match@1.0.0" into its entity type and version
+func parseSchema(schema string) (entityType, version string, err error) {
+	if err := validateSchemaVersion(schema); err != nil {
+		return "", "", err
+	}
+
+	name, version, _ := strings.Cut(schema, "@")
+	parts := strings.SplitN(name, ".", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return "", "", fmt.Errorf("%w: schema must be in format 'ptd.v1.type@version'", ErrInvalidSchema)
+	}
+	if _, ok := parseSemver(version); !ok {
+		return "", "", fmt.Errorf("%w: version must be semantic (major.minor.patch)", ErrInvalidSchema)
+	}
+	return parts[2], version, nil
+}
+
+// parseSemver parses a major.minor.patch version
+func parseSemver(version string) ([3]int, bool) {
+	var v [3]int
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// semverLess reports whether version a precedes b
+func semverLess(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
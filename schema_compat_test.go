@@ -0,0 +1,78 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSchemaCompatibilityChecker_Check(t *testing.T) {
+	c := NewSchemaCompatibilityChecker()
+	if err := c.RegisterSchemaVersion(TypeMatch, "1.2.0", "current"); err != nil {
+		t.Fatalf("RegisterSchemaVersion failed: %v", err)
+	}
+	if err := c.RegisterSchemaVersion(TypeMatch, "0.9.0", "deprecated"); err != nil {
+		t.Fatalf("RegisterSchemaVersion failed: %v", err)
+	}
+
+	tests := []struct {
+		schema string
+		want   CompatibilityStatus
+	}{
+		{"ptd.v1.tournament@1.0.0", CompatibilityCurrent},
+		{"ptd.v1.match@1.2.0", CompatibilityCurrent},
+		{"ptd.v1.match@1.0.0", CompatibilityCurrent},
+		{"ptd.v1.match@1.1.5", CompatibilityOutdated},
+		{"ptd.v1.tournament@0.5.0", CompatibilityOutdated},
+		{"ptd.v1.match@0.9.0", CompatibilityDeprecated},
+		{"ptd.v1.match@2.0.0", CompatibilityUnknown},
+		{"ptd.v1.sponsor@1.0.0", CompatibilityUnknown},
+	}
+	for _, tt := range tests {
+		got, err := c.Check(tt.schema)
+		if err != nil {
+			t.Errorf("Check(%s) failed: %v", tt.schema, err)
+		}
+		if got != tt.want {
+			t.Errorf("Check(%s) = %s, want %s", tt.schema, got, tt.want)
+		}
+	}
+
+	if _, err := c.Check("tournament@1.0"); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema, got %v", err)
+	}
+	if err := c.RegisterSchemaVersion(TypeMatch, "1.0.0", "retired"); err == nil {
+		t.Error("Expected error for unknown status")
+	}
+	if err := c.RegisterSchemaVersion(TypeMatch, "1.0", "current"); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema for bad version, got %v", err)
+	}
+}
+
+func TestValidateEnvelopeStrict_DeprecatedSchema(t *testing.T) {
+	const entityType = "compat-test"
+	err := RegisterEntityType(entityType, FieldValidatorFunc(func(interface{}) error { return nil }))
+	if err != nil && !errors.Is(err, ErrDuplicateEntity) {
+		t.Fatalf("RegisterEntityType failed: %v", err)
+	}
+	if err := RegisterSchemaVersion(entityType, "0.1.0", "deprecated"); err != nil {
+		t.Fatalf("RegisterSchemaVersion failed: %v", err)
+	}
+
+	envelope := Envelope[map[string]interface{}]{
+		ID:   GenerateID(entityType),
+		Type: entityType,
+		Spec: map[string]interface{}{},
+		Meta: Meta{Schema: "ptd.v1." + entityType + "@0.1.0"},
+	}
+	if err := ValidateEnvelopeStrict(envelope); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Expected ErrUnsupportedVersion in strict mode, got %v", err)
+	}
+	if err := ValidateEnvelopeQuick(envelope); err != nil {
+		t.Errorf("Non-strict validation should accept deprecated schemas: %v", err)
+	}
+
+	envelope.Meta.Schema = "ptd.v1." + entityType + "@0.2.0"
+	if err := ValidateEnvelopeStrict(envelope); err != nil {
+		t.Errorf("Unregistered version should pass: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package ptd
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FieldValidator validates the spec of a custom entity type.
+// Implementations receive the spec as decoded by the caller, typically a
+// map[string]interface{} for envelopes read from NDJSON.
+type FieldValidator interface {
+	Validate(spec interface{}) error
+}
+
+// FieldValidatorFunc adapts an ordinary function to a FieldValidator
+type FieldValidatorFunc func(spec interface{}) error
+
+// Validate calls f(spec)
+func (f FieldValidatorFunc) Validate(spec interface{}) error {
+	return f(spec)
+}
+
+// builtinTypes are the entity types validated by SchemaValidator itself
+var builtinTypes = []string{
+	TypeTournament, TypeEvent, TypeMatch, TypeEntry, TypePlayer, TypeCoach,
+	TypeRound, TypePhase, TypeBracket, TypeVenue, TypeOrganizer, TypeOfficial,
+	TypeRanking,
+}
+
+// entityTypeRegistry holds custom entity types registered at runtime
+var entityTypeRegistry = struct {
+	sync.RWMutex
+	validators map[string]FieldValidator
+}{validators: make(map[string]FieldValidator)}
+
+// RegisterEntityType registers a custom entity type (e.g., "sponsor") and the
+// validator used for its specs. Once registered, SchemaValidator accepts the
+// type in strict mode and validates its specs with validator in both modes.
+// Built-in types cannot be overridden and a type can only be registered once.
+func RegisterEntityType(typeName string, validator FieldValidator) error {
+	if typeName == "" {
+		return fmt.Errorf("%w: entity type name is required", ErrInvalidType)
+	}
+	if validator == nil {
+		return fmt.Errorf("%w: validator is required for entity type %s", ErrValidation, typeName)
+	}
+	if contains(builtinTypes, typeName) {
+		return fmt.Errorf("%w: %s is a built-in entity type", ErrInvalidType, typeName)
+	}
+
+	entityTypeRegistry.Lock()
+	defer entityTypeRegistry.Unlock()
+
+	if _, exists := entityTypeRegistry.validators[typeName]; exists {
+		return fmt.Errorf("%w: entity type %s is already registered", ErrDuplicateEntity, typeName)
+	}
+	entityTypeRegistry.validators[typeName] = validator
+
+	return nil
+}
+
+// ListRegisteredTypes returns the custom entity types registered at runtime, sorted
+func ListRegisteredTypes() []string {
+	entityTypeRegistry.RLock()
+	defer entityTypeRegistry.RUnlock()
+
+	types := make([]string, 0, len(entityTypeRegistry.validators))
+	for typeName := range entityTypeRegistry.validators {
+		types = append(types, typeName)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// registeredValidator returns the validator registered for a custom entity type
+func registeredValidator(typeName string) (FieldValidator, bool) {
+	entityTypeRegistry.RLock()
+	defer entityTypeRegistry.RUnlock()
+
+	validator, ok := entityTypeRegistry.validators[typeName]
+	return validator, ok
+}
@@ -0,0 +1,67 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterEntityType(t *testing.T) {
+	sponsorValidator := FieldValidatorFunc(func(spec interface{}) error {
+		m, ok := spec.(map[string]interface{})
+		if !ok {
+			return newValidationError(ErrInvalidFormat, "test_sponsor", "test_sponsor", "sponsor spec must be object")
+		}
+		if name, _ := m["name"].(string); name == "" {
+			return newValidationError(ErrMissingField, "test_sponsor", "test_sponsor.name", "test_sponsor.name is required")
+		}
+		return nil
+	})
+
+	if err := RegisterEntityType("test_sponsor", sponsorValidator); err != nil {
+		t.Fatalf("Failed to register entity type: %v", err)
+	}
+
+	// Duplicate registration
+	if err := RegisterEntityType("test_sponsor", sponsorValidator); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity, got %v", err)
+	}
+
+	// Built-in and empty names are rejected
+	if err := RegisterEntityType(TypeMatch, sponsorValidator); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType for built-in type, got %v", err)
+	}
+	if err := RegisterEntityType("", sponsorValidator); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType for empty name, got %v", err)
+	}
+
+	if !contains(ListRegisteredTypes(), "test_sponsor") {
+		t.Error("ListRegisteredTypes should include test_sponsor")
+	}
+
+	strict := NewSchemaValidator(true)
+
+	envelope := &Envelope[map[string]interface{}]{
+		ID:   GenerateID("test_sponsor"),
+		Type: "test_sponsor",
+		Spec: map[string]interface{}{"name": "Butterfly"},
+		Meta: Meta{Schema: "ptd.v1.test_sponsor@1.0.0"},
+	}
+	if err := strict.ValidateEnvelope(envelope); err != nil {
+		t.Errorf("Registered type should validate in strict mode: %v", err)
+	}
+
+	envelope.Spec = map[string]interface{}{}
+	err := strict.ValidateEnvelope(envelope)
+	if !errors.Is(err, ErrMissingField) {
+		t.Fatalf("Expected registered validator to reject spec, got %v", err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.EntityID != envelope.ID {
+		t.Errorf("Registered validator errors should carry entity context: %v", err)
+	}
+
+	// Unregistered custom types are still rejected in strict mode
+	if err := strict.ValidateEntity("test_unregistered", map[string]interface{}{}); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for unregistered type, got %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package ptd
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 )
@@ -10,7 +12,7 @@ func TestValidateTournament(t *testing.T) {
 
 	// Valid tournament
 	tournament := Tournament{
-		Name:      "Test Tournament",
+		Name:      MultiName{Default: "Test Tournament"},
 		StartDate: time.Now(),
 		EndDate:   time.Now().Add(24 * time.Hour),
 		Status:    "published",
@@ -31,7 +33,7 @@ func TestValidateTournament(t *testing.T) {
 
 	// Invalid: bad status
 	badStatus := Tournament{
-		Name:   "Test",
+		Name:   MultiName{Default: "Test"},
 		Status: "invalid_status",
 	}
 
@@ -41,7 +43,7 @@ func TestValidateTournament(t *testing.T) {
 
 	// Invalid: end before start
 	badDates := Tournament{
-		Name:      "Test",
+		Name:      MultiName{Default: "Test"},
 		StartDate: time.Now(),
 		EndDate:   time.Now().Add(-24 * time.Hour),
 	}
@@ -57,7 +59,7 @@ func TestValidateEvent(t *testing.T) {
 	// Valid event
 	event := Event{
 		TournamentID: GenerateID(TypeTournament),
-		Name:         "Men's Singles",
+		Name:         MultiName{Default: "Men's Singles"},
 		EventType:    "singles",
 		Gender:       "male",
 		Status:       "published",
@@ -69,7 +71,7 @@ func TestValidateEvent(t *testing.T) {
 
 	// Invalid: missing tournament_id
 	invalid := Event{
-		Name: "Test Event",
+		Name: MultiName{Default: "Test Event"},
 	}
 
 	if err := validator.validateEvent(invalid); err == nil {
@@ -88,7 +90,7 @@ func TestValidateEvent(t *testing.T) {
 	// Invalid: bad event type
 	badType := Event{
 		TournamentID: GenerateID(TypeTournament),
-		Name:         "Test",
+		Name:         MultiName{Default: "Test"},
 		EventType:    "invalid",
 	}
 
@@ -166,6 +168,59 @@ func TestValidateEntry(t *testing.T) {
 	}
 }
 
+func TestValidateEntryWithContext(t *testing.T) {
+	validator := NewSchemaValidator(false)
+
+	event := Envelope[Event]{
+		ID:   GenerateID(TypeEvent),
+		Type: TypeEvent,
+		Spec: Event{Name: MultiName{Default: "Men's Singles"}, MaxEntries: 2},
+	}
+
+	newEntry := func(status string) Envelope[Entry] {
+		return Envelope[Entry]{
+			ID:   GenerateID(TypeEntry),
+			Type: TypeEntry,
+			Spec: Entry{
+				EventID: event.ID,
+				Status:  status,
+				Players: []Player{{FirstName: "John", LastName: "Doe"}},
+			},
+		}
+	}
+
+	existing := []Envelope[Entry]{newEntry("confirmed")}
+
+	// Room left
+	if err := validator.ValidateEntryWithContext(newEntry("confirmed"), existing, event); err != nil {
+		t.Errorf("Entry should fit in event: %v", err)
+	}
+
+	// Event full
+	existing = append(existing, newEntry("confirmed"))
+	if err := validator.ValidateEntryWithContext(newEntry("confirmed"), existing, event); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for full event, got %v", err)
+	}
+
+	// Registered entries are not capped
+	if err := validator.ValidateEntryWithContext(newEntry("registered"), existing, event); err != nil {
+		t.Errorf("Registered entry should not be capped: %v", err)
+	}
+
+	// Unlimited event
+	event.Spec.MaxEntries = 0
+	if err := validator.ValidateEntryWithContext(newEntry("confirmed"), existing, event); err != nil {
+		t.Errorf("Unlimited event should accept entry: %v", err)
+	}
+
+	// Regular entry validation still applies
+	invalid := newEntry("confirmed")
+	invalid.Spec.Players = nil
+	if err := validator.ValidateEntryWithContext(invalid, nil, event); err == nil {
+		t.Error("Entry with no players should fail validation")
+	}
+}
+
 func TestValidatePlayer(t *testing.T) {
 	validator := NewSchemaValidator(false)
 
@@ -196,6 +251,187 @@ func TestValidatePlayer(t *testing.T) {
 	}
 }
 
+func TestValidatePlayer_Country(t *testing.T) {
+	validator := NewSchemaValidator(false)
+
+	if err := validator.validatePlayer(Player{LastName: "Doe", Country: "US"}); err != nil {
+		t.Errorf("Player with alpha-2 country failed validation: %v", err)
+	}
+	err := validator.validatePlayer(Player{LastName: "Doe", Country: "Atlantis"})
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.FieldPath != "player.country" {
+		t.Errorf("Player with unknown country: error = %v, want player.country validation error", err)
+	}
+
+	// Pointers are normalized in place, maps are left as they are
+	player := &Player{LastName: "Doe", Country: "us"}
+	if err := validator.ValidateEntity(TypePlayer, player); err != nil || player.Country != "USA" {
+		t.Errorf("ValidateEntity(*Player) = %v, country %q; want USA", err, player.Country)
+	}
+	spec := map[string]interface{}{"last_name": "Doe", "country": "jp"}
+	if err := validator.ValidateEntity(TypePlayer, spec); err != nil || spec["country"] != "jp" {
+		t.Errorf("ValidateEntity(map) = %v, country %v; want jp unchanged", err, spec["country"])
+	}
+
+	// Envelopes passed by pointer have their player normalized
+	envelope := &Envelope[Player]{
+		ID:   GenerateID(TypePlayer),
+		Type: TypePlayer,
+		Spec: Player{LastName: "Doe", Country: "GER"},
+		Meta: Meta{Schema: "ptd.v1.player@1.0.0"},
+	}
+	if err := validator.ValidateEnvelope(envelope); err != nil || envelope.Spec.Country != "DEU" {
+		t.Errorf("ValidateEnvelope(*Envelope) = %v, country %q; want DEU", err, envelope.Spec.Country)
+	}
+	if err := validator.ValidateEnvelope(*envelope); err != nil {
+		t.Errorf("ValidateEnvelope(Envelope) error = %v", err)
+	}
+	if err := validator.ValidateEntity(TypePlayer, map[string]interface{}{"last_name": "Doe", "country": "XX"}); !errors.Is(err, ErrValidation) {
+		t.Errorf("ValidateEntity(map) with unknown country error = %v, want ErrValidation", err)
+	}
+}
+
+func TestValidatePhase(t *testing.T) {
+	validator := NewSchemaValidator(true)
+
+	// Valid phase
+	phase := TournamentPhase{
+		EventID:          GenerateID(TypeEvent),
+		PhaseName:        "qualifying",
+		PhaseOrder:       1,
+		RoundIDs:         []string{GenerateID(TypeRound)},
+		AdvancementCount: 16,
+		Status:           "scheduled",
+	}
+
+	if err := validator.ValidateEntity(TypePhase, phase); err != nil {
+		t.Errorf("Valid phase failed validation: %v", err)
+	}
+
+	// Invalid phases
+	tests := []struct {
+		name   string
+		modify func(*TournamentPhase)
+		want   error
+	}{
+		{"missing event", func(p *TournamentPhase) { p.EventID = "" }, ErrMissingField},
+		{"missing name", func(p *TournamentPhase) { p.PhaseName = "" }, ErrMissingField},
+		{"zero order", func(p *TournamentPhase) { p.PhaseOrder = 0 }, ErrValidation},
+		{"negative advancement", func(p *TournamentPhase) { p.AdvancementCount = -1 }, ErrValidation},
+		{"bad status", func(p *TournamentPhase) { p.Status = "postponed" }, ErrValidation},
+	}
+	for _, tt := range tests {
+		invalid := phase
+		tt.modify(&invalid)
+		if err := validator.ValidateEntity(TypePhase, invalid); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	// Map form
+	if err := validator.ValidateEntity(TypePhase, map[string]interface{}{"event_id": phase.EventID, "phase_name": "main_draw"}); err != nil {
+		t.Errorf("Valid phase map failed validation: %v", err)
+	}
+	if err := validator.ValidateEntity(TypePhase, map[string]interface{}{"event_id": phase.EventID}); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField for phase map without name, got %v", err)
+	}
+}
+
+func TestValidateCoach(t *testing.T) {
+	validator := NewSchemaValidator(true)
+
+	coach := Coach{FirstName: "Liu", LastName: "Guoliang", Certifications: []string{"ITTF Level 3"}, Players: []string{GenerateID(TypePlayer)}}
+	if err := validator.ValidateEntity(TypeCoach, coach); err != nil {
+		t.Errorf("Valid coach failed validation: %v", err)
+	}
+
+	if err := validator.ValidateEntity(TypeCoach, Coach{}); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField for nameless coach, got %v", err)
+	}
+	coach.Players = append(coach.Players, "")
+	if err := validator.ValidateEntity(TypeCoach, coach); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for empty player ID, got %v", err)
+	}
+
+	if err := validator.ValidateEntity(TypeCoach, map[string]interface{}{"last_name": "Guoliang"}); err != nil {
+		t.Errorf("Valid coach map failed validation: %v", err)
+	}
+	if err := validator.ValidateEntity(TypeCoach, map[string]interface{}{}); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField for nameless coach map, got %v", err)
+	}
+}
+
+func TestValidateBuiltinTypes(t *testing.T) {
+	validator := NewSchemaValidator(true)
+	eventID := GenerateID(TypeEvent)
+
+	tests := []struct {
+		entityType string
+		valid      interface{}
+		invalid    interface{}
+		want       error
+		validMap   map[string]interface{}
+	}{
+		{TypeRound, Round{EventID: eventID, Name: "Round of 16", RoundNumber: 1}, Round{EventID: eventID, Name: "Final"}, ErrValidation,
+			map[string]interface{}{"event_id": eventID, "name": "Final"}},
+		{TypeBracket, Bracket{EventID: eventID, Name: "Main draw", Size: 32}, Bracket{EventID: eventID}, ErrMissingField,
+			map[string]interface{}{"event_id": eventID, "name": "Main draw"}},
+		{TypeRanking, Ranking{Player: Player{LastName: "Ma"}, System: "ITTF", Rank: 1, Points: 9000}, Ranking{Player: Player{LastName: "Ma"}, System: "ITTF"}, ErrValidation,
+			map[string]interface{}{"player": map[string]interface{}{"last_name": "Ma"}, "system": "ITTF"}},
+		{TypeVenue, Venue{Name: MultiName{Default: "Olympic Hall"}}, Venue{City: "Paris"}, ErrMissingField,
+			map[string]interface{}{"name": map[string]interface{}{"default": "Olympic Hall"}}},
+		{TypeOrganizer, Organizer{Name: "Example Federation", Type: "federation"}, Organizer{Type: "club"}, ErrMissingField,
+			map[string]interface{}{"name": "Example Federation"}},
+		{TypeOfficial, Official{Name: "A. Referee", Role: "referee"}, Official{Name: "A. Referee"}, ErrMissingField,
+			map[string]interface{}{"name": "A. Referee", "role": "umpire"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entityType, func(t *testing.T) {
+			if err := validator.ValidateEntity(tt.entityType, tt.valid); err != nil {
+				t.Errorf("Valid %s failed validation: %v", tt.entityType, err)
+			}
+			if err := validator.ValidateEntity(tt.entityType, tt.invalid); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+			if err := validator.ValidateEntity(tt.entityType, tt.validMap); err != nil {
+				t.Errorf("Valid %s map failed validation: %v", tt.entityType, err)
+			}
+			if err := validator.ValidateEntity(tt.entityType, map[string]interface{}{}); !errors.Is(err, ErrMissingField) {
+				t.Errorf("Expected ErrMissingField for empty %s map, got %v", tt.entityType, err)
+			}
+		})
+	}
+}
+
+func TestValidateVenue_RoundTrip(t *testing.T) {
+	validator := NewSchemaValidator(true)
+
+	// A name without translations marshals as a plain string
+	venue := Envelope[Venue]{
+		ID:   GenerateID(TypeVenue),
+		Type: TypeVenue,
+		Spec: Venue{Name: MultiName{Default: "Olympic Hall"}, City: "Paris"},
+		Meta: Meta{Schema: "ptd.v1.venue@1.0.0", Version: 1},
+	}
+	data, err := json.Marshal(venue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Envelope[map[string]interface{}]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded.Spec["name"].(string); !ok {
+		t.Fatalf("Expected name to marshal as a string, got %s", data)
+	}
+	if err := validator.ValidateEntity(TypeVenue, decoded.Spec); err != nil {
+		t.Errorf("Decoded venue failed validation: %v", err)
+	}
+	if err := validator.ValidateEnvelope(decoded); err != nil {
+		t.Errorf("Decoded venue envelope failed validation: %v", err)
+	}
+}
+
 func TestValidateEnvelope(t *testing.T) {
 	validator := NewSchemaValidator(false)
 
@@ -204,7 +440,7 @@ func TestValidateEnvelope(t *testing.T) {
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
 		Spec: Tournament{
-			Name:   "Test Tournament",
+			Name:   MultiName{Default: "Test Tournament"},
 			Status: "published",
 		},
 		Meta: Meta{
@@ -223,7 +459,7 @@ func TestValidateEnvelope(t *testing.T) {
 	// Invalid: missing ID
 	invalid := &Envelope[Tournament]{
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
 	}
 
@@ -235,7 +471,7 @@ func TestValidateEnvelope(t *testing.T) {
 	badSchema := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{Schema: "invalid"},
 	}
 
@@ -339,7 +575,7 @@ func TestQuickValidation(t *testing.T) {
 	envelope := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{
 			Schema:    "ptd.v1.tournament@1.0.0",
 			CreatedAt: time.Now(),
@@ -361,9 +597,9 @@ func TestValidateTournamentWithVenue(t *testing.T) {
 	validator := NewSchemaValidator(false)
 
 	tournament := Tournament{
-		Name: "Championship 2025",
+		Name: MultiName{Default: "Championship 2025"},
 		Venue: &Venue{
-			Name:    "Sports Complex",
+			Name:    MultiName{Default: "Sports Complex"},
 			City:    "Tokyo",
 			Country: "Japan",
 		},
@@ -380,7 +616,7 @@ func TestValidateEventWithAgeGroup(t *testing.T) {
 
 	event := Event{
 		TournamentID: GenerateID(TypeTournament),
-		Name:         "U19 Singles",
+		Name:         MultiName{Default: "U19 Singles"},
 		EventType:    "singles",
 		AgeGroup: &AgeGroup{
 			Name:   "Under 19",
@@ -393,3 +629,97 @@ func TestValidateEventWithAgeGroup(t *testing.T) {
 		t.Errorf("Event with age group failed validation: %v", err)
 	}
 }
+
+func TestValidationErrorContext(t *testing.T) {
+	validator := NewSchemaValidator(false)
+
+	envelope := &Envelope[Match]{
+		ID:   GenerateID(TypeMatch),
+		Type: TypeMatch,
+		Spec: Match{MatchNumber: "M1"},
+		Meta: Meta{Schema: "ptd.v1.match@1.0.0"},
+	}
+
+	err := validator.ValidateEnvelope(envelope)
+	if !errors.Is(err, ErrMissingField) {
+		t.Fatalf("Expected ErrMissingField, got %v", err)
+	}
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Expected *ValidationError, got %T", err)
+	}
+	if ve.EntityID != envelope.ID {
+		t.Errorf("EntityID = %s, want %s", ve.EntityID, envelope.ID)
+	}
+	if ve.EntityType != TypeMatch {
+		t.Errorf("EntityType = %s, want %s", ve.EntityType, TypeMatch)
+	}
+	if ve.FieldPath != "match.event_id" {
+		t.Errorf("FieldPath = %s, want match.event_id", ve.FieldPath)
+	}
+
+	// Schema format errors carry context too
+	envelope.Spec.EventID = GenerateID(TypeEvent)
+	envelope.Meta.Schema = "invalid"
+	err = validator.ValidateEnvelope(envelope)
+	if !errors.Is(err, ErrInvalidSchema) {
+		t.Fatalf("Expected ErrInvalidSchema, got %v", err)
+	}
+	if !errors.As(err, &ve) || ve.FieldPath != "meta.schema" || ve.EntityID != envelope.ID {
+		t.Errorf("Unexpected schema error context: %+v", ve)
+	}
+}
+
+func TestValidateEvent_RegistrationWindow(t *testing.T) {
+	validator := NewSchemaValidator(false)
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	opens := start.AddDate(0, -2, 0)
+	closes := start.AddDate(0, 0, -7)
+	late := start.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		opens  *time.Time
+		closes *time.Time
+		field  string
+	}{
+		{"valid window", &opens, &closes, ""},
+		{"only closing", nil, &closes, ""},
+		{"opens after closing", &closes, &opens, "event.registration_open"},
+		{"opens at closing", &closes, &closes, "event.registration_open"},
+		{"closes after start", &opens, &late, "event.registration_close"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := Event{
+				TournamentID:      GenerateID(TypeTournament),
+				Name:              MultiName{Default: "Men's Singles"},
+				StartDate:         start,
+				RegistrationOpen:  tt.opens,
+				RegistrationClose: tt.closes,
+			}
+			err := validator.validateEvent(event)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("validateEvent() error = %v", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) || ve.FieldPath != tt.field {
+				t.Errorf("validateEvent() error = %v, want %s", err, tt.field)
+			}
+
+			// Events decoded as maps are checked the same way
+			spec, err := toJSONValue(event)
+			if err != nil {
+				t.Fatalf("toJSONValue() error = %v", err)
+			}
+			if err := validator.validateEvent(spec); !errors.As(err, &ve) || ve.FieldPath != tt.field {
+				t.Errorf("validateEvent(map) error = %v, want %s", err, tt.field)
+			}
+		})
+	}
+}
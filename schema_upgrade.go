@@ -0,0 +1,199 @@
+package ptd
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SchemaUpgradeFunc upgrades an entity spec, in its decoded JSON form, by
+// one schema version: adding new fields with their defaults, removing or
+// reshaping old ones. Specs decoded with a type that already declares the
+// new fields hold them with zero values, so defaults should be applied to
+// zero values rather than only to missing keys.
+type SchemaUpgradeFunc func(spec map[string]interface{}) error
+
+// SchemaUpgradeResult describes the changes UpgradeSchema made to a spec.
+// Field paths are relative to the spec, e.g. "venue.timezone".
+type SchemaUpgradeResult struct {
+	OriginalSchema string   `json:"original_schema"`
+	UpgradedSchema string   `json:"upgraded_schema"`
+	FieldsAdded    []string `json:"fields_added,omitempty"`
+	FieldsRemoved  []string `json:"fields_removed,omitempty"`
+	FieldsModified []string `json:"fields_modified,omitempty"`
+}
+
+// schemaUpgrade is one registered upgrade step
+type schemaUpgrade struct {
+	toVersion string
+	upgrade   SchemaUpgradeFunc
+}
+
+// schemaUpgradeRegistry holds upgrade steps by entity type and source version
+var schemaUpgradeRegistry = struct {
+	sync.RWMutex
+	steps map[string]map[string]schemaUpgrade
+}{steps: make(map[string]map[string]schemaUpgrade)}
+
+// RegisterSchemaUpgrade registers the function that upgrades specs of an
+// entity type from fromVersion to toVersion. Each version can be upgraded
+// by one function; UpgradeSchema chains steps to reach its target.
+func RegisterSchemaUpgrade(entityType, fromVersion, toVersion string, upgrade SchemaUpgradeFunc) error {
+	if entityType == "" {
+		return fmt.Errorf("%w: entity type is required", ErrInvalidType)
+	}
+	from, ok := parseSemver(fromVersion)
+	if !ok {
+		return fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, fromVersion)
+	}
+	to, ok := parseSemver(toVersion)
+	if !ok {
+		return fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, toVersion)
+	}
+	if !semverLess(from, to) {
+		return fmt.Errorf("%w: upgrade must go to a newer version, got %s to %s", ErrInvalidSchema, fromVersion, toVersion)
+	}
+	if upgrade == nil {
+		return fmt.Errorf("%w: upgrade function is required", ErrValidation)
+	}
+
+	schemaUpgradeRegistry.Lock()
+	defer schemaUpgradeRegistry.Unlock()
+
+	steps := schemaUpgradeRegistry.steps[entityType]
+	if steps == nil {
+		steps = make(map[string]schemaUpgrade)
+		schemaUpgradeRegistry.steps[entityType] = steps
+	}
+	if _, exists := steps[fromVersion]; exists {
+		return fmt.Errorf("%w: upgrade of %s from %s is already registered", ErrDuplicateEntity, entityType, fromVersion)
+	}
+	steps[fromVersion] = schemaUpgrade{toVersion: toVersion, upgrade: upgrade}
+
+	return nil
+}
+
+// UpgradeSchema upgrades an envelope in place to targetVersion of its
+// schema, applying the registered upgrade steps in turn and setting
+// Meta.Schema. envelope must be a pointer, such as *Envelope[Tournament];
+// spec fields its type cannot hold are dropped, so decode into
+// *Envelope[json.RawMessage] to keep everything. An existing signature no
+// longer verifies after a change and should be renewed.
+//
+// Upgrading an envelope already at targetVersion is a no-op. Returns
+// ErrUnsupportedVersion if the envelope is newer than targetVersion or no
+// chain of registered steps leads to it.
+func UpgradeSchema(envelope interface{}, targetVersion string) (*SchemaUpgradeResult, error) {
+	target := reflect.ValueOf(envelope)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return nil, fmt.Errorf("%w: envelope must be a non-nil pointer, got %T", ErrValidation, envelope)
+	}
+	targetSemver, ok := parseSemver(targetVersion)
+	if !ok {
+		return nil, fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, targetVersion)
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal envelope: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("%w: envelope is not a JSON object: %v", ErrInvalidFormat, err)
+	}
+	meta, _ := fields["meta"].(map[string]interface{})
+	schema, _ := meta["schema"].(string)
+	entityType, version, err := parseSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SchemaUpgradeResult{OriginalSchema: schema, UpgradedSchema: schema}
+	if version == targetVersion {
+		return result, nil
+	}
+	if current, _ := parseSemver(version); semverLess(targetSemver, current) {
+		return nil, fmt.Errorf("%w: cannot downgrade %s to %s", ErrUnsupportedVersion, schema, targetVersion)
+	}
+
+	original, _ := fields["spec"].(map[string]interface{})
+	spec, err := toJSONValue(original)
+	if err != nil {
+		return nil, err
+	}
+	upgraded, _ := spec.(map[string]interface{})
+	if upgraded == nil {
+		upgraded = make(map[string]interface{})
+	}
+
+	for version != targetVersion {
+		schemaUpgradeRegistry.RLock()
+		step, ok := schemaUpgradeRegistry.steps[entityType][version]
+		schemaUpgradeRegistry.RUnlock()
+
+		next, _ := parseSemver(step.toVersion)
+		if !ok || semverLess(targetSemver, next) {
+			return nil, fmt.Errorf("%w: no upgrade path for %s from %s to %s", ErrUnsupportedVersion, entityType, version, targetVersion)
+		}
+		if err := step.upgrade(upgraded); err != nil {
+			return nil, fmt.Errorf("failed to upgrade %s from %s to %s: %w", entityType, version, step.toVersion, err)
+		}
+		version = step.toVersion
+	}
+
+	name, _, _ := strings.Cut(schema, "@")
+	result.UpgradedSchema = name + "@" + targetVersion
+	meta["schema"] = result.UpgradedSchema
+	fields["spec"] = upgraded
+	classifySpecChanges("", original, upgraded, result)
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal upgraded envelope: %w", err)
+	}
+	// Reset the envelope so fields removed by the upgrade do not linger
+	target.Elem().Set(reflect.Zero(target.Elem().Type()))
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode upgraded envelope: %v", ErrInvalidFormat, err)
+	}
+
+	return result, nil
+}
+
+// classifySpecChanges records the fields added, removed and modified
+// between two decoded JSON objects, descending into nested objects
+func classifySpecChanges(path string, old, new map[string]interface{}, result *SchemaUpgradeResult) {
+	keys := make([]string, 0, len(old)+len(new))
+	for key := range old {
+		keys = append(keys, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := joinFieldPath(path, key)
+		oldValue, inOld := old[key]
+		newValue, inNew := new[key]
+		switch {
+		case !inOld:
+			result.FieldsAdded = append(result.FieldsAdded, field)
+		case !inNew:
+			result.FieldsRemoved = append(result.FieldsRemoved, field)
+		default:
+			oldObject, oldIsObject := oldValue.(map[string]interface{})
+			newObject, newIsObject := newValue.(map[string]interface{})
+			if oldIsObject && newIsObject {
+				classifySpecChanges(field, oldObject, newObject, result)
+			} else if !reflect.DeepEqual(oldValue, newValue) {
+				result.FieldsModified = append(result.FieldsModified, field)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package ptd
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testWidget is the 1.2.0 spec of the test_widget entity type
+type testWidget struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+	Size  struct {
+		Width int    `json:"width"`
+		Unit  string `json:"unit"`
+	} `json:"size"`
+}
+
+func registerTestWidgetUpgrades(t *testing.T) {
+	t.Helper()
+	steps := []struct {
+		from, to string
+		upgrade  SchemaUpgradeFunc
+	}{
+		{"1.0.0", "1.1.0", func(spec map[string]interface{}) error {
+			if color, _ := spec["color"].(string); color == "" {
+				spec["color"] = "black"
+			}
+			return nil
+		}},
+		{"1.1.0", "1.2.0", func(spec map[string]interface{}) error {
+			size, _ := spec["size"].(map[string]interface{})
+			if size == nil {
+				return errors.New("size is required")
+			}
+			size["unit"] = "mm"
+			if width, ok := size["width"].(float64); ok {
+				size["width"] = width * 10
+			}
+			delete(spec, "legacy_code")
+			return nil
+		}},
+	}
+	for _, step := range steps {
+		if err := RegisterSchemaUpgrade("test_widget", step.from, step.to, step.upgrade); err != nil && !errors.Is(err, ErrDuplicateEntity) {
+			t.Fatalf("RegisterSchemaUpgrade(%s) error = %v", step.from, err)
+		}
+	}
+}
+
+func TestUpgradeSchema(t *testing.T) {
+	registerTestWidgetUpgrades(t)
+
+	raw := `{"id":"ptd:test_widget:1","type":"test_widget","spec":{"name":"Net","size":{"width":15},"legacy_code":"N1"},"meta":{"schema":"ptd.v1.test_widget@1.0.0","version":1}}`
+	var envelope Envelope[testWidget]
+	if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := UpgradeSchema(&envelope, "1.2.0")
+	if err != nil {
+		t.Fatalf("UpgradeSchema() error = %v", err)
+	}
+
+	want := &SchemaUpgradeResult{
+		OriginalSchema: "ptd.v1.test_widget@1.0.0",
+		UpgradedSchema: "ptd.v1.test_widget@1.2.0",
+		FieldsModified: []string{"color", "size.unit", "size.width"},
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("UpgradeSchema() = %+v, want %+v", result, want)
+	}
+	if envelope.Meta.Schema != "ptd.v1.test_widget@1.2.0" || envelope.Spec.Color != "black" ||
+		envelope.Spec.Size.Width != 150 || envelope.Spec.Size.Unit != "mm" {
+		t.Errorf("upgraded envelope = %+v", envelope)
+	}
+
+	// Upgrading again is a no-op
+	again, err := UpgradeSchema(&envelope, "1.2.0")
+	if err != nil {
+		t.Fatalf("UpgradeSchema() again error = %v", err)
+	}
+	if again.UpgradedSchema != again.OriginalSchema || again.FieldsAdded != nil || envelope.Spec.Size.Width != 150 {
+		t.Errorf("UpgradeSchema() again = %+v, want a no-op", again)
+	}
+}
+
+func TestUpgradeSchema_RawSpec(t *testing.T) {
+	registerTestWidgetUpgrades(t)
+
+	envelope := &Envelope[json.RawMessage]{
+		ID:   "ptd:test_widget:2",
+		Type: "test_widget",
+		Spec: json.RawMessage(`{"name":"Paddle","size":{"width":3},"legacy_code":"P2","extra":true}`),
+		Meta: Meta{Schema: "ptd.v1.test_widget@1.1.0", Version: 1},
+	}
+	result, err := UpgradeSchema(envelope, "1.2.0")
+	if err != nil {
+		t.Fatalf("UpgradeSchema() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.FieldsAdded, []string{"size.unit"}) {
+		t.Errorf("FieldsAdded = %v, want [size.unit]", result.FieldsAdded)
+	}
+	if !reflect.DeepEqual(result.FieldsRemoved, []string{"legacy_code"}) {
+		t.Errorf("FieldsRemoved = %v, want [legacy_code]", result.FieldsRemoved)
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(envelope.Spec, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec["legacy_code"]; ok || spec["extra"] != true {
+		t.Errorf("upgraded spec = %v", spec)
+	}
+}
+
+func TestUpgradeSchema_Errors(t *testing.T) {
+	registerTestWidgetUpgrades(t)
+
+	newEnvelope := func(version string) *Envelope[json.RawMessage] {
+		return &Envelope[json.RawMessage]{
+			ID:   "ptd:test_widget:3",
+			Type: "test_widget",
+			Spec: json.RawMessage(`{"name":"Ball"}`),
+			Meta: Meta{Schema: "ptd.v1.test_widget@" + version},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		envelope interface{}
+		target   string
+		want     error
+	}{
+		{"not a pointer", *newEnvelope("1.0.0"), "1.2.0", ErrValidation},
+		{"downgrade", newEnvelope("1.2.0"), "1.0.0", ErrUnsupportedVersion},
+		{"no path", newEnvelope("1.2.0"), "2.0.0", ErrUnsupportedVersion},
+		{"past a step", newEnvelope("1.0.0"), "1.0.5", ErrUnsupportedVersion},
+		{"invalid target", newEnvelope("1.0.0"), "next", ErrInvalidSchema},
+		{"invalid schema", &Envelope[json.RawMessage]{Meta: Meta{Schema: "widget"}}, "1.2.0", ErrInvalidSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UpgradeSchema(tt.envelope, tt.target); !errors.Is(err, tt.want) {
+				t.Errorf("UpgradeSchema() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// A failing step leaves the envelope untouched
+	envelope := newEnvelope("1.1.0")
+	if _, err := UpgradeSchema(envelope, "1.2.0"); err == nil {
+		t.Error("UpgradeSchema() succeeded without the size required by the 1.2.0 step")
+	}
+	if envelope.Meta.Schema != "ptd.v1.test_widget@1.1.0" {
+		t.Errorf("Schema = %s after a failed upgrade", envelope.Meta.Schema)
+	}
+}
+
+func TestRegisterSchemaUpgrade_Invalid(t *testing.T) {
+	noop := func(map[string]interface{}) error { return nil }
+	if err := RegisterSchemaUpgrade("test_widget", "1.1.0", "1.0.0", noop); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("Expected ErrInvalidSchema for a downgrade step, got %v", err)
+	}
+	if err := RegisterSchemaUpgrade("", "1.0.0", "1.1.0", noop); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+	registerTestWidgetUpgrades(t)
+	if err := RegisterSchemaUpgrade("test_widget", "1.0.0", "1.1.0", noop); !errors.Is(err, ErrDuplicateEntity) {
+		t.Errorf("Expected ErrDuplicateEntity, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package ptd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// specialFinals are Final values that record a result without a set score
+var specialFinals = []string{"W/O", "WO", "RET", "DQ", "DSQ"}
+
+// IsSpecial reports whether the score records a walkover, retirement or
+// disqualification rather than a completed set score
+func (s *Score) IsSpecial() bool {
+	return s.Walkover || s.Retirement || s.Disqualify ||
+		contains(specialFinals, strings.ToUpper(strings.TrimSpace(s.Final)))
+}
+
+// SetWins returns the number of sets won by the home and away sides
+func (s *Score) SetWins() (home, away int) {
+	for _, set := range s.Sets {
+		switch {
+		case set.HomeScore > set.AwayScore:
+			home++
+		case set.AwayScore > set.HomeScore:
+			away++
+		}
+	}
+	return home, away
+}
+
+// ValidateFinal checks that Final is in "home-away" form and agrees with the
+// sets won. Special finals (walkover, retirement, disqualification) are only
+// checked when they carry a set count.
+func (s *Score) ValidateFinal() error {
+	if s.Final == "" {
+		if s.IsSpecial() {
+			return nil
+		}
+		return newValidationError(ErrMissingField, TypeMatch, "match.score.final", "match.score.final is required")
+	}
+
+	finalHome, finalAway, ok := parseFinal(s.Final)
+	if !ok {
+		if s.IsSpecial() {
+			return nil
+		}
+		return newValidationError(ErrInvalidFormat, TypeMatch, "match.score.final", "invalid match.score.final: %s", s.Final)
+	}
+
+	if len(s.Sets) == 0 {
+		return nil
+	}
+
+	home, away := s.SetWins()
+	if home != finalHome || away != finalAway {
+		return newValidationError(ErrValidation, TypeMatch, "match.score.final", "match.score.final %s does not match set wins %d-%d", s.Final, home, away)
+	}
+
+	return nil
+}
+
+// Validate checks the score for internal consistency and, when rules are
+// given, against the scoring system (number of sets and game points).
+// Pass Event.EffectiveRules to apply an event's overrides of the tournament
+// rules. Special results are exempt from the rule checks.
+func (s *Score) Validate(rules *Rules) error {
+	var errs []error
+
+	if err := s.ValidateFinal(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, set := range s.Sets {
+		if set.HomeScore < 0 || set.AwayScore < 0 {
+			errs = append(errs, newValidationError(ErrValidation, TypeMatch, fmt.Sprintf("match.score.sets[%d]", i), "set %d has a negative score", i+1))
+		}
+	}
+
+	if rules != nil && !s.IsSpecial() {
+		if bestOf := rules.BestOf(); bestOf > 0 {
+			if len(s.Sets) > bestOf {
+				errs = append(errs, newValidationError(ErrValidation, TypeMatch, "match.score.sets", "%d sets played in a best of %d", len(s.Sets), bestOf))
+			}
+
+			home, away := s.SetWins()
+			setsToWin := bestOf/2 + 1
+			if len(s.Sets) > 0 && home != setsToWin && away != setsToWin {
+				errs = append(errs, newValidationError(ErrValidation, TypeMatch, "match.score.sets", "neither side won %d sets in a best of %d", setsToWin, bestOf))
+			}
+		}
+
+		if rules.GamePoints > 0 {
+			for i, set := range s.Sets {
+				if max(set.HomeScore, set.AwayScore) < rules.GamePoints {
+					errs = append(errs, newValidationError(ErrValidation, TypeMatch, fmt.Sprintf("match.score.sets[%d]", i), "set %d was not played to %d points", i+1, rules.GamePoints))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// BestOf returns N for a "best_of_N" scoring system, or 0 if the scoring
+// system does not define a set count
+func (r *Rules) BestOf() int {
+	n, ok := strings.CutPrefix(r.ScoringSystem, "best_of_")
+	if !ok {
+		return 0
+	}
+	bestOf, err := strconv.Atoi(n)
+	if err != nil || bestOf <= 0 {
+		return 0
+	}
+	return bestOf
+}
+
+// VerifyScoreIntegrity cross-checks the match status, score and winner.
+// A completed match must have a score; a non-special score must name a
+// winner who is one of the entries and who won more sets; and the score
+// itself must pass Score.Validate. All violations are returned joined.
+func (m *Match) VerifyScoreIntegrity(rules *Rules) error {
+	var errs []error
+
+	if m.Status == "completed" && m.Score == nil {
+		errs = append(errs, newValidationError(ErrMissingField, TypeMatch, "match.score", "completed match has no score"))
+	}
+
+	if m.Score != nil {
+		if err := m.Score.Validate(rules); err != nil {
+			errs = append(errs, err)
+		}
+
+		if m.Score.Final != "" && !m.Score.IsSpecial() {
+			if m.Winner == "" {
+				errs = append(errs, newValidationError(ErrMissingField, TypeMatch, "match.winner", "scored match has no winner"))
+			} else if err := m.verifyWinnerSide(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// UpdateScore corrects the score of a completed match, recording the old
+// and new scores in ScoreHistory. Reason and updatedBy are required so every
+// correction can be audited. The new score is checked with Score.Validate;
+// the match carries no rules, so only internal consistency is checked.
+func (m *Match) UpdateScore(newScore *Score, reason string, updatedBy string) error {
+	if m.Status != "completed" {
+		return newValidationError(ErrValidation, TypeMatch, "match.status", "cannot update score of %s match", m.Status)
+	}
+	if newScore == nil {
+		return newValidationError(ErrMissingField, TypeMatch, "match.score", "new score is required")
+	}
+	if reason == "" {
+		return newValidationError(ErrMissingField, TypeMatch, "match.score_history.reason", "reason is required for a score update")
+	}
+	if updatedBy == "" {
+		return newValidationError(ErrMissingField, TypeMatch, "match.score_history.updated_by", "updated_by is required for a score update")
+	}
+	if err := newScore.Validate(nil); err != nil {
+		return err
+	}
+
+	var oldScore Score
+	if m.Score != nil {
+		oldScore = *m.Score
+	}
+
+	m.ScoreHistory = append(m.ScoreHistory, ScoreRevision{
+		OldScore:  oldScore,
+		NewScore:  *newScore,
+		Reason:    reason,
+		UpdatedBy: updatedBy,
+		UpdatedAt: time.Now(),
+	})
+	m.Score = newScore
+
+	return nil
+}
+
+// verifyWinnerSide checks that the winner is an entry of the match and won more sets
+func (m *Match) verifyWinnerSide() error {
+	home, away := m.Score.SetWins()
+
+	switch {
+	case m.HomeEntry != nil && m.Winner == m.HomeEntry.EntryID:
+		if home <= away && len(m.Score.Sets) > 0 {
+			return newValidationError(ErrValidation, TypeMatch, "match.winner", "winner is home entry but sets are %d-%d", home, away)
+		}
+	case m.AwayEntry != nil && m.Winner == m.AwayEntry.EntryID:
+		if away <= home && len(m.Score.Sets) > 0 {
+			return newValidationError(ErrValidation, TypeMatch, "match.winner", "winner is away entry but sets are %d-%d", home, away)
+		}
+	case m.HomeEntry != nil || m.AwayEntry != nil:
+		return newValidationError(ErrValidation, TypeMatch, "match.winner", "winner %s is not an entry of this match", m.Winner)
+	}
+
+	return nil
+}
+
+// parseFinal parses a "home-away" final score
+func parseFinal(final string) (home, away int, ok bool) {
+	h, a, found := strings.Cut(strings.TrimSpace(final), "-")
+	if !found {
+		return 0, 0, false
+	}
+	home, err := strconv.Atoi(strings.TrimSpace(h))
+	if err != nil {
+		return 0, 0, false
+	}
+	away, err = strconv.Atoi(strings.TrimSpace(a))
+	if err != nil {
+		return 0, 0, false
+	}
+	return home, away, true
+}
@@ -0,0 +1,184 @@
+package ptd
+
+import (
+	"errors"
+	"testing"
+)
+
+func newTestScore(final string, sets ...[2]int) *Score {
+	score := &Score{Final: final}
+	for i, s := range sets {
+		score.Sets = append(score.Sets, SetScore{SetNumber: i + 1, HomeScore: s[0], AwayScore: s[1]})
+	}
+	return score
+}
+
+func TestScore_SetWins(t *testing.T) {
+	score := newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10})
+	home, away := score.SetWins()
+	if home != 3 || away != 1 {
+		t.Errorf("SetWins() = %d-%d, want 3-1", home, away)
+	}
+}
+
+func TestScore_ValidateFinal(t *testing.T) {
+	tests := []struct {
+		name    string
+		score   *Score
+		wantErr bool
+	}{
+		{"matching final", newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10}), false},
+		{"mismatched final", newTestScore("3-0", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10}), true},
+		{"malformed final", newTestScore("three-one"), true},
+		{"missing final", newTestScore(""), true},
+		{"walkover flag", &Score{Walkover: true}, false},
+		{"special final", newTestScore("W/O"), false},
+		{"final without sets", newTestScore("3-2"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.score.ValidateFinal()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFinal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScore_Validate(t *testing.T) {
+	rules := &Rules{ScoringSystem: "best_of_5", GamePoints: 11}
+
+	valid := newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10})
+	if err := valid.Validate(rules); err != nil {
+		t.Errorf("Valid score failed validation: %v", err)
+	}
+
+	tooFewSets := newTestScore("2-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7})
+	if err := tooFewSets.Validate(rules); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for unfinished best of 5, got %v", err)
+	}
+
+	shortSet := newTestScore("3-0", [2]int{11, 9}, [2]int{9, 7}, [2]int{11, 7})
+	if err := shortSet.Validate(rules); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for set below game points, got %v", err)
+	}
+
+	retired := newTestScore("1-0", [2]int{11, 9})
+	retired.Retirement = true
+	if err := retired.Validate(rules); err != nil {
+		t.Errorf("Retirement should be exempt from rule checks: %v", err)
+	}
+
+	if err := tooFewSets.Validate(nil); err != nil {
+		t.Errorf("Score without rules should only be checked for consistency: %v", err)
+	}
+}
+
+func TestRules_BestOf(t *testing.T) {
+	tests := map[string]int{
+		"best_of_5": 5,
+		"best_of_7": 7,
+		"best_of_x": 0,
+		"rally":     0,
+		"":          0,
+	}
+	for system, want := range tests {
+		r := &Rules{ScoringSystem: system}
+		if got := r.BestOf(); got != want {
+			t.Errorf("BestOf(%q) = %d, want %d", system, got, want)
+		}
+	}
+}
+
+func TestMatch_VerifyScoreIntegrity(t *testing.T) {
+	homeID := GenerateID(TypeEntry)
+	awayID := GenerateID(TypeEntry)
+	rules := &Rules{ScoringSystem: "best_of_5", GamePoints: 11}
+
+	newMatch := func() Match {
+		return Match{
+			Status:    "completed",
+			HomeEntry: &EntryRef{EntryID: homeID},
+			AwayEntry: &EntryRef{EntryID: awayID},
+			Winner:    homeID,
+			Score:     newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10}),
+		}
+	}
+
+	valid := newMatch()
+	if err := valid.VerifyScoreIntegrity(rules); err != nil {
+		t.Errorf("Valid match failed integrity check: %v", err)
+	}
+
+	noScore := newMatch()
+	noScore.Score = nil
+	if err := noScore.VerifyScoreIntegrity(rules); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField for completed match without score, got %v", err)
+	}
+
+	noWinner := newMatch()
+	noWinner.Winner = ""
+	if err := noWinner.VerifyScoreIntegrity(rules); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField for scored match without winner, got %v", err)
+	}
+
+	wrongWinner := newMatch()
+	wrongWinner.Winner = awayID
+	if err := wrongWinner.VerifyScoreIntegrity(rules); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for winner who lost, got %v", err)
+	}
+
+	walkover := newMatch()
+	walkover.Winner = ""
+	walkover.Score = &Score{Final: "W/O", Walkover: true}
+	if err := walkover.VerifyScoreIntegrity(rules); err != nil {
+		t.Errorf("Walkover without winner should pass: %v", err)
+	}
+
+	// Multiple violations are all reported
+	multi := newMatch()
+	multi.Winner = ""
+	multi.Score.Final = "3-0"
+	err := multi.VerifyScoreIntegrity(rules)
+	if !errors.Is(err, ErrMissingField) || !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected both violations to be reported, got %v", err)
+	}
+}
+
+func TestMatch_UpdateScore(t *testing.T) {
+	original := newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10})
+	match := Match{Status: "completed", Score: original}
+
+	corrected := newTestScore("3-0", [2]int{11, 9}, [2]int{11, 8}, [2]int{11, 7})
+	if err := match.UpdateScore(corrected, "scoresheet error", "referee"); err != nil {
+		t.Fatalf("UpdateScore failed: %v", err)
+	}
+	if match.Score != corrected {
+		t.Error("Score was not replaced")
+	}
+	if len(match.ScoreHistory) != 1 {
+		t.Fatalf("Expected 1 revision, got %d", len(match.ScoreHistory))
+	}
+	rev := match.ScoreHistory[0]
+	if rev.OldScore.Final != "3-1" || rev.NewScore.Final != "3-0" || rev.Reason != "scoresheet error" || rev.UpdatedBy != "referee" || rev.UpdatedAt.IsZero() {
+		t.Errorf("Unexpected revision: %+v", rev)
+	}
+
+	// Invalid score is rejected and history is untouched
+	if err := match.UpdateScore(newTestScore("3-0", [2]int{11, 9}), "typo", "referee"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for invalid score, got %v", err)
+	}
+	if len(match.ScoreHistory) != 1 || match.Score != corrected {
+		t.Error("Rejected update should not change the match")
+	}
+
+	if err := match.UpdateScore(corrected, "", "referee"); !errors.Is(err, ErrMissingField) {
+		t.Errorf("Expected ErrMissingField without reason, got %v", err)
+	}
+
+	scheduled := Match{Status: "scheduled"}
+	if err := scheduled.UpdateScore(corrected, "early", "referee"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation for scheduled match, got %v", err)
+	}
+}
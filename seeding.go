@@ -0,0 +1,187 @@
+package ptd
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+// SeedingAnomaly describes two seeds meeting earlier than a properly seeded
+// single-elimination draw allows
+type SeedingAnomaly struct {
+	MatchID       string `json:"match_id"`
+	RoundName     string `json:"round_name"`
+	HomeEntryID   string `json:"home_entry_id"`
+	AwayEntryID   string `json:"away_entry_id"`
+	HomeSeed      int    `json:"home_seed"`
+	AwaySeed      int    `json:"away_seed"`
+	ExpectedRound int    `json:"expected_round"` // Earliest round (1-based) the seeds should meet
+}
+
+// DetectSeedingAnomalies checks a single-elimination draw for seeded
+// entries meeting too early. In a standard draw seeds 1 and 2 can only meet
+// in the final, seeds 3 and 4 no earlier than the semifinals, seeds 5 to 8
+// no earlier than the quarterfinals, and so on.
+//
+// The matches should all belong to one draw. They are grouped into rounds
+// by RoundID and each round is numbered by its size, the round with a
+// single match being the final; the draw size is the number of entries
+// rounded up to a power of two. Seeds are taken from the entries, falling
+// back to the seed on the match's entry reference.
+//
+// Returns ErrInvalidFormat if none of the entries has a seed assigned.
+func DetectSeedingAnomalies(matches []Envelope[Match], entries []Envelope[Entry]) ([]SeedingAnomaly, error) {
+	seeds := entrySeeds(entries)
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("%w: no entry has a seed assigned", ErrInvalidFormat)
+	}
+
+	drawSize := 2
+	for drawSize < len(entries) {
+		drawSize *= 2
+	}
+	totalRounds := bits.Len(uint(drawSize)) - 1
+
+	roundSizes := make(map[string]int)
+	for _, match := range matches {
+		roundSizes[match.Spec.RoundID]++
+	}
+
+	var anomalies []SeedingAnomaly
+	for _, match := range matches {
+		m := match.Spec
+		homeSeed, awaySeed := refSeed(m.HomeEntry, seeds), refSeed(m.AwayEntry, seeds)
+		if homeSeed == 0 || awaySeed == 0 {
+			continue
+		}
+
+		size := roundSizes[m.RoundID]
+		round := totalRounds - ceilLog2(size)
+		expected := totalRounds - ceilLog2(max(homeSeed, awaySeed)) + 1
+		if round >= expected {
+			continue
+		}
+
+		anomalies = append(anomalies, SeedingAnomaly{
+			MatchID:       match.ID,
+			RoundName:     eliminationRoundName(size),
+			HomeEntryID:   m.HomeEntry.EntryID,
+			AwayEntryID:   m.AwayEntry.EntryID,
+			HomeSeed:      homeSeed,
+			AwaySeed:      awaySeed,
+			ExpectedRound: expected,
+		})
+	}
+
+	return anomalies, nil
+}
+
+// entrySeeds maps the IDs of seeded entries to their seeds
+func entrySeeds(entries []Envelope[Entry]) map[string]int {
+	seeds := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Spec.Seed != nil && *entry.Spec.Seed > 0 {
+			seeds[entry.ID] = *entry.Spec.Seed
+		}
+	}
+	return seeds
+}
+
+// refSeed returns the seed of an entry reference, looked up in seeds and
+// falling back to the reference's own seed; 0 means unseeded
+func refSeed(ref *EntryRef, seeds map[string]int) int {
+	if ref == nil {
+		return 0
+	}
+	if seed, ok := seeds[ref.EntryID]; ok {
+		return seed
+	}
+	if ref.Seed != nil && *ref.Seed > 0 {
+		return *ref.Seed
+	}
+	return 0
+}
+
+// ceilLog2 returns the smallest k with 2^k >= n
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n - 1))
+}
+
+// eliminationRoundName names a single-elimination round by its number of matches
+func eliminationRoundName(matches int) string {
+	switch {
+	case matches <= 1:
+		return "Final"
+	case matches == 2:
+		return "Semifinals"
+	case matches <= 4:
+		return "Quarterfinals"
+	default:
+		return fmt.Sprintf("Round of %d", 2<<ceilLog2(matches))
+	}
+}
+
+// RotateSeedPositions returns a copy of entries with the entries seeded
+// from and to swapped, each recording the change in its SeedingHistory.
+// The input entries are not modified. Returns ErrValidation if from or to
+// is outside 1..len(entries), if they are equal, or if either seed is held
+// by no entry or by more than one.
+func RotateSeedPositions(entries []Envelope[Entry], from, to int) ([]Envelope[Entry], error) {
+	for _, seed := range []int{from, to} {
+		if seed < 1 || seed > len(entries) {
+			return nil, newValidationError(ErrValidation, TypeEntry, "entry.seed", "seed %d is out of range 1-%d", seed, len(entries))
+		}
+	}
+	if from == to {
+		return nil, newValidationError(ErrValidation, TypeEntry, "entry.seed", "cannot swap seed %d with itself", from)
+	}
+
+	holder := func(seed int) (int, error) {
+		index := -1
+		for i, entry := range entries {
+			if entry.Spec.Seed == nil || *entry.Spec.Seed != seed {
+				continue
+			}
+			if index >= 0 {
+				return 0, newValidationError(ErrValidation, TypeEntry, "entry.seed", "seed %d is assigned to both %s and %s", seed, entries[index].ID, entry.ID)
+			}
+			index = i
+		}
+		if index < 0 {
+			return 0, newValidationError(ErrValidation, TypeEntry, "entry.seed", "no entry has seed %d", seed)
+		}
+		return index, nil
+	}
+	fromIndex, err := holder(from)
+	if err != nil {
+		return nil, err
+	}
+	toIndex, err := holder(to)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := make([]Envelope[Entry], len(entries))
+	copy(rotated, entries)
+
+	now := time.Now()
+	reseed := func(i, seed int, other string) {
+		spec := &rotated[i].Spec
+		history := make([]SeedRevision, len(spec.SeedingHistory), len(spec.SeedingHistory)+1)
+		copy(history, spec.SeedingHistory)
+		spec.SeedingHistory = append(history, SeedRevision{
+			OldSeed:     *spec.Seed,
+			NewSeed:     seed,
+			SwappedWith: other,
+			UpdatedAt:   now,
+		})
+		spec.Seed = &seed
+	}
+	reseed(fromIndex, to, entries[toIndex].ID)
+	reseed(toIndex, from, entries[fromIndex].ID)
+
+	return rotated, nil
+}
@@ -0,0 +1,132 @@
+package ptd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDetectSeedingAnomalies(t *testing.T) {
+	// 16-entry draw with the top 8 seeded
+	var entries []Envelope[Entry]
+	for i := 0; i < 16; i++ {
+		entry := Envelope[Entry]{ID: GenerateID(TypeEntry), Type: TypeEntry}
+		if i < 8 {
+			entry.Spec.Seed = intPtr(i + 1)
+		}
+		entries = append(entries, entry)
+	}
+	seed := func(n int) string { return entries[n-1].ID }
+	unseeded := func(n int) string { return entries[7+n].ID }
+
+	match := func(round, home, away string) Envelope[Match] {
+		return Envelope[Match]{ID: GenerateID(TypeMatch), Type: TypeMatch, Spec: Match{
+			RoundID:   round,
+			HomeEntry: &EntryRef{EntryID: home},
+			AwayEntry: &EntryRef{EntryID: away},
+		}}
+	}
+
+	var matches []Envelope[Match]
+	// Round of 16: seeds 1 and 2 meet, everyone else plays an unseeded entry
+	matches = append(matches, match("r16", seed(1), seed(2)))
+	for i := 3; i <= 8; i++ {
+		matches = append(matches, match("r16", seed(i), unseeded(i)))
+	}
+	matches = append(matches, match("r16", unseeded(1), unseeded(2)))
+	// Quarterfinals: 3 vs 6 is fine, 4 vs 3 should wait for the semifinals
+	matches = append(matches,
+		match("qf", seed(3), seed(6)),
+		match("qf", seed(4), seed(5)),
+		match("qf", seed(7), seed(8)),
+		match("qf", seed(1), unseeded(1)))
+	// Final between seeds 1 and 2 is expected
+	matches = append(matches, match("f", seed(1), seed(2)))
+
+	anomalies, err := DetectSeedingAnomalies(matches, entries)
+	if err != nil {
+		t.Fatalf("DetectSeedingAnomalies failed: %v", err)
+	}
+	if len(anomalies) != 1 {
+		t.Fatalf("Expected 1 anomaly, got %d: %+v", len(anomalies), anomalies)
+	}
+	got := anomalies[0]
+	if got.MatchID != matches[0].ID || got.RoundName != "Round of 16" || got.HomeSeed != 1 || got.AwaySeed != 2 || got.ExpectedRound != 4 {
+		t.Errorf("Unexpected anomaly: %+v", got)
+	}
+
+	// Seeds 3 and 4 meeting in the quarterfinals
+	matches[8] = match("qf", seed(4), seed(3))
+	anomalies, err = DetectSeedingAnomalies(matches, entries)
+	if err != nil {
+		t.Fatalf("DetectSeedingAnomalies failed: %v", err)
+	}
+	if len(anomalies) != 2 || anomalies[1].RoundName != "Quarterfinals" || anomalies[1].ExpectedRound != 3 {
+		t.Errorf("Expected quarterfinal anomaly, got %+v", anomalies)
+	}
+
+	for i := range entries {
+		entries[i].Spec.Seed = nil
+	}
+	if _, err := DetectSeedingAnomalies(matches, entries); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat without seeds, got %v", err)
+	}
+}
+
+func TestRotateSeedPositions(t *testing.T) {
+	var entries []Envelope[Entry]
+	for i := 1; i <= 4; i++ {
+		entries = append(entries, Envelope[Entry]{ID: fmt.Sprintf("ptd:entry:%d", i), Type: TypeEntry, Spec: Entry{Seed: intPtr(i)}})
+	}
+	entries = append(entries, Envelope[Entry]{ID: "ptd:entry:wildcard", Type: TypeEntry})
+
+	rotated, err := RotateSeedPositions(entries, 3, 4)
+	if err != nil {
+		t.Fatalf("RotateSeedPositions() error = %v", err)
+	}
+	if *rotated[2].Spec.Seed != 4 || *rotated[3].Spec.Seed != 3 || *rotated[0].Spec.Seed != 1 {
+		t.Errorf("seeds = %d, %d, %d; want 1, 4, 3", *rotated[0].Spec.Seed, *rotated[2].Spec.Seed, *rotated[3].Spec.Seed)
+	}
+	history := rotated[2].Spec.SeedingHistory
+	if len(history) != 1 || history[0].OldSeed != 3 || history[0].NewSeed != 4 || history[0].SwappedWith != "ptd:entry:4" {
+		t.Errorf("SeedingHistory = %+v", history)
+	}
+	if *entries[2].Spec.Seed != 3 || entries[2].Spec.SeedingHistory != nil {
+		t.Error("RotateSeedPositions() modified its input")
+	}
+
+	// Swapping back adds to the history
+	restored, err := RotateSeedPositions(rotated, 4, 3)
+	if err != nil {
+		t.Fatalf("RotateSeedPositions() error = %v", err)
+	}
+	if *restored[2].Spec.Seed != 3 || len(restored[2].Spec.SeedingHistory) != 2 || len(rotated[2].Spec.SeedingHistory) != 1 {
+		t.Errorf("restored entry = %+v", restored[2].Spec)
+	}
+}
+
+func TestRotateSeedPositions_Errors(t *testing.T) {
+	entries := []Envelope[Entry]{
+		{ID: "ptd:entry:1", Spec: Entry{Seed: intPtr(1)}},
+		{ID: "ptd:entry:2", Spec: Entry{Seed: intPtr(1)}},
+		{ID: "ptd:entry:3"},
+	}
+
+	tests := []struct {
+		name     string
+		from, to int
+	}{
+		{"out of range", 1, 4},
+		{"zero", 0, 1},
+		{"same seed", 2, 2},
+		{"unassigned seed", 1, 3},
+		{"duplicate seed", 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RotateSeedPositions(entries, tt.from, tt.to); !errors.Is(err, ErrValidation) {
+				t.Errorf("RotateSeedPositions(%d, %d) error = %v, want ErrValidation", tt.from, tt.to, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package ptd
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pagination limits for PackageServer list endpoints
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// Pagination response headers
+const (
+	HeaderNextCursor = "X-PTD-Next-Cursor"
+	HeaderTotalCount = "X-PTD-Total-Count"
+)
+
+// PackageServer serves the contents of a package over HTTP:
+//
+//	GET /manifest          the package manifest
+//	GET /entities/{type}   a page of entities of the given type
+//
+// List endpoints accept cursor and limit query parameters and return
+// entities in ULID order. The cursor is the base64-encoded ULID of the last
+// entity of the previous page, as returned in the X-PTD-Next-Cursor header;
+// for an entity whose ID holds no ULID it encodes the full ID instead.
+type PackageServer struct {
+	pkg *Package
+	mux *http.ServeMux
+}
+
+// NewPackageServer creates a server for the given package
+func NewPackageServer(pkg *Package) *PackageServer {
+	s := &PackageServer{pkg: pkg, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /manifest", s.handleManifest)
+	s.mux.HandleFunc("GET /entities/{type}", s.handleEntities)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *PackageServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *PackageServer) handleManifest(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.pkg.Manifest)
+}
+
+func (s *PackageServer) handleEntities(w http.ResponseWriter, r *http.Request) {
+	entityType := r.PathValue("type")
+	if _, exists := s.pkg.Manifest.Entities[entityType]; !exists {
+		http.Error(w, fmt.Sprintf("unknown entity type %q", entityType), http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := DefaultPageLimit
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, MaxPageLimit)
+	}
+
+	var after string
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		after = cursor
+	}
+
+	page, next, total, err := s.pkg.paginateEntities(entityType, after, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(HeaderTotalCount, strconv.Itoa(total))
+	if next != "" {
+		w.Header().Set(HeaderNextCursor, encodeCursor(next))
+	}
+	if page == nil {
+		page = []json.RawMessage{}
+	}
+	writeJSON(w, page)
+}
+
+// keyedLine is an NDJSON line with its pagination key
+type keyedLine struct {
+	key  string
+	line json.RawMessage
+}
+
+// pageHeap is a max-heap of lines by key, holding the smallest keys seen
+type pageHeap []keyedLine
+
+func (h pageHeap) Len() int           { return len(h) }
+func (h pageHeap) Less(i, j int) bool { return h[i].key > h[j].key }
+func (h pageHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pageHeap) Push(x any)        { *h = append(*h, x.(keyedLine)) }
+func (h *pageHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// paginateEntities returns up to limit entities of a type whose key (see
+// entityKey) sorts after the given one, in key order, the key to continue
+// from if more remain, and the number of entities of the type.
+//
+// The file is streamed once, keeping only the page being assembled. IDs are
+// generated with monotonic ULIDs and appended as they are created, so the
+// file is normally in key order already: lines up to the cursor are skipped
+// and, once the page is full, later lines are only counted. Lines out of
+// order, such as from merged packages, still land on the right page.
+func (p *Package) paginateEntities(entityType, after string, limit int) ([]json.RawMessage, string, int, error) {
+	var page pageHeap
+	total, remaining := 0, 0
+	var decodeErr error
+	err := p.scanEntityLines(entityType, func(_ int, line []byte) bool {
+		id, err := envelopeID(line)
+		if err != nil {
+			decodeErr = err
+			return false
+		}
+		total++
+
+		key := entityKey(id)
+		if key <= after {
+			return true
+		}
+		remaining++
+		if len(page) == limit {
+			if key >= page[0].key {
+				return true
+			}
+			heap.Pop(&page)
+		}
+		heap.Push(&page, keyedLine{key: key, line: json.RawMessage(bytes.Clone(line))})
+		return true
+	})
+	if err == nil {
+		err = decodeErr
+	}
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	sort.Slice(page, func(i, j int) bool {
+		return page[i].key < page[j].key
+	})
+	lines := make([]json.RawMessage, len(page))
+	for i, entry := range page {
+		lines[i] = entry.line
+	}
+
+	var next string
+	if remaining > len(page) {
+		next = page[len(page)-1].key
+	}
+	return lines, next, total, nil
+}
+
+// idULID returns the upper-case ULID part of a PTD ID, or the ID itself if
+// it is not in ptd:type:identifier form
+func idULID(id string) string {
+	if _, _, identifier, err := ParseID(id); err == nil {
+		return strings.ToUpper(identifier)
+	}
+	return strings.ToUpper(id)
+}
+
+// entityKey returns the key entities are paginated by: the ULID from
+// idULID if the ID holds one, otherwise the full ID
+func entityKey(id string) string {
+	if key := idULID(id); IsULID(key) {
+		return key
+	}
+	return id
+}
+
+// encodeCursor encodes an entity key as a pagination cursor
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+// decodeCursor decodes a pagination cursor to its entity key
+func decodeCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(cursor, "="))
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid cursor", ErrInvalidFormat)
+	}
+	key := string(data)
+	if key == "" || !utf8.ValidString(key) || strings.ContainsFunc(key, unicode.IsControl) || entityKey(key) != key {
+		return "", fmt.Errorf("%w: invalid cursor", ErrInvalidFormat)
+	}
+	return key, nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
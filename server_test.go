@@ -0,0 +1,156 @@
+package ptd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// fetchPage requests a page of events and decodes the response
+func fetchPage(t *testing.T, server http.Handler, query string) ([]Envelope[Event], http.Header) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/entities/event"+query, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s returned %d: %s", query, rec.Code, rec.Body.String())
+	}
+	var events []Envelope[Event]
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatalf("Failed to decode page: %v", err)
+	}
+	return events, rec.Header()
+}
+
+func TestPackageServer_Pagination(t *testing.T) {
+	pkg, ids := newEditTestPackage(t, 250)
+	server := NewPackageServer(pkg)
+
+	// First page uses the default limit
+	first, header := fetchPage(t, server, "")
+	if len(first) != DefaultPageLimit {
+		t.Fatalf("Expected %d entities on first page, got %d", DefaultPageLimit, len(first))
+	}
+	if header.Get(HeaderTotalCount) != "250" {
+		t.Errorf("Unexpected total count: %s", header.Get(HeaderTotalCount))
+	}
+	if first[0].ID != ids[0] {
+		t.Errorf("First page should start with the oldest entity")
+	}
+	cursor := header.Get(HeaderNextCursor)
+	if cursor == "" {
+		t.Fatal("Expected next cursor on first page")
+	}
+
+	// Middle page
+	middle, header := fetchPage(t, server, "?limit=100&cursor="+cursor)
+	if len(middle) != 100 || middle[0].ID != ids[100] || middle[99].ID != ids[199] {
+		t.Fatalf("Unexpected middle page: %d entities", len(middle))
+	}
+	cursor = header.Get(HeaderNextCursor)
+
+	// Last page has no next cursor
+	last, header := fetchPage(t, server, "?limit=100&cursor="+cursor)
+	if len(last) != 50 || last[49].ID != ids[249] {
+		t.Fatalf("Unexpected last page: %d entities", len(last))
+	}
+	if header.Get(HeaderNextCursor) != "" {
+		t.Error("Last page should not have a next cursor")
+	}
+
+	// Limits above the maximum are capped
+	all, _ := fetchPage(t, server, "?limit="+strconv.Itoa(MaxPageLimit+1))
+	if len(all) != 250 {
+		t.Errorf("Expected all 250 entities, got %d", len(all))
+	}
+}
+
+func TestPackageServer_PaginationNonULIDs(t *testing.T) {
+	pkg := NewPackage("Legacy IDs")
+	defer pkg.Cleanup()
+	ids := []string{"ptd:event:open", "ptd:event:OPEN", "legacy-7", GenerateID(TypeEvent)}
+	var events []interface{}
+	for _, id := range ids {
+		events = append(events, Envelope[Event]{ID: id, Type: TypeEvent, Spec: Event{Name: MultiName{Default: id}}, Meta: Meta{Schema: "ptd.v1.event@1.0.0"}})
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("AddEntities failed: %v", err)
+	}
+	server := NewPackageServer(pkg)
+
+	// The server accepts every cursor it hands out and returns each entity once
+	seen := make(map[string]bool)
+	query := "?limit=1"
+	for page := 0; page < len(ids); page++ {
+		events, header := fetchPage(t, server, query)
+		if len(events) != 1 || seen[events[0].ID] {
+			t.Fatalf("Unexpected page %d: %+v", page, events)
+		}
+		seen[events[0].ID] = true
+		query = "?limit=1&cursor=" + header.Get(HeaderNextCursor)
+	}
+	if len(seen) != len(ids) || query != "?limit=1&cursor=" {
+		t.Errorf("Expected all %d entities and no final cursor, got %v, %q", len(ids), seen, query)
+	}
+}
+
+func TestPackageServer_PaginationOrder(t *testing.T) {
+	pkg := NewPackage("Merged")
+	defer pkg.Cleanup()
+	ids := make([]string, 5)
+	for i := range ids {
+		ids[i] = GenerateID(TypeEvent)
+	}
+	// Entities appended out of ULID order, as after merging two packages
+	var events []interface{}
+	for _, i := range []int{3, 0, 4, 1, 2} {
+		events = append(events, Envelope[Event]{ID: ids[i], Type: TypeEvent, Spec: Event{Name: MultiName{Default: ids[i]}}, Meta: Meta{Schema: "ptd.v1.event@1.0.0"}})
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("AddEntities failed: %v", err)
+	}
+	server := NewPackageServer(pkg)
+
+	first, header := fetchPage(t, server, "?limit=2")
+	if len(first) != 2 || first[0].ID != ids[0] || first[1].ID != ids[1] {
+		t.Fatalf("Unexpected first page: %+v", first)
+	}
+	// The cursor is the base64-encoded ULID of the last entity
+	cursor := header.Get(HeaderNextCursor)
+	if decoded, err := base64.RawURLEncoding.DecodeString(cursor); err != nil || string(decoded) != idULID(ids[1]) {
+		t.Errorf("Cursor %q decodes to %q, want ULID %s", cursor, decoded, idULID(ids[1]))
+	}
+
+	rest, header := fetchPage(t, server, "?cursor="+cursor)
+	if len(rest) != 3 || rest[0].ID != ids[2] || rest[2].ID != ids[4] || header.Get(HeaderNextCursor) != "" {
+		t.Errorf("Unexpected last page: %+v", rest)
+	}
+	if header.Get(HeaderTotalCount) != "5" {
+		t.Errorf("Unexpected total count: %s", header.Get(HeaderTotalCount))
+	}
+}
+
+func TestPackageServer_Errors(t *testing.T) {
+	pkg, _ := newEditTestPackage(t, 1)
+	server := NewPackageServer(pkg)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/entities/event?limit=0", http.StatusBadRequest},
+		{"/entities/event?limit=abc", http.StatusBadRequest},
+		{"/entities/event?cursor=not-a-cursor", http.StatusBadRequest},
+		{"/entities/match", http.StatusNotFound},
+		{"/manifest", http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.code {
+			t.Errorf("GET %s returned %d, want %d", tt.path, rec.Code, tt.code)
+		}
+	}
+}
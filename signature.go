@@ -103,6 +103,8 @@ func attachSignature(envelope interface{}, sig *Signature) error {
 		e.Meta.Signature = sig
 	case *Envelope[Entry]:
 		e.Meta.Signature = sig
+	case *Envelope[json.RawMessage]:
+		e.Meta.Signature = sig
 	default:
 		// Fallback: try to set via JSON marshaling/unmarshaling
 		data, err := json.Marshal(envelope)
@@ -210,6 +212,8 @@ func extractSignature(envelope interface{}) (*Signature, error) {
 		return e.Meta.Signature, nil
 	case *Envelope[Entry]:
 		return e.Meta.Signature, nil
+	case *Envelope[json.RawMessage]:
+		return e.Meta.Signature, nil
 	default:
 		// Fallback: try to extract via JSON
 		data, err := json.Marshal(envelope)
@@ -72,7 +72,7 @@ func TestSignAndVerifyEnvelope(t *testing.T) {
 
 	// Create envelope
 	tournament := Tournament{
-		Name:      "Test Tournament",
+		Name:      MultiName{Default: "Test Tournament"},
 		StartDate: time.Now(),
 		EndDate:   time.Now().Add(24 * time.Hour),
 		Status:    "published",
@@ -128,7 +128,7 @@ func TestVerifyWithWrongKey(t *testing.T) {
 	envelope := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{
 			Schema:    "ptd.v1.tournament@1.0.0",
 			Version:   1,
@@ -156,7 +156,7 @@ func TestVerifyWithoutSignature(t *testing.T) {
 	envelope := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{
 			Schema:    "ptd.v1.tournament@1.0.0",
 			Version:   1,
@@ -215,7 +215,7 @@ func TestVerifyWithPublicKeyID(t *testing.T) {
 	envelope := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Official Tournament"},
+		Spec: Tournament{Name: MultiName{Default: "Official Tournament"}},
 		Meta: Meta{
 			Schema:    "ptd.v1.tournament@1.0.0",
 			Version:   1,
@@ -257,7 +257,7 @@ func TestCanonicalJSONExcludesSignature(t *testing.T) {
 	envelope := &Envelope[Tournament]{
 		ID:   GenerateID(TypeTournament),
 		Type: TypeTournament,
-		Spec: Tournament{Name: "Test"},
+		Spec: Tournament{Name: MultiName{Default: "Test"}},
 		Meta: Meta{
 			Schema:    "ptd.v1.tournament@1.0.0",
 			Version:   1,
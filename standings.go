@@ -0,0 +1,63 @@
+package ptd
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// StandingRow is one line of a league table
+type StandingRow struct {
+	Position   int    `json:"position"`
+	EntryID    string `json:"entry_id"`
+	PlayerName string `json:"player_name"`
+	Played     int    `json:"played"`
+	Won        int    `json:"won"`
+	Lost       int    `json:"lost"`
+	SetsWon    int    `json:"sets_won"`
+	SetsLost   int    `json:"sets_lost"`
+	Points     int    `json:"points"`
+}
+
+// standingsTemplate renders a league table as an embeddable HTML fragment
+var standingsTemplate = template.Must(template.New("standings").Parse(`<table class="ptd-standings">
+  <caption>{{.Caption}}</caption>
+  <thead>
+    <tr><th scope="col">Position</th><th scope="col">Player</th><th scope="col">Played</th><th scope="col">Won</th><th scope="col">Lost</th><th scope="col">Sets Won</th><th scope="col">Sets Lost</th><th scope="col">Points</th></tr>
+  </thead>
+  <tbody>
+{{- range .Rows}}
+    <tr{{if .Highlight}} class="ptd-qualified" style="background-color: #e6f4ea"{{end}} data-entry-id="{{.EntryID}}"><td>{{.Position}}</td><th scope="row">{{.PlayerName}}</th><td>{{.Played}}</td><td>{{.Won}}</td><td>{{.Lost}}</td><td>{{.SetsWon}}</td><td>{{.SetsLost}}</td><td>{{.Points}}</td></tr>
+{{- end}}
+  </tbody>
+</table>
+`))
+
+// ExportStandingsHTML renders standings as a semantic HTML table captioned
+// with the event name. Rows at the given positions (e.g., those that advance
+// to the next stage) get the "ptd-qualified" class and a highlight color.
+// All values are HTML-escaped.
+func ExportStandingsHTML(standings []StandingRow, event Envelope[Event], highlightPositions ...int) ([]byte, error) {
+	highlight := make(map[int]bool, len(highlightPositions))
+	for _, position := range highlightPositions {
+		highlight[position] = true
+	}
+
+	type row struct {
+		StandingRow
+		Highlight bool
+	}
+	data := struct {
+		Caption string
+		Rows    []row
+	}{Caption: event.Spec.Name.String()}
+	for _, standing := range standings {
+		data.Rows = append(data.Rows, row{StandingRow: standing, Highlight: highlight[standing.Position]})
+	}
+
+	var buf bytes.Buffer
+	if err := standingsTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("%w: failed to render standings: %v", ErrExportFailed, err)
+	}
+	return buf.Bytes(), nil
+}
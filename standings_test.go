@@ -0,0 +1,48 @@
+package ptd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExportStandingsHTML(t *testing.T) {
+	event := Envelope[Event]{ID: GenerateID(TypeEvent), Spec: Event{Name: MultiName{Default: "Group A"}}}
+	standings := []StandingRow{
+		{Position: 1, EntryID: "ptd:entry:1", PlayerName: "Ma Long", Played: 3, Won: 3, SetsWon: 9, SetsLost: 2, Points: 6},
+		{Position: 2, EntryID: "ptd:entry:2", PlayerName: "Fan Zhendong", Played: 3, Won: 2, Lost: 1, SetsWon: 7, SetsLost: 4, Points: 5},
+		{Position: 3, EntryID: "ptd:entry:3", PlayerName: "<script>alert(1)</script>", Played: 3, Won: 1, Lost: 2, SetsWon: 4, SetsLost: 7, Points: 4},
+	}
+
+	html, err := ExportStandingsHTML(standings, event, 1, 2)
+	if err != nil {
+		t.Fatalf("ExportStandingsHTML failed: %v", err)
+	}
+	out := string(html)
+
+	for _, want := range []string{
+		`<table class="ptd-standings">`,
+		"<caption>Group A</caption>",
+		`<th scope="col">Sets Lost</th>`,
+		`<th scope="row">Ma Long</th><td>3</td><td>3</td><td>0</td><td>9</td><td>2</td><td>6</td>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("Player names must be escaped")
+	}
+	if n := strings.Count(out, `class="ptd-qualified"`); n != 2 {
+		t.Errorf("Expected 2 highlighted rows, got %d", n)
+	}
+
+	// No highlights
+	html, err = ExportStandingsHTML(standings, event)
+	if err != nil {
+		t.Fatalf("ExportStandingsHTML failed: %v", err)
+	}
+	if strings.Contains(string(html), "ptd-qualified") {
+		t.Error("No rows should be highlighted")
+	}
+}
@@ -0,0 +1,50 @@
+package ptd
+
+import "time"
+
+// TimingViolation reports whether the match started more than threshold
+// after its scheduled time, and by how much it started late. Matches
+// without both a scheduled and an actual start time are never in violation.
+func (m *Match) TimingViolation(threshold time.Duration) (bool, time.Duration) {
+	if m.ScheduledAt == nil || m.StartedAt == nil {
+		return false, 0
+	}
+	delay := m.StartedAt.Sub(*m.ScheduledAt)
+	if delay <= threshold {
+		return false, 0
+	}
+	return true, delay
+}
+
+// RecordMatchStart sets the match's actual start time. If the match has
+// already ended, its score duration is recalculated.
+func RecordMatchStart(match *Envelope[Match], at time.Time) {
+	if match == nil {
+		return
+	}
+	match.Spec.StartedAt = &at
+	match.Spec.updateDuration()
+}
+
+// RecordMatchEnd sets the match's actual end time and, if the match has a
+// start time and a score, sets the score duration to the time played
+func RecordMatchEnd(match *Envelope[Match], at time.Time) {
+	if match == nil {
+		return
+	}
+	match.Spec.EndedAt = &at
+	match.Spec.updateDuration()
+}
+
+// updateDuration sets Score.Duration from StartedAt and EndedAt
+func (m *Match) updateDuration() {
+	if m.Score == nil || m.StartedAt == nil || m.EndedAt == nil {
+		return
+	}
+	played := m.EndedAt.Sub(*m.StartedAt)
+	if played < 0 {
+		return
+	}
+	seconds := int(played.Round(time.Second) / time.Second)
+	m.Score.Duration = &Duration{Minutes: seconds / 60, Seconds: seconds % 60}
+}
@@ -0,0 +1,72 @@
+package ptd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatch_TimingViolation(t *testing.T) {
+	scheduled := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		scheduled *time.Time
+		started   time.Duration // after scheduled; negative means not started
+		violation bool
+		delay     time.Duration
+	}{
+		{"on time", &scheduled, 0, false, 0},
+		{"within threshold", &scheduled, 5 * time.Minute, false, 0},
+		{"late", &scheduled, 12 * time.Minute, true, 12 * time.Minute},
+		{"not started", &scheduled, -1, false, 0},
+		{"unscheduled", nil, 30 * time.Minute, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Match{ScheduledAt: tt.scheduled}
+			if tt.started >= 0 {
+				started := scheduled.Add(tt.started)
+				m.StartedAt = &started
+			}
+			violation, delay := m.TimingViolation(5 * time.Minute)
+			if violation != tt.violation || delay != tt.delay {
+				t.Errorf("TimingViolation() = %v, %v; want %v, %v", violation, delay, tt.violation, tt.delay)
+			}
+		})
+	}
+}
+
+func TestRecordMatchStartEnd(t *testing.T) {
+	start := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	match := &Envelope[Match]{Type: TypeMatch, Spec: Match{Score: newTestScore("3-0")}}
+
+	RecordMatchStart(match, start)
+	if match.Spec.StartedAt == nil || !match.Spec.StartedAt.Equal(start) {
+		t.Fatalf("Expected StartedAt %v, got %v", start, match.Spec.StartedAt)
+	}
+	if match.Spec.Score.Duration != nil {
+		t.Errorf("Duration should not be set before the match ends")
+	}
+
+	RecordMatchEnd(match, start.Add(42*time.Minute+30*time.Second))
+	if d := match.Spec.Score.Duration; d == nil || d.Minutes != 42 || d.Seconds != 30 {
+		t.Errorf("Expected duration 42m 30s, got %+v", d)
+	}
+
+	// Correcting the start time recalculates the duration
+	RecordMatchStart(match, start.Add(2*time.Minute))
+	if d := match.Spec.Score.Duration; d.Minutes != 40 || d.Seconds != 30 {
+		t.Errorf("Expected duration 40m 30s, got %+v", d)
+	}
+
+	// Matches without a score only record the times
+	unscored := &Envelope[Match]{Type: TypeMatch}
+	RecordMatchStart(unscored, start)
+	RecordMatchEnd(unscored, start.Add(time.Hour))
+	if unscored.Spec.EndedAt == nil || unscored.Spec.Score != nil {
+		t.Errorf("Expected end time only, got %+v", unscored.Spec)
+	}
+
+	RecordMatchStart(nil, start)
+	RecordMatchEnd(nil, start)
+}
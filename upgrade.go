@@ -0,0 +1,151 @@
+package ptd
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PackageUpgrader moves the entities of a package from one schema version
+// to a newer one without invalidating their signatures.
+//
+// New fields introduced by a minor schema version are optional and tagged
+// omitempty, so an entity gains them with their zero values simply by being
+// read with the newer types: its spec is carried over byte for byte. The
+// upgrade bumps each entity's schema version and Meta.Version, moves any
+// existing signature to Meta.PriorSignatures together with the metadata it
+// covered, and signs the upgraded envelope with Signer. Prior signatures
+// stay verifiable with VerifyPriorSignatures.
+type PackageUpgrader struct {
+	fromVersion string
+	toVersion   string
+
+	// Signer signs upgraded envelopes. If nil, upgraded envelopes carry
+	// only their prior signatures.
+	Signer *Signer
+}
+
+// NewPackageUpgrader creates an upgrader for entities whose schema version
+// is fromVersion (e.g., "1.0.0"), upgrading them to toVersion
+func NewPackageUpgrader(fromVersion, toVersion string) *PackageUpgrader {
+	return &PackageUpgrader{fromVersion: fromVersion, toVersion: toVersion}
+}
+
+// Upgrade returns an upgraded copy of pkg in a new working directory.
+// Entities at other schema versions are copied unchanged. The package
+// version is set to toVersion if it was fromVersion.
+func (u *PackageUpgrader) Upgrade(pkg *Package) (*Package, error) {
+	from, ok := parseSemver(u.fromVersion)
+	if !ok {
+		return nil, fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, u.fromVersion)
+	}
+	to, ok := parseSemver(u.toVersion)
+	if !ok {
+		return nil, fmt.Errorf("%w: version must be semantic (major.minor.patch): %s", ErrInvalidSchema, u.toVersion)
+	}
+	if !semverLess(from, to) {
+		return nil, fmt.Errorf("%w: cannot upgrade from %s to %s", ErrUnsupportedVersion, u.fromVersion, u.toVersion)
+	}
+	if from[0] != to[0] {
+		return nil, fmt.Errorf("%w: %s to %s is a major version change and cannot be upgraded losslessly", ErrUnsupportedVersion, u.fromVersion, u.toVersion)
+	}
+
+	upgraded, err := pkg.workingCopy()
+	if err != nil {
+		return nil, err
+	}
+	if upgraded.Version == u.fromVersion {
+		upgraded.Version = u.toVersion
+	}
+	if upgraded.Manifest.Version == u.fromVersion {
+		upgraded.Manifest.Version = u.toVersion
+	}
+
+	now := time.Now().UTC()
+	for _, entityType := range pkg.entityTypes() {
+		lines, err := pkg.readEntityLines(entityType)
+		if err != nil {
+			upgraded.Cleanup()
+			return nil, err
+		}
+
+		for i, line := range lines {
+			if lines[i], err = u.upgradeLine(line, now); err != nil {
+				upgraded.Cleanup()
+				return nil, fmt.Errorf("failed to upgrade %s entities: %w", entityType, err)
+			}
+		}
+
+		if err := upgraded.writeEntityLines(entityType, lines); err != nil {
+			upgraded.Cleanup()
+			return nil, err
+		}
+	}
+
+	return upgraded, nil
+}
+
+// upgradeLine upgrades one NDJSON line. The spec is kept as raw JSON so the
+// content covered by existing signatures is preserved exactly.
+func (u *PackageUpgrader) upgradeLine(line json.RawMessage, now time.Time) (json.RawMessage, error) {
+	var envelope Envelope[json.RawMessage]
+	if err := json.Unmarshal(line, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode entity: %v", ErrInvalidPackage, err)
+	}
+
+	name, version, found := strings.Cut(envelope.Meta.Schema, "@")
+	if !found || version != u.fromVersion {
+		return line, nil
+	}
+
+	if envelope.Meta.Signature != nil {
+		envelope.Meta.PriorSignatures = append(envelope.Meta.PriorSignatures, PriorSignature{
+			Signature: *envelope.Meta.Signature,
+			Schema:    envelope.Meta.Schema,
+			Version:   envelope.Meta.Version,
+			UpdatedAt: envelope.Meta.UpdatedAt,
+		})
+		envelope.Meta.Signature = nil
+	}
+	envelope.Meta.Schema = name + "@" + u.toVersion
+	envelope.Meta.Version++
+	envelope.Meta.UpdatedAt = now
+
+	if u.Signer != nil {
+		if err := u.Signer.Sign(&envelope); err != nil {
+			return nil, fmt.Errorf("failed to sign %s: %w", envelope.ID, err)
+		}
+	}
+
+	return json.Marshal(envelope)
+}
+
+// VerifyPriorSignatures verifies every signature in Meta.PriorSignatures
+// against the envelope content it was made over. lookupFunc returns the
+// public key for a signature's public key ID. It returns nil if the
+// envelope has no prior signatures.
+func VerifyPriorSignatures[T any](envelope *Envelope[T], lookupFunc func(string) (ed25519.PublicKey, error)) error {
+	priors := envelope.Meta.PriorSignatures
+	for i, prior := range priors {
+		publicKey, err := lookupFunc(prior.Signature.PublicKeyID)
+		if err != nil {
+			return ErrSignatureKeyMissing
+		}
+
+		// Reconstruct the envelope as it was when this signature was made
+		earlier := *envelope
+		earlier.Meta.Schema = prior.Schema
+		earlier.Meta.Version = prior.Version
+		earlier.Meta.UpdatedAt = prior.UpdatedAt
+		earlier.Meta.PriorSignatures = priors[:i:i]
+		signature := prior.Signature
+		earlier.Meta.Signature = &signature
+
+		if err := Verify(&earlier, publicKey); err != nil {
+			return fmt.Errorf("prior signature %d by %s: %w", i, prior.Signature.SignedBy, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package ptd
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPackageUpgrader_Upgrade(t *testing.T) {
+	oldSigner, err := NewSigner("key-2024", "icc:2024")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	newSigner, err := NewSigner("key-2025", "icc:2025")
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	keys := map[string]ed25519.PublicKey{
+		"key-2024": oldSigner.publicKey,
+		"key-2025": newSigner.publicKey,
+	}
+	lookup := func(id string) (ed25519.PublicKey, error) {
+		if key, ok := keys[id]; ok {
+			return key, nil
+		}
+		return nil, ErrSignatureKeyMissing
+	}
+
+	scheduled := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	match := &Envelope[Match]{
+		ID:   GenerateID(TypeMatch),
+		Type: TypeMatch,
+		Spec: Match{EventID: GenerateID(TypeEvent), MatchNumber: "M1 <QF>", Status: "completed",
+			ScheduledAt: &scheduled, Score: newTestScore("3-1", [2]int{11, 9}, [2]int{8, 11}, [2]int{11, 7}, [2]int{12, 10})},
+		Meta: Meta{Schema: "ptd.v1.match@1.0.0", Version: 3, CreatedAt: scheduled, UpdatedAt: scheduled.Add(time.Hour), Source: "icc:test"},
+	}
+	if err := oldSigner.Sign(match); err != nil {
+		t.Fatalf("Failed to sign match: %v", err)
+	}
+	other := Envelope[Event]{ID: GenerateID(TypeEvent), Type: TypeEvent, Spec: Event{Name: MultiName{Default: "MS"}},
+		Meta: Meta{Schema: "ptd.v1.event@0.9.0", Version: 1}}
+
+	pkg := NewPackage("Upgrade test")
+	defer pkg.Cleanup()
+	if err := pkg.AddEntities(TypeMatch, []interface{}{match}); err != nil {
+		t.Fatalf("Failed to add match: %v", err)
+	}
+	if err := pkg.AddEntities(TypeEvent, []interface{}{other}); err != nil {
+		t.Fatalf("Failed to add event: %v", err)
+	}
+
+	upgrader := NewPackageUpgrader("1.0.0", "1.1.0")
+	upgrader.Signer = newSigner
+	upgraded, err := upgrader.Upgrade(pkg)
+	if err != nil {
+		t.Fatalf("Upgrade failed: %v", err)
+	}
+	defer upgraded.Cleanup()
+
+	matches, err := decodeEntityLines[Match](upgraded, TypeMatch)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Failed to read upgraded match: %v", err)
+	}
+	got := &matches[0]
+	if got.Meta.Schema != "ptd.v1.match@1.1.0" || got.Meta.Version != 4 {
+		t.Errorf("Expected schema 1.1.0 version 4, got %s version %d", got.Meta.Schema, got.Meta.Version)
+	}
+	if got.Spec.StartedAt != nil || got.Spec.MatchNumber != "M1 <QF>" {
+		t.Errorf("Spec should be carried over unchanged: %+v", got.Spec)
+	}
+
+	// New signature covers the upgraded envelope, the old one the original
+	if err := VerifyWithPublicKeyID(got, lookup); err != nil {
+		t.Errorf("New signature does not verify: %v", err)
+	}
+	if len(got.Meta.PriorSignatures) != 1 || got.Meta.PriorSignatures[0].Signature.PublicKeyID != "key-2024" {
+		t.Fatalf("Expected the old signature to be kept, got %+v", got.Meta.PriorSignatures)
+	}
+	if err := VerifyPriorSignatures(got, lookup); err != nil {
+		t.Errorf("Prior signature does not verify: %v", err)
+	}
+
+	tampered := *got
+	tampered.Spec.Winner = "someone else"
+	if err := VerifyPriorSignatures(&tampered, lookup); !errors.Is(err, ErrSignatureFailed) {
+		t.Errorf("Expected tampered spec to fail prior verification, got %v", err)
+	}
+
+	// A second upgrade chains the signatures
+	again := NewPackageUpgrader("1.1.0", "1.2.0")
+	again.Signer = oldSigner
+	twice, err := again.Upgrade(upgraded)
+	if err != nil {
+		t.Fatalf("Second upgrade failed: %v", err)
+	}
+	defer twice.Cleanup()
+	matches, _ = decodeEntityLines[Match](twice, TypeMatch)
+	if len(matches[0].Meta.PriorSignatures) != 2 {
+		t.Fatalf("Expected 2 prior signatures, got %d", len(matches[0].Meta.PriorSignatures))
+	}
+	if err := VerifyPriorSignatures(&matches[0], lookup); err != nil {
+		t.Errorf("Chained prior signatures do not verify: %v", err)
+	}
+
+	// Entities at other schema versions are untouched
+	oldLines, _ := pkg.readEntityLines(TypeEvent)
+	newLines, _ := upgraded.readEntityLines(TypeEvent)
+	if string(oldLines[0]) != string(newLines[0]) {
+		t.Errorf("Event at another version was modified:\n%s\n%s", oldLines[0], newLines[0])
+	}
+	var event Envelope[json.RawMessage]
+	if err := json.Unmarshal(newLines[0], &event); err != nil || event.Meta.Version != 1 {
+		t.Errorf("Unexpected event after upgrade: %s", newLines[0])
+	}
+}
+
+func TestPackageUpgrader_InvalidVersions(t *testing.T) {
+	pkg := NewPackage("Upgrade test")
+	defer pkg.Cleanup()
+
+	tests := []struct {
+		from, to string
+		want     error
+	}{
+		{"1.0", "1.1.0", ErrInvalidSchema},
+		{"1.0.0", "latest", ErrInvalidSchema},
+		{"1.1.0", "1.0.0", ErrUnsupportedVersion},
+		{"1.0.0", "2.0.0", ErrUnsupportedVersion},
+	}
+	for _, tt := range tests {
+		if _, err := NewPackageUpgrader(tt.from, tt.to).Upgrade(pkg); !errors.Is(err, tt.want) {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.want, err)
+		}
+	}
+}
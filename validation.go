@@ -0,0 +1,195 @@
+package ptd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ValidateDateOrder checks that events fall within the tournament's dates and
+// that sequential rounds of each event do not overlap. Rounds are ordered by
+// RoundNumber within their event. One ValidationError is returned per violation.
+func ValidateDateOrder(events []Envelope[Event], tournament Envelope[Tournament], rounds ...Envelope[Round]) []ValidationError {
+	var violations []ValidationError
+
+	t := tournament.Spec
+	for _, event := range events {
+		e := event.Spec
+		if !t.StartDate.IsZero() && !e.StartDate.IsZero() && e.StartDate.Before(t.StartDate) {
+			violations = append(violations, ValidationError{
+				EntityID:   event.ID,
+				EntityType: TypeEvent,
+				FieldPath:  "event.start_date",
+				Message:    fmt.Sprintf("event.start_date %s is before tournament.start_date %s", e.StartDate.Format("2006-01-02"), t.StartDate.Format("2006-01-02")),
+				Err:        ErrValidation,
+			})
+		}
+		if !t.EndDate.IsZero() && !e.EndDate.IsZero() && e.EndDate.After(t.EndDate) {
+			violations = append(violations, ValidationError{
+				EntityID:   event.ID,
+				EntityType: TypeEvent,
+				FieldPath:  "event.end_date",
+				Message:    fmt.Sprintf("event.end_date %s is after tournament.end_date %s", e.EndDate.Format("2006-01-02"), t.EndDate.Format("2006-01-02")),
+				Err:        ErrValidation,
+			})
+		}
+	}
+
+	// Group rounds by event and order them
+	byEvent := make(map[string][]Envelope[Round])
+	var eventIDs []string
+	for _, round := range rounds {
+		if _, ok := byEvent[round.Spec.EventID]; !ok {
+			eventIDs = append(eventIDs, round.Spec.EventID)
+		}
+		byEvent[round.Spec.EventID] = append(byEvent[round.Spec.EventID], round)
+	}
+
+	for _, eventID := range eventIDs {
+		eventRounds := byEvent[eventID]
+		sort.SliceStable(eventRounds, func(i, j int) bool {
+			return eventRounds[i].Spec.RoundNumber < eventRounds[j].Spec.RoundNumber
+		})
+
+		for i := 0; i+1 < len(eventRounds); i++ {
+			cur, next := eventRounds[i].Spec, eventRounds[i+1].Spec
+			if cur.EndDate.IsZero() || next.StartDate.IsZero() {
+				continue
+			}
+			if cur.EndDate.After(next.StartDate) {
+				violations = append(violations, ValidationError{
+					EntityID:   eventRounds[i+1].ID,
+					EntityType: TypeRound,
+					FieldPath:  "round.start_date",
+					Message:    fmt.Sprintf("round %d starts before round %d ends", next.RoundNumber, cur.RoundNumber),
+					Err:        ErrValidation,
+				})
+			}
+		}
+	}
+
+	return violations
+}
+
+// ValidateMatchReferenceChain checks that a match belongs to the round,
+// event and tournament given: the match and round reference the event, the
+// match references the round and the event references the tournament. It
+// is meant to run before a package is published. Every broken link is
+// reported as a *ValidationError wrapping ErrInvalidID; they are returned
+// joined, or nil if the chain is intact.
+func ValidateMatchReferenceChain(match Envelope[Match], round Envelope[Round], event Envelope[Event], tournament Envelope[Tournament]) error {
+	var errs []error
+	check := func(ok bool, entityID, entityType, fieldPath, reference, want string) {
+		if ok {
+			return
+		}
+		err := newValidationError(ErrInvalidID, entityType, fieldPath, "%s %q does not reference %s", fieldPath, reference, want)
+		err.EntityID = entityID
+		errs = append(errs, err)
+	}
+
+	check(match.Spec.EventID == event.ID, match.ID, TypeMatch, "match.event_id", match.Spec.EventID, event.ID)
+	check(match.Spec.RoundID == round.ID, match.ID, TypeMatch, "match.round_id", match.Spec.RoundID, round.ID)
+	check(round.Spec.EventID == event.ID, round.ID, TypeRound, "round.event_id", round.Spec.EventID, event.ID)
+	check(event.Spec.TournamentID == tournament.ID, event.ID, TypeEvent, "event.tournament_id", event.Spec.TournamentID, tournament.ID)
+
+	return errors.Join(errs...)
+}
+
+// ValidatePackage validates every entity in the package and the date order of
+// its tournaments, events and rounds. Validation failures are returned as
+// violations; the error is reserved for failures to read the package.
+func (v *SchemaValidator) ValidatePackage(pkg *Package) ([]ValidationError, error) {
+	if pkg.Manifest == nil {
+		return nil, ErrManifestMissing
+	}
+
+	var violations []ValidationError
+	for _, entityType := range pkg.entityTypes() {
+		lines, err := pkg.readEntityLines(entityType)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, line := range lines {
+			var envelope Envelope[map[string]interface{}]
+			if err := json.Unmarshal(line, &envelope); err != nil {
+				violations = append(violations, ValidationError{
+					EntityType: entityType,
+					Message:    fmt.Sprintf("line %d: %v", i+1, err),
+					Err:        ErrInvalidFormat,
+				})
+				continue
+			}
+
+			if err := v.ValidateEnvelope(&envelope); err != nil {
+				violations = append(violations, *asValidationError(err))
+			}
+		}
+	}
+
+	tournaments, err := decodeEntityLines[Tournament](pkg, TypeTournament)
+	if err != nil {
+		return nil, err
+	}
+	events, err := decodeEntityLines[Event](pkg, TypeEvent)
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := decodeEntityLines[Round](pkg, TypeRound)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tournament := range tournaments {
+		var tournamentEvents []Envelope[Event]
+		eventIDs := make(map[string]bool)
+		for _, event := range events {
+			if event.Spec.TournamentID == tournament.ID {
+				tournamentEvents = append(tournamentEvents, event)
+				eventIDs[event.ID] = true
+			}
+		}
+
+		var tournamentRounds []Envelope[Round]
+		for _, round := range rounds {
+			if eventIDs[round.Spec.EventID] {
+				tournamentRounds = append(tournamentRounds, round)
+			}
+		}
+
+		violations = append(violations, ValidateDateOrder(tournamentEvents, tournament, tournamentRounds...)...)
+	}
+
+	return violations, nil
+}
+
+// decodeEntityLines decodes the package's entities of one type, skipping
+// lines that do not decode (those are reported by envelope validation)
+func decodeEntityLines[T any](pkg *Package, entityType string) ([]Envelope[T], error) {
+	lines, err := pkg.readEntityLines(entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	envelopes := make([]Envelope[T], 0, len(lines))
+	for _, line := range lines {
+		var envelope Envelope[T]
+		if err := json.Unmarshal(line, &envelope); err != nil {
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+	}
+
+	return envelopes, nil
+}
+
+// asValidationError returns the ValidationError in err's chain, wrapping err if there is none
+func asValidationError(err error) *ValidationError {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve
+	}
+	return &ValidationError{Err: err}
+}
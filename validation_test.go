@@ -0,0 +1,159 @@
+package ptd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateDateOrder(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tournament := Envelope[Tournament]{
+		ID:   GenerateID(TypeTournament),
+		Spec: Tournament{Name: MultiName{Default: "Open"}, StartDate: start, EndDate: start.Add(3 * day)},
+	}
+
+	good := Envelope[Event]{
+		ID:   GenerateID(TypeEvent),
+		Spec: Event{StartDate: start, EndDate: start.Add(2 * day)},
+	}
+	early := Envelope[Event]{
+		ID:   GenerateID(TypeEvent),
+		Spec: Event{StartDate: start.Add(-day), EndDate: start.Add(day)},
+	}
+	late := Envelope[Event]{
+		ID:   GenerateID(TypeEvent),
+		Spec: Event{StartDate: start, EndDate: start.Add(4 * day)},
+	}
+
+	if v := ValidateDateOrder([]Envelope[Event]{good}, tournament); len(v) != 0 {
+		t.Errorf("Expected no violations, got %v", v)
+	}
+
+	violations := ValidateDateOrder([]Envelope[Event]{good, early, late}, tournament)
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d: %v", len(violations), violations)
+	}
+	if violations[0].EntityID != early.ID || violations[0].FieldPath != "event.start_date" {
+		t.Errorf("Unexpected first violation: %+v", violations[0])
+	}
+	if violations[1].EntityID != late.ID || violations[1].FieldPath != "event.end_date" {
+		t.Errorf("Unexpected second violation: %+v", violations[1])
+	}
+	if !errors.Is(&violations[0], ErrValidation) {
+		t.Error("Violations should wrap ErrValidation")
+	}
+
+	// Overlapping rounds, given out of order
+	r1 := Envelope[Round]{ID: GenerateID(TypeRound), Spec: Round{EventID: good.ID, RoundNumber: 1, StartDate: start, EndDate: start.Add(day)}}
+	r2 := Envelope[Round]{ID: GenerateID(TypeRound), Spec: Round{EventID: good.ID, RoundNumber: 2, StartDate: start.Add(day / 2), EndDate: start.Add(2 * day)}}
+	violations = ValidateDateOrder([]Envelope[Event]{good}, tournament, r2, r1)
+	if len(violations) != 1 || violations[0].EntityID != r2.ID {
+		t.Errorf("Expected round overlap violation for round 2, got %v", violations)
+	}
+}
+
+func TestSchemaValidator_ValidatePackage(t *testing.T) {
+	pkg := NewPackage("Validation test")
+	defer pkg.Cleanup()
+
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	tournamentID := GenerateID(TypeTournament)
+
+	tournaments := []interface{}{
+		Envelope[Tournament]{
+			ID:   tournamentID,
+			Type: TypeTournament,
+			Spec: Tournament{Name: MultiName{Default: "Open"}, StartDate: start, EndDate: start.Add(48 * time.Hour)},
+			Meta: Meta{Schema: "ptd.v1.tournament@1.0.0"},
+		},
+	}
+	lateEventID := GenerateID(TypeEvent)
+	events := []interface{}{
+		Envelope[Event]{
+			ID:   lateEventID,
+			Type: TypeEvent,
+			Spec: Event{TournamentID: tournamentID, Name: MultiName{Default: "MS"}, StartDate: start, EndDate: start.Add(72 * time.Hour)},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+		Envelope[Event]{
+			ID:   GenerateID(TypeEvent),
+			Type: TypeEvent,
+			Spec: Event{TournamentID: tournamentID},
+			Meta: Meta{Schema: "ptd.v1.event@1.0.0"},
+		},
+	}
+	if err := pkg.AddEntities(TypeTournament, tournaments); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+	if err := pkg.AddEntities(TypeEvent, events); err != nil {
+		t.Fatalf("Failed to add entities: %v", err)
+	}
+
+	violations, err := NewSchemaValidator(false).ValidatePackage(pkg)
+	if err != nil {
+		t.Fatalf("ValidatePackage failed: %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %d: %v", len(violations), violations)
+	}
+
+	var missingName, dateOrder bool
+	for _, v := range violations {
+		switch v.FieldPath {
+		case "event.name":
+			missingName = errors.Is(&v, ErrMissingField)
+		case "event.end_date":
+			dateOrder = v.EntityID == lateEventID
+		}
+	}
+	if !missingName {
+		t.Error("Expected missing event.name violation")
+	}
+	if !dateOrder {
+		t.Error("Expected event.end_date violation")
+	}
+}
+
+func TestValidateMatchReferenceChain(t *testing.T) {
+	tournament := Envelope[Tournament]{ID: "ptd:tournament:1", Type: TypeTournament}
+	event := Envelope[Event]{ID: "ptd:event:1", Type: TypeEvent, Spec: Event{TournamentID: tournament.ID}}
+	round := Envelope[Round]{ID: "ptd:round:1", Type: TypeRound, Spec: Round{EventID: event.ID}}
+	match := Envelope[Match]{ID: "ptd:match:1", Type: TypeMatch, Spec: Match{EventID: event.ID, RoundID: round.ID}}
+
+	if err := ValidateMatchReferenceChain(match, round, event, tournament); err != nil {
+		t.Fatalf("ValidateMatchReferenceChain() error = %v", err)
+	}
+
+	// Every broken link is reported
+	match.Spec.EventID = "ptd:event:2"
+	match.Spec.RoundID = ""
+	round.Spec.EventID = "ptd:event:2"
+	event.Spec.TournamentID = "ptd:tournament:2"
+	err := ValidateMatchReferenceChain(match, round, event, tournament)
+	if !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("ValidateMatchReferenceChain() error = %v, want ErrInvalidID", err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("ValidateMatchReferenceChain() error %T is not a joined error", err)
+	}
+	var got []string
+	for _, e := range joined.Unwrap() {
+		ve := asValidationError(e)
+		got = append(got, ve.EntityID+" "+ve.FieldPath)
+	}
+	want := []string{
+		"ptd:match:1 match.event_id",
+		"ptd:match:1 match.round_id",
+		"ptd:round:1 round.event_id",
+		"ptd:event:1 event.tournament_id",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Chain breaks = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package ptd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long an archive must stay unchanged after a
+// write before Watch reopens it
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// Watch monitors the archive at archivePath and calls onChange with the
+// reopened package after each change. Changes are reported by fsnotify on
+// the archive's directory, so saves that replace the file, such as a write
+// to a temporary file followed by a rename, are seen as well as writes in
+// place. A save is only picked up once the file has been quiet for
+// DefaultWatchDebounce, so a save made of several writes triggers a single
+// call. The package is opened with OpenPackage, so its hashes are
+// verified; versions that fail to open, such as a half-written archive,
+// are skipped.
+//
+// Watch blocks until ctx is cancelled and then returns ctx.Err(). It returns
+// early with an error if archivePath cannot be read when Watch starts, or
+// if the file system watcher fails.
+func Watch(ctx context.Context, archivePath string, onChange func(*Package)) error {
+	if _, err := os.Stat(archivePath); err != nil {
+		return fmt.Errorf("failed to watch archive: %w", err)
+	}
+	archivePath, err := filepath.Abs(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to watch archive: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch archive: %w", err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(archivePath)); err != nil {
+		return fmt.Errorf("failed to watch archive: %w", err)
+	}
+
+	debounce := time.NewTimer(DefaultWatchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("failed to watch archive: watcher closed")
+			}
+			if filepath.Clean(event.Name) != archivePath || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			debounce.Reset(DefaultWatchDebounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return fmt.Errorf("failed to watch archive: watcher closed")
+			}
+			return fmt.Errorf("failed to watch archive: %w", err)
+
+		case <-debounce.C:
+			pkg, err := OpenPackage(archivePath)
+			if err != nil {
+				continue
+			}
+			onChange(pkg)
+		}
+	}
+}
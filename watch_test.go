@@ -0,0 +1,68 @@
+package ptd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "live.ptd")
+	writeArchive := func(description string, modTime time.Time) {
+		t.Helper()
+		pkg := NewPackage(description)
+		defer pkg.Cleanup()
+		if err := pkg.CreateArchive(path); err != nil {
+			t.Fatalf("Failed to create archive: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set modification time: %v", err)
+		}
+	}
+	base := time.Now().Add(-time.Hour)
+	writeArchive("Round 1", base)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan *Package, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, path, func(pkg *Package) { changes <- pkg })
+	}()
+
+	// A save made of several writes triggers a single reload
+	time.Sleep(100 * time.Millisecond) // Let Watch start watching
+	writeArchive("Round 2 (partial)", base.Add(time.Minute))
+	writeArchive("Round 2", base.Add(2*time.Minute))
+
+	select {
+	case pkg := <-changes:
+		if pkg.Manifest.Description != "Round 2" {
+			t.Errorf("Expected reloaded package, got %q", pkg.Manifest.Description)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for change")
+	}
+
+	select {
+	case pkg := <-changes:
+		t.Errorf("Unexpected extra change: %q", pkg.Manifest.Description)
+	case <-time.After(2 * DefaultWatchDebounce):
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatch_MissingArchive(t *testing.T) {
+	err := Watch(context.Background(), filepath.Join(t.TempDir(), "missing.ptd"), func(*Package) {})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+}